@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// ExpiryRecorder records an audit record for every route and tcp route
+// mapping that expires.
+type ExpiryRecorder struct {
+	database db.DB
+	recorder Recorder
+	logger   lager.Logger
+}
+
+func NewExpiryRecorder(database db.DB, recorder Recorder, logger lager.Logger) *ExpiryRecorder {
+	return &ExpiryRecorder{
+		database: database,
+		recorder: recorder,
+		logger:   logger,
+	}
+}
+
+func (r *ExpiryRecorder) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	httpEventChan, httpErrChan, httpCancel := r.database.WatchChanges(db.HTTP_WATCH)
+	tcpEventChan, tcpErrChan, tcpCancel := r.database.WatchChanges(db.TCP_WATCH)
+	defer httpCancel()
+	defer tcpCancel()
+	close(ready)
+
+	for {
+		select {
+		case event, ok := <-httpEventChan:
+			if !ok {
+				return nil
+			}
+			if event.Type != db.ExpireEvent {
+				continue
+			}
+			var route models.Route
+			err := json.Unmarshal([]byte(event.Value), &route)
+			if err != nil {
+				r.logger.Error("failed-to-unmarshal-expired-route", err, lager.Data{"value": event.Value})
+				continue
+			}
+			r.record(models.AuditKindHttpRoute, route.AuditKey(), event.Value)
+		case event, ok := <-tcpEventChan:
+			if !ok {
+				return nil
+			}
+			if event.Type != db.ExpireEvent {
+				continue
+			}
+			var tcpMapping models.TcpRouteMapping
+			err := json.Unmarshal([]byte(event.Value), &tcpMapping)
+			if err != nil {
+				r.logger.Error("failed-to-unmarshal-expired-tcp-route-mapping", err, lager.Data{"value": event.Value})
+				continue
+			}
+			r.record(models.AuditKindTcpRoute, tcpMapping.AuditKey(), event.Value)
+		case err := <-httpErrChan:
+			return err
+		case err := <-tcpErrChan:
+			return err
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (r *ExpiryRecorder) record(kind, key, value string) {
+	r.recorder.Record(models.AuditRecord{
+		Action: models.AuditActionExpire,
+		Kind:   kind,
+		Key:    key,
+		Actor:  SystemActor,
+		Before: models.AuditValue(value),
+	})
+}
@@ -0,0 +1,83 @@
+package audit_test
+
+import (
+	"os"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/audit"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	"code.cloudfoundry.org/routing-api/db"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	"github.com/coreos/etcd/Godeps/_workspace/src/golang.org/x/net/context"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExpiryRecorder", func() {
+	var (
+		database       *fake_db.FakeDB
+		recorder       *fake_audit.FakeRecorder
+		httpEvents     chan db.Event
+		tcpEvents      chan db.Event
+		expiryRecorder *audit.ExpiryRecorder
+		process        ifrit.Process
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		recorder = &fake_audit.FakeRecorder{}
+		httpEvents = make(chan db.Event)
+		tcpEvents = make(chan db.Event)
+		database.WatchChangesStub = func(watchType string) (<-chan db.Event, <-chan error, context.CancelFunc) {
+			if watchType == db.HTTP_WATCH {
+				return httpEvents, nil, func() {}
+			}
+			return tcpEvents, nil, func() {}
+		}
+
+		logger := lagertest.NewTestLogger("audit-test")
+		expiryRecorder = audit.NewExpiryRecorder(database, recorder, logger)
+		process = ifrit.Invoke(expiryRecorder)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("records expired http routes", func() {
+		event, err := db.NewEventFromInterface(db.ExpireEvent, models.NewRoute("a.example.com", 8080, "1.2.3.4", "", "", 60))
+		Expect(err).NotTo(HaveOccurred())
+		httpEvents <- event
+
+		Eventually(recorder.RecordCallCount).Should(Equal(1))
+		record := recorder.RecordArgsForCall(0)
+		Expect(record.Action).To(Equal(models.AuditActionExpire))
+		Expect(record.Kind).To(Equal(models.AuditKindHttpRoute))
+		Expect(record.Key).To(Equal("a.example.com"))
+		Expect(record.Actor).To(Equal(audit.SystemActor))
+		Expect(record.Before).To(Equal(models.AuditValue(event.Value)))
+	})
+
+	It("records expired tcp route mappings", func() {
+		event, err := db.NewEventFromInterface(db.ExpireEvent, models.NewTcpRouteMapping("rg-guid", 52000, "1.2.3.4", 60000, 60))
+		Expect(err).NotTo(HaveOccurred())
+		tcpEvents <- event
+
+		Eventually(recorder.RecordCallCount).Should(Equal(1))
+		record := recorder.RecordArgsForCall(0)
+		Expect(record.Kind).To(Equal(models.AuditKindTcpRoute))
+		Expect(record.Key).To(Equal("rg-guid:52000"))
+	})
+
+	It("ignores other events", func() {
+		event, err := db.NewEventFromInterface(db.DeleteEvent, models.NewRoute("a.example.com", 8080, "1.2.3.4", "", "", 60))
+		Expect(err).NotTo(HaveOccurred())
+		httpEvents <- event
+
+		Consistently(recorder.RecordCallCount).Should(Equal(0))
+	})
+})
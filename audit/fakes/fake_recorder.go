@@ -0,0 +1,98 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/routing-api/audit"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type FakeRecorder struct {
+	EnabledStub        func() bool
+	enabledMutex       sync.RWMutex
+	enabledArgsForCall []struct{}
+	enabledReturns     struct {
+		result1 bool
+	}
+	RecordStub        func(record models.AuditRecord)
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		record models.AuditRecord
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRecorder) Enabled() bool {
+	fake.enabledMutex.Lock()
+	fake.enabledArgsForCall = append(fake.enabledArgsForCall, struct{}{})
+	fake.recordInvocation("Enabled", []interface{}{})
+	fake.enabledMutex.Unlock()
+	if fake.EnabledStub != nil {
+		return fake.EnabledStub()
+	} else {
+		return fake.enabledReturns.result1
+	}
+}
+
+func (fake *FakeRecorder) EnabledCallCount() int {
+	fake.enabledMutex.RLock()
+	defer fake.enabledMutex.RUnlock()
+	return len(fake.enabledArgsForCall)
+}
+
+func (fake *FakeRecorder) EnabledReturns(result1 bool) {
+	fake.EnabledStub = nil
+	fake.enabledReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRecorder) Record(record models.AuditRecord) {
+	fake.recordMutex.Lock()
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		record models.AuditRecord
+	}{record})
+	fake.recordInvocation("Record", []interface{}{record})
+	fake.recordMutex.Unlock()
+	if fake.RecordStub != nil {
+		fake.RecordStub(record)
+	}
+}
+
+func (fake *FakeRecorder) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeRecorder) RecordArgsForCall(i int) models.AuditRecord {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.recordArgsForCall[i].record
+}
+
+func (fake *FakeRecorder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.enabledMutex.RLock()
+	defer fake.enabledMutex.RUnlock()
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeRecorder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ audit.Recorder = new(FakeRecorder)
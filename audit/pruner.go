@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+)
+
+// Pruner periodically deletes audit records older than the retention period,
+// and the oldest records beyond maxRecords.
+type Pruner struct {
+	database   db.DB
+	retention  time.Duration
+	maxRecords int
+	interval   time.Duration
+	clock      clock.Clock
+	logger     lager.Logger
+}
+
+func NewPruner(database db.DB, retention time.Duration, maxRecords int, interval time.Duration, clock clock.Clock, logger lager.Logger) *Pruner {
+	return &Pruner{
+		database:   database,
+		retention:  retention,
+		maxRecords: maxRecords,
+		interval:   interval,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+func (p *Pruner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C():
+			olderThan := p.clock.Now().Add(-p.retention)
+			err := p.database.PruneAuditRecords(olderThan, p.maxRecords)
+			if err != nil {
+				p.logger.Error("failed-to-prune-audit-records", err)
+				continue
+			}
+			p.logger.Debug("pruned-audit-records", lager.Data{"older_than": olderThan, "max_records": p.maxRecords})
+		case <-signals:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package audit_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/audit"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Pruner", func() {
+	var (
+		database  *fake_db.FakeDB
+		fakeClock *fakeclock.FakeClock
+		logger    *lagertest.TestLogger
+		process   ifrit.Process
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		now = time.Unix(100000, 0)
+		fakeClock = fakeclock.NewFakeClock(now)
+		logger = lagertest.NewTestLogger("audit-test")
+	})
+
+	JustBeforeEach(func() {
+		pruner := audit.NewPruner(database, time.Hour, 100, time.Minute, fakeClock, logger)
+		process = ifrit.Invoke(pruner)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("prunes records older than the retention or beyond the max records on every interval", func() {
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(database.PruneAuditRecordsCallCount).Should(Equal(1))
+		olderThan, maxRecords := database.PruneAuditRecordsArgsForCall(0)
+		Expect(olderThan).To(Equal(now.Add(time.Minute).Add(-time.Hour)))
+		Expect(maxRecords).To(Equal(100))
+
+		fakeClock.Increment(time.Minute)
+		Eventually(database.PruneAuditRecordsCallCount).Should(Equal(2))
+	})
+
+	Context("when pruning fails", func() {
+		BeforeEach(func() {
+			database.PruneAuditRecordsReturns(errors.New("stuff broke"))
+		})
+
+		It("logs the error and keeps running", func() {
+			fakeClock.WaitForWatcherAndIncrement(time.Minute)
+			Eventually(logger).Should(gbytes.Say("failed-to-prune-audit-records"))
+			Consistently(process.Wait()).ShouldNot(Receive())
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// SystemActor is the actor recorded for mutations that are not caused by an
+// API request, such as route expiry.
+const SystemActor = "routing-api"
+
+//go:generate counterfeiter -o fakes/fake_recorder.go . Recorder
+type Recorder interface {
+	// Enabled reports whether records are persisted. Callers use it to skip
+	// the work of collecting before values when auditing is turned off.
+	Enabled() bool
+	Record(record models.AuditRecord)
+}
+
+type recorder struct {
+	database db.DB
+	clock    clock.Clock
+	logger   lager.Logger
+}
+
+func NewRecorder(database db.DB, clock clock.Clock, logger lager.Logger) Recorder {
+	return &recorder{
+		database: database,
+		clock:    clock,
+		logger:   logger,
+	}
+}
+
+func (r *recorder) Enabled() bool {
+	return true
+}
+
+// Record persists the record. Failing to write an audit record does not fail
+// the mutation it describes; the error is logged together with the record.
+func (r *recorder) Record(record models.AuditRecord) {
+	if record.Time.IsZero() {
+		record.Time = r.clock.Now()
+	}
+
+	err := r.database.SaveAuditRecord(record)
+	if err != nil {
+		r.logger.Error("failed-to-save-audit-record", err, lager.Data{"record": record})
+	}
+}
+
+type noopRecorder struct{}
+
+func NewNoopRecorder() Recorder {
+	return noopRecorder{}
+}
+
+func (noopRecorder) Enabled() bool {
+	return false
+}
+
+func (noopRecorder) Record(models.AuditRecord) {}
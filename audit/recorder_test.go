@@ -0,0 +1,64 @@
+package audit_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/audit"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		database  *fake_db.FakeDB
+		fakeClock *fakeclock.FakeClock
+		logger    *lagertest.TestLogger
+		recorder  audit.Recorder
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		now = time.Unix(1000, 0)
+		fakeClock = fakeclock.NewFakeClock(now)
+		logger = lagertest.NewTestLogger("audit-test")
+		recorder = audit.NewRecorder(database, fakeClock, logger)
+	})
+
+	It("is enabled", func() {
+		Expect(recorder.Enabled()).To(BeTrue())
+	})
+
+	It("saves the record with the current time", func() {
+		recorder.Record(models.AuditRecord{Action: models.AuditActionUpsert, Key: "a.example.com"})
+
+		Expect(database.SaveAuditRecordCallCount()).To(Equal(1))
+		record := database.SaveAuditRecordArgsForCall(0)
+		Expect(record.Key).To(Equal("a.example.com"))
+		Expect(record.Time).To(Equal(now))
+	})
+
+	Context("when saving fails", func() {
+		BeforeEach(func() {
+			database.SaveAuditRecordReturns(errors.New("stuff broke"))
+		})
+
+		It("logs the error", func() {
+			recorder.Record(models.AuditRecord{Key: "a.example.com"})
+			Expect(logger).To(gbytes.Say("failed-to-save-audit-record"))
+		})
+	})
+
+	Describe("NewNoopRecorder", func() {
+		It("is disabled", func() {
+			Expect(audit.NewNoopRecorder().Enabled()).To(BeFalse())
+		})
+	})
+})
@@ -2,16 +2,20 @@ package routing_api
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	"code.cloudfoundry.org/routing-api/tracing"
 	trace "code.cloudfoundry.org/trace-logger"
 	"github.com/tedsuo/rata"
 	"github.com/vito/go-sse/sse"
@@ -24,22 +28,54 @@ const (
 //go:generate counterfeiter -o fake_routing_api/fake_client.go . Client
 type Client interface {
 	SetToken(string)
+	WithContext(context.Context) Client
 	UpsertRoutes([]models.Route) error
+	UpsertRoutesIfMatch([]models.Route, models.ModificationTag) error
+	UpsertRoutesDryRun([]models.Route) ([]models.RouteChange, error)
 	Routes() ([]models.Route, error)
 	DeleteRoutes([]models.Route) error
+	DeleteRoutesIfMatch([]models.Route, models.ModificationTag) error
+	DeleteRoutesDryRun([]models.Route) ([]models.RouteChange, error)
+	DrainRoutes([]models.Route, time.Duration) error
+	DeleteRoutesBySelector(models.RouteSelector, bool) ([]models.Route, error)
 	RouterGroups() ([]models.RouterGroup, error)
 	UpdateRouterGroup(models.RouterGroup) error
+	UpdateRouterGroupDryRun(models.RouterGroup) (models.RouterGroupChange, error)
 	UpsertTcpRouteMappings([]models.TcpRouteMapping) error
+	UpsertTcpRouteMappingsIfMatch([]models.TcpRouteMapping, models.ModificationTag) error
+	UpsertTcpRouteMappingsDryRun([]models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error)
 	DeleteTcpRouteMappings([]models.TcpRouteMapping) error
+	DeleteTcpRouteMappingsIfMatch([]models.TcpRouteMapping, models.ModificationTag) error
+	DeleteTcpRouteMappingsDryRun([]models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error)
+	DrainTcpRouteMappings([]models.TcpRouteMapping, time.Duration) error
+	DeleteTcpRouteMappingsBySelector(models.TcpRouteMappingSelector, bool) ([]models.TcpRouteMapping, error)
 	TcpRouteMappings() ([]models.TcpRouteMapping, error)
+	AuditRecords(models.AuditFilter) ([]models.AuditRecord, error)
+	RouteHistory(models.Route) ([]models.RouteVersion, error)
+	TcpRouteMappingHistory(models.TcpRouteMapping) ([]models.RouteVersion, error)
+	QuotaUsage() ([]models.QuotaUsage, error)
 
 	SubscribeToEvents() (EventSource, error)
 	SubscribeToEventsWithMaxRetries(retries uint16) (EventSource, error)
 	SubscribeToTcpEvents() (TcpEventSource, error)
 	SubscribeToTcpEventsWithMaxRetries(retries uint16) (TcpEventSource, error)
+	SubscribeToEventsWithWebSocket(filter models.EventFilter, window uint64) (WebSocketEventSource, error)
+	SubscribeToTcpEventsWithWebSocket(filter models.EventFilter, window uint64) (TcpWebSocketEventSource, error)
 }
 
 func NewClient(url string, skipTLSVerification bool) Client {
+	return newClient(url, skipTLSVerification, false)
+}
+
+// NewClientWithProtobuf returns a client that requests the route, tcp route
+// mapping and router group lists and the events as protobuf instead of JSON.
+func NewClientWithProtobuf(url string, skipTLSVerification bool) Client {
+	return newClient(url, skipTLSVerification, true)
+}
+
+func newClient(url string, skipTLSVerification bool, protobuf bool) Client {
+	// The transports ask for gzip compressed responses and decompress them
+	// transparently, including the event streams.
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: skipTLSVerification,
 	}
@@ -67,7 +103,13 @@ func NewClient(url string, skipTLSVerification bool) Client {
 
 		tokenMutex: &sync.RWMutex{},
 
-		reqGen: rata.NewRequestGenerator(url, Routes()),
+		routesCache:           &listCache{},
+		tcpRouteMappingsCache: &listCache{},
+
+		protobuf: protobuf,
+
+		tlsConfig: tlsConfig,
+		reqGen:    rata.NewRequestGenerator(url, Routes()),
 	}
 }
 
@@ -78,22 +120,89 @@ type client struct {
 	tokenMutex *sync.RWMutex
 	authToken  string
 
-	reqGen *rata.RequestGenerator
+	routesCache           *listCache
+	tcpRouteMappingsCache *listCache
+
+	protobuf bool
+
+	// ctx is the trace context the requests propagate
+	ctx context.Context
+
+	tlsConfig *tls.Config
+	reqGen    *rata.RequestGenerator
+}
+
+// WithContext returns a client sharing the token and caches of c whose
+// requests continue the trace of ctx: they have the W3C traceparent header of
+// the span of ctx, or of its remote parent.
+func (c *client) WithContext(ctx context.Context) Client {
+	traced := *c
+	traced.ctx = ctx
+	return &traced
+}
+
+// setTraceParent propagates the trace context of the client in header.
+func (c *client) setTraceParent(header http.Header) {
+	if c.ctx != nil {
+		tracing.Inject(c.ctx, header)
+	}
+}
+
+// listCache holds the last response of a list endpoint together with its
+// ETag, which is the revision of the listed table.
+type listCache struct {
+	mutex       sync.Mutex
+	etag        string
+	contentType string
+	body        []byte
+}
+
+func (l *listCache) get() (string, string, []byte) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.etag, l.contentType, l.body
+}
+
+func (l *listCache) set(etag string, contentType string, body []byte) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.etag = etag
+	l.contentType = contentType
+	l.body = body
 }
 
 func (c *client) SetToken(token string) {
 	c.tokenMutex.Lock()
 	defer c.tokenMutex.Unlock()
 	c.authToken = token
+
+	// The lists are filtered by the scopes of the token.
+	c.routesCache.set("", "", nil)
+	c.tcpRouteMappingsCache.set("", "", nil)
 }
 
 func (c *client) UpsertRoutes(routes []models.Route) error {
 	return c.doRequest(UpsertRoute, nil, nil, routes, nil)
 }
 
+// UpsertRoutesIfMatch updates the routes only if they are still at the given
+// modification tag. On a mismatch the returned Error is a
+// PreconditionFailedError carrying the current tag.
+func (c *client) UpsertRoutesIfMatch(routes []models.Route, tag models.ModificationTag) error {
+	return c.doConditionalRequest(UpsertRoute, tag, routes)
+}
+
+// UpsertRoutesDryRun validates the routes against the server without saving
+// them and returns the changes registering them would make.
+func (c *client) UpsertRoutesDryRun(routes []models.Route) ([]models.RouteChange, error) {
+	var changes []models.RouteChange
+	err := c.doRequest(UpsertRoute, nil, dryRunQuery(), routes, &changes)
+	return changes, err
+}
+
 func (c *client) Routes() ([]models.Route, error) {
 	var routes []models.Route
-	err := c.doRequest(ListRoute, nil, nil, nil, &routes)
+	err := c.doCachedRequest(ListRoute, c.routesCache, &routes)
 	return routes, err
 }
 
@@ -101,9 +210,23 @@ func (c *client) UpdateRouterGroup(group models.RouterGroup) error {
 	return c.doRequest(UpdateRouterGroup, rata.Params{"guid": group.Guid}, nil, group, nil)
 }
 
+// UpdateRouterGroupDryRun validates the update without saving it and returns
+// the change it would make, including the TCP route mappings left outside the
+// new reservable ports.
+func (c *client) UpdateRouterGroupDryRun(group models.RouterGroup) (models.RouterGroupChange, error) {
+	var change models.RouterGroupChange
+	err := c.doRequest(UpdateRouterGroup, rata.Params{"guid": group.Guid}, dryRunQuery(), group, &change)
+	return change, err
+}
+
 func (c *client) RouterGroups() ([]models.RouterGroup, error) {
 	var routerGroups []models.RouterGroup
-	err := c.doRequest(ListRouterGroups, nil, nil, nil, &routerGroups)
+	req, err := c.createRequest(ListRouterGroups, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setAccept(req)
+	err = c.do(req, &routerGroups)
 	return routerGroups, err
 }
 
@@ -111,13 +234,66 @@ func (c *client) DeleteRoutes(routes []models.Route) error {
 	return c.doRequest(DeleteRoute, nil, nil, routes, nil)
 }
 
+func (c *client) DeleteRoutesIfMatch(routes []models.Route, tag models.ModificationTag) error {
+	return c.doConditionalRequest(DeleteRoute, tag, routes)
+}
+
+// DeleteRoutesDryRun returns the changes deleting the routes would make
+// without deleting them.
+func (c *client) DeleteRoutesDryRun(routes []models.Route) ([]models.RouteChange, error) {
+	var changes []models.RouteChange
+	err := c.doRequest(DeleteRoute, nil, dryRunQuery(), routes, &changes)
+	return changes, err
+}
+
+// DrainRoutes marks the routes as draining. Routers stop sending new requests
+// to them and the routes are removed once the drain duration has passed.
+func (c *client) DrainRoutes(routes []models.Route, drain time.Duration) error {
+	return c.doRequest(DeleteRoute, nil, drainQuery(drain), routes, nil)
+}
+
+// DeleteRoutesBySelector deletes every route matched by the selector and
+// returns the deleted routes. With dryRun nothing is deleted and the routes
+// that would be deleted are returned.
+func (c *client) DeleteRoutesBySelector(selector models.RouteSelector, dryRun bool) ([]models.Route, error) {
+	query := url.Values{}
+	if selector.LogGuid != "" {
+		query.Set("log_guid", selector.LogGuid)
+	}
+	if selector.IP != "" {
+		query.Set("ip", selector.IP)
+	}
+	if selector.Owner != "" {
+		query.Set("owner", selector.Owner)
+	}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+
+	var routes []models.Route
+	err := c.doRequest(DeleteRoutesBySelector, nil, query, nil, &routes)
+	return routes, err
+}
+
 func (c *client) UpsertTcpRouteMappings(tcpRouteMappings []models.TcpRouteMapping) error {
 	return c.doRequest(UpsertTcpRouteMapping, nil, nil, tcpRouteMappings, nil)
 }
 
+func (c *client) UpsertTcpRouteMappingsIfMatch(tcpRouteMappings []models.TcpRouteMapping, tag models.ModificationTag) error {
+	return c.doConditionalRequest(UpsertTcpRouteMapping, tag, tcpRouteMappings)
+}
+
+// UpsertTcpRouteMappingsDryRun validates the mappings against the server
+// without saving them, see UpsertRoutesDryRun.
+func (c *client) UpsertTcpRouteMappingsDryRun(tcpRouteMappings []models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error) {
+	var changes []models.TcpRouteMappingChange
+	err := c.doRequest(UpsertTcpRouteMapping, nil, dryRunQuery(), tcpRouteMappings, &changes)
+	return changes, err
+}
+
 func (c *client) TcpRouteMappings() ([]models.TcpRouteMapping, error) {
 	var tcpRouteMappings []models.TcpRouteMapping
-	err := c.doRequest(ListTcpRouteMapping, nil, nil, nil, &tcpRouteMappings)
+	err := c.doCachedRequest(ListTcpRouteMapping, c.tcpRouteMappingsCache, &tcpRouteMappings)
 	return tcpRouteMappings, err
 }
 
@@ -125,6 +301,114 @@ func (c *client) DeleteTcpRouteMappings(tcpRouteMappings []models.TcpRouteMappin
 	return c.doRequest(DeleteTcpRouteMapping, nil, nil, tcpRouteMappings, nil)
 }
 
+func (c *client) DeleteTcpRouteMappingsIfMatch(tcpRouteMappings []models.TcpRouteMapping, tag models.ModificationTag) error {
+	return c.doConditionalRequest(DeleteTcpRouteMapping, tag, tcpRouteMappings)
+}
+
+// DeleteTcpRouteMappingsDryRun returns the changes deleting the mappings
+// would make without deleting them.
+func (c *client) DeleteTcpRouteMappingsDryRun(tcpRouteMappings []models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error) {
+	var changes []models.TcpRouteMappingChange
+	err := c.doRequest(DeleteTcpRouteMapping, nil, dryRunQuery(), tcpRouteMappings, &changes)
+	return changes, err
+}
+
+// DrainTcpRouteMappings marks the mappings as draining. Routers stop accepting
+// new connections for them and the mappings are removed once the drain
+// duration has passed.
+func (c *client) DrainTcpRouteMappings(tcpRouteMappings []models.TcpRouteMapping, drain time.Duration) error {
+	return c.doRequest(DeleteTcpRouteMapping, nil, drainQuery(drain), tcpRouteMappings, nil)
+}
+
+// drainQuery encodes a drain duration in whole seconds, rounded up.
+func drainQuery(drain time.Duration) url.Values {
+	seconds := int((drain + time.Second - 1) / time.Second)
+	return url.Values{"drain": []string{strconv.Itoa(seconds)}}
+}
+
+func dryRunQuery() url.Values {
+	return url.Values{"dry_run": []string{"true"}}
+}
+
+// DeleteTcpRouteMappingsBySelector deletes every mapping matched by the
+// selector, see DeleteRoutesBySelector.
+func (c *client) DeleteTcpRouteMappingsBySelector(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error) {
+	query := url.Values{}
+	if selector.RouterGroupGuid != "" {
+		query.Set("router_group_guid", selector.RouterGroupGuid)
+	}
+	if selector.HostIP != "" {
+		query.Set("backend_ip", selector.HostIP)
+	}
+	if selector.Owner != "" {
+		query.Set("owner", selector.Owner)
+	}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+
+	var tcpRouteMappings []models.TcpRouteMapping
+	err := c.doRequest(DeleteTcpRouteMappingsBySelector, nil, query, nil, &tcpRouteMappings)
+	return tcpRouteMappings, err
+}
+
+func (c *client) AuditRecords(filter models.AuditFilter) ([]models.AuditRecord, error) {
+	query := url.Values{}
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.Actor != "" {
+		query.Set("actor", filter.Actor)
+	}
+	if filter.Key != "" {
+		query.Set("route", filter.Key)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var records []models.AuditRecord
+	err := c.doRequest(ListAuditRecords, nil, query, nil, &records)
+	return records, err
+}
+
+// RouteHistory returns the recorded versions of the route with the same url,
+// ip and port, most recent first.
+func (c *client) RouteHistory(route models.Route) ([]models.RouteVersion, error) {
+	query := url.Values{}
+	query.Set("route", route.Route)
+	query.Set("ip", route.IP)
+	query.Set("port", strconv.Itoa(int(route.Port)))
+
+	var versions []models.RouteVersion
+	err := c.doRequest(ListRouteHistory, nil, query, nil, &versions)
+	return versions, err
+}
+
+// TcpRouteMappingHistory returns the recorded versions of the tcp route
+// mapping with the same router group, port and backend, most recent first.
+func (c *client) TcpRouteMappingHistory(tcpMapping models.TcpRouteMapping) ([]models.RouteVersion, error) {
+	query := url.Values{}
+	query.Set("router_group_guid", tcpMapping.RouterGroupGuid)
+	query.Set("port", strconv.Itoa(int(tcpMapping.ExternalPort)))
+	query.Set("backend_ip", tcpMapping.HostIP)
+	query.Set("backend_port", strconv.Itoa(int(tcpMapping.HostPort)))
+
+	var versions []models.RouteVersion
+	err := c.doRequest(ListTcpRouteHistory, nil, query, nil, &versions)
+	return versions, err
+}
+
+// QuotaUsage returns the limit and current usage of every enabled quota.
+func (c *client) QuotaUsage() ([]models.QuotaUsage, error) {
+	var usages []models.QuotaUsage
+	err := c.doRequest(ListQuotas, nil, nil, nil, &usages)
+	return usages, err
+}
+
 func (c *client) SubscribeToEvents() (EventSource, error) {
 	eventSource, err := c.doSubscribe(EventStreamRoute, defaultMaxRetries)
 	if err != nil {
@@ -172,6 +456,9 @@ func (c *client) doSubscribe(routeName string, retries uint16) (RawEventSource,
 			if err != nil {
 				panic(err) // totally shouldn't happen
 			}
+			setRequestID(request)
+			c.setTraceParent(request.Header)
+			c.setAccept(request)
 
 			trace.DumpRequest(request)
 			return request
@@ -210,6 +497,8 @@ func (c *client) createRequest(requestName string, params rata.Params, queryPara
 	req.URL.RawQuery = queryParams.Encode()
 	req.ContentLength = int64(len(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(req)
+	c.setTraceParent(req.Header)
 	c.tokenMutex.RLock()
 	defer c.tokenMutex.RUnlock()
 	req.Header.Add("Authorization", "bearer "+c.authToken)
@@ -225,6 +514,65 @@ func (c *client) doRequest(requestName string, params rata.Params, queryParams u
 	return c.do(req, response)
 }
 
+func (c *client) doConditionalRequest(requestName string, tag models.ModificationTag, request interface{}) error {
+	req, err := c.createRequest(requestName, nil, nil, request)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("If-Match", models.FormatModificationTag(tag))
+	return c.do(req, nil)
+}
+
+// doCachedRequest sends the ETag of the cached response as If-None-Match and
+// decodes the cached response again if the server reports it is unchanged.
+func (c *client) doCachedRequest(requestName string, cache *listCache, response interface{}) error {
+	req, err := c.createRequest(requestName, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	c.setAccept(req)
+	etag, contentType, body := cache.get()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	trace.DumpRequest(req)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	trace.DumpResponse(res)
+
+	if res.StatusCode == http.StatusNotModified && etag != "" {
+		return decodeBody(contentType, body, response)
+	}
+
+	if res.StatusCode == http.StatusUnauthorized {
+		err := NewError(UnauthorizedError, "unauthorized")
+		err.RequestID = responseRequestID(res)
+		return err
+	}
+
+	if res.StatusCode > 299 {
+		return transformResponseError(res)
+	}
+
+	body, err = ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	contentType = res.Header.Get("Content-Type")
+	cache.set(res.Header.Get("ETag"), contentType, body)
+
+	return decodeBody(contentType, body, response)
+}
+
 func (c *client) do(req *http.Request, response interface{}) error {
 	trace.DumpRequest(req)
 
@@ -239,7 +587,9 @@ func (c *client) do(req *http.Request, response interface{}) error {
 	trace.DumpResponse(res)
 
 	if res.StatusCode == http.StatusUnauthorized {
-		return NewError(UnauthorizedError, "unauthorized")
+		err := NewError(UnauthorizedError, "unauthorized")
+		err.RequestID = responseRequestID(res)
+		return err
 	}
 
 	if res.StatusCode > 299 {
@@ -247,13 +597,67 @@ func (c *client) do(req *http.Request, response interface{}) error {
 	}
 
 	if response != nil {
+		if res.Header.Get("Content-Type") == protos.ContentType {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				return err
+			}
+			return protos.Unmarshal(body, response)
+		}
 		return json.NewDecoder(res.Body).Decode(response)
 	}
 
 	return nil
 }
 
+// setAccept asks for protobuf if the client was created with
+// NewClientWithProtobuf. The server answers with JSON otherwise.
+func (c *client) setAccept(req *http.Request) {
+	if c.protobuf {
+		req.Header.Set("Accept", protos.ContentType)
+	}
+}
+
+func decodeBody(contentType string, body []byte, response interface{}) error {
+	if contentType == protos.ContentType {
+		return protos.Unmarshal(body, response)
+	}
+	return json.Unmarshal(body, response)
+}
+
+// setRequestID sets a new id on req, which the server logs and reports in the
+// errors of the request.
+func setRequestID(req *http.Request) {
+	id, err := NewRequestID()
+	if err == nil {
+		req.Header.Set(VcapRequestIDHeader, id)
+	}
+}
+
+// responseRequestID returns the id the server echoed, or else the id the
+// request was sent with.
+func responseRequestID(res *http.Response) string {
+	if id := res.Header.Get(VcapRequestIDHeader); id != "" {
+		return id
+	}
+	if res.Request != nil {
+		return res.Request.Header.Get(VcapRequestIDHeader)
+	}
+	return ""
+}
+
+// transformResponseError decodes the Error in the body of a failed response,
+// including the Details of validation errors. The Error has the RequestID of
+// the failed request.
 func transformResponseError(res *http.Response) error {
+	errResponse := decodeResponseError(res)
+	if errResponse.RequestID == "" {
+		errResponse.RequestID = responseRequestID(res)
+	}
+	return errResponse
+}
+
+func decodeResponseError(res *http.Response) Error {
 	errResponse := Error{}
 	data, err := ioutil.ReadAll(res.Body)
 	if err != nil {
@@ -1,21 +1,29 @@
 package routing_api_test
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"time"
 
 	"code.cloudfoundry.org/routing-api"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	"code.cloudfoundry.org/routing-api/tracing"
 	trace "code.cloudfoundry.org/trace-logger"
 	"github.com/onsi/gomega/ghttp"
 	"github.com/vito/go-sse/sse"
+	"golang.org/x/net/websocket"
 )
 
 const (
@@ -33,6 +41,12 @@ var _ = Describe("Client", func() {
 		TCP_ROUTER_GROUPS_API_URL         = "/routing/v1/router_groups"
 		EVENTS_SSE_URL                    = "/routing/v1/events"
 		TCP_EVENTS_SSE_URL                = "/routing/v1/tcp_routes/events"
+		AUDIT_API_URL                     = "/routing/v1/audit"
+		ROUTE_HISTORY_API_URL             = "/routing/v1/routes/history"
+		TCP_ROUTE_HISTORY_API_URL         = "/routing/v1/tcp_routes/history"
+		QUOTAS_API_URL                    = "/routing/v1/quotas"
+		EVENTS_WEBSOCKET_URL              = "/routing/v1/events/ws"
+		TCP_EVENTS_WEBSOCKET_URL          = "/routing/v1/tcp_routes/events/ws"
 	)
 
 	var server *ghttp.Server
@@ -94,6 +108,70 @@ var _ = Describe("Client", func() {
 			})
 		})
 
+		Context("When the server returns a validation error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusBadRequest, `{
+						"name": "RouteInvalidError",
+						"message": "Each route request requires an IP",
+						"details": [
+							{"index": 1, "field": "ip", "code": "missing", "message": "Each route request requires an IP"},
+							{"index": 1, "field": "ttl", "code": "out_of_range", "message": "Max ttl is 120"}
+						]
+					}`),
+				)
+			})
+
+			It("decodes the details of the violations", func() {
+				Expect(err).To(HaveOccurred())
+				apiErr, ok := err.(routing_api.Error)
+				Expect(ok).To(BeTrue())
+				Expect(apiErr.Type).To(Equal(routing_api.RouteInvalidError))
+				Expect(apiErr.Details).To(Equal([]routing_api.ErrorDetail{
+					{Index: 1, Field: "ip", Code: routing_api.MissingFieldCode, Message: "Each route request requires an IP"},
+					{Index: 1, Field: "ttl", Code: routing_api.OutOfRangeCode, Message: "Max ttl is 120"},
+				}))
+			})
+		})
+
+		Context("When the server does not echo the request id", func() {
+			var requestID string
+
+			BeforeEach(func() {
+				server.AppendHandlers(
+					func(w http.ResponseWriter, req *http.Request) {
+						requestID = req.Header.Get("X-Vcap-Request-Id")
+						w.WriteHeader(http.StatusInternalServerError)
+						w.Write([]byte(`{"name":"DBCommunicationError","message":"stuff broke"}`))
+					},
+				)
+			})
+
+			It("sends a request id and exposes it on the error", func() {
+				Expect(requestID).NotTo(BeEmpty())
+				Expect(err).To(HaveOccurred())
+				apiErr, ok := err.(routing_api.Error)
+				Expect(ok).To(BeTrue())
+				Expect(apiErr.RequestID).To(Equal(requestID))
+			})
+		})
+
+		Context("When the server reports the request id of an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusInternalServerError,
+						`{"name":"DBCommunicationError","message":"stuff broke","request_id":"server-request-id"}`,
+						http.Header{"X-Vcap-Request-Id": []string{"server-request-id"}}),
+				)
+			})
+
+			It("exposes the request id of the server on the error", func() {
+				apiErr, ok := err.(routing_api.Error)
+				Expect(ok).To(BeTrue())
+				Expect(apiErr.RequestID).To(Equal("server-request-id"))
+			})
+		})
+
 		Context("When the server returns an error", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
@@ -429,6 +507,385 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("DrainRoutes", func() {
+		var err error
+		JustBeforeEach(func() {
+			err = client.DrainRoutes([]models.Route{route1, route2}, 30*time.Second)
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", ROUTES_API_URL, "drain=30"),
+						ghttp.VerifyJSONRepresenting([]models.Route{route1, route2}),
+						ghttp.RespondWith(http.StatusNoContent, nil),
+					),
+				)
+			})
+
+			It("sends the drain duration in seconds to the server", func() {
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", ROUTES_API_URL, "drain=30"),
+						ghttp.RespondWith(http.StatusBadRequest, nil),
+					),
+				)
+			})
+
+			It("receives an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("UpsertRoutesDryRun", func() {
+		var (
+			err     error
+			changes []models.RouteChange
+		)
+
+		JustBeforeEach(func() {
+			changes, err = client.UpsertRoutesDryRun([]models.Route{route1})
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				data, _ := json.Marshal([]models.RouteChange{{Action: models.ChangeCreate, After: &route1}})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", ROUTES_API_URL, "dry_run=true"),
+						ghttp.VerifyJSONRepresenting([]models.Route{route1}),
+						ghttp.RespondWith(http.StatusOK, data),
+					),
+				)
+			})
+
+			It("returns the changes", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(changes).To(HaveLen(1))
+				Expect(changes[0].Action).To(Equal(models.ChangeCreate))
+			})
+		})
+
+		Context("when the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", ROUTES_API_URL, "dry_run=true"),
+						ghttp.RespondWith(http.StatusBadRequest, nil),
+					),
+				)
+			})
+
+			It("receives an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(changes).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("DeleteRoutesDryRun", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", ROUTES_API_URL, "dry_run=true"),
+					ghttp.VerifyJSONRepresenting([]models.Route{route1}),
+					ghttp.RespondWith(http.StatusOK, `[{"action":"none"}]`),
+				),
+			)
+		})
+
+		It("returns the changes", func() {
+			changes, err := client.DeleteRoutesDryRun([]models.Route{route1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(Equal([]models.RouteChange{{Action: models.ChangeNone}}))
+		})
+	})
+
+	Context("UpsertTcpRouteMappingsDryRun", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", TCP_CREATE_ROUTE_MAPPINGS_API_URL, "dry_run=true"),
+					ghttp.RespondWith(http.StatusOK, `[{"action":"create"}]`),
+				),
+			)
+		})
+
+		It("returns the changes", func() {
+			tcpMapping := models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+			changes, err := client.UpsertTcpRouteMappingsDryRun([]models.TcpRouteMapping{tcpMapping})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(Equal([]models.TcpRouteMappingChange{{Action: models.ChangeCreate}}))
+		})
+	})
+
+	Context("DeleteTcpRouteMappingsDryRun", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", TCP_DELETE_ROUTE_MAPPINGS_API_URL, "dry_run=true"),
+					ghttp.RespondWith(http.StatusOK, `[{"action":"delete"}]`),
+				),
+			)
+		})
+
+		It("returns the changes", func() {
+			tcpMapping := models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+			changes, err := client.DeleteTcpRouteMappingsDryRun([]models.TcpRouteMapping{tcpMapping})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(Equal([]models.TcpRouteMappingChange{{Action: models.ChangeDelete}}))
+		})
+	})
+
+	Context("UpdateRouterGroupDryRun", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", TCP_ROUTER_GROUPS_API_URL+"/router-group-guid", "dry_run=true"),
+					ghttp.RespondWith(http.StatusOK, `{"action":"update","out_of_range_tcp_routes":[{"router_group_guid":"router-group-guid","port":9000}]}`),
+				),
+			)
+		})
+
+		It("returns the change", func() {
+			change, err := client.UpdateRouterGroupDryRun(models.RouterGroup{Guid: "router-group-guid", ReservablePorts: "8000"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(change.Action).To(Equal(models.ChangeUpdate))
+			Expect(change.OutOfRangeTcpRoutes).To(HaveLen(1))
+			Expect(change.OutOfRangeTcpRoutes[0].ExternalPort).To(Equal(uint16(9000)))
+		})
+	})
+
+	Context("DrainTcpRouteMappings", func() {
+		var (
+			err             error
+			tcpRouteMapping models.TcpRouteMapping
+		)
+		BeforeEach(func() {
+			tcpRouteMapping = models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+		})
+		JustBeforeEach(func() {
+			err = client.DrainTcpRouteMappings([]models.TcpRouteMapping{tcpRouteMapping}, 1500*time.Millisecond)
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", TCP_DELETE_ROUTE_MAPPINGS_API_URL, "drain=2"),
+						ghttp.VerifyJSONRepresenting([]models.TcpRouteMapping{tcpRouteMapping}),
+						ghttp.RespondWith(http.StatusNoContent, nil),
+					),
+				)
+			})
+
+			It("rounds the drain duration up to whole seconds", func() {
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Context("DeleteRoutesBySelector", func() {
+		var (
+			err    error
+			routes []models.Route
+		)
+		JustBeforeEach(func() {
+			routes, err = client.DeleteRoutesBySelector(models.RouteSelector{LogGuid: "my-app", Owner: "cf"}, true)
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				data, _ := json.Marshal([]models.Route{route1})
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", "/routing/v1/routes/selector", "dry_run=true&log_guid=my-app&owner=cf"),
+						ghttp.RespondWith(http.StatusOK, data),
+					),
+				)
+			})
+
+			It("returns the selected routes", func() {
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(routes).To(Equal([]models.Route{route1}))
+			})
+		})
+
+		Context("when the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", "/routing/v1/routes/selector"),
+						ghttp.RespondWith(http.StatusBadRequest, nil),
+					),
+				)
+			})
+
+			It("receives an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("DeleteTcpRouteMappingsBySelector", func() {
+		var (
+			err             error
+			tcpRouteMapping models.TcpRouteMapping
+			tcpMappings     []models.TcpRouteMapping
+		)
+		BeforeEach(func() {
+			tcpRouteMapping = models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+			data, _ := json.Marshal([]models.TcpRouteMapping{tcpRouteMapping})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/routing/v1/tcp_routes/selector", "backend_ip=1.2.3.4&router_group_guid=router-group-guid-001"),
+					ghttp.RespondWith(http.StatusOK, data),
+				),
+			)
+		})
+		JustBeforeEach(func() {
+			selector := models.TcpRouteMappingSelector{RouterGroupGuid: "router-group-guid-001", HostIP: "1.2.3.4"}
+			tcpMappings, err = client.DeleteTcpRouteMappingsBySelector(selector, false)
+		})
+
+		It("returns the deleted mappings", func() {
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tcpMappings).To(Equal([]models.TcpRouteMapping{tcpRouteMapping}))
+		})
+	})
+
+	Context("UpsertRoutesIfMatch", func() {
+		var (
+			err error
+			tag models.ModificationTag
+		)
+		BeforeEach(func() {
+			tag = models.ModificationTag{Guid: "some-guid", Index: 3}
+		})
+		JustBeforeEach(func() {
+			err = client.UpsertRoutesIfMatch([]models.Route{route1}, tag)
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", ROUTES_API_URL),
+						ghttp.VerifyHeaderKV("If-Match", `"some-guid:3"`),
+						ghttp.VerifyJSONRepresenting([]models.Route{route1}),
+					),
+				)
+			})
+
+			It("sends the modification tag in the If-Match header", func() {
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the modification tag does not match", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", ROUTES_API_URL),
+						ghttp.RespondWith(http.StatusPreconditionFailed, `{
+							"name": "PreconditionFailedError",
+							"message": "Modification tag mismatch",
+							"modification_tag": {"guid": "some-guid", "index": 4}
+						}`),
+					),
+				)
+			})
+
+			It("returns the current modification tag", func() {
+				Expect(err).To(HaveOccurred())
+				apiErr, ok := err.(routing_api.Error)
+				Expect(ok).To(BeTrue())
+				Expect(apiErr.Type).To(Equal(routing_api.PreconditionFailedError))
+				Expect(apiErr.ModificationTag).To(Equal(&models.ModificationTag{Guid: "some-guid", Index: 4}))
+			})
+		})
+	})
+
+	Context("DeleteRoutesIfMatch", func() {
+		var err error
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", ROUTES_API_URL),
+					ghttp.VerifyHeaderKV("If-Match", `"some-guid:3"`),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+		})
+		JustBeforeEach(func() {
+			err = client.DeleteRoutesIfMatch([]models.Route{route1}, models.ModificationTag{Guid: "some-guid", Index: 3})
+		})
+
+		It("sends the modification tag in the If-Match header", func() {
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("UpsertTcpRouteMappingsIfMatch", func() {
+		var err error
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", TCP_CREATE_ROUTE_MAPPINGS_API_URL),
+					ghttp.VerifyHeaderKV("If-Match", `"some-guid:3"`),
+					ghttp.RespondWith(http.StatusCreated, nil),
+				),
+			)
+		})
+		JustBeforeEach(func() {
+			tcpRouteMapping := models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+			err = client.UpsertTcpRouteMappingsIfMatch([]models.TcpRouteMapping{tcpRouteMapping}, models.ModificationTag{Guid: "some-guid", Index: 3})
+		})
+
+		It("sends the modification tag in the If-Match header", func() {
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("DeleteTcpRouteMappingsIfMatch", func() {
+		var err error
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", TCP_DELETE_ROUTE_MAPPINGS_API_URL),
+					ghttp.VerifyHeaderKV("If-Match", `"some-guid:3"`),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+		})
+		JustBeforeEach(func() {
+			tcpRouteMapping := models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+			err = client.DeleteTcpRouteMappingsIfMatch([]models.TcpRouteMapping{tcpRouteMapping}, models.ModificationTag{Guid: "some-guid", Index: 3})
+		})
+
+		It("sends the modification tag in the If-Match header", func() {
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("Routes", func() {
 		var routes []models.Route
 		var err error
@@ -452,38 +909,104 @@ var _ = Describe("Client", func() {
 				Expect(server.ReceivedRequests()).Should(HaveLen(1))
 			})
 
-			It("gets a list of routes from the server", func() {
+			It("gets a list of routes from the server", func() {
+				routes, err = client.Routes()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(routes).To(Equal([]models.Route{route1, route2}))
+			})
+
+			It("does not send a body in the request", func() {
+				server.SetHandler(0,
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", ROUTES_API_URL),
+						ghttp.VerifyBody([]byte{}),
+						ghttp.RespondWith(http.StatusOK, data),
+					),
+				)
+				routes, err = client.Routes()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("logs the request and response", func() {
+				routes, err = client.Routes()
+				Expect(err).NotTo(HaveOccurred())
+				expectedBody, _ := json.Marshal([]models.Route{route1, route2})
+
+				r, err := ioutil.ReadAll(stdout)
+				log := string(r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(log).To(ContainSubstring("REQUEST: "))
+				Expect(log).To(ContainSubstring("GET " + ROUTES_API_URL + " HTTP/1.1"))
+
+				Expect(log).To(ContainSubstring("RESPONSE: "))
+				Expect(log).To(ContainSubstring("HTTP/1.1 200 OK"))
+				Expect(log).To(ContainSubstring(string(expectedBody)))
+			})
+		})
+
+		Context("when the server returns an ETag", func() {
+			BeforeEach(func() {
+				data, _ = json.Marshal([]models.Route{route1, route2})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", ROUTES_API_URL),
+						ghttp.RespondWith(http.StatusOK, data, http.Header{"ETag": []string{`"42"`}}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", ROUTES_API_URL),
+						ghttp.VerifyHeaderKV("If-None-Match", `"42"`),
+						ghttp.RespondWith(http.StatusNotModified, nil),
+					),
+				)
+			})
+
+			It("returns the cached routes when they have not changed", func() {
+				routes, err = client.Routes()
+				Expect(err).NotTo(HaveOccurred())
+
+				routes, err = client.Routes()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).Should(HaveLen(2))
+				Expect(routes).To(Equal([]models.Route{route1, route2}))
+			})
+
+			It("decodes compressed responses", func() {
+				compressed := &bytes.Buffer{}
+				gz := gzip.NewWriter(compressed)
+				_, err := gz.Write(data)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gz.Close()).To(Succeed())
+
+				server.SetHandler(0,
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", ROUTES_API_URL),
+						ghttp.VerifyHeaderKV("Accept-Encoding", "gzip"),
+						ghttp.RespondWith(http.StatusOK, compressed.Bytes(), http.Header{"Content-Encoding": []string{"gzip"}}),
+					),
+				)
+
 				routes, err = client.Routes()
 				Expect(err).NotTo(HaveOccurred())
 				Expect(routes).To(Equal([]models.Route{route1, route2}))
 			})
 
-			It("does not send a body in the request", func() {
-				server.SetHandler(0,
+			It("does not send the ETag after the token changed", func() {
+				server.SetHandler(1,
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", ROUTES_API_URL),
-						ghttp.VerifyBody([]byte{}),
+						ghttp.VerifyHeaderKV("If-None-Match"),
 						ghttp.RespondWith(http.StatusOK, data),
 					),
 				)
-				routes, err = client.Routes()
-				Expect(err).NotTo(HaveOccurred())
-			})
 
-			It("logs the request and response", func() {
 				routes, err = client.Routes()
 				Expect(err).NotTo(HaveOccurred())
-				expectedBody, _ := json.Marshal([]models.Route{route1, route2})
 
-				r, err := ioutil.ReadAll(stdout)
-				log := string(r)
+				client.SetToken("another-token")
+				routes, err = client.Routes()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(log).To(ContainSubstring("REQUEST: "))
-				Expect(log).To(ContainSubstring("GET " + ROUTES_API_URL + " HTTP/1.1"))
-
-				Expect(log).To(ContainSubstring("RESPONSE: "))
-				Expect(log).To(ContainSubstring("HTTP/1.1 200 OK"))
-				Expect(log).To(ContainSubstring(string(expectedBody)))
+				Expect(routes).To(Equal([]models.Route{route1, route2}))
 			})
 		})
 
@@ -622,6 +1145,195 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("AuditRecords", func() {
+		var (
+			err     error
+			records []models.AuditRecord
+			record  models.AuditRecord
+		)
+
+		BeforeEach(func() {
+			record = models.AuditRecord{
+				Time:   time.Unix(1000, 0).UTC(),
+				Action: models.AuditActionDelete,
+				Kind:   models.AuditKindHttpRoute,
+				Key:    "a.b.c",
+				Actor:  "admin",
+				Before: models.AuditValue(`{"route":"a.b.c"}`),
+			}
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				data, _ := json.Marshal([]models.AuditRecord{record})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", AUDIT_API_URL, "actor=admin&limit=5&route=a.b.c&since=1970-01-01T00%3A16%3A40Z"),
+						ghttp.RespondWith(http.StatusOK, data),
+					),
+				)
+			})
+
+			It("sends the filter as query parameters and returns the records", func() {
+				records, err = client.AuditRecords(models.AuditFilter{
+					Since: time.Unix(1000, 0).UTC(),
+					Actor: "admin",
+					Key:   "a.b.c",
+					Limit: 5,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(records).To(Equal([]models.AuditRecord{record}))
+			})
+		})
+
+		Context("When the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", AUDIT_API_URL),
+						ghttp.RespondWith(http.StatusBadRequest, nil),
+					),
+				)
+			})
+
+			It("returns an error", func() {
+				records, err = client.AuditRecords(models.AuditFilter{})
+				Expect(err).To(HaveOccurred())
+				Expect(records).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("RouteHistory", func() {
+		var (
+			err      error
+			versions []models.RouteVersion
+			version  models.RouteVersion
+		)
+
+		BeforeEach(func() {
+			ttl := 60
+			version = models.RouteVersion{
+				Kind:            models.HistoryKindHttpRoute,
+				Key:             "a.b.c,1.2.3.4:8080",
+				Time:            time.Unix(1000, 0).UTC(),
+				Action:          models.RouteVersionUpdated,
+				ModificationTag: models.ModificationTag{Guid: "abc", Index: 2},
+				TTL:             &ttl,
+			}
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				data, _ := json.Marshal([]models.RouteVersion{version})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", ROUTE_HISTORY_API_URL, "ip=1.2.3.4&port=8080&route=a.b.c"),
+						ghttp.RespondWith(http.StatusOK, data),
+					),
+				)
+			})
+
+			It("sends the route key as query parameters and returns the versions", func() {
+				versions, err = client.RouteHistory(models.NewRoute("a.b.c", 8080, "1.2.3.4", "", "", 0))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(versions).To(Equal([]models.RouteVersion{version}))
+			})
+		})
+
+		Context("When the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", ROUTE_HISTORY_API_URL),
+						ghttp.RespondWith(http.StatusBadRequest, nil),
+					),
+				)
+			})
+
+			It("returns an error", func() {
+				versions, err = client.RouteHistory(models.NewRoute("a.b.c", 8080, "1.2.3.4", "", "", 0))
+				Expect(err).To(HaveOccurred())
+				Expect(versions).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("TcpRouteMappingHistory", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TCP_ROUTE_HISTORY_API_URL, "backend_ip=1.2.3.4&backend_port=60000&port=52000&router_group_guid=rg-guid"),
+					ghttp.RespondWith(http.StatusOK, `[]`),
+				),
+			)
+		})
+
+		It("sends the mapping key as query parameters", func() {
+			versions, err := client.TcpRouteMappingHistory(models.NewTcpRouteMapping("rg-guid", 52000, "1.2.3.4", 60000, 0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(versions).To(BeEmpty())
+		})
+	})
+
+	Context("QuotaUsage", func() {
+		var (
+			err    error
+			usages []models.QuotaUsage
+			usage  models.QuotaUsage
+		)
+
+		BeforeEach(func() {
+			usage = models.QuotaUsage{
+				Quota: models.QuotaHttpRoutesPerOwner,
+				Limit: 10,
+				Usage: map[string]int{"cf": 3},
+			}
+		})
+
+		Context("when the server returns a valid response", func() {
+			BeforeEach(func() {
+				data, _ := json.Marshal([]models.QuotaUsage{usage})
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", QUOTAS_API_URL),
+						ghttp.RespondWith(http.StatusOK, data),
+					),
+				)
+			})
+
+			It("returns the usage of the quotas", func() {
+				usages, err = client.QuotaUsage()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).Should(HaveLen(1))
+				Expect(usages).To(Equal([]models.QuotaUsage{usage}))
+			})
+		})
+
+		Context("When the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", QUOTAS_API_URL),
+						ghttp.RespondWith(http.StatusInternalServerError, nil),
+					),
+				)
+			})
+
+			It("returns an error", func() {
+				usages, err = client.QuotaUsage()
+				Expect(err).To(HaveOccurred())
+				Expect(usages).To(BeEmpty())
+			})
+		})
+	})
+
 	Context("RouterGroups", func() {
 		var (
 			routerGroups []models.RouterGroup
@@ -776,6 +1488,109 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("NewClientWithProtobuf", func() {
+		protobufHeader := http.Header{"Content-Type": []string{protos.ContentType}}
+
+		BeforeEach(func() {
+			client = routing_api.NewClientWithProtobuf(server.URL(), false)
+		})
+
+		It("requests and decodes the routes as protobuf", func() {
+			data, err := protos.Marshal([]models.Route{route1, route2})
+			Expect(err).NotTo(HaveOccurred())
+			header := http.Header{
+				"Content-Type": []string{protos.ContentType},
+				"ETag":         []string{`"42"`},
+			}
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", ROUTES_API_URL),
+					ghttp.VerifyHeaderKV("Accept", protos.ContentType),
+					ghttp.RespondWith(http.StatusOK, data, header),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", ROUTES_API_URL),
+					ghttp.VerifyHeaderKV("If-None-Match", `"42"`),
+					ghttp.RespondWith(http.StatusNotModified, nil),
+				),
+			)
+
+			routes, err := client.Routes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(Equal([]models.Route{route1, route2}))
+
+			routes, err = client.Routes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(Equal([]models.Route{route1, route2}))
+		})
+
+		It("requests and decodes the router groups as protobuf", func() {
+			routerGroup := models.RouterGroup{
+				Guid:            DefaultRouterGroupGuid,
+				Name:            DefaultRouterGroupName,
+				Type:            DefaultRouterGroupType,
+				ReservablePorts: "1024-65535",
+			}
+			data, err := protos.Marshal([]models.RouterGroup{routerGroup})
+			Expect(err).NotTo(HaveOccurred())
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TCP_ROUTER_GROUPS_API_URL),
+					ghttp.VerifyHeaderKV("Accept", protos.ContentType),
+					ghttp.RespondWith(http.StatusOK, data, protobufHeader),
+				),
+			)
+
+			routerGroups, err := client.RouterGroups()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routerGroups).To(Equal([]models.RouterGroup{routerGroup}))
+		})
+
+		It("falls back to JSON when the server does not support protobuf", func() {
+			tcpRoute := models.NewTcpRouteMapping("rguid1", 52000, "1.1.1.1", 60000, 60)
+			data, _ := json.Marshal([]models.TcpRouteMapping{tcpRoute})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TCP_ROUTES_API_URL),
+					ghttp.VerifyHeaderKV("Accept", protos.ContentType),
+					ghttp.RespondWith(http.StatusOK, data),
+				),
+			)
+
+			tcpRoutes, err := client.TcpRouteMappings()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tcpRoutes).To(Equal([]models.TcpRouteMapping{tcpRoute}))
+		})
+
+		It("requests the events as protobuf", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", EVENTS_SSE_URL),
+					ghttp.VerifyHeaderKV("Accept", protos.ContentType),
+					func(w http.ResponseWriter, req *http.Request) {
+						defer GinkgoRecover()
+						data, err := protos.MarshalRouteEvent(route1, protos.EventMetadata{Revision: 9})
+						Expect(err).NotTo(HaveOccurred())
+						writeErr := sse.Event{
+							ID:   "1",
+							Name: "Upsert",
+							Data: []byte(base64.StdEncoding.EncodeToString(data)),
+						}.Write(w)
+						Expect(writeErr).ToNot(HaveOccurred())
+					},
+				),
+			)
+
+			eventSource, err := client.SubscribeToEvents()
+			Expect(err).NotTo(HaveOccurred())
+
+			ev, err := eventSource.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ev.Route).To(Equal(route1))
+			Expect(ev.Revision).To(Equal(uint64(9)))
+		})
+	})
+
 	Context("SubscribeToEvents", func() {
 		var eventSource routing_api.EventSource
 		var err error
@@ -1007,4 +1822,112 @@ var _ = Describe("Client", func() {
 			Expect(attemptChan).To(Receive())
 		})
 	})
+
+	Context("SubscribeToEventsWithWebSocket", func() {
+		var controls chan routing_api.WebSocketControl
+
+		BeforeEach(func() {
+			controls = make(chan routing_api.WebSocketControl, 2)
+			data, _ := json.Marshal(route1)
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", EVENTS_WEBSOCKET_URL, url.Values{
+						"filter": []string{`{"log_guid":"potato"}`},
+						"window": []string{"10"},
+					}.Encode()),
+					ghttp.VerifyHeader(http.Header{
+						"Authorization": []string{"bearer"},
+					}),
+					websocket.Handler(func(conn *websocket.Conn) {
+						defer GinkgoRecover()
+						err := websocket.JSON.Send(conn, routing_api.WebSocketEvent{ID: 1, Name: "Upsert", Data: string(data)})
+						Expect(err).NotTo(HaveOccurred())
+						for i := 0; i < 2; i++ {
+							var control routing_api.WebSocketControl
+							Expect(websocket.JSON.Receive(conn, &control)).To(Succeed())
+							controls <- control
+						}
+					}).ServeHTTP,
+				),
+			)
+		})
+
+		It("receives events and sends filters and acknowledgements", func() {
+			eventSource, err := client.SubscribeToEventsWithWebSocket(models.EventFilter{LogGuid: "potato"}, 10)
+			Expect(err).NotTo(HaveOccurred())
+			defer eventSource.Close()
+
+			event, err := eventSource.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Action).To(Equal("Upsert"))
+			Expect(event.Route).To(Equal(route1))
+
+			Expect(eventSource.Ack()).To(Succeed())
+			Expect(eventSource.SetFilter(models.EventFilter{Route: "a.b.c"})).To(Succeed())
+
+			Eventually(controls).Should(Receive(Equal(routing_api.WebSocketControl{Ack: 1})))
+			Eventually(controls).Should(Receive(Equal(routing_api.WebSocketControl{Filter: &models.EventFilter{Route: "a.b.c"}})))
+		})
+	})
+
+	Context("SubscribeToTcpEventsWithWebSocket", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TCP_EVENTS_WEBSOCKET_URL),
+					websocket.Handler(func(conn *websocket.Conn) {
+						_ = websocket.JSON.Send(conn, routing_api.WebSocketEvent{
+							ID:   1,
+							Name: "Delete",
+							Data: `{"router_group_guid":"rguid1","port":52000,"backend_ip":"1.1.1.1","backend_port":60000}`,
+						})
+					}).ServeHTTP,
+				),
+			)
+		})
+
+		It("receives tcp events", func() {
+			eventSource, err := client.SubscribeToTcpEventsWithWebSocket(models.EventFilter{}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			defer eventSource.Close()
+
+			event, err := eventSource.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Action).To(Equal("Delete"))
+			Expect(event.TcpRouteMapping.RouterGroupGuid).To(Equal("rguid1"))
+			Expect(event.TcpRouteMapping.ExternalPort).To(Equal(uint16(52000)))
+		})
+	})
+
+	Context("WithContext", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", ROUTES_API_URL),
+					ghttp.VerifyHeaderKV("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+					ghttp.RespondWith(http.StatusOK, "[]"),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", ROUTES_API_URL),
+					func(w http.ResponseWriter, req *http.Request) {
+						Expect(req.Header.Get("Traceparent")).To(BeEmpty())
+					},
+					ghttp.RespondWith(http.StatusOK, "[]"),
+				),
+			)
+		})
+
+		It("continues the trace of the context in its requests", func() {
+			header := http.Header{}
+			header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			ctx := tracing.Extract(context.Background(), header)
+
+			_, err := client.WithContext(ctx).Routes()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.Routes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
 })
@@ -0,0 +1,101 @@
+package routing_api
+
+import (
+	"code.cloudfoundry.org/routing-api/models"
+	"github.com/tedsuo/rata"
+)
+
+//go:generate counterfeiter -o fake_routing_api/fake_client_v2.go . ClientV2
+type ClientV2 interface {
+	SetToken(string)
+	Routes() ([]models.RouteV2, error)
+	CreateRoute(models.RouteV2) (models.RouteV2, error)
+	Route(guid string) (models.RouteV2, error)
+	UpdateRoute(models.RouteV2) (models.RouteV2, error)
+	DeleteRoute(guid string) error
+	TcpRouteMappings() ([]models.TcpRouteMappingV2, error)
+	CreateTcpRouteMapping(models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error)
+	TcpRouteMapping(guid string) (models.TcpRouteMappingV2, error)
+	UpdateTcpRouteMapping(models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error)
+	DeleteTcpRouteMapping(guid string) error
+}
+
+// NewClientV2 returns a client of the v2 API, which addresses routes and tcp
+// route mappings by their guid. The v2 API requires a SQL database.
+func NewClientV2(url string, skipTLSVerification bool) ClientV2 {
+	return &clientV2{client: newClient(url, skipTLSVerification, false).(*client)}
+}
+
+type clientV2 struct {
+	client *client
+}
+
+func (c *clientV2) SetToken(token string) {
+	c.client.SetToken(token)
+}
+
+func (c *clientV2) Routes() ([]models.RouteV2, error) {
+	var routes []models.RouteV2
+	err := c.client.doRequest(ListRoutesV2, nil, nil, nil, &routes)
+	return routes, err
+}
+
+// CreateRoute registers a route that does not exist yet. Registering an
+// existing route fails with a DBConflictError.
+func (c *clientV2) CreateRoute(route models.RouteV2) (models.RouteV2, error) {
+	var created models.RouteV2
+	err := c.client.doRequest(CreateRouteV2, nil, nil, route, &created)
+	return created, err
+}
+
+func (c *clientV2) Route(guid string) (models.RouteV2, error) {
+	var route models.RouteV2
+	err := c.client.doRequest(GetRouteV2, rata.Params{"guid": guid}, nil, nil, &route)
+	return route, err
+}
+
+// UpdateRoute updates the ttl and log guid of the route with the guid of
+// route. If route has a modification tag, the update fails with a
+// PreconditionFailedError unless the route is still at that tag.
+func (c *clientV2) UpdateRoute(route models.RouteV2) (models.RouteV2, error) {
+	var updated models.RouteV2
+	err := c.client.doRequest(UpdateRouteV2, rata.Params{"guid": route.Guid}, nil, route, &updated)
+	return updated, err
+}
+
+func (c *clientV2) DeleteRoute(guid string) error {
+	return c.client.doRequest(DeleteRouteV2, rata.Params{"guid": guid}, nil, nil, nil)
+}
+
+func (c *clientV2) TcpRouteMappings() ([]models.TcpRouteMappingV2, error) {
+	var tcpMappings []models.TcpRouteMappingV2
+	err := c.client.doRequest(ListTcpRouteMappingsV2, nil, nil, nil, &tcpMappings)
+	return tcpMappings, err
+}
+
+// CreateTcpRouteMapping registers a mapping that does not exist yet.
+// Registering an existing mapping fails with a DBConflictError.
+func (c *clientV2) CreateTcpRouteMapping(tcpMapping models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error) {
+	var created models.TcpRouteMappingV2
+	err := c.client.doRequest(CreateTcpRouteMappingV2, nil, nil, tcpMapping, &created)
+	return created, err
+}
+
+func (c *clientV2) TcpRouteMapping(guid string) (models.TcpRouteMappingV2, error) {
+	var tcpMapping models.TcpRouteMappingV2
+	err := c.client.doRequest(GetTcpRouteMappingV2, rata.Params{"guid": guid}, nil, nil, &tcpMapping)
+	return tcpMapping, err
+}
+
+// UpdateTcpRouteMapping updates the ttl of the mapping with the guid of
+// tcpMapping. If tcpMapping has a modification tag, the update fails with a
+// PreconditionFailedError unless the mapping is still at that tag.
+func (c *clientV2) UpdateTcpRouteMapping(tcpMapping models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error) {
+	var updated models.TcpRouteMappingV2
+	err := c.client.doRequest(UpdateTcpRouteMappingV2, rata.Params{"guid": tcpMapping.Guid}, nil, tcpMapping, &updated)
+	return updated, err
+}
+
+func (c *clientV2) DeleteTcpRouteMapping(guid string) error {
+	return c.client.doRequest(DeleteTcpRouteMappingV2, rata.Params{"guid": guid}, nil, nil, nil)
+}
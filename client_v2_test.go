@@ -0,0 +1,180 @@
+package routing_api_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/models"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("ClientV2", func() {
+	const (
+		ROUTES_V2_API_URL     = "/routing/v2/routes"
+		TCP_ROUTES_V2_API_URL = "/routing/v2/tcp_routes"
+	)
+
+	var (
+		server *ghttp.Server
+		client routing_api.ClientV2
+		route  models.RouteV2
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		client = routing_api.NewClientV2(server.URL(), false)
+		client.SetToken("some-token")
+
+		route = models.NewRouteV2(models.NewRoute("a.b.c", 33, "1.1.1.1", "potato", "", 55))
+		route.Guid = "route-guid"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("Routes", func() {
+		It("lists the routes", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", ROUTES_V2_API_URL),
+					ghttp.VerifyHeader(http.Header{"Authorization": []string{"bearer some-token"}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, []models.RouteV2{route}),
+				),
+			)
+
+			routes, err := client.Routes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(Equal([]models.RouteV2{route}))
+		})
+	})
+
+	Context("CreateRoute", func() {
+		It("posts the route and returns the created route", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", ROUTES_V2_API_URL),
+					ghttp.VerifyJSONRepresenting(route),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, route),
+				),
+			)
+
+			created, err := client.CreateRoute(route)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.Guid).To(Equal("route-guid"))
+		})
+
+		It("returns a DBConflictError when the route exists", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusConflict, routing_api.NewError(routing_api.DBConflictError, "exists")),
+			)
+
+			_, err := client.CreateRoute(route)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(routing_api.Error).Type).To(Equal(routing_api.DBConflictError))
+		})
+	})
+
+	Context("Route", func() {
+		It("gets the route with the guid", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", ROUTES_V2_API_URL+"/route-guid"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, route),
+				),
+			)
+
+			found, err := client.Route("route-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(Equal(route))
+		})
+
+		It("returns a ResourceNotFoundError when the route does not exist", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusNotFound, routing_api.NewError(routing_api.ResourceNotFoundError, "not found")),
+			)
+
+			_, err := client.Route("unknown")
+			Expect(err).To(HaveOccurred())
+			Expect(err.(routing_api.Error).Type).To(Equal(routing_api.ResourceNotFoundError))
+		})
+	})
+
+	Context("UpdateRoute", func() {
+		It("puts the route to its guid", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", ROUTES_V2_API_URL+"/route-guid"),
+					ghttp.VerifyJSONRepresenting(route),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, route),
+				),
+			)
+
+			_, err := client.UpdateRoute(route)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("DeleteRoute", func() {
+		It("deletes the route with the guid", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", ROUTES_V2_API_URL+"/route-guid"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+
+			Expect(client.DeleteRoute("route-guid")).To(Succeed())
+		})
+	})
+
+	Context("TcpRouteMappings", func() {
+		var tcpMapping models.TcpRouteMappingV2
+
+		BeforeEach(func() {
+			tcpMapping = models.NewTcpRouteMappingV2(models.NewTcpRouteMapping("rguid1", 52000, "1.2.3.4", 60000, 60))
+			tcpMapping.Guid = "mapping-guid"
+		})
+
+		It("lists the mappings", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TCP_ROUTES_V2_API_URL),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, []models.TcpRouteMappingV2{tcpMapping}),
+				),
+			)
+
+			tcpMappings, err := client.TcpRouteMappings()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tcpMappings).To(Equal([]models.TcpRouteMappingV2{tcpMapping}))
+		})
+
+		It("updates the mapping with the guid", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", TCP_ROUTES_V2_API_URL+"/mapping-guid"),
+					ghttp.VerifyJSONRepresenting(tcpMapping),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, tcpMapping),
+				),
+			)
+
+			updated, err := client.UpdateTcpRouteMapping(tcpMapping)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(Equal(tcpMapping))
+		})
+
+		It("deletes the mapping with the guid", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", TCP_ROUTES_V2_API_URL+"/mapping-guid"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+
+			Expect(client.DeleteTcpRouteMapping("mapping-guid")).To(Succeed())
+		})
+	})
+})
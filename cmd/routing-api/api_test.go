@@ -1,7 +1,9 @@
 package main_test
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/tedsuo/ifrit"
@@ -289,6 +291,16 @@ var _ = Describe("Routes API", func() {
 				Expect(routes).ToNot(ContainElement(matchers.MatchHttpRoute(route3)))
 			})
 
+			It("rejects bodies over the maximum body size with their request id", func() {
+				body := bytes.Repeat([]byte(" "), 10*1024*1024+1)
+				resp, err := http.Post(fmt.Sprintf("http://%s/routing/v1/routes", routingAPIAddress), "application/json", bytes.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Body.Close()).To(Succeed())
+
+				Expect(resp.StatusCode).To(Equal(http.StatusRequestEntityTooLarge))
+				Expect(resp.Header.Get("X-Vcap-Request-Id")).NotTo(BeEmpty())
+			})
+
 			Context("when a route has a context path", func() {
 				var routeWithPath models.Route
 
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -15,18 +17,28 @@ import (
 	"code.cloudfoundry.org/lager/lagerflags"
 	"code.cloudfoundry.org/locket"
 	"code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/audit"
 	"code.cloudfoundry.org/routing-api/config"
 	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/grpcapi"
 	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/health"
 	"code.cloudfoundry.org/routing-api/helpers"
+	"code.cloudfoundry.org/routing-api/history"
 	"code.cloudfoundry.org/routing-api/metrics"
 	"code.cloudfoundry.org/routing-api/migration"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/openapi"
+	"code.cloudfoundry.org/routing-api/quota"
+	"code.cloudfoundry.org/routing-api/tracing"
 	uaaclient "code.cloudfoundry.org/uaa-go-client"
 	uaaconfig "code.cloudfoundry.org/uaa-go-client/config"
 	"github.com/cactus/go-statsd-client/statsd"
 	"github.com/cloudfoundry/dropsonde"
 	"github.com/nu7hatch/gouuid"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"code.cloudfoundry.org/clock"
 	"github.com/tedsuo/ifrit"
@@ -38,10 +50,13 @@ import (
 )
 
 const (
-	DEFAULT_ETCD_WORKERS = 25
-	routingApiLockPath   = "routing_api_lock"
-	sessionName          = "routing_api"
-	pruningInterval      = 10 * time.Second
+	DEFAULT_ETCD_WORKERS   = 25
+	routingApiLockPath     = "routing_api_lock"
+	sessionName            = "routing_api"
+	pruningInterval        = 10 * time.Second
+	auditPruningInterval   = 1 * time.Hour
+	historyPruningInterval = 1 * time.Hour
+	uaaKeyPollingInterval  = 1 * time.Minute
 )
 
 var port = flag.Uint("port", 8080, "Port to run rounting-api server on")
@@ -102,7 +117,26 @@ func main() {
 		}
 	}()
 
-	apiServer := constructApiServer(cfg, database, statsdClient, logger.Session("api-server"))
+	var stats metrics.PartialStatsdClient = statsdClient
+	apiDatabase := database
+	var prometheusMetrics *metrics.PrometheusMetrics
+	if cfg.Prometheus.Enabled {
+		prometheusMetrics = metrics.NewPrometheusMetrics()
+		stats = prometheusMetrics.Statsd(statsdClient)
+		apiDatabase = db.NewInstrumentedDB(database, prometheusMetrics.ObserveDBOperation)
+	}
+
+	clock := clock.NewClock()
+	var tracer *tracing.Tracer
+	if cfg.Tracing.Enabled {
+		tracer = constructTracer(cfg.Tracing, logger.Session("tracing"))
+	}
+	auditor := constructAuditRecorder(cfg, database, clock, logger.Session("audit"))
+	quotas := quota.NewEnforcer(database, cfg.Quotas)
+	checker := health.NewChecker()
+	checker.Add("database", database.Ping)
+	uaaClient, uaaKeyPoller := constructUaaClient(cfg, checker, clock, logger.Session("api-server"))
+	apiServer, grpcServer := constructApiServer(cfg, uaaClient, apiDatabase, statsdClient, stats, prometheusMetrics, tracer, auditor, quotas, checker, logger.Session("api-server"))
 	stopper := constructStopper(database)
 
 	routerRegister := constructRouteRegister(
@@ -112,34 +146,83 @@ func main() {
 		database,
 		logger.Session("route-register"),
 	)
-	clock := clock.NewClock()
 
 	etcdDone := make(chan struct{})
 	releaseLock := make(chan os.Signal)
 	lockErrChan := make(chan error)
 	lockMaintainer := initializeLockMaintainer(logger, cfg.ConsulCluster.Servers, sessionName,
 		cfg.ConsulCluster.LockTTL, cfg.ConsulCluster.RetryInterval, clock)
+	lockHeld := health.NewCondition("consul lock is not held")
+	checker.Add("consul-lock", lockHeld.Check)
+	lockMaintainer = health.Track(lockMaintainer, lockHeld)
 	lockAcquirer := initializeLockAcquirer(lockMaintainer, releaseLock, lockErrChan)
 	lockReleaser := initializeLockReleaser(releaseLock, lockErrChan, cfg.ConsulCluster.RetryInterval)
 	metricsTicker := time.NewTicker(cfg.MetricsReportingInterval)
-	metricsReporter := metrics.NewMetricsReporter(database, statsdClient, metricsTicker, logger.Session("metrics"))
+	metricsReporter := metrics.NewMetricsReporter(database, quotas, stats, metricsTicker, logger.Session("metrics"))
 	migrationProcess := runMigration(cfg, database, &cfg.Etcd, etcdDone, logger.Session("migration"))
+	migrated := health.NewCondition("migration has not finished")
+	checker.Add("migration", migrated.Check)
+	migrationProcess = health.Track(migrationProcess, migrated)
 	routerGroupSeeder := seedRouterGroups(cfg, database, logger.Session("seeding"))
 
-	members := grouper.Members{
+	members := grouper.Members{}
+	if cfg.AdminAddress != "" {
+		// started first, so that it reports readiness during the migration
+		adminHandler := checker.AdminHandler(logger.Session("admin-server"))
+		if prometheusMetrics != nil && cfg.Prometheus.Address == "" {
+			adminHandler = withMetrics(adminHandler, prometheusMetrics, logger.Session("admin-server"))
+		}
+		adminServer := http_server.New(cfg.AdminAddress, adminHandler)
+		members = append(members, grouper.Member{Name: "admin-server", Runner: adminServer})
+	}
+	if prometheusMetrics != nil && cfg.Prometheus.Address != "" {
+		metricsServer := http_server.New(cfg.Prometheus.Address, withMetrics(http.NotFoundHandler(), prometheusMetrics, logger.Session("metrics-server")))
+		members = append(members, grouper.Member{Name: "metrics-server", Runner: metricsServer})
+	}
+
+	if tracer != nil {
+		// started before the api server, so that it exports its last spans
+		members = append(members, grouper.Member{Name: "tracer", Runner: tracer})
+	}
+
+	members = append(members,
 		grouper.Member{Name: "migration", Runner: migrationProcess},
 		grouper.Member{Name: "lock-acquirer", Runner: lockAcquirer},
 		grouper.Member{Name: "seed-router-groups", Runner: routerGroupSeeder},
 		grouper.Member{Name: "api-server", Runner: apiServer},
+	)
+
+	if grpcServer != nil {
+		members = append(members, grouper.Member{Name: "grpc-server", Runner: grpcServer})
+	}
+
+	members = append(members,
 		grouper.Member{Name: "conn-stopper", Runner: stopper},
 		grouper.Member{Name: "route-register", Runner: routerRegister},
 		grouper.Member{Name: "metrics", Runner: metricsReporter},
-	}
+		grouper.Member{Name: "uaa-key-poller", Runner: uaaKeyPoller},
+	)
 
 	if isSql(cfg.SqlDB) {
 		routePruner := runCleanupRoutes(database, logger)
 		members = append(members, grouper.Member{Name: "sql-route-pruner", Runner: routePruner})
 	}
+	if cfg.Audit.Enabled {
+		expiryRecorder := audit.NewExpiryRecorder(database, auditor, logger.Session("audit-expiry"))
+		auditPruner := audit.NewPruner(database, cfg.Audit.Retention, cfg.Audit.MaxRecords, auditPruningInterval, clock, logger.Session("audit-pruner"))
+		members = append(members,
+			grouper.Member{Name: "audit-expiry-recorder", Runner: expiryRecorder},
+			grouper.Member{Name: "audit-pruner", Runner: auditPruner},
+		)
+	}
+	if cfg.RouteHistory.Enabled {
+		historyRecorder := history.NewRecorder(database, cfg.RouteHistory.MaxVersions, clock, logger.Session("route-history"))
+		historyPruner := history.NewPruner(database, cfg.RouteHistory.Retention, historyPruningInterval, clock, logger.Session("route-history-pruner"))
+		members = append(members,
+			grouper.Member{Name: "route-history-recorder", Runner: historyRecorder},
+			grouper.Member{Name: "route-history-pruner", Runner: historyPruner},
+		)
+	}
 	members = append(members, grouper.Member{Name: "lock-releaser", Runner: lockReleaser})
 
 	group := grouper.NewOrdered(os.Interrupt, members)
@@ -157,6 +240,55 @@ func main() {
 	logger.Info("exited")
 }
 
+func constructTracer(cfg config.TracingConfig, logger lager.Logger) *tracing.Tracer {
+	var exporter tracing.Exporter
+	var err error
+	switch cfg.Exporter {
+	case "stdout":
+		var out io.Writer
+		out, err = spanOutput(cfg.Path)
+		if err == nil {
+			exporter, err = tracing.NewWriterExporter(out)
+		}
+	default:
+		exporter, err = tracing.NewOTLPExporter(cfg.Endpoint)
+	}
+	if err != nil {
+		logger.Error("failed-to-create-exporter", err)
+		os.Exit(1)
+	}
+	sampler, err := tracing.NewSampler(cfg.Sampler, cfg.SamplerRatio)
+	if err != nil {
+		logger.Error("failed-to-create-sampler", err)
+		os.Exit(1)
+	}
+
+	otel.SetTextMapPropagator(tracing.Propagator)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Error("failed-to-export-spans", err)
+	}))
+	logger.Info("exporting-spans", lager.Data{"exporter": cfg.Exporter, "endpoint": cfg.Endpoint, "path": cfg.Path, "sampler": cfg.Sampler})
+	return tracing.NewTracer(exporter, sampler, cfg.Interval, logger)
+}
+
+// spanOutput is where the stdout exporter writes its spans: stderr, so that
+// they do not interleave with the logs on stdout, or the file at path.
+func spanOutput(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stderr, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
+
+// withMetrics serves the Prometheus metrics on /metrics and every other path
+// with handler.
+func withMetrics(handler http.Handler, prometheusMetrics *metrics.PrometheusMetrics, logger lager.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.Handle(metrics.MetricsPath, prometheusMetrics.Handler(logger))
+	return mux
+}
+
 func isSql(sqlDB config.SqlDB) bool {
 	return (sqlDB.Host != "" && sqlDB.Port > 0 && sqlDB.Schema != "")
 }
@@ -241,37 +373,87 @@ func constructRouteRegister(
 	return helpers.NewRouteRegister(database, route, ticker, logger)
 }
 
-func constructApiServer(cfg config.Config, database db.DB, statsdClient statsd.Statter, logger lager.Logger) ifrit.Runner {
-
-	uaaClient, err := newUaaClient(logger, cfg)
-	if err != nil {
-		logger.Error("Failed to create uaa client", err)
-		os.Exit(1)
+func constructAuditRecorder(cfg config.Config, database db.DB, clock clock.Clock, logger lager.Logger) audit.Recorder {
+	if !cfg.Audit.Enabled {
+		return audit.NewNoopRecorder()
 	}
+	logger.Info("audit-enabled", lager.Data{"retention": cfg.Audit.Retention.String(), "max_records": cfg.Audit.MaxRecords})
+	return audit.NewRecorder(database, clock, logger)
+}
 
-	_, err = uaaClient.FetchKey()
+func constructApiServer(cfg config.Config, uaaClient uaaclient.Client, database db.DB, statsdClient statsd.Statter, stats metrics.PartialStatsdClient, prometheusMetrics *metrics.PrometheusMetrics, tracer *tracing.Tracer, auditor audit.Recorder, quotas quota.Enforcer, checker *health.Checker, logger lager.Logger) (ifrit.Runner, ifrit.Runner) {
+	validator := handlers.NewValidator()
+	routesHandler := handlers.NewRoutesHandler(uaaClient, cfg.TTLPolicies.Http.Policy(), validator, database, logger, auditor, quotas)
+	eventStreamHandler := handlers.NewEventStreamHandler(uaaClient, database, logger, stats)
+	routerGroupsHandler := handlers.NewRouteGroupsHandler(uaaClient, logger, database, auditor, cfg.TTLPolicies.Tcp.Policy())
+	tcpMappingsHandler := handlers.NewTcpRouteMappingsHandler(uaaClient, validator, database, cfg.TTLPolicies.Tcp.Policy(), logger, auditor, quotas)
+	auditHandler := handlers.NewAuditHandler(uaaClient, database, logger)
+	historyHandler := handlers.NewHistoryHandler(uaaClient, database, logger)
+	quotaHandler := handlers.NewQuotaHandler(uaaClient, quotas, logger)
+	openAPIHandler, err := openapi.NewHandler(logger)
 	if err != nil {
-		logger.Error("Failed to get verification key from UAA", err)
+		logger.Error("failed-to-generate-openapi-document", err)
 		os.Exit(1)
 	}
 
-	validator := handlers.NewValidator()
-	routesHandler := handlers.NewRoutesHandler(uaaClient, int(cfg.MaxTTL.Seconds()), validator, database, logger)
-	eventStreamHandler := handlers.NewEventStreamHandler(uaaClient, database, logger, statsdClient)
-	routerGroupsHandler := handlers.NewRouteGroupsHandler(uaaClient, logger, database)
-	tcpMappingsHandler := handlers.NewTcpRouteMappingsHandler(uaaClient, validator, database, int(cfg.MaxTTL.Seconds()), logger)
-
 	actions := rata.Handlers{
-		routing_api.UpsertRoute:           route(routesHandler.Upsert),
-		routing_api.DeleteRoute:           route(routesHandler.Delete),
-		routing_api.ListRoute:             route(routesHandler.List),
-		routing_api.EventStreamRoute:      route(eventStreamHandler.EventStream),
-		routing_api.ListRouterGroups:      route(routerGroupsHandler.ListRouterGroups),
-		routing_api.UpdateRouterGroup:     route(routerGroupsHandler.UpdateRouterGroup),
-		routing_api.UpsertTcpRouteMapping: route(tcpMappingsHandler.Upsert),
-		routing_api.DeleteTcpRouteMapping: route(tcpMappingsHandler.Delete),
-		routing_api.ListTcpRouteMapping:   route(tcpMappingsHandler.List),
-		routing_api.EventStreamTcpRoute:   route(eventStreamHandler.TcpEventStream),
+		routing_api.UpsertRoute:                      route(routesHandler.Upsert),
+		routing_api.DeleteRoute:                      route(routesHandler.Delete),
+		routing_api.DeleteRoutesBySelector:           route(routesHandler.DeleteBySelector),
+		routing_api.ListRoute:                        handlers.GzipWrap(route(routesHandler.List)),
+		routing_api.EventStreamRoute:                 handlers.GzipWrap(route(eventStreamHandler.EventStream)),
+		routing_api.ListRouterGroups:                 handlers.GzipWrap(route(routerGroupsHandler.ListRouterGroups)),
+		routing_api.UpdateRouterGroup:                route(routerGroupsHandler.UpdateRouterGroup),
+		routing_api.UpsertTcpRouteMapping:            route(tcpMappingsHandler.Upsert),
+		routing_api.DeleteTcpRouteMapping:            route(tcpMappingsHandler.Delete),
+		routing_api.DeleteTcpRouteMappingsBySelector: route(tcpMappingsHandler.DeleteBySelector),
+		routing_api.ListTcpRouteMapping:              handlers.GzipWrap(route(tcpMappingsHandler.List)),
+		routing_api.EventStreamTcpRoute:              handlers.GzipWrap(route(eventStreamHandler.TcpEventStream)),
+		routing_api.ListAuditRecords:                 route(auditHandler.List),
+		routing_api.ListRouteHistory:                 route(historyHandler.ListRouteHistory),
+		routing_api.ListTcpRouteHistory:              route(historyHandler.ListTcpRouteHistory),
+		routing_api.ListQuotas:                       route(quotaHandler.List),
+		routing_api.EventStreamWebSocketRoute:        route(eventStreamHandler.EventStreamWebSocket),
+		routing_api.EventStreamTcpWebSocketRoute:     route(eventStreamHandler.TcpEventStreamWebSocket),
+		routing_api.OpenAPIRoute:                     openAPIHandler,
+		routing_api.HealthRoute:                      health.LivenessHandler(logger),
+		routing_api.ReadyRoute:                       checker.ReadinessHandler(false, logger),
+
+		routing_api.ListRoutesV2:            route(routesHandler.ListV2),
+		routing_api.CreateRouteV2:           route(routesHandler.CreateV2),
+		routing_api.GetRouteV2:              route(routesHandler.GetV2),
+		routing_api.UpdateRouteV2:           route(routesHandler.UpdateV2),
+		routing_api.DeleteRouteV2:           route(routesHandler.DeleteV2),
+		routing_api.ListTcpRouteMappingsV2:  route(tcpMappingsHandler.ListV2),
+		routing_api.CreateTcpRouteMappingV2: route(tcpMappingsHandler.CreateV2),
+		routing_api.GetTcpRouteMappingV2:    route(tcpMappingsHandler.GetV2),
+		routing_api.UpdateTcpRouteMappingV2: route(tcpMappingsHandler.UpdateV2),
+		routing_api.DeleteTcpRouteMappingV2: route(tcpMappingsHandler.DeleteV2),
+	}
+
+	for name := range cfg.RequestLimits.Endpoints {
+		if _, ok := actions[name]; !ok {
+			logger.Error("unknown-request-limits-endpoint", fmt.Errorf("no endpoint is named %s", name))
+			os.Exit(1)
+		}
+	}
+	// the limits wrap the actions innermost, so that a request they reject is
+	// still logged, counted and traced with its request id by the wrappers
+	// below and by LogWrap around the router
+	for name, action := range actions {
+		limits := cfg.RequestLimits.Endpoint(name)
+		actions[name] = handlers.LimitWrap(action, handlers.RequestLimits{
+			MaxBodySize:    limits.MaxBodySize,
+			MaxBatchLength: limits.MaxBatchLength,
+			Strict:         cfg.RequestLimits.StrictDecoding,
+		}, logger)
+		if prometheusMetrics != nil {
+			actions[name] = prometheusMetrics.InstrumentHandler(name, actions[name])
+		}
+		actions[name] = handlers.MetricsWrap(actions[name], name, statsdClient, logger)
+		if tracer != nil {
+			actions[name] = handlers.TraceWrap(actions[name], name, tracer, logger)
+		}
 	}
 
 	handler, err := rata.NewRouter(routing_api.Routes(), actions)
@@ -280,8 +462,81 @@ func constructApiServer(cfg config.Config, database db.DB, statsdClient statsd.S
 		os.Exit(1)
 	}
 
+	handler = handlers.SourceIPWrap(handler, cfg.Audit.TrustedProxyNetworks)
 	handler = handlers.LogWrap(handler, logger)
-	return http_server.New(":"+strconv.Itoa(int(*port)), handler)
+	apiServer := http_server.New(":"+strconv.Itoa(int(*port)), handler)
+
+	if cfg.Grpc.Port == 0 {
+		return apiServer, nil
+	}
+	grpcServer := grpcapi.NewServer(database, validator, uaaClient, quotas, auditor, cfg.TTLPolicies.Http.Policy(), cfg.TTLPolicies.Tcp.Policy(), logger.Session("grpc"))
+	return apiServer, constructGrpcServer(cfg.Grpc, grpcServer, uaaClient, logger.Session("grpc"))
+}
+
+func constructGrpcServer(cfg config.GrpcConfig, grpcServer *grpcapi.Server, uaaClient uaaclient.Client, logger lager.Logger) ifrit.Runner {
+	creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		logger.Error("failed-to-load-certificate", err)
+		os.Exit(1)
+	}
+
+	server := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(grpcapi.UnaryTokenInterceptor(uaaClient, logger)),
+		grpc.ChainStreamInterceptor(grpcapi.StreamTokenInterceptor(uaaClient, logger)),
+	)
+	grpcapi.RegisterRoutingAPIServer(server, grpcServer)
+
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
+		if err != nil {
+			return err
+		}
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- server.Serve(listener)
+		}()
+
+		logger.Info("started", lager.Data{"port": cfg.Port})
+		close(ready)
+
+		select {
+		case <-signals:
+			// watches never finish, so the server cannot stop gracefully
+			server.Stop()
+			return nil
+		case err := <-errChan:
+			return err
+		}
+	})
+}
+
+// constructUaaClient creates the UAA client and fetches the verification key,
+// which the routing API needs to serve any request. The returned runner
+// fetches the key again every uaaKeyPollingInterval and fails the uaa
+// readiness check while UAA does not serve it.
+func constructUaaClient(cfg config.Config, checker *health.Checker, clock clock.Clock, logger lager.Logger) (uaaclient.Client, ifrit.Runner) {
+	uaaClient, err := newUaaClient(logger, cfg)
+	if err != nil {
+		logger.Error("Failed to create uaa client", err)
+		os.Exit(1)
+	}
+
+	uaaKeyLoaded := health.NewCondition("UAA verification key is not loaded")
+	checker.Add("uaa", uaaKeyLoaded.Check)
+	_, err = uaaClient.FetchKey()
+	if err != nil {
+		logger.Error("Failed to get verification key from UAA", err)
+		os.Exit(1)
+	}
+	uaaKeyLoaded.Set()
+
+	fetchKey := func() error {
+		_, err := uaaClient.FetchKey()
+		return err
+	}
+	return uaaClient, health.NewPoller(fetchKey, uaaKeyLoaded, uaaKeyPollingInterval, clock, logger.Session("uaa-key-poller"))
 }
 
 func newUaaClient(logger lager.Logger, routingApiConfig config.Config) (uaaclient.Client, error) {
@@ -122,6 +122,24 @@ var _ = Describe("Main", func() {
 			Eventually(routingAPIRunner.ExitCode()).Should(Equal(0))
 		})
 
+		It("serves its liveness and readiness", func() {
+			routingAPIRunner := testrunner.New(routingAPIBinPath, routingAPIArgs)
+			proc := ifrit.Invoke(routingAPIRunner)
+			defer ginkgomon.Interrupt(proc)
+
+			response, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/health", routingAPIPort))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Body.Close()).To(Succeed())
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			Eventually(func() int {
+				response, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ready", routingAPIPort))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Body.Close()).To(Succeed())
+				return response.StatusCode
+			}).Should(Equal(http.StatusOK))
+		})
+
 		It("closes open event streams when the process exits", func() {
 			routingAPIRunner := testrunner.New(routingAPIBinPath, routingAPIArgs)
 			proc := ifrit.Invoke(routingAPIRunner)
@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"net"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -32,6 +35,10 @@ type SqlDB struct {
 	Type     string `yaml:"type"`
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
+	// MaxEventsPerSubscriber is the number of events buffered for each
+	// subscriber to the changes of the routes. A subscriber that falls
+	// further behind loses events and is told to resync.
+	MaxEventsPerSubscriber int `yaml:"max_events_per_subscriber"`
 }
 
 type Etcd struct {
@@ -48,8 +55,140 @@ type ConsulCluster struct {
 	RetryInterval time.Duration `yaml:"retry_interval"`
 }
 
+type AuditConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Retention time.Duration `yaml:"retention"`
+	// MaxRecords is how many records are kept at most. The oldest records
+	// beyond it are deleted when the records are pruned.
+	MaxRecords int `yaml:"max_records"`
+	// TrustedProxies are the addresses or CIDR networks of the proxies whose
+	// X-Forwarded-For header is trusted to report the source IP of requests.
+	TrustedProxies       []string     `yaml:"trusted_proxies"`
+	TrustedProxyNetworks []*net.IPNet `yaml:"-"`
+}
+
+type RouteHistoryConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	MaxVersions int  `yaml:"max_versions"`
+	// Retention is how long versions are kept, so that the history of
+	// deleted and expired routes does not grow without bound.
+	Retention time.Duration `yaml:"retention"`
+}
+
+// QuotaConfig limits the number of routes per owner, the client id of the
+// token that registered them, and per log guid. A limit of 0 disables the
+// quota.
+type QuotaConfig struct {
+	MaxHttpRoutesPerOwner   int `yaml:"max_http_routes_per_owner"`
+	MaxHttpRoutesPerLogGuid int `yaml:"max_http_routes_per_log_guid"`
+	MaxTcpRoutesPerOwner    int `yaml:"max_tcp_routes_per_owner"`
+}
+
+// GrpcConfig enables the gRPC API on Port, served over TLS with the
+// certificate in CertFile and KeyFile. A port of 0 disables it.
+type GrpcConfig struct {
+	Port     uint16 `yaml:"port"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// PrometheusConfig serves the metrics in the Prometheus format on /metrics of
+// Address, or of the admin listener when Address is empty.
+type PrometheusConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+}
+
+// TracingConfig exports a span for every request and for the token decoding
+// and database calls it makes. Exporter is "otlp", to post the spans to the
+// OTLP/HTTP Endpoint of a collector, or "stdout", to write them as JSON to
+// stderr, away from the logs on stdout, or to the file at Path when it is set.
+// Sampler is one of the OTEL_TRACES_SAMPLER samplers, e.g.
+// "parentbased_traceidratio" to sample SamplerRatio of the traces that do not
+// continue a sampled traceparent.
+type TracingConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Exporter     string        `yaml:"exporter"`
+	Endpoint     string        `yaml:"endpoint"`
+	Path         string        `yaml:"path"`
+	Interval     time.Duration `yaml:"interval"`
+	Sampler      string        `yaml:"sampler"`
+	SamplerRatio float64       `yaml:"sampler_ratio"`
+}
+
+var tracingSamplers = []string{
+	"always_on",
+	"always_off",
+	"traceidratio",
+	"parentbased_always_on",
+	"parentbased_always_off",
+	"parentbased_traceidratio",
+}
+
+func (c TracingConfig) validSampler() bool {
+	for _, sampler := range tracingSamplers {
+		if c.Sampler == sampler {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestLimitsConfig bounds the bodies of requests. MaxBodySize is in bytes
+// and MaxBatchLength is the number of routes of a batch request; a limit of 0
+// disables it. Endpoints override the limits of endpoints named by their route
+// name, such as UpsertRoute. StrictDecoding rejects fields the routes do not
+// have.
+type RequestLimitsConfig struct {
+	MaxBodySize    int64                           `yaml:"max_body_size"`
+	MaxBatchLength int                             `yaml:"max_batch_length"`
+	StrictDecoding bool                            `yaml:"strict_decoding"`
+	Endpoints      map[string]EndpointLimitsConfig `yaml:"endpoints"`
+}
+
+// EndpointLimitsConfig overrides the request limits of an endpoint with its
+// non-zero limits.
+type EndpointLimitsConfig struct {
+	MaxBodySize    int64 `yaml:"max_body_size"`
+	MaxBatchLength int   `yaml:"max_batch_length"`
+}
+
+// Endpoint returns the limits of the endpoint with the route name.
+func (c RequestLimitsConfig) Endpoint(name string) EndpointLimitsConfig {
+	limits := EndpointLimitsConfig{MaxBodySize: c.MaxBodySize, MaxBatchLength: c.MaxBatchLength}
+	override := c.Endpoints[name]
+	if override.MaxBodySize != 0 {
+		limits.MaxBodySize = override.MaxBodySize
+	}
+	if override.MaxBatchLength != 0 {
+		limits.MaxBatchLength = override.MaxBatchLength
+	}
+	return limits
+}
+
+// TTLPolicy is the maximum TTL of a kind of route and the TTL routes
+// registered without one get. Router groups can override it for their TCP
+// routes.
+type TTLPolicy struct {
+	MaxTTL     time.Duration `yaml:"max_ttl"`
+	DefaultTTL time.Duration `yaml:"default_ttl"`
+}
+
+func (p TTLPolicy) Policy() models.TTLPolicy {
+	return models.TTLPolicy{
+		MaxTTL:     int(p.MaxTTL.Seconds()),
+		DefaultTTL: int(p.DefaultTTL.Seconds()),
+	}
+}
+
+type TTLPolicies struct {
+	Http TTLPolicy `yaml:"http"`
+	Tcp  TTLPolicy `yaml:"tcp"`
+}
+
 type Config struct {
 	DebugAddress                    string              `yaml:"debug_address"`
+	AdminAddress                    string              `yaml:"admin_address"`
 	LogGuid                         string              `yaml:"log_guid"`
 	MetronConfig                    MetronConfig        `yaml:"metron_config"`
 	MaxTTL                          time.Duration       `yaml:"max_ttl"`
@@ -64,6 +203,14 @@ type Config struct {
 	Etcd                            Etcd                `yaml:"etcd"`
 	SqlDB                           SqlDB               `yaml:"sqldb"`
 	ConsulCluster                   ConsulCluster       `yaml:"consul_cluster"`
+	Audit                           AuditConfig         `yaml:"audit"`
+	RouteHistory                    RouteHistoryConfig  `yaml:"route_history"`
+	Quotas                          QuotaConfig         `yaml:"quotas"`
+	TTLPolicies                     TTLPolicies         `yaml:"ttl_policies"`
+	Grpc                            GrpcConfig          `yaml:"grpc"`
+	RequestLimits                   RequestLimitsConfig `yaml:"request_limits"`
+	Prometheus                      PrometheusConfig    `yaml:"prometheus"`
+	Tracing                         TracingConfig       `yaml:"tracing"`
 }
 
 func NewConfigFromFile(configFile string, authDisabled bool) (Config, error) {
@@ -134,9 +281,123 @@ func (cfg *Config) process() error {
 		cfg.MaxTTL = 2 * time.Minute
 	}
 
+	// routes default to the max ttl unless configured otherwise
+	for _, policy := range []*TTLPolicy{&cfg.TTLPolicies.Http, &cfg.TTLPolicies.Tcp} {
+		if policy.MaxTTL == 0 {
+			policy.MaxTTL = cfg.MaxTTL
+		}
+		if policy.DefaultTTL == 0 {
+			policy.DefaultTTL = policy.MaxTTL
+		}
+		if policy.MaxTTL < 0 || policy.DefaultTTL < 0 {
+			return errors.New("TTL policies cannot be negative")
+		}
+		if policy.DefaultTTL > policy.MaxTTL {
+			return errors.New("default_ttl cannot be greater than max_ttl")
+		}
+	}
+
+	if cfg.Audit.Retention == 0 {
+		cfg.Audit.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.Audit.MaxRecords <= 0 {
+		cfg.Audit.MaxRecords = 100000
+	}
+
+	for _, proxy := range cfg.Audit.TrustedProxies {
+		network, err := parseNetwork(proxy)
+		if err != nil {
+			return err
+		}
+		cfg.Audit.TrustedProxyNetworks = append(cfg.Audit.TrustedProxyNetworks, network)
+	}
+
+	if cfg.RouteHistory.MaxVersions <= 0 {
+		cfg.RouteHistory.MaxVersions = 10
+	}
+	if cfg.RouteHistory.Retention == 0 {
+		cfg.RouteHistory.Retention = 7 * 24 * time.Hour
+	}
+
+	if cfg.Quotas.MaxHttpRoutesPerOwner < 0 ||
+		cfg.Quotas.MaxHttpRoutesPerLogGuid < 0 ||
+		cfg.Quotas.MaxTcpRoutesPerOwner < 0 {
+		return errors.New("Quotas cannot be negative")
+	}
+
+	if cfg.RequestLimits.MaxBodySize == 0 {
+		cfg.RequestLimits.MaxBodySize = 10 * 1024 * 1024
+	}
+	if cfg.RequestLimits.MaxBodySize < 0 || cfg.RequestLimits.MaxBatchLength < 0 {
+		return errors.New("Request limits cannot be negative")
+	}
+	for _, limits := range cfg.RequestLimits.Endpoints {
+		if limits.MaxBodySize < 0 || limits.MaxBatchLength < 0 {
+			return errors.New("Request limits cannot be negative")
+		}
+	}
+
+	if cfg.SqlDB.MaxEventsPerSubscriber < 0 {
+		return errors.New("max_events_per_subscriber cannot be negative")
+	}
+
+	if cfg.Grpc.Port != 0 && (cfg.Grpc.CertFile == "" || cfg.Grpc.KeyFile == "") {
+		return errors.New("gRPC API requires a cert_file and key_file")
+	}
+
+	if cfg.Prometheus.Enabled && cfg.Prometheus.Address == "" && cfg.AdminAddress == "" {
+		return errors.New("Prometheus metrics require an address or an admin_address")
+	}
+
+	if cfg.Tracing.Exporter == "" {
+		cfg.Tracing.Exporter = "otlp"
+	}
+	if cfg.Tracing.Endpoint == "" {
+		cfg.Tracing.Endpoint = "http://127.0.0.1:4318/v1/traces"
+	}
+	if cfg.Tracing.Interval <= 0 {
+		cfg.Tracing.Interval = 5 * time.Second
+	}
+	if cfg.Tracing.Sampler == "" {
+		cfg.Tracing.Sampler = "parentbased_always_on"
+	}
+	if cfg.Tracing.SamplerRatio == 0 {
+		cfg.Tracing.SamplerRatio = 1
+	}
+	if cfg.Tracing.Exporter != "otlp" && cfg.Tracing.Exporter != "stdout" {
+		return errors.New("Tracing exporter must be otlp or stdout")
+	}
+	if !cfg.Tracing.validSampler() {
+		return errors.New("Tracing sampler must be one of " + strings.Join(tracingSamplers, ", "))
+	}
+	if cfg.Tracing.SamplerRatio < 0 || cfg.Tracing.SamplerRatio > 1 {
+		return errors.New("Tracing sampler ratio must be between 0 and 1")
+	}
+
 	if err := cfg.RouterGroups.Validate(); err != nil {
 		return err
 	}
+	for _, routerGroup := range cfg.RouterGroups {
+		if err := routerGroup.ValidateTTLs(cfg.TTLPolicies.Tcp.Policy().MaxTTL); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
+
+// parseNetwork parses a CIDR network, or a single address as the network of
+// just that address.
+func parseNetwork(value string) (*net.IPNet, error) {
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		return network, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("Invalid trusted proxy: %s", value)
+	}
+	if ip.To4() != nil {
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
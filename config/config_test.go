@@ -21,6 +21,7 @@ var _ = Describe("Config", func() {
 
 					Expect(err).NotTo(HaveOccurred())
 					Expect(cfg.LogGuid).To(Equal("my_logs"))
+					Expect(cfg.AdminAddress).To(Equal("127.0.0.1:8081"))
 					Expect(cfg.MetronConfig.Address).To(Equal("1.2.3.4"))
 					Expect(cfg.MetronConfig.Port).To(Equal("4567"))
 					Expect(cfg.StatsdClientFlushInterval).To(Equal(10 * time.Millisecond))
@@ -31,11 +32,44 @@ var _ = Describe("Config", func() {
 					Expect(cfg.SqlDB.Username).To(Equal("username"))
 					Expect(cfg.SqlDB.Password).To(Equal("password"))
 					Expect(cfg.SqlDB.Port).To(Equal(1234))
+					Expect(cfg.SqlDB.MaxEventsPerSubscriber).To(Equal(2048))
 					Expect(cfg.MaxTTL).To(Equal(2 * time.Minute))
 					Expect(cfg.Etcd.NodeURLS).To(Equal([]string{"http://localhost:1234"}))
 					Expect(cfg.ConsulCluster.Servers).To(Equal("http://localhost:5678"))
 					Expect(cfg.ConsulCluster.LockTTL).To(Equal(10 * time.Second))
 					Expect(cfg.ConsulCluster.RetryInterval).To(Equal(5 * time.Second))
+					Expect(cfg.Audit.Enabled).To(BeTrue())
+					Expect(cfg.Audit.Retention).To(Equal(24 * time.Hour))
+					Expect(cfg.Audit.MaxRecords).To(Equal(5000))
+					Expect(cfg.Audit.TrustedProxies).To(Equal([]string{"10.0.0.0/8", "192.168.1.1"}))
+					Expect(cfg.Audit.TrustedProxyNetworks).To(HaveLen(2))
+					Expect(cfg.Audit.TrustedProxyNetworks[0].String()).To(Equal("10.0.0.0/8"))
+					Expect(cfg.Audit.TrustedProxyNetworks[1].String()).To(Equal("192.168.1.1/32"))
+					Expect(cfg.RouteHistory.Enabled).To(BeTrue())
+					Expect(cfg.RouteHistory.MaxVersions).To(Equal(20))
+					Expect(cfg.RouteHistory.Retention).To(Equal(72 * time.Hour))
+					Expect(cfg.Quotas.MaxHttpRoutesPerOwner).To(Equal(1000))
+					Expect(cfg.Quotas.MaxHttpRoutesPerLogGuid).To(Equal(100))
+					Expect(cfg.Quotas.MaxTcpRoutesPerOwner).To(Equal(500))
+					Expect(cfg.TTLPolicies.Http).To(Equal(config.TTLPolicy{MaxTTL: 60 * time.Second, DefaultTTL: 30 * time.Second}))
+					Expect(cfg.TTLPolicies.Tcp).To(Equal(config.TTLPolicy{MaxTTL: time.Hour, DefaultTTL: 2 * time.Minute}))
+					Expect(cfg.Grpc.Port).To(Equal(uint16(3001)))
+					Expect(cfg.Grpc.CertFile).To(Equal("/var/vcap/jobs/routing-api/config/certs/grpc.crt"))
+					Expect(cfg.Grpc.KeyFile).To(Equal("/var/vcap/jobs/routing-api/config/certs/grpc.key"))
+					Expect(cfg.RequestLimits.MaxBodySize).To(Equal(int64(1048576)))
+					Expect(cfg.RequestLimits.MaxBatchLength).To(Equal(1000))
+					Expect(cfg.RequestLimits.StrictDecoding).To(BeTrue())
+					Expect(cfg.RequestLimits.Endpoint("UpsertTcpRouteMapping")).To(Equal(config.EndpointLimitsConfig{MaxBodySize: 1048576, MaxBatchLength: 5000}))
+					Expect(cfg.RequestLimits.Endpoint("UpsertRoute")).To(Equal(config.EndpointLimitsConfig{MaxBodySize: 1048576, MaxBatchLength: 1000}))
+					Expect(cfg.Prometheus).To(Equal(config.PrometheusConfig{Enabled: true}))
+					Expect(cfg.Tracing).To(Equal(config.TracingConfig{
+						Enabled:      true,
+						Exporter:     "otlp",
+						Endpoint:     "http://127.0.0.1:4318/v1/traces",
+						Interval:     5 * time.Second,
+						Sampler:      "parentbased_traceidratio",
+						SamplerRatio: 0.25,
+					}))
 				})
 
 				Context("when there is no token endpoint specified", func() {
@@ -87,6 +121,14 @@ var _ = Describe("Config", func() {
 						Expect(cfg.StatsdClientFlushInterval).To(Equal(10 * time.Millisecond))
 						Expect(cfg.OAuth.TokenEndpoint).To(BeEmpty())
 						Expect(cfg.OAuth.Port).To(Equal(0))
+						Expect(cfg.Audit.Enabled).To(BeFalse())
+						Expect(cfg.Audit.Retention).To(Equal(7 * 24 * time.Hour))
+						Expect(cfg.Audit.MaxRecords).To(Equal(100000))
+						Expect(cfg.RouteHistory.Enabled).To(BeFalse())
+						Expect(cfg.RouteHistory.MaxVersions).To(Equal(10))
+						Expect(cfg.RouteHistory.Retention).To(Equal(7 * 24 * time.Hour))
+						Expect(cfg.TTLPolicies.Http).To(Equal(config.TTLPolicy{MaxTTL: 2 * time.Minute, DefaultTTL: 2 * time.Minute}))
+						Expect(cfg.TTLPolicies.Tcp).To(Equal(config.TTLPolicy{MaxTTL: 2 * time.Minute, DefaultTTL: 2 * time.Minute}))
 					})
 				})
 			})
@@ -314,5 +356,257 @@ statsd_client_flush_interval: "10ms"`
 			})
 
 		})
+
+		Context("when a quota is negative", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+quotas:
+  max_http_routes_per_owner: -1`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Quotas cannot be negative"))
+			})
+		})
+
+		Context("when no request limits are configured", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"`
+
+			It("limits the body size to 10 MiB only", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.RequestLimits.MaxBodySize).To(Equal(int64(10 * 1024 * 1024)))
+				Expect(cfg.RequestLimits.MaxBatchLength).To(Equal(0))
+				Expect(cfg.RequestLimits.StrictDecoding).To(BeFalse())
+			})
+		})
+
+		Context("when a trusted proxy is invalid", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+audit:
+  trusted_proxies: ["10.0.0.0/33"]`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Invalid trusted proxy: 10.0.0.0/33"))
+			})
+		})
+
+		Context("when max_events_per_subscriber is negative", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+sqldb:
+  max_events_per_subscriber: -1`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("max_events_per_subscriber cannot be negative"))
+			})
+		})
+
+		Context("when a request limit is negative", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+request_limits:
+  endpoints:
+    UpsertRoute:
+      max_batch_length: -1`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Request limits cannot be negative"))
+			})
+		})
+
+		Context("when tracing is not configured", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"`
+
+			It("defaults to a local OTLP collector", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Tracing).To(Equal(config.TracingConfig{
+					Exporter:     "otlp",
+					Endpoint:     "http://127.0.0.1:4318/v1/traces",
+					Interval:     5 * time.Second,
+					Sampler:      "parentbased_always_on",
+					SamplerRatio: 1,
+				}))
+			})
+		})
+
+		Context("when the stdout exporter writes to a file", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+tracing:
+  enabled: true
+  exporter: stdout
+  path: /tmp/spans.log`
+
+			It("keeps the path", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Tracing.Exporter).To(Equal("stdout"))
+				Expect(cfg.Tracing.Path).To(Equal("/tmp/spans.log"))
+			})
+		})
+
+		Context("when the tracing exporter is unknown", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+tracing:
+  enabled: true
+  exporter: zipkin`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Tracing exporter must be otlp or stdout"))
+			})
+		})
+
+		Context("when the tracing sampler is unknown", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+tracing:
+  enabled: true
+  sampler: sometimes`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Tracing sampler must be one of always_on, always_off, traceidratio, parentbased_always_on, parentbased_always_off, parentbased_traceidratio"))
+			})
+		})
+
+		Context("when the tracing sampler ratio is out of range", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+tracing:
+  enabled: true
+  sampler: traceidratio
+  sampler_ratio: 1.5`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Tracing sampler ratio must be between 0 and 1"))
+			})
+		})
+
+		Context("when prometheus metrics have no listener", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+prometheus:
+  enabled: true`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("Prometheus metrics require an address or an admin_address"))
+			})
+		})
+
+		Context("when a default ttl is greater than the max ttl", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+ttl_policies:
+  tcp:
+    max_ttl: 60s
+    default_ttl: 120s`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("default_ttl cannot be greater than max_ttl"))
+			})
+		})
+
+		Context("when only a max ttl is set", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+ttl_policies:
+  tcp:
+    max_ttl: 1h`
+
+			It("defaults the default ttl to the max ttl", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.TTLPolicies.Tcp.DefaultTTL).To(Equal(time.Hour))
+				Expect(cfg.TTLPolicies.Http.MaxTTL).To(Equal(2 * time.Minute))
+			})
+		})
+
+		Context("when a router group raises the max ttl", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+ttl_policies:
+  tcp:
+    max_ttl: 1h
+router_groups:
+- name: router-group-1
+  reservable_ports: 1200
+  type: tcp
+  max_ttl: 7200`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("TTLs cannot be greater than the global max_ttl of 3600 in router group: router-group-1"))
+			})
+		})
+
+		Context("when the grpc api has no certificate", func() {
+			testConfig := `log_guid: "my_logs"
+system_domain: "example.com"
+metrics_reporting_interval: "500ms"
+statsd_endpoint: "localhost:8125"
+statsd_client_flush_interval: "10ms"
+grpc:
+  port: 3001`
+
+			It("returns an error", func() {
+				err := cfg.Initialize([]byte(testConfig), true)
+				Expect(err).To(MatchError("gRPC API requires a cert_file and key_file"))
+			})
+		})
 	})
 })
@@ -1,22 +1,36 @@
 package db
 
-import "github.com/jinzhu/gorm"
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+)
 
 //go:generate counterfeiter -o fakes/fake_client.go . Client
 type Client interface {
 	Close() error
 	Where(query interface{}, args ...interface{}) Client
+	Model(value interface{}) Client
+	Order(value interface{}) Client
+	Limit(limit interface{}) Client
+	Offset(offset interface{}) Client
+	Select(query interface{}, args ...interface{}) Client
+	Group(query string) Client
 	Create(value interface{}) (int64, error)
 	Delete(value interface{}, where ...interface{}) (int64, error)
 	Save(value interface{}) (int64, error)
 	Update(attrs ...interface{}) (int64, error)
 	First(out interface{}, where ...interface{}) error
 	Find(out interface{}, where ...interface{}) error
+	Count(value interface{}) error
+	Rows() (*sql.Rows, error)
 	AutoMigrate(values ...interface{}) error
 	Begin() Client
 	Rollback() error
 	Commit() error
 	HasTable(value interface{}) bool
+	Ping() error
 }
 
 type gormClient struct {
@@ -37,6 +51,42 @@ func (c *gormClient) Where(query interface{}, args ...interface{}) Client {
 	return &newClient
 }
 
+func (c *gormClient) Model(value interface{}) Client {
+	var newClient gormClient
+	newClient.db = c.db.Model(value)
+	return &newClient
+}
+
+func (c *gormClient) Order(value interface{}) Client {
+	var newClient gormClient
+	newClient.db = c.db.Order(value)
+	return &newClient
+}
+
+func (c *gormClient) Limit(limit interface{}) Client {
+	var newClient gormClient
+	newClient.db = c.db.Limit(limit)
+	return &newClient
+}
+
+func (c *gormClient) Offset(offset interface{}) Client {
+	var newClient gormClient
+	newClient.db = c.db.Offset(offset)
+	return &newClient
+}
+
+func (c *gormClient) Select(query interface{}, args ...interface{}) Client {
+	var newClient gormClient
+	newClient.db = c.db.Select(query, args...)
+	return &newClient
+}
+
+func (c *gormClient) Group(query string) Client {
+	var newClient gormClient
+	newClient.db = c.db.Group(query)
+	return &newClient
+}
+
 func (c *gormClient) Create(value interface{}) (int64, error) {
 	newDb := c.db.Create(value)
 	return newDb.RowsAffected, newDb.Error
@@ -65,6 +115,14 @@ func (c *gormClient) Find(out interface{}, where ...interface{}) error {
 	return c.db.Find(out, where...).Error
 }
 
+func (c *gormClient) Count(value interface{}) error {
+	return c.db.Count(value).Error
+}
+
+func (c *gormClient) Rows() (*sql.Rows, error) {
+	return c.db.Rows()
+}
+
 func (c *gormClient) AutoMigrate(values ...interface{}) error {
 	return c.db.AutoMigrate(values...).Error
 }
@@ -86,3 +144,11 @@ func (c *gormClient) Commit() error {
 func (c *gormClient) HasTable(value interface{}) bool {
 	return c.db.HasTable(value)
 }
+
+// Ping gives up after pingTimeout, so that an unreachable database fails the
+// readiness check instead of blocking it.
+func (c *gormClient) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	return c.db.DB().PingContext(ctx)
+}
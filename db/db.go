@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/routing-api/config"
@@ -19,17 +20,52 @@ import (
 //go:generate counterfeiter -o fakes/fake_db.go . DB
 type DB interface {
 	ReadRoutes() ([]models.Route, error)
+	StreamRoutes(fn func(models.Route) error) error
+	ReadRoute(route models.Route) (models.Route, error)
+	ReadRouteByGuid(guid string) (models.Route, error)
 	SaveRoute(route models.Route) error
+	CreateRoute(route models.Route) error
 	DeleteRoute(route models.Route) error
+	SaveRouteIfMatch(route models.Route, expected models.ModificationTag) error
+	DeleteRouteIfMatch(route models.Route, expected models.ModificationTag) error
+	DrainRoute(route models.Route, drainTTL int) error
+	DrainRouteIfMatch(route models.Route, drainTTL int, expected models.ModificationTag) error
+	DeleteRoutesBySelector(selector models.RouteSelector, dryRun bool) ([]models.Route, error)
+	CountRoutes(selector models.RouteSelector) (int, error)
+	CountRoutesPerOwner() (map[string]int, error)
+	CountRoutesPerLogGuid() (map[string]int, error)
 
 	ReadTcpRouteMappings() ([]models.TcpRouteMapping, error)
+	StreamTcpRouteMappings(fn func(models.TcpRouteMapping) error) error
+	ReadTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error)
+	ReadTcpRouteMappingByGuid(guid string) (models.TcpRouteMapping, error)
 	SaveTcpRouteMapping(tcpMapping models.TcpRouteMapping) error
+	CreateTcpRouteMapping(tcpMapping models.TcpRouteMapping) error
 	DeleteTcpRouteMapping(tcpMapping models.TcpRouteMapping) error
+	SaveTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error
+	DeleteTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error
+	DrainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int) error
+	DrainTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, drainTTL int, expected models.ModificationTag) error
+	DeleteTcpRouteMappingsBySelector(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error)
+	CountTcpRouteMappings(selector models.TcpRouteMappingSelector) (int, error)
+	CountTcpRouteMappingsPerOwner() (map[string]int, error)
 
 	ReadRouterGroups() (models.RouterGroups, error)
 	ReadRouterGroup(guid string) (models.RouterGroup, error)
 	SaveRouterGroup(routerGroup models.RouterGroup) error
 
+	SaveAuditRecord(record models.AuditRecord) error
+	ReadAuditRecords(filter models.AuditFilter) ([]models.AuditRecord, error)
+	PruneAuditRecords(olderThan time.Time, maxRecords int) error
+
+	ReadRevision(table string) (uint64, error)
+
+	SaveRouteVersion(version models.RouteVersion, maxVersions int) error
+	ReadRouteVersions(kind, key string) ([]models.RouteVersion, error)
+	PruneRouteVersions(olderThan time.Time) error
+
+	Ping() error
+
 	CancelWatches()
 	WatchChanges(watchType string) (<-chan Event, <-chan error, context.CancelFunc)
 }
@@ -38,20 +74,45 @@ const (
 	TCP_MAPPING_BASE_KEY  string = "/v1/tcp_routes/router_groups"
 	HTTP_ROUTE_BASE_KEY   string = "/routes"
 	ROUTER_GROUP_BASE_KEY string = "/v1/router_groups"
+	AUDIT_BASE_KEY        string = "/v1/audit"
+	HISTORY_BASE_KEY      string = "/v1/history"
 	defaultDialTimeout           = 30 * time.Second
+	pingTimeout                  = 5 * time.Second
 	maxRetries                   = 3
 	TCP_WATCH             string = "tcp-watch"
 	HTTP_WATCH            string = "http-watch"
 	ROUTER_GROUP_WATCH    string = "router-group-watch"
+	HTTP_ROUTES_TABLE     string = "http_routes"
+	TCP_ROUTES_TABLE      string = "tcp_routes"
+	ROUTER_GROUPS_TABLE   string = "router_groups"
 )
 
 var ErrorConflict = errors.New("etcd failed to compare")
 
+// ErrGuidsNotSupported is returned when looking up a route by its guid in
+// etcd, which does not store the guids of routes.
+var ErrGuidsNotSupported = errors.New("routes cannot be looked up by guid when the routing api is backed by etcd")
+
+// WithRequestID returns a database that records id in the events of its
+// writes. Events of databases that cannot carry it, such as etcd, have no
+// request id.
+func WithRequestID(database DB, id string) DB {
+	if db, ok := database.(interface {
+		withRequestID(id string) DB
+	}); ok && id != "" {
+		return db.withRequestID(id)
+	}
+	return database
+}
+
 type EtcdDB struct {
 	Client     client.Client
 	KeysAPI    client.KeysAPI
 	Ctx        context.Context
 	CancelFunc context.CancelFunc
+
+	revisionsLock sync.Mutex
+	revisions     map[string]etcdTableRevision
 }
 
 func NewETCD(conf *config.Etcd) (*EtcdDB, error) {
@@ -126,6 +187,49 @@ func (e *EtcdDB) ReadRoutes() ([]models.Route, error) {
 	return listRoutes, nil
 }
 
+// StreamRoutes calls fn for every route. Etcd returns all routes in a single
+// response, so they are read first.
+func (e *EtcdDB) StreamRoutes(fn func(models.Route) error) error {
+	routes, err := e.ReadRoutes()
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		err = fn(route)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns a zero-value struct and nil error when the route could not be found.
+func (e *EtcdDB) ReadRoute(route models.Route) (models.Route, error) {
+	response, err := e.KeysAPI.Get(context.Background(), generateHttpRouteKey(route), readOpts())
+	if err != nil {
+		if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+			return models.Route{}, nil
+		}
+		return models.Route{}, err
+	}
+
+	result := models.Route{}
+	err = json.Unmarshal([]byte(response.Node.Value), &result)
+	if err != nil {
+		return models.Route{}, err
+	}
+	if response.Node.Expiration != nil {
+		result.ExpiresAt = *response.Node.Expiration
+	}
+	return result, nil
+}
+
+// ReadRouteByGuid is not supported, because the guids of routes are not
+// stored in etcd.
+func (e *EtcdDB) ReadRouteByGuid(guid string) (models.Route, error) {
+	return models.Route{}, ErrGuidsNotSupported
+}
+
 func readOpts() *client.GetOptions {
 	return &client.GetOptions{
 		Recursive: true,
@@ -134,18 +238,27 @@ func readOpts() *client.GetOptions {
 
 func createOpts(ttl int) *client.SetOptions {
 	return &client.SetOptions{
-		TTL:       time.Duration(ttl) * time.Second,
+		TTL:       keyTTL(ttl),
 		PrevExist: "false",
 	}
 }
 
 func updateOptsWithTTL(ttl int, prevIndex uint64) *client.SetOptions {
 	return &client.SetOptions{
-		TTL:       time.Duration(ttl) * time.Second,
+		TTL:       keyTTL(ttl),
 		PrevIndex: prevIndex,
 	}
 }
 
+// keyTTL returns the etcd TTL of a route. Permanent routes are stored without
+// a TTL, so they never expire.
+func keyTTL(ttl int) time.Duration {
+	if ttl == models.PermanentTTL {
+		return 0
+	}
+	return time.Duration(ttl) * time.Second
+}
+
 func updateOpts(prevIndex uint64) *client.SetOptions {
 	return &client.SetOptions{
 		PrevIndex: prevIndex,
@@ -213,6 +326,23 @@ func (e *EtcdDB) SaveRoute(route models.Route) error {
 	return nil
 }
 
+// CreateRoute registers a route that does not exist. The key is only set if
+// it is absent, so it fails with ErrorConflict when the route exists.
+func (e *EtcdDB) CreateRoute(route models.Route) error {
+	tag, err := models.NewModificationTag()
+	if err != nil {
+		return err
+	}
+	route.ModificationTag = tag
+
+	routeJSON, _ := json.Marshal(route)
+	_, err = e.KeysAPI.Set(ctx(), generateHttpRouteKey(route), string(routeJSON), createOpts(*route.TTL))
+	if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeNodeExist {
+		return ErrorConflict
+	}
+	return err
+}
+
 func (e *EtcdDB) DeleteRoute(route models.Route) error {
 	key := generateHttpRouteKey(route)
 
@@ -227,6 +357,255 @@ func (e *EtcdDB) DeleteRoute(route models.Route) error {
 	return err
 }
 
+// SaveRouteIfMatch updates an existing route only if its modification tag is
+// still the expected one. The compare and swap uses the etcd index of the node
+// that was read, so a concurrent write in between is detected as a mismatch.
+func (e *EtcdDB) SaveRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	key := generateHttpRouteKey(route)
+
+	node, current, err := e.readModificationTag(key)
+	if err != nil {
+		return err
+	}
+	if node == nil || current != expected {
+		return ModificationTagMismatchError{Current: current}
+	}
+
+	route.ModificationTag = current
+	route.ModificationTag.Increment()
+
+	routeJSON, _ := json.Marshal(route)
+	_, err = e.KeysAPI.Set(ctx(), key, string(routeJSON), updateOptsWithTTL(*route.TTL, node.ModifiedIndex))
+	return e.conditionalWriteError(key, err)
+}
+
+func (e *EtcdDB) DeleteRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	key := generateHttpRouteKey(route)
+
+	node, current, err := e.readModificationTag(key)
+	if err != nil {
+		return err
+	}
+	if node == nil || current != expected {
+		return ModificationTagMismatchError{Current: current}
+	}
+
+	_, err = e.KeysAPI.Delete(ctx(), key, &client.DeleteOptions{PrevIndex: node.ModifiedIndex})
+	return e.conditionalWriteError(key, err)
+}
+
+// DeleteRoutesBySelector deletes every route matched by the selector and
+// returns the deleted routes. With dryRun the matched routes are returned
+// without deleting them. Unlike SqlDB, the routes are deleted one by one and a
+// failure leaves the routes deleted so far removed.
+func (e *EtcdDB) DeleteRoutesBySelector(selector models.RouteSelector, dryRun bool) ([]models.Route, error) {
+	routes, err := e.ReadRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []models.Route{}
+	for _, route := range routes {
+		if !selector.Matches(route) {
+			continue
+		}
+		if !dryRun {
+			err = e.DeleteRoute(route)
+			if dberr, ok := err.(DBError); ok && dberr.Type == KeyNotFound {
+				continue
+			}
+			if err != nil {
+				return deleted, err
+			}
+		}
+		deleted = append(deleted, route)
+	}
+	return deleted, nil
+}
+
+// CountRoutes returns the number of routes matched by the selector.
+func (e *EtcdDB) CountRoutes(selector models.RouteSelector) (int, error) {
+	routes, err := e.ReadRoutes()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, route := range routes {
+		if selector.Matches(route) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountRoutesPerOwner returns the number of routes of every owner.
+func (e *EtcdDB) CountRoutesPerOwner() (map[string]int, error) {
+	routes, err := e.ReadRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, route := range routes {
+		if route.Owner != "" {
+			counts[route.Owner]++
+		}
+	}
+	return counts, nil
+}
+
+// CountRoutesPerLogGuid returns the number of routes of every log guid.
+func (e *EtcdDB) CountRoutesPerLogGuid() (map[string]int, error) {
+	routes, err := e.ReadRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, route := range routes {
+		if route.LogGuid != "" {
+			counts[route.LogGuid]++
+		}
+	}
+	return counts, nil
+}
+
+// DrainRoute marks an existing route as draining and sets its TTL to drainTTL,
+// after which etcd expires it.
+func (e *EtcdDB) DrainRoute(route models.Route, drainTTL int) error {
+	return e.drainRoute(route, drainTTL, nil)
+}
+
+func (e *EtcdDB) DrainRouteIfMatch(route models.Route, drainTTL int, expected models.ModificationTag) error {
+	return e.drainRoute(route, drainTTL, &expected)
+}
+
+func (e *EtcdDB) drainRoute(route models.Route, drainTTL int, expected *models.ModificationTag) error {
+	key := generateHttpRouteKey(route)
+
+	response, err := e.KeysAPI.Get(ctx(), key, readOpts())
+	if err != nil {
+		if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+			if expected != nil {
+				return ModificationTagMismatchError{}
+			}
+			return DBError{Type: KeyNotFound, Message: "The specified route could not be found."}
+		}
+		return err
+	}
+
+	var existingRoute models.Route
+	err = json.Unmarshal([]byte(response.Node.Value), &existingRoute)
+	if err != nil {
+		return err
+	}
+	if expected != nil && existingRoute.ModificationTag != *expected {
+		return ModificationTagMismatchError{Current: existingRoute.ModificationTag}
+	}
+
+	existingRoute.Drain(drainTTL)
+	routeJSON, _ := json.Marshal(existingRoute)
+	_, err = e.KeysAPI.Set(ctx(), key, string(routeJSON), updateOptsWithTTL(drainTTL, response.Node.ModifiedIndex))
+	return e.drainError(key, err, expected)
+}
+
+// drainError reports a concurrent write between reading and draining an
+// entry as a tag mismatch for conditional drains and as a conflict otherwise.
+func (e *EtcdDB) drainError(key string, err error, expected *models.ModificationTag) error {
+	if expected != nil {
+		return e.conditionalWriteError(key, err)
+	}
+	if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeTestFailed {
+		return ErrorConflict
+	}
+	return err
+}
+
+// readModificationTag returns the node stored at key and the modification tag
+// of the route or tcp route mapping it holds. The node is nil when the key
+// does not exist.
+func (e *EtcdDB) readModificationTag(key string) (*client.Node, models.ModificationTag, error) {
+	response, err := e.KeysAPI.Get(ctx(), key, readOpts())
+	if err != nil {
+		if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+			return nil, models.ModificationTag{}, nil
+		}
+		return nil, models.ModificationTag{}, err
+	}
+
+	var value struct {
+		ModificationTag models.ModificationTag `json:"modification_tag"`
+	}
+	err = json.Unmarshal([]byte(response.Node.Value), &value)
+	if err != nil {
+		return nil, models.ModificationTag{}, err
+	}
+	return response.Node, value.ModificationTag, nil
+}
+
+// conditionalWriteError turns a failed compare and swap into a
+// ModificationTagMismatchError carrying the tag that won.
+func (e *EtcdDB) conditionalWriteError(key string, err error) error {
+	cerr, ok := err.(client.Error)
+	if !ok || (cerr.Code != client.ErrorCodeTestFailed && cerr.Code != client.ErrorCodeKeyNotFound) {
+		return err
+	}
+
+	_, current, readErr := e.readModificationTag(key)
+	if readErr != nil {
+		return readErr
+	}
+	return ModificationTagMismatchError{Current: current}
+}
+
+// ReadRevision returns the etcd index as of which the keys of the table are
+// current. etcd has a single index for all keys, which audit records and
+// route history also advance, so the revision is derived from the keys of the
+// table instead, see etcdTableRevision.
+func (e *EtcdDB) ReadRevision(table string) (uint64, error) {
+	var key string
+	switch table {
+	case HTTP_ROUTES_TABLE:
+		key = HTTP_ROUTE_BASE_KEY
+	case TCP_ROUTES_TABLE:
+		key = TCP_MAPPING_BASE_KEY
+	case ROUTER_GROUPS_TABLE:
+		key = ROUTER_GROUP_BASE_KEY
+	default:
+		return 0, fmt.Errorf("Invalid table: %s", table)
+	}
+
+	current := etcdTableRevision{}
+	response, err := e.KeysAPI.Get(ctx(), key, &client.GetOptions{Recursive: true})
+	if err == nil {
+		current.index = response.Index
+		current.addKeys(response.Node)
+	} else if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+		current.index = cerr.Index
+	} else {
+		return 0, err
+	}
+
+	e.revisionsLock.Lock()
+	defer e.revisionsLock.Unlock()
+	if e.revisions == nil {
+		e.revisions = map[string]etcdTableRevision{}
+	}
+	previous, ok := e.revisions[table]
+	current.revision = current.next(previous, ok)
+	e.revisions[table] = current
+	return current.revision, nil
+}
+
+// Ping reads the top-level listing to check that etcd can be reached.
+func (e *EtcdDB) Ping() error {
+	cxt, cancel := context.WithTimeout(ctx(), pingTimeout)
+	defer cancel()
+	_, err := e.KeysAPI.Get(cxt, "/", &client.GetOptions{})
+	return err
+}
+
 func (e *EtcdDB) WatchChanges(watchType string) (<-chan Event, <-chan error, context.CancelFunc) {
 	var filter string
 	events := make(chan Event)
@@ -407,6 +786,49 @@ func (e *EtcdDB) ReadTcpRouteMappings() ([]models.TcpRouteMapping, error) {
 	return listMappings, nil
 }
 
+// StreamTcpRouteMappings calls fn for every tcp route mapping. Etcd returns all
+// mappings in a single response, so they are read first.
+func (e *EtcdDB) StreamTcpRouteMappings(fn func(models.TcpRouteMapping) error) error {
+	tcpMappings, err := e.ReadTcpRouteMappings()
+	if err != nil {
+		return err
+	}
+	for _, tcpMapping := range tcpMappings {
+		err = fn(tcpMapping)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns a zero-value struct and nil error when the mapping could not be found.
+func (e *EtcdDB) ReadTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error) {
+	response, err := e.KeysAPI.Get(context.Background(), generateTcpRouteMappingKey(tcpMapping), readOpts())
+	if err != nil {
+		if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+			return models.TcpRouteMapping{}, nil
+		}
+		return models.TcpRouteMapping{}, err
+	}
+
+	result := models.TcpRouteMapping{}
+	err = json.Unmarshal([]byte(response.Node.Value), &result)
+	if err != nil {
+		return models.TcpRouteMapping{}, err
+	}
+	if response.Node.Expiration != nil {
+		result.ExpiresAt = *response.Node.Expiration
+	}
+	return result, nil
+}
+
+// ReadTcpRouteMappingByGuid is not supported, because the guids of tcp route
+// mappings are not stored in etcd.
+func (e *EtcdDB) ReadTcpRouteMappingByGuid(guid string) (models.TcpRouteMapping, error) {
+	return models.TcpRouteMapping{}, ErrGuidsNotSupported
+}
+
 func (e *EtcdDB) SaveTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
 	key := generateTcpRouteMappingKey(tcpMapping)
 
@@ -462,6 +884,23 @@ func (e *EtcdDB) SaveTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
 	return ErrorConflict
 }
 
+// CreateTcpRouteMapping registers a mapping that does not exist, see
+// CreateRoute.
+func (e *EtcdDB) CreateTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
+	tag, err := models.NewModificationTag()
+	if err != nil {
+		return err
+	}
+	tcpMapping.ModificationTag = tag
+
+	tcpRouteMappingJSON, _ := json.Marshal(tcpMapping)
+	_, err = e.KeysAPI.Set(ctx(), generateTcpRouteMappingKey(tcpMapping), string(tcpRouteMappingJSON), createOpts(*tcpMapping.TTL))
+	if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeNodeExist {
+		return ErrorConflict
+	}
+	return err
+}
+
 func (e *EtcdDB) DeleteTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
 	key := generateTcpRouteMappingKey(tcpMapping)
 	deleteOpt := &client.DeleteOptions{}
@@ -477,9 +916,320 @@ func (e *EtcdDB) DeleteTcpRouteMapping(tcpMapping models.TcpRouteMapping) error
 	return err
 }
 
+func (e *EtcdDB) SaveTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	key := generateTcpRouteMappingKey(tcpMapping)
+
+	node, current, err := e.readModificationTag(key)
+	if err != nil {
+		return err
+	}
+	if node == nil || current != expected {
+		return ModificationTagMismatchError{Current: current}
+	}
+
+	tcpMapping.ModificationTag = current
+	tcpMapping.ModificationTag.Increment()
+
+	tcpRouteJSON, _ := json.Marshal(tcpMapping)
+	_, err = e.KeysAPI.Set(ctx(), key, string(tcpRouteJSON), updateOptsWithTTL(*tcpMapping.TTL, node.ModifiedIndex))
+	return e.conditionalWriteError(key, err)
+}
+
+func (e *EtcdDB) DeleteTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	key := generateTcpRouteMappingKey(tcpMapping)
+
+	node, current, err := e.readModificationTag(key)
+	if err != nil {
+		return err
+	}
+	if node == nil || current != expected {
+		return ModificationTagMismatchError{Current: current}
+	}
+
+	_, err = e.KeysAPI.Delete(ctx(), key, &client.DeleteOptions{PrevIndex: node.ModifiedIndex})
+	return e.conditionalWriteError(key, err)
+}
+
+// DeleteTcpRouteMappingsBySelector deletes every mapping matched by the
+// selector, see DeleteRoutesBySelector.
+func (e *EtcdDB) DeleteTcpRouteMappingsBySelector(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error) {
+	tcpMappings, err := e.ReadTcpRouteMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []models.TcpRouteMapping{}
+	for _, tcpMapping := range tcpMappings {
+		if !selector.Matches(tcpMapping) {
+			continue
+		}
+		if !dryRun {
+			err = e.DeleteTcpRouteMapping(tcpMapping)
+			if dberr, ok := err.(DBError); ok && dberr.Type == KeyNotFound {
+				continue
+			}
+			if err != nil {
+				return deleted, err
+			}
+		}
+		deleted = append(deleted, tcpMapping)
+	}
+	return deleted, nil
+}
+
+// CountTcpRouteMappings returns the number of mappings matched by the
+// selector.
+func (e *EtcdDB) CountTcpRouteMappings(selector models.TcpRouteMappingSelector) (int, error) {
+	tcpMappings, err := e.ReadTcpRouteMappings()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, tcpMapping := range tcpMappings {
+		if selector.Matches(tcpMapping) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountTcpRouteMappingsPerOwner returns the number of mappings of every
+// owner.
+func (e *EtcdDB) CountTcpRouteMappingsPerOwner() (map[string]int, error) {
+	tcpMappings, err := e.ReadTcpRouteMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, tcpMapping := range tcpMappings {
+		if tcpMapping.Owner != "" {
+			counts[tcpMapping.Owner]++
+		}
+	}
+	return counts, nil
+}
+
+// DrainTcpRouteMapping marks an existing mapping as draining and sets its TTL
+// to drainTTL, after which etcd expires it.
+func (e *EtcdDB) DrainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int) error {
+	return e.drainTcpRouteMapping(tcpMapping, drainTTL, nil)
+}
+
+func (e *EtcdDB) DrainTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, drainTTL int, expected models.ModificationTag) error {
+	return e.drainTcpRouteMapping(tcpMapping, drainTTL, &expected)
+}
+
+func (e *EtcdDB) drainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int, expected *models.ModificationTag) error {
+	key := generateTcpRouteMappingKey(tcpMapping)
+
+	response, err := e.KeysAPI.Get(ctx(), key, readOpts())
+	if err != nil {
+		if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+			if expected != nil {
+				return ModificationTagMismatchError{}
+			}
+			return DBError{Type: KeyNotFound, Message: "The specified route (" + tcpMapping.String() + ") could not be found."}
+		}
+		return err
+	}
+
+	var existingTcpRouteMapping models.TcpRouteMapping
+	err = json.Unmarshal([]byte(response.Node.Value), &existingTcpRouteMapping)
+	if err != nil {
+		return err
+	}
+	if expected != nil && existingTcpRouteMapping.ModificationTag != *expected {
+		return ModificationTagMismatchError{Current: existingTcpRouteMapping.ModificationTag}
+	}
+
+	existingTcpRouteMapping.Drain(drainTTL)
+	tcpRouteJSON, _ := json.Marshal(existingTcpRouteMapping)
+	_, err = e.KeysAPI.Set(ctx(), key, string(tcpRouteJSON), updateOptsWithTTL(drainTTL, response.Node.ModifiedIndex))
+	return e.drainError(key, err, expected)
+}
+
 func generateTcpRouteMappingKey(tcpMapping models.TcpRouteMapping) string {
 	// Generating keys following this pattern
 	// /v1/tcp_routes/router_groups/{router_guid}/{port}/{host-ip}:{host-port}
 	return fmt.Sprintf("%s/%s/%d/%s:%d", TCP_MAPPING_BASE_KEY,
 		tcpMapping.RouterGroupGuid, tcpMapping.ExternalPort, tcpMapping.HostIP, tcpMapping.HostPort)
 }
+
+// Audit records are stored as in-order keys under /v1/audit, so that the
+// etcd index reflects the order in which they were recorded.
+func (e *EtcdDB) SaveAuditRecord(record models.AuditRecord) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = e.KeysAPI.CreateInOrder(ctx(), AUDIT_BASE_KEY, string(recordJSON), &client.CreateInOrderOptions{})
+	return err
+}
+
+// ReadAuditRecords returns the records matching the filter, most recent first.
+func (e *EtcdDB) ReadAuditRecords(filter models.AuditFilter) ([]models.AuditRecord, error) {
+	nodes, err := e.readAuditNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []models.AuditRecord{}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		record := models.AuditRecord{}
+		err = json.Unmarshal([]byte(nodes[i].Value), &record)
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Matches(record) {
+			continue
+		}
+		records = append(records, record)
+		if filter.Limit > 0 && len(records) == filter.Limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// PruneAuditRecords deletes the records older than olderThan and, for a
+// positive maxRecords, the oldest records beyond maxRecords.
+func (e *EtcdDB) PruneAuditRecords(olderThan time.Time, maxRecords int) error {
+	nodes, err := e.readAuditNodes()
+	if err != nil {
+		return err
+	}
+
+	for i, node := range nodes {
+		if maxRecords <= 0 || len(nodes)-i <= maxRecords {
+			record := models.AuditRecord{}
+			err = json.Unmarshal([]byte(node.Value), &record)
+			if err == nil && !record.Time.Before(olderThan) {
+				// records are sorted by creation, so all remaining ones are newer
+				break
+			}
+		}
+
+		_, err = e.KeysAPI.Delete(ctx(), node.Key, &client.DeleteOptions{})
+		if cerr, ok := err.(client.Error); err != nil && (!ok || cerr.Code != client.ErrorCodeKeyNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *EtcdDB) readAuditNodes() (client.Nodes, error) {
+	return e.readInOrderNodes(AUDIT_BASE_KEY)
+}
+
+// Route versions are stored as in-order keys under
+// /v1/history/{kind}/{escaped history key}, oldest first.
+func (e *EtcdDB) SaveRouteVersion(version models.RouteVersion, maxVersions int) error {
+	if version.Time.IsZero() {
+		version.Time = time.Now()
+	}
+
+	versionJSON, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	key := generateHistoryKey(version.Kind, version.Key)
+	_, err = e.KeysAPI.CreateInOrder(ctx(), key, string(versionJSON), &client.CreateInOrderOptions{})
+	if err != nil {
+		return err
+	}
+	if maxVersions <= 0 {
+		return nil
+	}
+
+	nodes, err := e.readInOrderNodes(key)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(nodes)-maxVersions; i++ {
+		_, err = e.KeysAPI.Delete(ctx(), nodes[i].Key, &client.DeleteOptions{})
+		if cerr, ok := err.(client.Error); err != nil && (!ok || cerr.Code != client.ErrorCodeKeyNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRouteVersions returns the versions recorded for the key, most recent
+// first.
+func (e *EtcdDB) ReadRouteVersions(kind, key string) ([]models.RouteVersion, error) {
+	nodes, err := e.readInOrderNodes(generateHistoryKey(kind, key))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []models.RouteVersion{}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		version := models.RouteVersion{}
+		err = json.Unmarshal([]byte(nodes[i].Value), &version)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (e *EtcdDB) PruneRouteVersions(olderThan time.Time) error {
+	kinds, err := e.readInOrderNodes(HISTORY_BASE_KEY)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range kinds {
+		for _, key := range kind.Nodes {
+			pruned := 0
+			for _, node := range key.Nodes {
+				version := models.RouteVersion{}
+				err = json.Unmarshal([]byte(node.Value), &version)
+				if err == nil && !version.Time.Before(olderThan) {
+					// versions are sorted by creation, so all remaining ones are newer
+					break
+				}
+
+				_, err = e.KeysAPI.Delete(ctx(), node.Key, &client.DeleteOptions{})
+				if cerr, ok := err.(client.Error); err != nil && (!ok || cerr.Code != client.ErrorCodeKeyNotFound) {
+					return err
+				}
+				pruned++
+			}
+
+			if pruned == len(key.Nodes) {
+				_, err = e.KeysAPI.Delete(ctx(), key.Key, &client.DeleteOptions{Dir: true})
+				if cerr, ok := err.(client.Error); err != nil && (!ok || (cerr.Code != client.ErrorCodeKeyNotFound && cerr.Code != client.ErrorCodeDirNotEmpty)) {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func generateHistoryKey(kind, key string) string {
+	return fmt.Sprintf("%s/%s/%s", HISTORY_BASE_KEY, kind, url.QueryEscape(key))
+}
+
+func (e *EtcdDB) readInOrderNodes(key string) (client.Nodes, error) {
+	getOpts := &client.GetOptions{
+		Recursive: true,
+		Sort:      true,
+	}
+	response, err := e.KeysAPI.Get(context.Background(), key, getOpts)
+	if err != nil {
+		if cerr, ok := err.(client.Error); ok && cerr.Code == client.ErrorCodeKeyNotFound {
+			return client.Nodes{}, nil
+		}
+		return nil, err
+	}
+	return response.Node.Nodes, nil
+}
@@ -10,20 +10,23 @@ import (
 
 	"github.com/coreos/etcd/Godeps/_workspace/src/golang.org/x/net/context"
 
-	"code.cloudfoundry.org/eventhub"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/routing-api/config"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
 	_ "github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/nu7hatch/gouuid"
 )
 
 type SqlDB struct {
 	Client       Client
-	tcpEventHub  eventhub.Hub
-	httpEventHub eventhub.Hub
+	tcpEventHub  *sequencedHub
+	httpEventHub *sequencedHub
+	revisions    *tableRevisions
+	requestID    string
 }
 
 const DeleteError = "Delete Fails: Route does not exist"
@@ -61,16 +64,23 @@ func NewSqlDB(cfg *config.SqlDB) (*SqlDB, error) {
 		return nil, err
 	}
 
-	tcpEventHub := eventhub.NewNonBlocking(1024)
-	httpEventHub := eventhub.NewNonBlocking(1024)
+	maxEventsPerSubscriber := cfg.MaxEventsPerSubscriber
+	if maxEventsPerSubscriber <= 0 {
+		maxEventsPerSubscriber = DefaultMaxEventsPerSubscriber
+	}
+	tcpEventHub := newSequencedHub(TCP_WATCH, maxEventsPerSubscriber)
+	httpEventHub := newSequencedHub(HTTP_WATCH, maxEventsPerSubscriber)
 
 	return &SqlDB{
 		Client:       NewGormClient(db),
 		tcpEventHub:  tcpEventHub,
 		httpEventHub: httpEventHub,
+		revisions:    newTableRevisions(uint64(time.Now().UnixNano())),
 	}, nil
 }
 
+// CleanupRoutes deletes expired routes every pruningInterval until signalled.
+// Permanent routes never expire.
 func (s *SqlDB) CleanupRoutes(logger lager.Logger, pruningInterval time.Duration, signals <-chan os.Signal) {
 	var tcpInFlight, httpInFlight int32
 	pruningTicker := time.NewTicker(pruningInterval)
@@ -80,7 +90,7 @@ func (s *SqlDB) CleanupRoutes(logger lager.Logger, pruningInterval time.Duration
 			if atomic.CompareAndSwapInt32(&tcpInFlight, 0, 1) {
 				go func() {
 					var tcpRoutes []models.TcpRouteMapping
-					err := s.Client.Find(&tcpRoutes, "expires_at < ?", time.Now())
+					err := s.Client.Find(&tcpRoutes, "expires_at < ? and permanent = ?", time.Now(), false)
 					if err != nil {
 						logger.Error("failed-to-prune-tcp-routes", err)
 						return
@@ -109,7 +119,7 @@ func (s *SqlDB) CleanupRoutes(logger lager.Logger, pruningInterval time.Duration
 			if atomic.CompareAndSwapInt32(&httpInFlight, 0, 1) {
 				go func() {
 					var httpRoutes []models.Route
-					err := s.Client.Find(&httpRoutes, "expires_at < ?", time.Now())
+					err := s.Client.Find(&httpRoutes, "expires_at < ? and permanent = ?", time.Now(), false)
 					if err != nil {
 						logger.Error("failed-to-prune-http-routes", err)
 						return
@@ -179,8 +189,12 @@ func (s *SqlDB) SaveRouterGroup(routerGroup models.RouterGroup) error {
 	} else {
 		_, err = s.Client.Create(&routerGroupDB)
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	s.revisions.increment(ROUTER_GROUPS_TABLE)
+	return nil
 }
 
 func updateRouterGroup(existingRouterGroup, currentRouterGroup *models.RouterGroup) {
@@ -193,13 +207,23 @@ func updateRouterGroup(existingRouterGroup, currentRouterGroup *models.RouterGro
 	if currentRouterGroup.ReservablePorts != "" {
 		existingRouterGroup.ReservablePorts = currentRouterGroup.ReservablePorts
 	}
+	// a TTL of 0 clears the override of the group, as in the etcd database
+	existingRouterGroup.MaxTTL = currentRouterGroup.MaxTTL
+	existingRouterGroup.DefaultTTL = currentRouterGroup.DefaultTTL
 }
 
-func updateTcpRouteMapping(existingTcpRouteMapping models.TcpRouteMapping, currentTcpRouteMapping models.TcpRouteMapping) models.TcpRouteMapping {
+// UpdatedTcpRouteMapping returns the mapping SaveTcpRouteMapping stores when
+// it registers currentTcpRouteMapping again over existingTcpRouteMapping.
+func UpdatedTcpRouteMapping(existingTcpRouteMapping models.TcpRouteMapping, currentTcpRouteMapping models.TcpRouteMapping) models.TcpRouteMapping {
 	existingTcpRouteMapping.ModificationTag.Increment()
 	if currentTcpRouteMapping.TTL != nil {
 		existingTcpRouteMapping.TTL = currentTcpRouteMapping.TTL
 	}
+	if currentTcpRouteMapping.Owner != "" {
+		existingTcpRouteMapping.Owner = currentTcpRouteMapping.Owner
+	}
+	existingTcpRouteMapping.Draining = false
+	existingTcpRouteMapping.Permanent = *existingTcpRouteMapping.TTL == models.PermanentTTL
 
 	existingTcpRouteMapping.ExpiresAt = time.Now().
 		Add(time.Duration(*existingTcpRouteMapping.TTL) * time.Second)
@@ -207,7 +231,9 @@ func updateTcpRouteMapping(existingTcpRouteMapping models.TcpRouteMapping, curre
 	return existingTcpRouteMapping
 }
 
-func updateRoute(existingRoute, currentRoute models.Route) models.Route {
+// UpdatedRoute returns the route SaveRoute stores when it registers
+// currentRoute again over existingRoute.
+func UpdatedRoute(existingRoute, currentRoute models.Route) models.Route {
 	existingRoute.ModificationTag.Increment()
 	if currentRoute.TTL != nil {
 		existingRoute.TTL = currentRoute.TTL
@@ -217,6 +243,14 @@ func updateRoute(existingRoute, currentRoute models.Route) models.Route {
 		existingRoute.LogGuid = currentRoute.LogGuid
 	}
 
+	if currentRoute.Owner != "" {
+		existingRoute.Owner = currentRoute.Owner
+	}
+
+	// registering a draining route again cancels draining
+	existingRoute.Draining = false
+	existingRoute.Permanent = *existingRoute.TTL == models.PermanentTTL
+
 	existingRoute.ExpiresAt = time.Now().
 		Add(time.Duration(*existingRoute.TTL) * time.Second)
 
@@ -232,14 +266,48 @@ func notImplementedError() error {
 func (s *SqlDB) ReadRoutes() ([]models.Route, error) {
 	var routes []models.Route
 	now := time.Now()
-	err := s.Client.Where("expires_at > ?", now).Find(&routes)
+	err := s.Client.Where("expires_at > ? or permanent = ?", now, true).Find(&routes)
 	if err != nil {
 		return nil, err
 	}
 	return routes, err
 }
 
-func (s *SqlDB) readRoute(route models.Route) (models.Route, error) {
+// StreamPageSize is the number of rows StreamRoutes and
+// StreamTcpRouteMappings read at a time.
+const StreamPageSize = 500
+
+// StreamRoutes calls fn for every route, reading them in pages ordered by
+// guid, so the routes are never held in memory together and no query stays
+// open while fn writes them to slow clients.
+func (s *SqlDB) StreamRoutes(fn func(models.Route) error) error {
+	after := ""
+	for {
+		var routes []models.Route
+		err := s.Client.Where("expires_at > ? or permanent = ?", time.Now(), true).
+			Where("guid > ?", after).
+			Order("guid").
+			Limit(StreamPageSize).
+			Find(&routes)
+		if err != nil {
+			return err
+		}
+
+		for _, route := range routes {
+			err = fn(route)
+			if err != nil {
+				return err
+			}
+		}
+		if len(routes) < StreamPageSize {
+			return nil
+		}
+		after = routes[len(routes)-1].Guid
+	}
+}
+
+// Returns a zero-value struct and nil error when the route could not be found.
+func (s *SqlDB) ReadRoute(route models.Route) (models.Route, error) {
 	var routes []models.Route
 	err := s.Client.Where("route = ? and ip = ? and port = ? and route_service_url = ?",
 		route.Route, route.IP, route.Port, route.RouteServiceUrl).Find(&routes)
@@ -257,14 +325,28 @@ func (s *SqlDB) readRoute(route models.Route) (models.Route, error) {
 	return models.Route{}, nil
 }
 
+// ReadRouteByGuid returns the unexpired route with the guid. Returns a
+// zero-value struct and nil error when the route could not be found.
+func (s *SqlDB) ReadRouteByGuid(guid string) (models.Route, error) {
+	var routes []models.Route
+	err := s.Client.Where("guid = ? and (expires_at > ? or permanent = ?)", guid, time.Now(), true).Find(&routes)
+	if err != nil {
+		return models.Route{}, err
+	}
+	if len(routes) == 0 {
+		return models.Route{}, nil
+	}
+	return routes[0], nil
+}
+
 func (s *SqlDB) SaveRoute(route models.Route) error {
-	existingRoute, err := s.readRoute(route)
+	existingRoute, err := s.ReadRoute(route)
 	if err != nil {
 		return err
 	}
 
 	if existingRoute != (models.Route{}) {
-		newRoute := updateRoute(existingRoute, route)
+		newRoute := UpdatedRoute(existingRoute, route)
 		_, err = s.Client.Save(&newRoute)
 		if err != nil {
 			return err
@@ -290,13 +372,46 @@ func (s *SqlDB) SaveRoute(route models.Route) error {
 	return s.emitEvent(CreateEvent, newRoute)
 }
 
+// CreateRoute registers a route that does not exist. It fails with
+// ErrorConflict when the route exists, including when a concurrent request
+// created it after it was read, which the unique index of the table rejects.
+func (s *SqlDB) CreateRoute(route models.Route) error {
+	existingRoute, err := s.ReadRoute(route)
+	if err != nil {
+		return err
+	}
+	if existingRoute != (models.Route{}) {
+		return ErrorConflict
+	}
+
+	newRoute, err := models.NewRouteWithModel(route)
+	if err != nil {
+		return err
+	}
+
+	tag, err := models.NewModificationTag()
+	if err != nil {
+		return err
+	}
+	newRoute.ModificationTag = tag
+
+	_, err = s.Client.Create(&newRoute)
+	if err != nil {
+		if existingRoute, readErr := s.ReadRoute(route); readErr == nil && existingRoute != (models.Route{}) {
+			return ErrorConflict
+		}
+		return err
+	}
+	return s.emitEvent(CreateEvent, newRoute)
+}
+
 func (s *SqlDB) DeleteRoute(route models.Route) error {
-	route, err := s.readRoute(route)
+	route, err := s.ReadRoute(route)
 	if err != nil {
 		return err
 	}
 	if route == (models.Route{}) {
-		return errors.New(DeleteError)
+		return DBError{Type: KeyNotFound, Message: DeleteError}
 	}
 
 	_, err = s.Client.Delete(&route)
@@ -306,17 +421,252 @@ func (s *SqlDB) DeleteRoute(route models.Route) error {
 	return s.emitEvent(DeleteEvent, route)
 }
 
+// SaveRouteIfMatch updates an existing route only if its modification tag is
+// still the expected one. The tag is part of the UPDATE condition, so a
+// concurrent write between the read and the update is detected as a mismatch.
+func (s *SqlDB) SaveRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	existingRoute, err := s.ReadRoute(route)
+	if err != nil {
+		return err
+	}
+	if existingRoute == (models.Route{}) || existingRoute.ModificationTag != expected {
+		return ModificationTagMismatchError{Current: existingRoute.ModificationTag}
+	}
+
+	newRoute := UpdatedRoute(existingRoute, route)
+	rowsAffected, err := s.Client.Model(&models.Route{}).
+		Where("guid = ? and modification_guid = ? and modification_index = ?", existingRoute.Guid, expected.Guid, expected.Index).
+		Update(map[string]interface{}{
+			"ttl":                newRoute.TTL,
+			"log_guid":           newRoute.LogGuid,
+			"owner":              newRoute.Owner,
+			"draining":           newRoute.Draining,
+			"permanent":          newRoute.Permanent,
+			"expires_at":         newRoute.ExpiresAt,
+			"modification_index": newRoute.ModificationTag.Index,
+		})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return s.routeTagMismatch(route)
+	}
+	return s.emitEvent(UpdateEvent, newRoute)
+}
+
+func (s *SqlDB) DeleteRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	existingRoute, err := s.ReadRoute(route)
+	if err != nil {
+		return err
+	}
+	if existingRoute == (models.Route{}) || existingRoute.ModificationTag != expected {
+		return ModificationTagMismatchError{Current: existingRoute.ModificationTag}
+	}
+
+	rowsAffected, err := s.Client.Delete(&models.Route{},
+		"guid = ? and modification_guid = ? and modification_index = ?", existingRoute.Guid, expected.Guid, expected.Index)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return s.routeTagMismatch(route)
+	}
+	return s.emitEvent(DeleteEvent, existingRoute)
+}
+
+// DrainRoute marks an existing route as draining and moves its expiry to
+// drainTTL seconds from now, after which CleanupRoutes removes it.
+func (s *SqlDB) DrainRoute(route models.Route, drainTTL int) error {
+	return s.drainRoute(route, drainTTL, nil)
+}
+
+func (s *SqlDB) DrainRouteIfMatch(route models.Route, drainTTL int, expected models.ModificationTag) error {
+	return s.drainRoute(route, drainTTL, &expected)
+}
+
+func (s *SqlDB) drainRoute(route models.Route, drainTTL int, expected *models.ModificationTag) error {
+	existingRoute, err := s.ReadRoute(route)
+	if err != nil {
+		return err
+	}
+	if existingRoute == (models.Route{}) {
+		if expected != nil {
+			return ModificationTagMismatchError{}
+		}
+		return DBError{Type: KeyNotFound, Message: "The specified route could not be found."}
+	}
+	if expected != nil && existingRoute.ModificationTag != *expected {
+		return ModificationTagMismatchError{Current: existingRoute.ModificationTag}
+	}
+
+	current := existingRoute.ModificationTag
+	existingRoute.Drain(drainTTL)
+	rowsAffected, err := s.Client.Model(&models.Route{}).
+		Where("guid = ? and modification_guid = ? and modification_index = ?", existingRoute.Guid, current.Guid, current.Index).
+		Update(map[string]interface{}{
+			"ttl":                existingRoute.TTL,
+			"draining":           true,
+			"permanent":          false,
+			"expires_at":         existingRoute.ExpiresAt,
+			"modification_index": existingRoute.ModificationTag.Index,
+		})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if expected != nil {
+			return s.routeTagMismatch(route)
+		}
+		return ErrorConflict
+	}
+	return s.emitEvent(DrainEvent, existingRoute)
+}
+
+// DeleteRoutesBySelector deletes every unexpired route matched by the selector
+// in a single transaction and emits a delete event for each of them. With
+// dryRun the matched routes are returned and nothing is deleted.
+func (s *SqlDB) DeleteRoutesBySelector(selector models.RouteSelector, dryRun bool) ([]models.Route, error) {
+	tx := s.Client.Begin()
+
+	routes := []models.Route{}
+	err := selectRoutes(tx, selector).Find(&routes)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if dryRun || len(routes) == 0 {
+		return routes, tx.Rollback()
+	}
+
+	guids := make([]string, 0, len(routes))
+	for _, route := range routes {
+		guids = append(guids, route.Guid)
+	}
+	_, err = tx.Delete(models.Route{}, "guid in (?)", guids)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		err = s.emitEvent(DeleteEvent, route)
+		if err != nil {
+			return routes, err
+		}
+	}
+	return routes, nil
+}
+
+// CountRoutes returns the number of unexpired routes matched by the selector.
+func (s *SqlDB) CountRoutes(selector models.RouteSelector) (int, error) {
+	var count int
+	err := selectRoutes(s.Client.Model(&models.Route{}), selector).Count(&count)
+	return count, err
+}
+
+// CountRoutesPerOwner returns the number of unexpired routes of every owner.
+func (s *SqlDB) CountRoutesPerOwner() (map[string]int, error) {
+	return countPer(selectRoutes(s.Client.Model(&models.Route{}), models.RouteSelector{}), "owner")
+}
+
+// CountRoutesPerLogGuid returns the number of unexpired routes of every log
+// guid.
+func (s *SqlDB) CountRoutesPerLogGuid() (map[string]int, error) {
+	return countPer(selectRoutes(s.Client.Model(&models.Route{}), models.RouteSelector{}), "log_guid")
+}
+
+// countPer counts the rows of query per non-empty value of column.
+func countPer(query Client, column string) (map[string]int, error) {
+	rows, err := query.Where(column+" <> ?", "").
+		Select(column + ", count(*)").
+		Group(column).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var (
+			key   string
+			count int
+		)
+		err = rows.Scan(&key, &count)
+		if err != nil {
+			return nil, err
+		}
+		counts[key] = count
+	}
+	return counts, rows.Err()
+}
+
+func selectRoutes(client Client, selector models.RouteSelector) Client {
+	query := client.Where("expires_at > ? or permanent = ?", time.Now(), true)
+	if selector.LogGuid != "" {
+		query = query.Where("log_guid = ?", selector.LogGuid)
+	}
+	if selector.IP != "" {
+		query = query.Where("ip = ?", selector.IP)
+	}
+	if selector.Owner != "" {
+		query = query.Where("owner = ?", selector.Owner)
+	}
+	return query
+}
+
+func (s *SqlDB) routeTagMismatch(route models.Route) error {
+	current, err := s.ReadRoute(route)
+	if err != nil {
+		return err
+	}
+	return ModificationTagMismatchError{Current: current.ModificationTag}
+}
+
 func (s *SqlDB) ReadTcpRouteMappings() ([]models.TcpRouteMapping, error) {
 	var tcpRoutes []models.TcpRouteMapping
 	now := time.Now()
-	err := s.Client.Where("expires_at > ?", now).Find(&tcpRoutes)
+	err := s.Client.Where("expires_at > ? or permanent = ?", now, true).Find(&tcpRoutes)
 	if err != nil {
 		return nil, err
 	}
 	return tcpRoutes, nil
 }
 
-func (s *SqlDB) readTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error) {
+// StreamTcpRouteMappings calls fn for every tcp route mapping, reading them in
+// pages ordered by guid like StreamRoutes.
+func (s *SqlDB) StreamTcpRouteMappings(fn func(models.TcpRouteMapping) error) error {
+	after := ""
+	for {
+		var tcpMappings []models.TcpRouteMapping
+		err := s.Client.Where("expires_at > ? or permanent = ?", time.Now(), true).
+			Where("guid > ?", after).
+			Order("guid").
+			Limit(StreamPageSize).
+			Find(&tcpMappings)
+		if err != nil {
+			return err
+		}
+
+		for _, tcpMapping := range tcpMappings {
+			err = fn(tcpMapping)
+			if err != nil {
+				return err
+			}
+		}
+		if len(tcpMappings) < StreamPageSize {
+			return nil
+		}
+		after = tcpMappings[len(tcpMappings)-1].Guid
+	}
+}
+
+// Returns a zero-value struct and nil error when the mapping could not be found.
+func (s *SqlDB) ReadTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error) {
 	var routes []models.TcpRouteMapping
 	var tcpRoute models.TcpRouteMapping
 	err := s.Client.Where("host_ip = ? and host_port = ? and external_port = ?",
@@ -336,16 +686,54 @@ func (s *SqlDB) readTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.T
 	return tcpRoute, err
 }
 
+// ReadTcpRouteMappingByGuid returns the unexpired mapping with the guid.
+// Returns a zero-value struct and nil error when the mapping could not be found.
+func (s *SqlDB) ReadTcpRouteMappingByGuid(guid string) (models.TcpRouteMapping, error) {
+	var tcpMappings []models.TcpRouteMapping
+	err := s.Client.Where("guid = ? and (expires_at > ? or permanent = ?)", guid, time.Now(), true).Find(&tcpMappings)
+	if err != nil {
+		return models.TcpRouteMapping{}, err
+	}
+	if len(tcpMappings) == 0 {
+		return models.TcpRouteMapping{}, nil
+	}
+	return tcpMappings[0], nil
+}
+
+// Ping checks that the database can be reached within pingTimeout.
+func (s *SqlDB) Ping() error {
+	return s.Client.Ping()
+}
+
+// withRequestID returns a copy of the database sharing its connection and
+// event hubs that records id in the events it emits.
+func (s *SqlDB) withRequestID(id string) DB {
+	db := *s
+	db.requestID = id
+	return &db
+}
+
 func (s *SqlDB) emitEvent(eventType EventType, obj interface{}) error {
 	event, err := NewEventFromInterface(eventType, obj)
 	if err != nil {
 		return err
 	}
+	event.RequestID = s.requestID
 
-	switch obj.(type) {
+	switch obj := obj.(type) {
 	case models.Route:
+		event.Revision = s.revisions.increment(HTTP_ROUTES_TABLE)
+		event.Protobuf, err = protos.MarshalRouteEvent(obj, protos.EventMetadata{Revision: event.Revision, RequestID: event.RequestID})
+		if err != nil {
+			return err
+		}
 		s.httpEventHub.Emit(event)
 	case models.TcpRouteMapping:
+		event.Revision = s.revisions.increment(TCP_ROUTES_TABLE)
+		event.Protobuf, err = protos.MarshalTcpRouteMappingEvent(obj, protos.EventMetadata{Revision: event.Revision, RequestID: event.RequestID})
+		if err != nil {
+			return err
+		}
 		s.tcpEventHub.Emit(event)
 	default:
 		return errors.New("Unknown event type")
@@ -354,13 +742,13 @@ func (s *SqlDB) emitEvent(eventType EventType, obj interface{}) error {
 }
 
 func (s *SqlDB) SaveTcpRouteMapping(tcpRouteMapping models.TcpRouteMapping) error {
-	existingTcpRouteMapping, err := s.readTcpRouteMapping(tcpRouteMapping)
+	existingTcpRouteMapping, err := s.ReadTcpRouteMapping(tcpRouteMapping)
 	if err != nil {
 		return err
 	}
 
 	if existingTcpRouteMapping != (models.TcpRouteMapping{}) {
-		newTcpRouteMapping := updateTcpRouteMapping(existingTcpRouteMapping, tcpRouteMapping)
+		newTcpRouteMapping := UpdatedTcpRouteMapping(existingTcpRouteMapping, tcpRouteMapping)
 		_, err = s.Client.Save(&newTcpRouteMapping)
 		if err != nil {
 			return err
@@ -387,13 +775,45 @@ func (s *SqlDB) SaveTcpRouteMapping(tcpRouteMapping models.TcpRouteMapping) erro
 	return s.emitEvent(CreateEvent, tcpMapping)
 }
 
+// CreateTcpRouteMapping registers a mapping that does not exist, see
+// CreateRoute.
+func (s *SqlDB) CreateTcpRouteMapping(tcpRouteMapping models.TcpRouteMapping) error {
+	existingTcpRouteMapping, err := s.ReadTcpRouteMapping(tcpRouteMapping)
+	if err != nil {
+		return err
+	}
+	if existingTcpRouteMapping != (models.TcpRouteMapping{}) {
+		return ErrorConflict
+	}
+
+	tcpMapping, err := models.NewTcpRouteMappingWithModel(tcpRouteMapping)
+	if err != nil {
+		return err
+	}
+
+	tag, err := models.NewModificationTag()
+	if err != nil {
+		return err
+	}
+	tcpMapping.ModificationTag = tag
+
+	_, err = s.Client.Create(&tcpMapping)
+	if err != nil {
+		if existing, readErr := s.ReadTcpRouteMapping(tcpRouteMapping); readErr == nil && existing != (models.TcpRouteMapping{}) {
+			return ErrorConflict
+		}
+		return err
+	}
+	return s.emitEvent(CreateEvent, tcpMapping)
+}
+
 func (s *SqlDB) DeleteTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
-	tcpMapping, err := s.readTcpRouteMapping(tcpMapping)
+	tcpMapping, err := s.ReadTcpRouteMapping(tcpMapping)
 	if err != nil {
 		return err
 	}
 	if tcpMapping == (models.TcpRouteMapping{}) {
-		return errors.New(DeleteError)
+		return DBError{Type: KeyNotFound, Message: DeleteError}
 	}
 
 	_, err = s.Client.Delete(&tcpMapping)
@@ -403,6 +823,300 @@ func (s *SqlDB) DeleteTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
 	return s.emitEvent(DeleteEvent, tcpMapping)
 }
 
+func (s *SqlDB) SaveTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	existingTcpRouteMapping, err := s.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		return err
+	}
+	if existingTcpRouteMapping == (models.TcpRouteMapping{}) || existingTcpRouteMapping.ModificationTag != expected {
+		return ModificationTagMismatchError{Current: existingTcpRouteMapping.ModificationTag}
+	}
+
+	newTcpRouteMapping := UpdatedTcpRouteMapping(existingTcpRouteMapping, tcpMapping)
+	rowsAffected, err := s.Client.Model(&models.TcpRouteMapping{}).
+		Where("guid = ? and modification_guid = ? and modification_index = ?", existingTcpRouteMapping.Guid, expected.Guid, expected.Index).
+		Update(map[string]interface{}{
+			"ttl":                newTcpRouteMapping.TTL,
+			"owner":              newTcpRouteMapping.Owner,
+			"draining":           newTcpRouteMapping.Draining,
+			"permanent":          newTcpRouteMapping.Permanent,
+			"expires_at":         newTcpRouteMapping.ExpiresAt,
+			"modification_index": newTcpRouteMapping.ModificationTag.Index,
+		})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return s.tcpRouteMappingTagMismatch(tcpMapping)
+	}
+	return s.emitEvent(UpdateEvent, newTcpRouteMapping)
+}
+
+func (s *SqlDB) DeleteTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	existingTcpRouteMapping, err := s.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		return err
+	}
+	if existingTcpRouteMapping == (models.TcpRouteMapping{}) || existingTcpRouteMapping.ModificationTag != expected {
+		return ModificationTagMismatchError{Current: existingTcpRouteMapping.ModificationTag}
+	}
+
+	rowsAffected, err := s.Client.Delete(&models.TcpRouteMapping{},
+		"guid = ? and modification_guid = ? and modification_index = ?", existingTcpRouteMapping.Guid, expected.Guid, expected.Index)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return s.tcpRouteMappingTagMismatch(tcpMapping)
+	}
+	return s.emitEvent(DeleteEvent, existingTcpRouteMapping)
+}
+
+// DrainTcpRouteMapping marks an existing mapping as draining and moves its
+// expiry to drainTTL seconds from now, after which CleanupRoutes removes it.
+func (s *SqlDB) DrainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int) error {
+	return s.drainTcpRouteMapping(tcpMapping, drainTTL, nil)
+}
+
+func (s *SqlDB) DrainTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, drainTTL int, expected models.ModificationTag) error {
+	return s.drainTcpRouteMapping(tcpMapping, drainTTL, &expected)
+}
+
+func (s *SqlDB) drainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int, expected *models.ModificationTag) error {
+	existingTcpRouteMapping, err := s.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		return err
+	}
+	if existingTcpRouteMapping == (models.TcpRouteMapping{}) {
+		if expected != nil {
+			return ModificationTagMismatchError{}
+		}
+		return DBError{Type: KeyNotFound, Message: "The specified route (" + tcpMapping.String() + ") could not be found."}
+	}
+	if expected != nil && existingTcpRouteMapping.ModificationTag != *expected {
+		return ModificationTagMismatchError{Current: existingTcpRouteMapping.ModificationTag}
+	}
+
+	current := existingTcpRouteMapping.ModificationTag
+	existingTcpRouteMapping.Drain(drainTTL)
+	rowsAffected, err := s.Client.Model(&models.TcpRouteMapping{}).
+		Where("guid = ? and modification_guid = ? and modification_index = ?", existingTcpRouteMapping.Guid, current.Guid, current.Index).
+		Update(map[string]interface{}{
+			"ttl":                existingTcpRouteMapping.TTL,
+			"draining":           true,
+			"permanent":          false,
+			"expires_at":         existingTcpRouteMapping.ExpiresAt,
+			"modification_index": existingTcpRouteMapping.ModificationTag.Index,
+		})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if expected != nil {
+			return s.tcpRouteMappingTagMismatch(tcpMapping)
+		}
+		return ErrorConflict
+	}
+	return s.emitEvent(DrainEvent, existingTcpRouteMapping)
+}
+
+// DeleteTcpRouteMappingsBySelector deletes every unexpired mapping matched by
+// the selector, see DeleteRoutesBySelector.
+func (s *SqlDB) DeleteTcpRouteMappingsBySelector(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error) {
+	tx := s.Client.Begin()
+
+	tcpMappings := []models.TcpRouteMapping{}
+	err := selectTcpRouteMappings(tx, selector).Find(&tcpMappings)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if dryRun || len(tcpMappings) == 0 {
+		return tcpMappings, tx.Rollback()
+	}
+
+	guids := make([]string, 0, len(tcpMappings))
+	for _, tcpMapping := range tcpMappings {
+		guids = append(guids, tcpMapping.Guid)
+	}
+	_, err = tx.Delete(models.TcpRouteMapping{}, "guid in (?)", guids)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tcpMapping := range tcpMappings {
+		err = s.emitEvent(DeleteEvent, tcpMapping)
+		if err != nil {
+			return tcpMappings, err
+		}
+	}
+	return tcpMappings, nil
+}
+
+// CountTcpRouteMappings returns the number of unexpired mappings matched by
+// the selector.
+func (s *SqlDB) CountTcpRouteMappings(selector models.TcpRouteMappingSelector) (int, error) {
+	var count int
+	err := selectTcpRouteMappings(s.Client.Model(&models.TcpRouteMapping{}), selector).Count(&count)
+	return count, err
+}
+
+// CountTcpRouteMappingsPerOwner returns the number of unexpired mappings of
+// every owner.
+func (s *SqlDB) CountTcpRouteMappingsPerOwner() (map[string]int, error) {
+	return countPer(selectTcpRouteMappings(s.Client.Model(&models.TcpRouteMapping{}), models.TcpRouteMappingSelector{}), "owner")
+}
+
+func selectTcpRouteMappings(client Client, selector models.TcpRouteMappingSelector) Client {
+	query := client.Where("expires_at > ? or permanent = ?", time.Now(), true)
+	if selector.RouterGroupGuid != "" {
+		query = query.Where("router_group_guid = ?", selector.RouterGroupGuid)
+	}
+	if selector.HostIP != "" {
+		query = query.Where("host_ip = ?", selector.HostIP)
+	}
+	if selector.Owner != "" {
+		query = query.Where("owner = ?", selector.Owner)
+	}
+	return query
+}
+
+func (s *SqlDB) tcpRouteMappingTagMismatch(tcpMapping models.TcpRouteMapping) error {
+	current, err := s.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		return err
+	}
+	return ModificationTagMismatchError{Current: current.ModificationTag}
+}
+
+func (s *SqlDB) SaveAuditRecord(record models.AuditRecord) error {
+	if record.Guid == "" {
+		guid, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		record.Guid = guid.String()
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	_, err := s.Client.Create(&record)
+	return err
+}
+
+// ReadAuditRecords returns the records matching the filter, most recent first.
+func (s *SqlDB) ReadAuditRecords(filter models.AuditFilter) ([]models.AuditRecord, error) {
+	query := s.Client.Order("time desc")
+	if !filter.Since.IsZero() {
+		query = query.Where("time >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("time <= ?", filter.Until)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Key != "" {
+		query = query.Where("audit_key = ?", filter.Key)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	records := []models.AuditRecord{}
+	err := query.Find(&records)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// PruneAuditRecords deletes the records older than olderThan and, for a
+// positive maxRecords, the oldest records beyond maxRecords.
+func (s *SqlDB) PruneAuditRecords(olderThan time.Time, maxRecords int) error {
+	_, err := s.Client.Delete(models.AuditRecord{}, "time < ?", olderThan)
+	if err != nil || maxRecords <= 0 {
+		return err
+	}
+
+	// the guid breaks ties between records of the same time, so that exactly
+	// maxRecords remain
+	cutoff := []models.AuditRecord{}
+	err = s.Client.Select("guid, time").Order("time desc, guid desc").Offset(maxRecords).Limit(1).Find(&cutoff)
+	if err != nil || len(cutoff) == 0 {
+		return err
+	}
+	_, err = s.Client.Delete(models.AuditRecord{}, "time < ? or (time = ? and guid <= ?)", cutoff[0].Time, cutoff[0].Time, cutoff[0].Guid)
+	return err
+}
+
+// SaveRouteVersion records the version and deletes the oldest versions of the
+// same key beyond maxVersions.
+func (s *SqlDB) SaveRouteVersion(version models.RouteVersion, maxVersions int) error {
+	if version.Model.Guid == "" {
+		guid, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		version.Model.Guid = guid.String()
+	}
+	if version.Time.IsZero() {
+		version.Time = time.Now()
+	}
+
+	_, err := s.Client.Create(&version)
+	if err != nil {
+		return err
+	}
+	if maxVersions <= 0 {
+		return nil
+	}
+
+	versions, err := s.ReadRouteVersions(version.Kind, version.Key)
+	if err != nil {
+		return err
+	}
+	if len(versions) <= maxVersions {
+		return nil
+	}
+
+	guids := []string{}
+	for _, old := range versions[maxVersions:] {
+		guids = append(guids, old.Model.Guid)
+	}
+	_, err = s.Client.Delete(models.RouteVersion{}, "guid in (?)", guids)
+	return err
+}
+
+// ReadRouteVersions returns the versions recorded for the key, most recent
+// first.
+func (s *SqlDB) ReadRouteVersions(kind, key string) ([]models.RouteVersion, error) {
+	versions := []models.RouteVersion{}
+	err := s.Client.Where("kind = ? and history_key = ?", kind, key).
+		Order("time desc").
+		Order("modification_index desc").
+		Find(&versions)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (s *SqlDB) PruneRouteVersions(olderThan time.Time) error {
+	_, err := s.Client.Delete(models.RouteVersion{}, "time < ?", olderThan)
+	return err
+}
+
+func (s *SqlDB) ReadRevision(table string) (uint64, error) {
+	return s.revisions.read(table)
+}
+
 func (s *SqlDB) Connect() error {
 	return notImplementedError()
 }
@@ -414,31 +1128,16 @@ func (s *SqlDB) CancelWatches() {
 }
 
 func (s *SqlDB) WatchChanges(watchType string) (<-chan Event, <-chan error, context.CancelFunc) {
-	var (
-		sub eventhub.Source
-		err error
-	)
+	var hub *sequencedHub
 	events := make(chan Event)
 	errors := make(chan error, 1)
 	cancelFunc := func() {}
 
 	switch watchType {
 	case TCP_WATCH:
-		sub, err = s.tcpEventHub.Subscribe()
-		if err != nil {
-			errors <- err
-			close(events)
-			close(errors)
-			return events, errors, cancelFunc
-		}
+		hub = s.tcpEventHub
 	case HTTP_WATCH:
-		sub, err = s.httpEventHub.Subscribe()
-		if err != nil {
-			errors <- err
-			close(events)
-			close(errors)
-			return events, errors, cancelFunc
-		}
+		hub = s.httpEventHub
 	default:
 		err := fmt.Errorf("Invalid watch type: %s", watchType)
 		errors <- err
@@ -447,36 +1146,23 @@ func (s *SqlDB) WatchChanges(watchType string) (<-chan Event, <-chan error, cont
 		return events, errors, cancelFunc
 	}
 
+	sub, last, err := hub.Subscribe()
+	if err != nil {
+		errors <- err
+		close(events)
+		close(errors)
+		return events, errors, cancelFunc
+	}
+
 	cancelFunc = func() {
 		_ = sub.Close()
 	}
 
-	go dispatchWatchEvents(sub, events, errors)
+	go hub.dispatch(sub, last, events, errors)
 
 	return events, errors, cancelFunc
 }
 
-func dispatchWatchEvents(sub eventhub.Source, events chan<- Event, errors chan<- error) {
-	defer close(events)
-	defer close(errors)
-	for {
-		event, err := sub.Next()
-		if err != nil {
-			if err == eventhub.ErrReadFromClosedSource {
-				return
-			}
-			errors <- err
-			return
-		}
-		watchEvent, ok := event.(Event)
-		if !ok {
-			errors <- fmt.Errorf("Incoming event is not a db.Event: %#v", event)
-		}
-
-		events <- watchEvent
-	}
-}
-
 func recordNotFound(err error) bool {
 	if err == gorm.ErrRecordNotFound {
 		return true
@@ -1,8 +1,11 @@
 package db_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -40,6 +43,11 @@ var _ = Describe("SqlDB", func() {
 				Expect(sqlDB).ToNot(BeNil())
 			})
 
+			It("can be pinged", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sqlDB.Ping()).To(Succeed())
+			})
+
 			Context("when config is nil", func() {
 				BeforeEach(func() {
 					sqlCfg = nil
@@ -76,6 +84,45 @@ var _ = Describe("SqlDB", func() {
 		})
 	}
 
+	StreamRoutes := func() {
+		Describe("StreamRoutes", func() {
+			It("reads the routes in pages", func() {
+				fakeClient := &fakes.FakeClient{}
+				fakeClient.WhereReturns(fakeClient)
+				fakeClient.OrderReturns(fakeClient)
+				fakeClient.LimitReturns(fakeClient)
+				fakeClient.FindStub = func(out interface{}, where ...interface{}) error {
+					size := db.StreamPageSize
+					if fakeClient.FindCallCount() > 1 {
+						size = 1
+					}
+					page := out.(*[]models.Route)
+					for i := 0; i < size; i++ {
+						route := models.NewRoute("post_here", 7000, "127.0.0.1", "", "", 60)
+						route.Guid = fmt.Sprintf("guid-%d-%04d", fakeClient.FindCallCount(), i)
+						*page = append(*page, route)
+					}
+					return nil
+				}
+				sqlDB.Client = fakeClient
+
+				streamed := 0
+				err := sqlDB.StreamRoutes(func(route models.Route) error {
+					streamed++
+					return nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(streamed).To(Equal(db.StreamPageSize + 1))
+				Expect(fakeClient.FindCallCount()).To(Equal(2))
+
+				query, args := fakeClient.WhereArgsForCall(3)
+				Expect(query).To(Equal("guid > ?"))
+				Expect(args).To(Equal([]interface{}{fmt.Sprintf("guid-1-%04d", db.StreamPageSize-1)}))
+				Expect(fakeClient.LimitArgsForCall(1)).To(Equal(db.StreamPageSize))
+			})
+		})
+	}
+
 	ReadRouterGroups := func() {
 		Describe("ReadRouterGroups", func() {
 			var (
@@ -238,6 +285,35 @@ var _ = Describe("SqlDB", func() {
 					Expect(rg.ReservablePorts).To(Equal(routerGroup.ReservablePorts))
 					Expect(rg.Type).To(Equal(routerGroup.Type))
 				})
+
+				It("updates the ttl policy of the router group", func() {
+					routerGroup.MaxTTL = 300
+					routerGroup.DefaultTTL = 30
+					err = sqlDB.SaveRouterGroup(routerGroup)
+					Expect(err).ToNot(HaveOccurred())
+					rg, err := sqlDB.ReadRouterGroup(routerGroup.Guid)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(rg.MaxTTL).To(Equal(300))
+					Expect(rg.DefaultTTL).To(Equal(30))
+				})
+
+				It("clears the ttl overrides of the router group", func() {
+					routerGroup.MaxTTL = 300
+					routerGroup.DefaultTTL = 30
+					err = sqlDB.SaveRouterGroup(routerGroup)
+					Expect(err).ToNot(HaveOccurred())
+
+					routerGroup.MaxTTL = 0
+					routerGroup.DefaultTTL = 0
+					err = sqlDB.SaveRouterGroup(routerGroup)
+					Expect(err).ToNot(HaveOccurred())
+					rg, err := sqlDB.ReadRouterGroup(routerGroup.Guid)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(rg.MaxTTL).To(Equal(0))
+					Expect(rg.DefaultTTL).To(Equal(0))
+				})
 			})
 
 			Context("when router group doesn't exist", func() {
@@ -372,6 +448,13 @@ var _ = Describe("SqlDB", func() {
 					Expect(tcpRoutes[0].TcpMappingEntity).To(Equal(tcpRoute.TcpMappingEntity))
 				})
 
+				It("reads the tcp route by its guid", func() {
+					tcpMapping, err := sqlDB.ReadTcpRouteMappingByGuid(tcpRouteWithModel.Guid)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(tcpMapping.Guid).To(Equal(tcpRouteWithModel.Guid))
+					Expect(tcpMapping.TcpMappingEntity).To(Equal(tcpRoute.TcpMappingEntity))
+				})
+
 				Context("when tcp routes have outlived their ttl", func() {
 					var (
 						routerGroupId            string
@@ -405,6 +488,23 @@ var _ = Describe("SqlDB", func() {
 						Expect(tcpRoutes).To(HaveLen(1))
 						Expect(tcpRoutes[0].TcpMappingEntity).To(Equal(tcpRoute.TcpMappingEntity))
 					})
+
+					It("streams only the live tcp routes", func() {
+						var streamed []models.TcpRouteMapping
+						err := sqlDB.StreamTcpRouteMappings(func(tcpMapping models.TcpRouteMapping) error {
+							streamed = append(streamed, tcpMapping)
+							return nil
+						})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(streamed).To(HaveLen(1))
+						Expect(streamed[0].TcpMappingEntity).To(Equal(tcpRoute.TcpMappingEntity))
+					})
+
+					It("does not read the expired tcp route by its guid", func() {
+						tcpMapping, err := sqlDB.ReadTcpRouteMappingByGuid(expiredTcpRouteWithModel.Guid)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(tcpMapping).To(Equal(models.TcpRouteMapping{}))
+					})
 				})
 			})
 
@@ -491,6 +591,7 @@ var _ = Describe("SqlDB", func() {
 				It("returns an error", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err).Should(MatchError(db.DeleteError))
+					Expect(err).To(Equal(db.DBError{Type: db.KeyNotFound, Message: db.DeleteError}))
 				})
 			})
 		})
@@ -528,6 +629,18 @@ var _ = Describe("SqlDB", func() {
 					Expect(dbRoute.ModificationTag.Index).To(BeNumerically("==", 1))
 				})
 
+				It("increments the revision of the http routes table", func() {
+					before, err := sqlDB.ReadRevision(db.HTTP_ROUTES_TABLE)
+					Expect(err).ToNot(HaveOccurred())
+
+					err = sqlDB.SaveRoute(httpRoute)
+					Expect(err).ToNot(HaveOccurred())
+
+					after, err := sqlDB.ReadRevision(db.HTTP_ROUTES_TABLE)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(after).To(BeNumerically(">", before))
+				})
+
 				It("refreshes the expiration time of the route", func() {
 					var dbRoute models.Route
 					var ttl = 9
@@ -583,6 +696,61 @@ var _ = Describe("SqlDB", func() {
 				})
 			})
 		})
+
+		Describe("CreateRoute", func() {
+			var httpRoute models.Route
+
+			BeforeEach(func() {
+				httpRoute = models.NewRoute("create_here", 7000, "127.0.0.1", "my-guid", "", 5)
+			})
+
+			AfterEach(func() {
+				_, err := sqlDB.Client.Delete(&httpRoute)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("creates the route", func() {
+				err := sqlDB.CreateRoute(httpRoute)
+				Expect(err).ToNot(HaveOccurred())
+
+				dbRoute, err := sqlDB.ReadRoute(httpRoute)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dbRoute.Guid).ToNot(BeEmpty())
+				Expect(dbRoute.ModificationTag.Index).To(BeNumerically("==", 0))
+			})
+
+			It("returns a conflict when the route exists", func() {
+				err := sqlDB.CreateRoute(httpRoute)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = sqlDB.CreateRoute(httpRoute)
+				Expect(err).To(Equal(db.ErrorConflict))
+			})
+
+			It("creates the route only once when created concurrently", func() {
+				var created, conflicts int32
+				done := make(chan struct{})
+				for i := 0; i < 5; i++ {
+					go func() {
+						defer GinkgoRecover()
+						defer func() { done <- struct{}{} }()
+						err := sqlDB.CreateRoute(httpRoute)
+						if err == db.ErrorConflict {
+							atomic.AddInt32(&conflicts, 1)
+						} else {
+							Expect(err).ToNot(HaveOccurred())
+							atomic.AddInt32(&created, 1)
+						}
+					}()
+				}
+				for i := 0; i < 5; i++ {
+					<-done
+				}
+
+				Expect(created).To(BeNumerically("==", 1))
+				Expect(conflicts).To(BeNumerically("==", 4))
+			})
+		})
 	}
 
 	ReadRoute := func() {
@@ -623,6 +791,14 @@ var _ = Describe("SqlDB", func() {
 					Expect(routes[0]).To(matchers.MatchHttpRoute(routeWithModel))
 				})
 
+				It("reads the route by its guid", func() {
+					route, err := sqlDB.ReadRouteByGuid(routeWithModel.Guid)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(route).To(matchers.MatchHttpRoute(routeWithModel))
+					Expect(route.Guid).To(Equal(routeWithModel.Guid))
+					Expect(route.CreatedAt).NotTo(BeZero())
+				})
+
 				Context("when http routes have outlived their ttl", func() {
 					var (
 						expiredRoute          models.Route
@@ -655,6 +831,56 @@ var _ = Describe("SqlDB", func() {
 						Expect(routes).To(HaveLen(1))
 						Expect(routes[0]).To(matchers.MatchHttpRoute(route))
 					})
+
+					It("streams only the live routes", func() {
+						var streamed []models.Route
+						err := sqlDB.StreamRoutes(func(route models.Route) error {
+							streamed = append(streamed, route)
+							return nil
+						})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(streamed).To(HaveLen(1))
+						Expect(streamed[0]).To(matchers.MatchHttpRoute(route))
+					})
+
+					It("stops streaming when the callback fails", func() {
+						calls := 0
+						err := sqlDB.StreamRoutes(func(route models.Route) error {
+							calls++
+							return errors.New("write failed")
+						})
+						Expect(err).To(MatchError("write failed"))
+						Expect(calls).To(Equal(1))
+					})
+
+					It("does not read the expired route by its guid", func() {
+						route, err := sqlDB.ReadRouteByGuid(expiredRouteWithModel.Guid)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(route).To(Equal(models.Route{}))
+					})
+				})
+
+				Context("when http routes are permanent", func() {
+					var permanentRouteWithModel models.Route
+
+					BeforeEach(func() {
+						permanentRoute := models.NewRoute("post_here", 7002, "127.0.0.1", "my-guid", "https://rs.com", models.PermanentTTL)
+						permanentRouteWithModel, err = models.NewRouteWithModel(permanentRoute)
+						Expect(err).NotTo(HaveOccurred())
+						_, err = sqlDB.Client.Create(&permanentRouteWithModel)
+						Expect(err).ToNot(HaveOccurred())
+					})
+
+					AfterEach(func() {
+						_, err = sqlDB.Client.Delete(&permanentRouteWithModel)
+						Expect(err).ToNot(HaveOccurred())
+					})
+
+					It("returns the permanent routes", func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(routes).To(HaveLen(2))
+						Expect(routes).To(ContainElement(matchers.MatchHttpRoute(permanentRouteWithModel)))
+					})
 				})
 			})
 
@@ -663,6 +889,12 @@ var _ = Describe("SqlDB", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(routes).To(Equal([]models.Route{}))
 				})
+
+				It("returns a zero-value route when reading by guid", func() {
+					route, err := sqlDB.ReadRouteByGuid("unknown-guid")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(route).To(Equal(models.Route{}))
+				})
 			})
 		})
 	}
@@ -743,6 +975,7 @@ var _ = Describe("SqlDB", func() {
 				It("returns an error", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err).Should(MatchError(db.DeleteError))
+					Expect(err).To(Equal(db.DBError{Type: db.KeyNotFound, Message: db.DeleteError}))
 				})
 			})
 		})
@@ -922,6 +1155,67 @@ var _ = Describe("SqlDB", func() {
 				})
 			})
 
+			Context("when a http route is saved with a request id", func() {
+				It("records the request id in the event", func() {
+					results, _, _ := sqlDB.WatchChanges(db.HTTP_WATCH)
+
+					httpRoute := models.NewRoute("post_here", 7004, "127.0.0.1", "my-guid", "https://rs.com", 5)
+					err := db.WithRequestID(sqlDB, "some-request-id").SaveRoute(httpRoute)
+					Expect(err).NotTo(HaveOccurred())
+
+					var event db.Event
+					Eventually(results).Should((Receive(&event)))
+					Expect(event.Type).To(Equal(db.CreateEvent))
+					Expect(event.RequestID).To(Equal("some-request-id"))
+
+					err = sqlDB.SaveRoute(httpRoute)
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(results).Should((Receive(&event)))
+					Expect(event.RequestID).To(BeEmpty())
+				})
+			})
+
+			Context("when the subscriber falls behind", func() {
+				var slowDB *db.SqlDB
+
+				BeforeEach(func() {
+					cfg := *sqlCfg
+					cfg.MaxEventsPerSubscriber = 2
+					slowDB, err = db.NewSqlDB(&cfg)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("tells it to resync and keeps sending the latest events", func() {
+					droppedBefore := db.GetEventsDropped(db.HTTP_WATCH)
+					results, _, _ := slowDB.WatchChanges(db.HTTP_WATCH)
+
+					for port := uint16(7010); port < 7016; port++ {
+						err := slowDB.SaveRoute(models.NewRoute("post_here", port, "127.0.0.1", "my-guid", "", 5))
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					var (
+						event    db.Event
+						dropped  uint64
+						received uint64
+					)
+					for !strings.Contains(event.Value, `"port":7015`) {
+						Eventually(results).Should(Receive(&event))
+						if event.Type == db.ResyncEvent {
+							var data struct{ Dropped uint64 }
+							Expect(json.Unmarshal([]byte(event.Value), &data)).To(Succeed())
+							dropped += data.Dropped
+						} else {
+							received++
+						}
+					}
+
+					Expect(dropped).To(BeNumerically(">", 0))
+					Expect(dropped + received).To(BeNumerically("==", 6))
+					Expect(db.GetEventsDropped(db.HTTP_WATCH) - droppedBefore).To(BeNumerically("==", dropped))
+				})
+			})
+
 			Context("when a http route is deleted", func() {
 				It("should return an delete watch event", func() {
 					httpRoute := models.NewRoute("post_here", 7003, "127.0.0.1", "my-guid", "https://rs.com", 5)
@@ -1088,6 +1382,32 @@ var _ = Describe("SqlDB", func() {
 							Expect(tcpRoutes[0]).To(matchers.MatchTcpRoute(tcpRoute))
 						})
 					})
+
+					Context("when permanent routes exist", func() {
+						BeforeEach(func() {
+							tcpRoute := models.NewTcpRouteMapping("guid", 3557, "127.0.0.1", 7879, models.PermanentTTL)
+							err := sqlDB.SaveTcpRouteMapping(tcpRoute)
+							Expect(err).ToNot(HaveOccurred())
+						})
+
+						It("should not prune the permanent routes", func() {
+							var tcpRoutes []models.TcpRouteMapping
+
+							Eventually(func() []models.TcpRouteMapping {
+								err := sqlDB.Client.Where("host_ip = ?", "127.0.0.1").Find(&tcpRoutes)
+								Expect(err).ToNot(HaveOccurred())
+								return tcpRoutes
+							}, 5).Should(HaveLen(1))
+							Consistently(func() []models.TcpRouteMapping {
+								err := sqlDB.Client.Where("host_ip = ?", "127.0.0.1").Find(&tcpRoutes)
+								Expect(err).ToNot(HaveOccurred())
+								return tcpRoutes
+							}, 1).Should(HaveLen(1))
+
+							Expect(tcpRoutes[0].ExternalPort).To(Equal(uint16(3557)))
+							Expect(tcpRoutes[0].Permanent).To(BeTrue())
+						})
+					})
 				})
 			})
 
@@ -1153,6 +1473,32 @@ var _ = Describe("SqlDB", func() {
 							Expect(httpRoutes[0]).To(matchers.MatchHttpRoute(httpRoute))
 						})
 					})
+
+					Context("when permanent routes exist", func() {
+						BeforeEach(func() {
+							httpRoute := models.NewRoute("post_here", 7002, "127.0.0.1", "my-guid", "https://rs.com", models.PermanentTTL)
+							err := sqlDB.SaveRoute(httpRoute)
+							Expect(err).ToNot(HaveOccurred())
+						})
+
+						It("should not prune the permanent routes", func() {
+							var httpRoutes []models.Route
+
+							Eventually(func() []models.Route {
+								err := sqlDB.Client.Where("ip = ?", "127.0.0.1").Find(&httpRoutes)
+								Expect(err).ToNot(HaveOccurred())
+								return httpRoutes
+							}, 5).Should(HaveLen(1))
+							Consistently(func() []models.Route {
+								err := sqlDB.Client.Where("ip = ?", "127.0.0.1").Find(&httpRoutes)
+								Expect(err).ToNot(HaveOccurred())
+								return httpRoutes
+							}, 1).Should(HaveLen(1))
+
+							Expect(httpRoutes[0].Port).To(Equal(uint16(7002)))
+							Expect(httpRoutes[0].Permanent).To(BeTrue())
+						})
+					})
 				})
 			})
 
@@ -1175,6 +1521,447 @@ var _ = Describe("SqlDB", func() {
 		})
 	}
 
+	RouteVersions := func() {
+		Describe("RouteVersions", func() {
+			var (
+				now  time.Time
+				key  string
+				ttls []int
+			)
+
+			BeforeEach(func() {
+				now = time.Now().Truncate(time.Second)
+				key = "a.example.com,1.2.3.4:8080"
+				ttls = []int{60, 120, 30}
+
+				for i := range ttls {
+					err := sqlDB.SaveRouteVersion(models.RouteVersion{
+						Kind:            models.HistoryKindHttpRoute,
+						Key:             key,
+						Time:            now.Add(time.Duration(i-3) * time.Minute),
+						Action:          models.RouteVersionUpdated,
+						ModificationTag: models.ModificationTag{Guid: "some-guid", Index: uint32(i)},
+						TTL:             &ttls[i],
+						LogGuid:         "log-guid",
+					}, 0)
+					Expect(err).ToNot(HaveOccurred())
+				}
+			})
+
+			AfterEach(func() {
+				_, err := sqlDB.Client.Delete(models.RouteVersion{}, "history_key = ?", key)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns the versions of the key, most recent first", func() {
+				versions, err := sqlDB.ReadRouteVersions(models.HistoryKindHttpRoute, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(versions).To(HaveLen(3))
+				Expect(versions[0].ModificationTag.Index).To(BeNumerically("==", 2))
+				Expect(*versions[0].TTL).To(Equal(30))
+				Expect(versions[0].LogGuid).To(Equal("log-guid"))
+				Expect(versions[2].ModificationTag.Index).To(BeNumerically("==", 0))
+			})
+
+			It("does not return versions of other keys or kinds", func() {
+				versions, err := sqlDB.ReadRouteVersions(models.HistoryKindTcpRoute, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(versions).To(BeEmpty())
+
+				versions, err = sqlDB.ReadRouteVersions(models.HistoryKindHttpRoute, "b.example.com,1.2.3.4:8080")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(versions).To(BeEmpty())
+			})
+
+			Context("when the number of versions exceeds the maximum", func() {
+				It("deletes the oldest versions", func() {
+					ttl := 90
+					err := sqlDB.SaveRouteVersion(models.RouteVersion{
+						Kind:            models.HistoryKindHttpRoute,
+						Key:             key,
+						Time:            now,
+						Action:          models.RouteVersionDeleted,
+						ModificationTag: models.ModificationTag{Guid: "some-guid", Index: 3},
+						TTL:             &ttl,
+					}, 2)
+					Expect(err).ToNot(HaveOccurred())
+
+					versions, err := sqlDB.ReadRouteVersions(models.HistoryKindHttpRoute, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(versions).To(HaveLen(2))
+					Expect(versions[0].Action).To(Equal(models.RouteVersionDeleted))
+					Expect(versions[1].ModificationTag.Index).To(BeNumerically("==", 2))
+				})
+			})
+		})
+	}
+
+	AuditRecords := func() {
+		Describe("AuditRecords", func() {
+			var (
+				now     time.Time
+				records []models.AuditRecord
+				filter  models.AuditFilter
+				err     error
+			)
+
+			BeforeEach(func() {
+				now = time.Now().Truncate(time.Second)
+				filter = models.AuditFilter{}
+
+				err = sqlDB.SaveAuditRecord(models.AuditRecord{
+					Time:   now.Add(-2 * time.Hour),
+					Action: models.AuditActionUpsert,
+					Kind:   models.AuditKindHttpRoute,
+					Key:    "a.example.com",
+					Actor:  "alice",
+					After:  models.AuditValue(`{"route":"a.example.com"}`),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				err = sqlDB.SaveAuditRecord(models.AuditRecord{
+					Time:   now.Add(-1 * time.Hour),
+					Action: models.AuditActionDelete,
+					Kind:   models.AuditKindHttpRoute,
+					Key:    "b.example.com",
+					Actor:  "bob",
+					Before: models.AuditValue(`{"route":"b.example.com"}`),
+				})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				_, err = sqlDB.Client.Delete(models.AuditRecord{}, "actor in (?)", []string{"alice", "bob"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			JustBeforeEach(func() {
+				records, err = sqlDB.ReadAuditRecords(filter)
+			})
+
+			It("returns all records, most recent first", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(records).To(HaveLen(2))
+				Expect(records[0].Actor).To(Equal("bob"))
+				Expect(records[0].Before).To(Equal(models.AuditValue(`{"route":"b.example.com"}`)))
+				Expect(records[0].Guid).ToNot(BeEmpty())
+				Expect(records[1].Actor).To(Equal("alice"))
+			})
+
+			Context("when filtering by actor", func() {
+				BeforeEach(func() {
+					filter.Actor = "alice"
+				})
+
+				It("returns only the records of that actor", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(records).To(HaveLen(1))
+					Expect(records[0].Key).To(Equal("a.example.com"))
+				})
+			})
+
+			Context("when filtering by key and time", func() {
+				BeforeEach(func() {
+					filter.Key = "b.example.com"
+					filter.Since = now.Add(-90 * time.Minute)
+				})
+
+				It("returns only the matching records", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(records).To(HaveLen(1))
+					Expect(records[0].Actor).To(Equal("bob"))
+				})
+			})
+
+			Context("when a limit is given", func() {
+				BeforeEach(func() {
+					filter.Limit = 1
+				})
+
+				It("returns at most that many records", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(records).To(HaveLen(1))
+					Expect(records[0].Actor).To(Equal("bob"))
+				})
+			})
+
+			Context("when records are pruned", func() {
+				BeforeEach(func() {
+					err = sqlDB.PruneAuditRecords(now.Add(-90*time.Minute), 0)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("removes the records older than the given time", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(records).To(HaveLen(1))
+					Expect(records[0].Actor).To(Equal("bob"))
+				})
+			})
+
+			Context("when records are pruned beyond a max number of records", func() {
+				BeforeEach(func() {
+					err = sqlDB.PruneAuditRecords(now.Add(-3*time.Hour), 1)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("removes the oldest records", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(records).To(HaveLen(1))
+					Expect(records[0].Actor).To(Equal("bob"))
+				})
+			})
+
+			Context("when records beyond the max number of records share their time with kept ones", func() {
+				BeforeEach(func() {
+					for _, key := range []string{"c.example.com", "d.example.com"} {
+						err = sqlDB.SaveAuditRecord(models.AuditRecord{
+							Time:   now.Add(-1 * time.Hour),
+							Action: models.AuditActionUpsert,
+							Kind:   models.AuditKindHttpRoute,
+							Key:    key,
+							Actor:  "bob",
+						})
+						Expect(err).ToNot(HaveOccurred())
+					}
+
+					err = sqlDB.PruneAuditRecords(now.Add(-3*time.Hour), 2)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("keeps exactly the max number of records", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(records).To(HaveLen(2))
+					Expect(records[0].Actor).To(Equal("bob"))
+					Expect(records[1].Actor).To(Equal("bob"))
+				})
+			})
+		})
+	}
+
+	ConditionalWrites := func() {
+		Describe("conditional writes", func() {
+			var (
+				err               error
+				modTag            models.ModificationTag
+				route             models.Route
+				routeWithModel    models.Route
+				tcpRoute          models.TcpRouteMapping
+				tcpRouteWithModel models.TcpRouteMapping
+			)
+
+			BeforeEach(func() {
+				modTag = models.ModificationTag{Guid: "some-tag", Index: 10}
+				route = models.NewRoute("post_here", 7000, "127.0.0.1", "my-guid", "https://rs.com", 100)
+				route.ModificationTag = modTag
+				routeWithModel, err = models.NewRouteWithModel(route)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = sqlDB.Client.Create(&routeWithModel)
+				Expect(err).ToNot(HaveOccurred())
+
+				tcpRoute = models.NewTcpRouteMapping(newUuid(), 3056, "127.0.0.1", 2990, 100)
+				tcpRoute.ModificationTag = modTag
+				tcpRouteWithModel, err = models.NewTcpRouteMappingWithModel(tcpRoute)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = sqlDB.Client.Create(&tcpRouteWithModel)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				_, err = sqlDB.Client.Delete(&routeWithModel)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = sqlDB.Client.Delete(&tcpRouteWithModel)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Describe("SaveRouteIfMatch", func() {
+				It("updates the route when the modification tag matches", func() {
+					err = sqlDB.SaveRouteIfMatch(route, modTag)
+					Expect(err).ToNot(HaveOccurred())
+
+					dbRoute, err := sqlDB.ReadRoute(route)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(dbRoute.ModificationTag).To(Equal(models.ModificationTag{Guid: "some-tag", Index: 11}))
+				})
+
+				It("returns the current tag when the modification tag does not match", func() {
+					err = sqlDB.SaveRouteIfMatch(route, models.ModificationTag{Guid: "some-tag", Index: 9})
+					Expect(err).To(Equal(db.ModificationTagMismatchError{Current: modTag}))
+
+					dbRoute, err := sqlDB.ReadRoute(route)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(dbRoute.ModificationTag).To(Equal(modTag))
+				})
+
+				It("returns a mismatch when the route does not exist", func() {
+					route.Port = 7001
+					err = sqlDB.SaveRouteIfMatch(route, modTag)
+					Expect(err).To(Equal(db.ModificationTagMismatchError{}))
+				})
+			})
+
+			Describe("DeleteRouteIfMatch", func() {
+				It("deletes the route when the modification tag matches", func() {
+					err = sqlDB.DeleteRouteIfMatch(route, modTag)
+					Expect(err).ToNot(HaveOccurred())
+
+					routes, err := sqlDB.ReadRoutes()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(routes).To(BeEmpty())
+				})
+
+				It("keeps the route when the modification tag does not match", func() {
+					err = sqlDB.DeleteRouteIfMatch(route, models.ModificationTag{Guid: "other-tag", Index: 10})
+					Expect(err).To(Equal(db.ModificationTagMismatchError{Current: modTag}))
+
+					routes, err := sqlDB.ReadRoutes()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(routes).To(HaveLen(1))
+				})
+			})
+
+			Describe("SaveTcpRouteMappingIfMatch", func() {
+				It("updates the mapping when the modification tag matches", func() {
+					err = sqlDB.SaveTcpRouteMappingIfMatch(tcpRoute, modTag)
+					Expect(err).ToNot(HaveOccurred())
+
+					dbTcpRoute, err := sqlDB.ReadTcpRouteMapping(tcpRoute)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(dbTcpRoute.ModificationTag).To(Equal(models.ModificationTag{Guid: "some-tag", Index: 11}))
+				})
+
+				It("returns the current tag when the modification tag does not match", func() {
+					err = sqlDB.SaveTcpRouteMappingIfMatch(tcpRoute, models.ModificationTag{Guid: "some-tag", Index: 11})
+					Expect(err).To(Equal(db.ModificationTagMismatchError{Current: modTag}))
+				})
+			})
+
+			Describe("DeleteTcpRouteMappingIfMatch", func() {
+				It("deletes the mapping when the modification tag matches", func() {
+					err = sqlDB.DeleteTcpRouteMappingIfMatch(tcpRoute, modTag)
+					Expect(err).ToNot(HaveOccurred())
+
+					tcpRoutes, err := sqlDB.ReadTcpRouteMappings()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(tcpRoutes).To(BeEmpty())
+				})
+
+				It("keeps the mapping when the modification tag does not match", func() {
+					err = sqlDB.DeleteTcpRouteMappingIfMatch(tcpRoute, models.ModificationTag{Guid: "some-tag", Index: 9})
+					Expect(err).To(Equal(db.ModificationTagMismatchError{Current: modTag}))
+
+					tcpRoutes, err := sqlDB.ReadTcpRouteMappings()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(tcpRoutes).To(HaveLen(1))
+				})
+			})
+
+			Describe("DeleteRoutesBySelector", func() {
+				var otherRouteWithModel models.Route
+
+				BeforeEach(func() {
+					otherRoute := models.NewRoute("post_here", 7001, "127.0.0.2", "other-guid", "https://rs.com", 100)
+					otherRoute.ModificationTag = modTag
+					otherRouteWithModel, err = models.NewRouteWithModel(otherRoute)
+					Expect(err).ToNot(HaveOccurred())
+					_, err = sqlDB.Client.Create(&otherRouteWithModel)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				AfterEach(func() {
+					_, err = sqlDB.Client.Delete(&otherRouteWithModel)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("deletes only the matching routes and emits a delete event for each", func() {
+					results, _, cancel := sqlDB.WatchChanges(db.HTTP_WATCH)
+					defer cancel()
+
+					deleted, err := sqlDB.DeleteRoutesBySelector(models.RouteSelector{LogGuid: "my-guid"}, false)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deleted).To(HaveLen(1))
+					Expect(deleted[0]).To(matchers.MatchHttpRoute(routeWithModel))
+
+					var event db.Event
+					Eventually(results).Should(Receive(&event))
+					Expect(event.Type).To(Equal(db.DeleteEvent))
+
+					routes, err := sqlDB.ReadRoutes()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(routes).To(HaveLen(1))
+					Expect(routes[0]).To(matchers.MatchHttpRoute(otherRouteWithModel))
+				})
+
+				It("deletes nothing in dry run mode", func() {
+					deleted, err := sqlDB.DeleteRoutesBySelector(models.RouteSelector{IP: "127.0.0.2"}, true)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deleted).To(HaveLen(1))
+					Expect(deleted[0]).To(matchers.MatchHttpRoute(otherRouteWithModel))
+
+					routes, err := sqlDB.ReadRoutes()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(routes).To(HaveLen(2))
+				})
+			})
+
+			Describe("CountRoutes", func() {
+				It("counts the routes matched by the selector", func() {
+					count, err := sqlDB.CountRoutes(models.RouteSelector{LogGuid: "my-guid"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(count).To(Equal(1))
+
+					count, err = sqlDB.CountRoutes(models.RouteSelector{LogGuid: "other-guid"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(count).To(Equal(0))
+				})
+			})
+
+			Describe("CountRoutesPerLogGuid", func() {
+				It("counts the routes of every log guid", func() {
+					counts, err := sqlDB.CountRoutesPerLogGuid()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(counts).To(Equal(map[string]int{"my-guid": 1}))
+				})
+			})
+
+			Describe("CountRoutesPerOwner", func() {
+				It("leaves out routes without an owner", func() {
+					counts, err := sqlDB.CountRoutesPerOwner()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(counts).To(BeEmpty())
+				})
+			})
+
+			Describe("CountTcpRouteMappings", func() {
+				It("counts the mappings matched by the selector", func() {
+					count, err := sqlDB.CountTcpRouteMappings(models.TcpRouteMappingSelector{RouterGroupGuid: tcpRoute.RouterGroupGuid})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(count).To(Equal(1))
+				})
+			})
+
+			Describe("DeleteTcpRouteMappingsBySelector", func() {
+				It("deletes the mappings of the router group", func() {
+					deleted, err := sqlDB.DeleteTcpRouteMappingsBySelector(models.TcpRouteMappingSelector{RouterGroupGuid: tcpRoute.RouterGroupGuid}, false)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deleted).To(HaveLen(1))
+
+					tcpRoutes, err := sqlDB.ReadTcpRouteMappings()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(tcpRoutes).To(BeEmpty())
+				})
+
+				It("returns no mappings when none match", func() {
+					deleted, err := sqlDB.DeleteTcpRouteMappingsBySelector(models.TcpRouteMappingSelector{Owner: "other-client"}, false)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deleted).To(BeEmpty())
+
+					tcpRoutes, err := sqlDB.ReadTcpRouteMappings()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(tcpRoutes).To(HaveLen(1))
+				})
+			})
+		})
+	}
+
 	Describe("Test with Mysql", func() {
 
 		var (
@@ -1193,12 +1980,17 @@ var _ = Describe("SqlDB", func() {
 			Expect(err).ToNot(HaveOccurred())
 			err = migration.NewV0InitMigration().Run(sqlDB)
 			Expect(err).ToNot(HaveOccurred())
+			err = migration.NewV2AuditMigration().Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+			err = migration.NewV3HistoryMigration().Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
 		})
 
 		CleanupRoutes()
 		WatcherRouteChanges()
 		DeleteRoute()
 		ReadRoute()
+		StreamRoutes()
 		SaveRoute()
 		DeleteTcpRouteMapping()
 		ReadTcpRouteMappings()
@@ -1206,6 +1998,9 @@ var _ = Describe("SqlDB", func() {
 		ReadRouterGroup()
 		ReadRouterGroups()
 		SaveRouterGroup()
+		AuditRecords()
+		ConditionalWrites()
+		RouteVersions()
 		Connection()
 	})
 
@@ -1226,12 +2021,17 @@ var _ = Describe("SqlDB", func() {
 			Expect(err).ToNot(HaveOccurred())
 			err = migration.NewV0InitMigration().Run(sqlDB)
 			Expect(err).ToNot(HaveOccurred())
+			err = migration.NewV2AuditMigration().Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+			err = migration.NewV3HistoryMigration().Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
 		})
 
 		CleanupRoutes()
 		WatcherRouteChanges()
 		DeleteRoute()
 		ReadRoute()
+		StreamRoutes()
 		SaveRoute()
 		DeleteTcpRouteMapping()
 		ReadTcpRouteMappings()
@@ -1239,6 +2039,9 @@ var _ = Describe("SqlDB", func() {
 		ReadRouterGroup()
 		ReadRouterGroups()
 		SaveRouterGroup()
+		AuditRecords()
+		ConditionalWrites()
+		RouteVersions()
 		Connection()
 	})
 
@@ -146,6 +146,15 @@ var _ = Describe("DB", func() {
 						_, _, json, _ := fakeKeysAPI.SetArgsForCall(0)
 						Expect(json).To(ContainSubstring("\"index\":0"))
 					})
+
+					It("stores a permanent route without a TTL", func() {
+						*route.TTL = models.PermanentTTL
+						err := fakeEtcd.SaveRoute(route)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(fakeKeysAPI.SetCallCount()).To(Equal(1))
+						_, _, _, opts := fakeKeysAPI.SetArgsForCall(0)
+						Expect(opts.TTL).To(BeZero())
+					})
 				})
 
 				Context("when an entry already exists", func() {
@@ -245,6 +254,85 @@ var _ = Describe("DB", func() {
 				})
 			})
 
+			Describe("SaveRouteIfMatch", func() {
+				var currentTag models.ModificationTag
+
+				BeforeEach(func() {
+					currentTag = models.ModificationTag{Guid: "guid", Index: 5}
+					route.ModificationTag = currentTag
+					routeJson, err := json.Marshal(&route)
+					Expect(err).ToNot(HaveOccurred())
+					fakeResp := &client.Response{Node: &client.Node{Value: string(routeJson), ModifiedIndex: 42}}
+					fakeKeysAPI.GetReturns(fakeResp, nil)
+				})
+
+				It("updates the route with the index of the node that was read", func() {
+					err := fakeEtcd.SaveRouteIfMatch(route, currentTag)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeKeysAPI.SetCallCount()).To(Equal(1))
+					_, _, json, opts := fakeKeysAPI.SetArgsForCall(0)
+					Expect(json).To(ContainSubstring("\"index\":6"))
+					Expect(opts.PrevIndex).To(Equal(uint64(42)))
+				})
+
+				It("returns the current tag without writing when the tag does not match", func() {
+					err := fakeEtcd.SaveRouteIfMatch(route, models.ModificationTag{Guid: "guid", Index: 4})
+					Expect(err).To(Equal(db.ModificationTagMismatchError{Current: currentTag}))
+					Expect(fakeKeysAPI.SetCallCount()).To(Equal(0))
+				})
+
+				Context("when the route does not exist", func() {
+					BeforeEach(func() {
+						fakeKeysAPI.GetReturns(nil, client.Error{Code: client.ErrorCodeKeyNotFound})
+					})
+
+					It("returns a mismatch without a current tag", func() {
+						err := fakeEtcd.SaveRouteIfMatch(route, currentTag)
+						Expect(err).To(Equal(db.ModificationTagMismatchError{}))
+						Expect(fakeKeysAPI.SetCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when Set operation fails with a compare error", func() {
+					BeforeEach(func() {
+						fakeKeysAPI.SetReturns(nil, client.Error{Code: client.ErrorCodeTestFailed})
+					})
+
+					It("returns a mismatch with the tag that was written concurrently", func() {
+						err := fakeEtcd.SaveRouteIfMatch(route, currentTag)
+						Expect(err).To(Equal(db.ModificationTagMismatchError{Current: currentTag}))
+						Expect(fakeKeysAPI.GetCallCount()).To(Equal(2))
+					})
+				})
+			})
+
+			Describe("DeleteRouteIfMatch", func() {
+				var currentTag models.ModificationTag
+
+				BeforeEach(func() {
+					currentTag = models.ModificationTag{Guid: "guid", Index: 5}
+					route.ModificationTag = currentTag
+					routeJson, err := json.Marshal(&route)
+					Expect(err).ToNot(HaveOccurred())
+					fakeResp := &client.Response{Node: &client.Node{Value: string(routeJson), ModifiedIndex: 42}}
+					fakeKeysAPI.GetReturns(fakeResp, nil)
+				})
+
+				It("deletes the route with the index of the node that was read", func() {
+					err := fakeEtcd.DeleteRouteIfMatch(route, currentTag)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeKeysAPI.DeleteCallCount()).To(Equal(1))
+					_, _, opts := fakeKeysAPI.DeleteArgsForCall(0)
+					Expect(opts.PrevIndex).To(Equal(uint64(42)))
+				})
+
+				It("returns the current tag without deleting when the tag does not match", func() {
+					err := fakeEtcd.DeleteRouteIfMatch(route, models.ModificationTag{Guid: "other-guid", Index: 5})
+					Expect(err).To(Equal(db.ModificationTagMismatchError{Current: currentTag}))
+					Expect(fakeKeysAPI.DeleteCallCount()).To(Equal(0))
+				})
+			})
+
 			Describe("WatchChanges with http events", func() {
 				It("does not return an error when canceled", func() {
 					_, errors, cancel := etcd.WatchChanges(db.HTTP_WATCH)
@@ -353,6 +441,32 @@ var _ = Describe("DB", func() {
 					})
 				})
 
+				Context("when the revision is read after a route is deleted", func() {
+					It("does not match the revision of the delete event", func() {
+						err := etcd.SaveRoute(route)
+						Expect(err).NotTo(HaveOccurred())
+						before, err := etcd.ReadRevision(db.HTTP_ROUTES_TABLE)
+						Expect(err).NotTo(HaveOccurred())
+
+						results, _, _ := etcd.WatchChanges(db.HTTP_WATCH)
+
+						err = etcd.DeleteRoute(route)
+						Expect(err).NotTo(HaveOccurred())
+
+						var event db.Event
+						Eventually(results).Should((Receive(&event)))
+						Expect(event.Type).To(Equal(db.DeleteEvent))
+
+						err = etcd.SaveAuditRecord(models.AuditRecord{Action: models.AuditActionDelete, Kind: models.AuditKindHttpRoute, Key: route.AuditKey()})
+						Expect(err).NotTo(HaveOccurred())
+
+						revision, err := etcd.ReadRevision(db.HTTP_ROUTES_TABLE)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(revision).NotTo(Equal(before))
+						Expect(revision).To(BeNumerically(">", event.Revision))
+					})
+				})
+
 				Context("when a route is expired", func() {
 					It("should return an expire watch event", func() {
 						*route.TTL = 1
@@ -444,6 +558,15 @@ var _ = Describe("DB", func() {
 						Expect(opts.TTL).To(Equal(50 * time.Second))
 					})
 
+					It("stores a permanent mapping without a TTL", func() {
+						*tcpMapping.TTL = models.PermanentTTL
+						err := fakeEtcd.SaveTcpRouteMapping(tcpMapping)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(fakeKeysAPI.SetCallCount()).To(Equal(1))
+						_, _, _, opts := fakeKeysAPI.SetArgsForCall(0)
+						Expect(opts.TTL).To(BeZero())
+					})
+
 					Context("when an entry already exists", func() {
 						BeforeEach(func() {
 							tcpMapping.ModificationTag = models.ModificationTag{Guid: "guid", Index: 5}
@@ -649,6 +772,53 @@ var _ = Describe("DB", func() {
 			})
 		})
 
+		Describe("ReadRevision", func() {
+			var routes []*client.Node
+
+			BeforeEach(func() {
+				routes = []*client.Node{
+					{Key: "/routes/a", ModifiedIndex: 10},
+					{Key: "/routes/b", ModifiedIndex: 12},
+				}
+			})
+
+			readRevision := func(index uint64) uint64 {
+				fakeKeysAPI.GetReturns(&client.Response{Index: index, Node: &client.Node{Key: "/routes", Dir: true, Nodes: routes}}, nil)
+				revision, err := fakeEtcd.ReadRevision(db.HTTP_ROUTES_TABLE)
+				Expect(err).NotTo(HaveOccurred())
+				return revision
+			}
+
+			It("reads the keys of the table", func() {
+				readRevision(20)
+				_, key, opts := fakeKeysAPI.GetArgsForCall(0)
+				Expect(key).To(Equal(db.HTTP_ROUTE_BASE_KEY))
+				Expect(opts.Recursive).To(BeTrue())
+			})
+
+			It("does not change when only other keys change", func() {
+				revision := readRevision(20)
+				Expect(readRevision(30)).To(Equal(revision))
+			})
+
+			It("changes to the modified index of an updated key", func() {
+				readRevision(20)
+				routes[0].ModifiedIndex = 25
+				Expect(readRevision(30)).To(Equal(uint64(25)))
+			})
+
+			It("changes to the etcd index when a key is deleted", func() {
+				readRevision(20)
+				routes = routes[:1]
+				Expect(readRevision(30)).To(Equal(uint64(30)))
+			})
+
+			It("rejects unknown tables", func() {
+				_, err := fakeEtcd.ReadRevision("unknown")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		Describe("ReadRouterGroups", func() {
 			var (
 				routerGroup models.RouterGroup
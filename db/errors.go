@@ -1,5 +1,7 @@
 package db
 
+import "code.cloudfoundry.org/routing-api/models"
+
 type DBError struct {
 	Type    string
 	Message string
@@ -14,3 +16,17 @@ const (
 	NonUpdatableField = "NonUpdatableField"
 	UniqueField       = "UniqueField"
 )
+
+// ModificationTagMismatchError is returned by conditional writes when the
+// stored modification tag differs from the expected one. Current is the zero
+// tag when the route does not exist.
+type ModificationTagMismatchError struct {
+	Current models.ModificationTag
+}
+
+func (err ModificationTagMismatchError) Error() string {
+	if err.Current.Guid == "" {
+		return "Modification tag mismatch: route does not exist"
+	}
+	return "Modification tag mismatch: current tag is " + models.FormatModificationTag(err.Current)
+}
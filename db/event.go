@@ -10,6 +10,13 @@ import (
 type Event struct {
 	Type  EventType
 	Value string
+	// Revision is the revision of the table after the event, or 0 if unknown.
+	Revision uint64
+	// RequestID is the id of the API request that caused the event, if known.
+	RequestID string
+	// Protobuf is the protobuf encoding of the event, encoded once before it
+	// is sent to the subscribers, or nil if it was not encoded.
+	Protobuf []byte
 }
 
 type EventType int
@@ -20,6 +27,10 @@ const (
 	DeleteEvent
 	ExpireEvent
 	UpdateEvent
+	DrainEvent
+	// ResyncEvent tells a subscriber that it lost events because it fell
+	// behind, and must read all routes again.
+	ResyncEvent
 )
 
 func (e EventType) String() string {
@@ -30,6 +41,10 @@ func (e EventType) String() string {
 		return "Upsert"
 	case DeleteEvent, ExpireEvent:
 		return "Delete"
+	case DrainEvent:
+		return "Drain"
+	case ResyncEvent:
+		return "resync-required"
 	default:
 		return "Invalid"
 	}
@@ -47,6 +62,28 @@ func NewEventFromInterface(eventType EventType, obj interface{}) (Event, error)
 	}, nil
 }
 
+// NewResyncEvent returns the ResyncEvent of a subscriber that lost dropped
+// events.
+func NewResyncEvent(dropped uint64) Event {
+	return Event{
+		Type:  ResyncEvent,
+		Value: fmt.Sprintf(`{"dropped":%d}`, dropped),
+	}
+}
+
+// Dropped returns the number of events lost by the subscriber of a
+// ResyncEvent, or 0 for other events.
+func (e Event) Dropped() uint64 {
+	if e.Type != ResyncEvent {
+		return 0
+	}
+	var data struct {
+		Dropped uint64 `json:"dropped"`
+	}
+	_ = json.Unmarshal([]byte(e.Value), &data)
+	return data.Dropped
+}
+
 func NewEventFromEtcd(event *client.Response) (Event, error) {
 	var eventType EventType
 
@@ -59,6 +96,9 @@ func NewEventFromEtcd(event *client.Response) (Event, error) {
 		eventType = CreateEvent
 	case "set", "update", "compareAndSwap":
 		eventType = UpdateEvent
+		if event.Node != nil && isDraining(event.Node.Value) {
+			eventType = DrainEvent
+		}
 	case "expire":
 		eventType = ExpireEvent
 		node = event.PrevNode
@@ -67,6 +107,9 @@ func NewEventFromEtcd(event *client.Response) (Event, error) {
 	}
 
 	newEvent := Event{Type: eventType}
+	if event.Node != nil {
+		newEvent.Revision = event.Node.ModifiedIndex
+	}
 
 	if node != nil {
 		newEvent.Value = node.Value
@@ -74,3 +117,13 @@ func NewEventFromEtcd(event *client.Response) (Event, error) {
 
 	return newEvent, nil
 }
+
+// isDraining reports whether the route or tcp route mapping stored in value
+// is draining.
+func isDraining(value string) bool {
+	var entity struct {
+		Draining bool `json:"draining"`
+	}
+	err := json.Unmarshal([]byte(value), &entity)
+	return err == nil && entity.Draining
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"code.cloudfoundry.org/eventhub"
+)
+
+// DefaultMaxEventsPerSubscriber is the number of events buffered for each
+// subscriber of the SqlDB when the configuration does not set it.
+const DefaultMaxEventsPerSubscriber = 1024
+
+var (
+	totalHttpEventsDropped int64
+	totalTcpEventsDropped  int64
+)
+
+// sequencedHub numbers the events it emits, so that subscribers can tell
+// when they lost events. The non-blocking hub drops the oldest buffered events
+// of a subscriber that falls behind without telling it.
+type sequencedHub struct {
+	hub       eventhub.Hub
+	watchType string
+
+	mutex    sync.Mutex
+	sequence uint64
+}
+
+type sequencedEvent struct {
+	sequence uint64
+	event    Event
+}
+
+func newSequencedHub(watchType string, maxEventsPerSubscriber int) *sequencedHub {
+	return &sequencedHub{
+		hub:       eventhub.NewNonBlocking(maxEventsPerSubscriber),
+		watchType: watchType,
+	}
+}
+
+func (h *sequencedHub) Emit(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.sequence++
+	h.hub.Emit(sequencedEvent{sequence: h.sequence, event: event})
+}
+
+// Subscribe returns a source of the events emitted from now on, and the
+// sequence of the last event emitted before.
+func (h *sequencedHub) Subscribe() (eventhub.Source, uint64, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	source, err := h.hub.Subscribe()
+	return source, h.sequence, err
+}
+
+func (h *sequencedHub) Close() error {
+	return h.hub.Close()
+}
+
+// dispatch sends the events of sub, the sequence of whose last event was
+// last, to events. When the subscriber lost events it sends a ResyncEvent
+// first.
+func (h *sequencedHub) dispatch(sub eventhub.Source, last uint64, events chan<- Event, errors chan<- error) {
+	defer close(events)
+	defer close(errors)
+	for {
+		event, err := sub.Next()
+		if err != nil {
+			if err == eventhub.ErrReadFromClosedSource {
+				return
+			}
+			errors <- err
+			return
+		}
+		sequenced, ok := event.(sequencedEvent)
+		if !ok {
+			errors <- fmt.Errorf("Incoming event is not a db.Event: %#v", event)
+			continue
+		}
+
+		if dropped := sequenced.sequence - last - 1; dropped > 0 {
+			countDroppedEvents(h.watchType, int64(dropped))
+			events <- NewResyncEvent(dropped)
+		}
+		last = sequenced.sequence
+
+		events <- sequenced.event
+	}
+}
+
+func countDroppedEvents(watchType string, count int64) {
+	switch watchType {
+	case HTTP_WATCH:
+		atomic.AddInt64(&totalHttpEventsDropped, count)
+	case TCP_WATCH:
+		atomic.AddInt64(&totalTcpEventsDropped, count)
+	}
+}
+
+// GetEventsDropped returns the number of events of watchType that the
+// subscribers of this process lost because they fell behind.
+func GetEventsDropped(watchType string) int64 {
+	switch watchType {
+	case HTTP_WATCH:
+		return atomic.LoadInt64(&totalHttpEventsDropped)
+	case TCP_WATCH:
+		return atomic.LoadInt64(&totalTcpEventsDropped)
+	default:
+		return 0
+	}
+}
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"database/sql"
 	"sync"
 
 	"code.cloudfoundry.org/routing-api/db"
@@ -112,6 +113,76 @@ type FakeClient struct {
 	hasTableReturns struct {
 		result1 bool
 	}
+	OrderStub        func(value interface{}) db.Client
+	orderMutex       sync.RWMutex
+	orderArgsForCall []struct {
+		value interface{}
+	}
+	orderReturns struct {
+		result1 db.Client
+	}
+	LimitStub        func(limit interface{}) db.Client
+	limitMutex       sync.RWMutex
+	limitArgsForCall []struct {
+		limit interface{}
+	}
+	limitReturns struct {
+		result1 db.Client
+	}
+	OffsetStub        func(offset interface{}) db.Client
+	offsetMutex       sync.RWMutex
+	offsetArgsForCall []struct {
+		offset interface{}
+	}
+	offsetReturns struct {
+		result1 db.Client
+	}
+	ModelStub        func(value interface{}) db.Client
+	modelMutex       sync.RWMutex
+	modelArgsForCall []struct {
+		value interface{}
+	}
+	modelReturns struct {
+		result1 db.Client
+	}
+	CountStub        func(value interface{}) error
+	countMutex       sync.RWMutex
+	countArgsForCall []struct {
+		value interface{}
+	}
+	countReturns struct {
+		result1 error
+	}
+	RowsStub        func() (*sql.Rows, error)
+	rowsMutex       sync.RWMutex
+	rowsArgsForCall []struct{}
+	rowsReturns     struct {
+		result1 *sql.Rows
+		result2 error
+	}
+	PingStub        func() error
+	pingMutex       sync.RWMutex
+	pingArgsForCall []struct{}
+	pingReturns     struct {
+		result1 error
+	}
+	GroupStub        func(query string) db.Client
+	groupMutex       sync.RWMutex
+	groupArgsForCall []struct {
+		query string
+	}
+	groupReturns struct {
+		result1 db.Client
+	}
+	SelectStub        func(query interface{}, args ...interface{}) db.Client
+	selectMutex       sync.RWMutex
+	selectArgsForCall []struct {
+		query interface{}
+		args  []interface{}
+	}
+	selectReturns struct {
+		result1 db.Client
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -521,6 +592,289 @@ func (fake *FakeClient) HasTableReturns(result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeClient) Order(value interface{}) db.Client {
+	fake.orderMutex.Lock()
+	fake.orderArgsForCall = append(fake.orderArgsForCall, struct {
+		value interface{}
+	}{value})
+	fake.recordInvocation("Order", []interface{}{value})
+	fake.orderMutex.Unlock()
+	if fake.OrderStub != nil {
+		return fake.OrderStub(value)
+	} else {
+		return fake.orderReturns.result1
+	}
+}
+
+func (fake *FakeClient) OrderCallCount() int {
+	fake.orderMutex.RLock()
+	defer fake.orderMutex.RUnlock()
+	return len(fake.orderArgsForCall)
+}
+
+func (fake *FakeClient) OrderArgsForCall(i int) interface{} {
+	fake.orderMutex.RLock()
+	defer fake.orderMutex.RUnlock()
+	return fake.orderArgsForCall[i].value
+}
+
+func (fake *FakeClient) OrderReturns(result1 db.Client) {
+	fake.OrderStub = nil
+	fake.orderReturns = struct {
+		result1 db.Client
+	}{result1}
+}
+
+func (fake *FakeClient) Limit(limit interface{}) db.Client {
+	fake.limitMutex.Lock()
+	fake.limitArgsForCall = append(fake.limitArgsForCall, struct {
+		limit interface{}
+	}{limit})
+	fake.recordInvocation("Limit", []interface{}{limit})
+	fake.limitMutex.Unlock()
+	if fake.LimitStub != nil {
+		return fake.LimitStub(limit)
+	} else {
+		return fake.limitReturns.result1
+	}
+}
+
+func (fake *FakeClient) LimitCallCount() int {
+	fake.limitMutex.RLock()
+	defer fake.limitMutex.RUnlock()
+	return len(fake.limitArgsForCall)
+}
+
+func (fake *FakeClient) LimitArgsForCall(i int) interface{} {
+	fake.limitMutex.RLock()
+	defer fake.limitMutex.RUnlock()
+	return fake.limitArgsForCall[i].limit
+}
+
+func (fake *FakeClient) LimitReturns(result1 db.Client) {
+	fake.LimitStub = nil
+	fake.limitReturns = struct {
+		result1 db.Client
+	}{result1}
+}
+
+func (fake *FakeClient) Offset(offset interface{}) db.Client {
+	fake.offsetMutex.Lock()
+	fake.offsetArgsForCall = append(fake.offsetArgsForCall, struct {
+		offset interface{}
+	}{offset})
+	fake.recordInvocation("Offset", []interface{}{offset})
+	fake.offsetMutex.Unlock()
+	if fake.OffsetStub != nil {
+		return fake.OffsetStub(offset)
+	} else {
+		return fake.offsetReturns.result1
+	}
+}
+
+func (fake *FakeClient) OffsetCallCount() int {
+	fake.offsetMutex.RLock()
+	defer fake.offsetMutex.RUnlock()
+	return len(fake.offsetArgsForCall)
+}
+
+func (fake *FakeClient) OffsetArgsForCall(i int) interface{} {
+	fake.offsetMutex.RLock()
+	defer fake.offsetMutex.RUnlock()
+	return fake.offsetArgsForCall[i].offset
+}
+
+func (fake *FakeClient) OffsetReturns(result1 db.Client) {
+	fake.OffsetStub = nil
+	fake.offsetReturns = struct {
+		result1 db.Client
+	}{result1}
+}
+
+func (fake *FakeClient) Model(value interface{}) db.Client {
+	fake.modelMutex.Lock()
+	fake.modelArgsForCall = append(fake.modelArgsForCall, struct {
+		value interface{}
+	}{value})
+	fake.recordInvocation("Model", []interface{}{value})
+	fake.modelMutex.Unlock()
+	if fake.ModelStub != nil {
+		return fake.ModelStub(value)
+	} else {
+		return fake.modelReturns.result1
+	}
+}
+
+func (fake *FakeClient) ModelCallCount() int {
+	fake.modelMutex.RLock()
+	defer fake.modelMutex.RUnlock()
+	return len(fake.modelArgsForCall)
+}
+
+func (fake *FakeClient) ModelArgsForCall(i int) interface{} {
+	fake.modelMutex.RLock()
+	defer fake.modelMutex.RUnlock()
+	return fake.modelArgsForCall[i].value
+}
+
+func (fake *FakeClient) ModelReturns(result1 db.Client) {
+	fake.ModelStub = nil
+	fake.modelReturns = struct {
+		result1 db.Client
+	}{result1}
+}
+
+func (fake *FakeClient) Count(value interface{}) error {
+	fake.countMutex.Lock()
+	fake.countArgsForCall = append(fake.countArgsForCall, struct {
+		value interface{}
+	}{value})
+	fake.recordInvocation("Count", []interface{}{value})
+	fake.countMutex.Unlock()
+	if fake.CountStub != nil {
+		return fake.CountStub(value)
+	} else {
+		return fake.countReturns.result1
+	}
+}
+
+func (fake *FakeClient) CountCallCount() int {
+	fake.countMutex.RLock()
+	defer fake.countMutex.RUnlock()
+	return len(fake.countArgsForCall)
+}
+
+func (fake *FakeClient) CountArgsForCall(i int) interface{} {
+	fake.countMutex.RLock()
+	defer fake.countMutex.RUnlock()
+	return fake.countArgsForCall[i].value
+}
+
+func (fake *FakeClient) CountReturns(result1 error) {
+	fake.CountStub = nil
+	fake.countReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) Rows() (*sql.Rows, error) {
+	fake.rowsMutex.Lock()
+	fake.rowsArgsForCall = append(fake.rowsArgsForCall, struct{}{})
+	fake.recordInvocation("Rows", []interface{}{})
+	fake.rowsMutex.Unlock()
+	if fake.RowsStub != nil {
+		return fake.RowsStub()
+	} else {
+		return fake.rowsReturns.result1, fake.rowsReturns.result2
+	}
+}
+
+func (fake *FakeClient) RowsCallCount() int {
+	fake.rowsMutex.RLock()
+	defer fake.rowsMutex.RUnlock()
+	return len(fake.rowsArgsForCall)
+}
+
+func (fake *FakeClient) RowsReturns(result1 *sql.Rows, result2 error) {
+	fake.RowsStub = nil
+	fake.rowsReturns = struct {
+		result1 *sql.Rows
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Ping() error {
+	fake.pingMutex.Lock()
+	fake.pingArgsForCall = append(fake.pingArgsForCall, struct{}{})
+	fake.recordInvocation("Ping", []interface{}{})
+	fake.pingMutex.Unlock()
+	if fake.PingStub != nil {
+		return fake.PingStub()
+	} else {
+		return fake.pingReturns.result1
+	}
+}
+
+func (fake *FakeClient) PingCallCount() int {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	return len(fake.pingArgsForCall)
+}
+
+func (fake *FakeClient) PingReturns(result1 error) {
+	fake.PingStub = nil
+	fake.pingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) Group(query string) db.Client {
+	fake.groupMutex.Lock()
+	fake.groupArgsForCall = append(fake.groupArgsForCall, struct {
+		query string
+	}{query})
+	fake.recordInvocation("Group", []interface{}{query})
+	fake.groupMutex.Unlock()
+	if fake.GroupStub != nil {
+		return fake.GroupStub(query)
+	} else {
+		return fake.groupReturns.result1
+	}
+}
+
+func (fake *FakeClient) GroupCallCount() int {
+	fake.groupMutex.RLock()
+	defer fake.groupMutex.RUnlock()
+	return len(fake.groupArgsForCall)
+}
+
+func (fake *FakeClient) GroupArgsForCall(i int) string {
+	fake.groupMutex.RLock()
+	defer fake.groupMutex.RUnlock()
+	return fake.groupArgsForCall[i].query
+}
+
+func (fake *FakeClient) GroupReturns(result1 db.Client) {
+	fake.GroupStub = nil
+	fake.groupReturns = struct {
+		result1 db.Client
+	}{result1}
+}
+
+func (fake *FakeClient) Select(query interface{}, args ...interface{}) db.Client {
+	fake.selectMutex.Lock()
+	fake.selectArgsForCall = append(fake.selectArgsForCall, struct {
+		query interface{}
+		args  []interface{}
+	}{query, args})
+	fake.recordInvocation("Select", []interface{}{query, args})
+	fake.selectMutex.Unlock()
+	if fake.SelectStub != nil {
+		return fake.SelectStub(query, args...)
+	} else {
+		return fake.selectReturns.result1
+	}
+}
+
+func (fake *FakeClient) SelectCallCount() int {
+	fake.selectMutex.RLock()
+	defer fake.selectMutex.RUnlock()
+	return len(fake.selectArgsForCall)
+}
+
+func (fake *FakeClient) SelectArgsForCall(i int) (interface{}, []interface{}) {
+	fake.selectMutex.RLock()
+	defer fake.selectMutex.RUnlock()
+	return fake.selectArgsForCall[i].query, fake.selectArgsForCall[i].args
+}
+
+func (fake *FakeClient) SelectReturns(result1 db.Client) {
+	fake.SelectStub = nil
+	fake.selectReturns = struct {
+		result1 db.Client
+	}{result1}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -550,6 +904,24 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.commitMutex.RUnlock()
 	fake.hasTableMutex.RLock()
 	defer fake.hasTableMutex.RUnlock()
+	fake.orderMutex.RLock()
+	defer fake.orderMutex.RUnlock()
+	fake.limitMutex.RLock()
+	defer fake.limitMutex.RUnlock()
+	fake.offsetMutex.RLock()
+	defer fake.offsetMutex.RUnlock()
+	fake.modelMutex.RLock()
+	defer fake.modelMutex.RUnlock()
+	fake.countMutex.RLock()
+	defer fake.countMutex.RUnlock()
+	fake.rowsMutex.RLock()
+	defer fake.rowsMutex.RUnlock()
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	fake.groupMutex.RLock()
+	defer fake.groupMutex.RUnlock()
+	fake.selectMutex.RLock()
+	defer fake.selectMutex.RUnlock()
 	return fake.invocations
 }
 
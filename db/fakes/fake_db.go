@@ -3,6 +3,7 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/routing-api/db"
 	"code.cloudfoundry.org/routing-api/models"
@@ -93,6 +94,275 @@ type FakeDB struct {
 		result2 <-chan error
 		result3 context.CancelFunc
 	}
+	ReadRouteStub        func(route models.Route) (models.Route, error)
+	readRouteMutex       sync.RWMutex
+	readRouteArgsForCall []struct {
+		route models.Route
+	}
+	readRouteReturns struct {
+		result1 models.Route
+		result2 error
+	}
+	ReadTcpRouteMappingStub        func(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error)
+	readTcpRouteMappingMutex       sync.RWMutex
+	readTcpRouteMappingArgsForCall []struct {
+		tcpMapping models.TcpRouteMapping
+	}
+	readTcpRouteMappingReturns struct {
+		result1 models.TcpRouteMapping
+		result2 error
+	}
+	SaveAuditRecordStub        func(record models.AuditRecord) error
+	saveAuditRecordMutex       sync.RWMutex
+	saveAuditRecordArgsForCall []struct {
+		record models.AuditRecord
+	}
+	saveAuditRecordReturns struct {
+		result1 error
+	}
+	ReadAuditRecordsStub        func(filter models.AuditFilter) ([]models.AuditRecord, error)
+	readAuditRecordsMutex       sync.RWMutex
+	readAuditRecordsArgsForCall []struct {
+		filter models.AuditFilter
+	}
+	readAuditRecordsReturns struct {
+		result1 []models.AuditRecord
+		result2 error
+	}
+	PruneAuditRecordsStub        func(olderThan time.Time, maxRecords int) error
+	pruneAuditRecordsMutex       sync.RWMutex
+	pruneAuditRecordsArgsForCall []struct {
+		olderThan  time.Time
+		maxRecords int
+	}
+	pruneAuditRecordsReturns struct {
+		result1 error
+	}
+	SaveRouteIfMatchStub        func(route models.Route, expected models.ModificationTag) error
+	saveRouteIfMatchMutex       sync.RWMutex
+	saveRouteIfMatchArgsForCall []struct {
+		route    models.Route
+		expected models.ModificationTag
+	}
+	saveRouteIfMatchReturns struct {
+		result1 error
+	}
+	DeleteRouteIfMatchStub        func(route models.Route, expected models.ModificationTag) error
+	deleteRouteIfMatchMutex       sync.RWMutex
+	deleteRouteIfMatchArgsForCall []struct {
+		route    models.Route
+		expected models.ModificationTag
+	}
+	deleteRouteIfMatchReturns struct {
+		result1 error
+	}
+	SaveTcpRouteMappingIfMatchStub        func(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error
+	saveTcpRouteMappingIfMatchMutex       sync.RWMutex
+	saveTcpRouteMappingIfMatchArgsForCall []struct {
+		tcpMapping models.TcpRouteMapping
+		expected   models.ModificationTag
+	}
+	saveTcpRouteMappingIfMatchReturns struct {
+		result1 error
+	}
+	DeleteTcpRouteMappingIfMatchStub        func(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error
+	deleteTcpRouteMappingIfMatchMutex       sync.RWMutex
+	deleteTcpRouteMappingIfMatchArgsForCall []struct {
+		tcpMapping models.TcpRouteMapping
+		expected   models.ModificationTag
+	}
+	deleteTcpRouteMappingIfMatchReturns struct {
+		result1 error
+	}
+	ReadRevisionStub        func(table string) (uint64, error)
+	readRevisionMutex       sync.RWMutex
+	readRevisionArgsForCall []struct {
+		table string
+	}
+	readRevisionReturns struct {
+		result1 uint64
+		result2 error
+	}
+	SaveRouteVersionStub        func(version models.RouteVersion, maxVersions int) error
+	saveRouteVersionMutex       sync.RWMutex
+	saveRouteVersionArgsForCall []struct {
+		version     models.RouteVersion
+		maxVersions int
+	}
+	saveRouteVersionReturns struct {
+		result1 error
+	}
+	ReadRouteVersionsStub        func(kind string, key string) ([]models.RouteVersion, error)
+	readRouteVersionsMutex       sync.RWMutex
+	readRouteVersionsArgsForCall []struct {
+		kind string
+		key  string
+	}
+	readRouteVersionsReturns struct {
+		result1 []models.RouteVersion
+		result2 error
+	}
+	DrainRouteStub        func(route models.Route, drainTTL int) error
+	drainRouteMutex       sync.RWMutex
+	drainRouteArgsForCall []struct {
+		route    models.Route
+		drainTTL int
+	}
+	drainRouteReturns struct {
+		result1 error
+	}
+	DrainRouteIfMatchStub        func(route models.Route, drainTTL int, expected models.ModificationTag) error
+	drainRouteIfMatchMutex       sync.RWMutex
+	drainRouteIfMatchArgsForCall []struct {
+		route    models.Route
+		drainTTL int
+		expected models.ModificationTag
+	}
+	drainRouteIfMatchReturns struct {
+		result1 error
+	}
+	DrainTcpRouteMappingStub        func(tcpMapping models.TcpRouteMapping, drainTTL int) error
+	drainTcpRouteMappingMutex       sync.RWMutex
+	drainTcpRouteMappingArgsForCall []struct {
+		tcpMapping models.TcpRouteMapping
+		drainTTL   int
+	}
+	drainTcpRouteMappingReturns struct {
+		result1 error
+	}
+	DrainTcpRouteMappingIfMatchStub        func(tcpMapping models.TcpRouteMapping, drainTTL int, expected models.ModificationTag) error
+	drainTcpRouteMappingIfMatchMutex       sync.RWMutex
+	drainTcpRouteMappingIfMatchArgsForCall []struct {
+		tcpMapping models.TcpRouteMapping
+		drainTTL   int
+		expected   models.ModificationTag
+	}
+	drainTcpRouteMappingIfMatchReturns struct {
+		result1 error
+	}
+	DeleteRoutesBySelectorStub        func(selector models.RouteSelector, dryRun bool) ([]models.Route, error)
+	deleteRoutesBySelectorMutex       sync.RWMutex
+	deleteRoutesBySelectorArgsForCall []struct {
+		selector models.RouteSelector
+		dryRun   bool
+	}
+	deleteRoutesBySelectorReturns struct {
+		result1 []models.Route
+		result2 error
+	}
+	DeleteTcpRouteMappingsBySelectorStub        func(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error)
+	deleteTcpRouteMappingsBySelectorMutex       sync.RWMutex
+	deleteTcpRouteMappingsBySelectorArgsForCall []struct {
+		selector models.TcpRouteMappingSelector
+		dryRun   bool
+	}
+	deleteTcpRouteMappingsBySelectorReturns struct {
+		result1 []models.TcpRouteMapping
+		result2 error
+	}
+	CountRoutesStub        func(selector models.RouteSelector) (int, error)
+	countRoutesMutex       sync.RWMutex
+	countRoutesArgsForCall []struct {
+		selector models.RouteSelector
+	}
+	countRoutesReturns struct {
+		result1 int
+		result2 error
+	}
+	CountTcpRouteMappingsStub        func(selector models.TcpRouteMappingSelector) (int, error)
+	countTcpRouteMappingsMutex       sync.RWMutex
+	countTcpRouteMappingsArgsForCall []struct {
+		selector models.TcpRouteMappingSelector
+	}
+	countTcpRouteMappingsReturns struct {
+		result1 int
+		result2 error
+	}
+	StreamRoutesStub        func(fn func(models.Route) error) error
+	streamRoutesMutex       sync.RWMutex
+	streamRoutesArgsForCall []struct {
+		fn func(models.Route) error
+	}
+	streamRoutesReturns struct {
+		result1 error
+	}
+	StreamTcpRouteMappingsStub        func(fn func(models.TcpRouteMapping) error) error
+	streamTcpRouteMappingsMutex       sync.RWMutex
+	streamTcpRouteMappingsArgsForCall []struct {
+		fn func(models.TcpRouteMapping) error
+	}
+	streamTcpRouteMappingsReturns struct {
+		result1 error
+	}
+	ReadRouteByGuidStub        func(guid string) (models.Route, error)
+	readRouteByGuidMutex       sync.RWMutex
+	readRouteByGuidArgsForCall []struct {
+		guid string
+	}
+	readRouteByGuidReturns struct {
+		result1 models.Route
+		result2 error
+	}
+	ReadTcpRouteMappingByGuidStub        func(guid string) (models.TcpRouteMapping, error)
+	readTcpRouteMappingByGuidMutex       sync.RWMutex
+	readTcpRouteMappingByGuidArgsForCall []struct {
+		guid string
+	}
+	readTcpRouteMappingByGuidReturns struct {
+		result1 models.TcpRouteMapping
+		result2 error
+	}
+	PingStub        func() error
+	pingMutex       sync.RWMutex
+	pingArgsForCall []struct{}
+	pingReturns     struct {
+		result1 error
+	}
+	PruneRouteVersionsStub        func(olderThan time.Time) error
+	pruneRouteVersionsMutex       sync.RWMutex
+	pruneRouteVersionsArgsForCall []struct {
+		olderThan time.Time
+	}
+	pruneRouteVersionsReturns struct {
+		result1 error
+	}
+	CountRoutesPerOwnerStub        func() (map[string]int, error)
+	countRoutesPerOwnerMutex       sync.RWMutex
+	countRoutesPerOwnerArgsForCall []struct{}
+	countRoutesPerOwnerReturns     struct {
+		result1 map[string]int
+		result2 error
+	}
+	CountRoutesPerLogGuidStub        func() (map[string]int, error)
+	countRoutesPerLogGuidMutex       sync.RWMutex
+	countRoutesPerLogGuidArgsForCall []struct{}
+	countRoutesPerLogGuidReturns     struct {
+		result1 map[string]int
+		result2 error
+	}
+	CountTcpRouteMappingsPerOwnerStub        func() (map[string]int, error)
+	countTcpRouteMappingsPerOwnerMutex       sync.RWMutex
+	countTcpRouteMappingsPerOwnerArgsForCall []struct{}
+	countTcpRouteMappingsPerOwnerReturns     struct {
+		result1 map[string]int
+		result2 error
+	}
+	CreateRouteStub        func(route models.Route) error
+	createRouteMutex       sync.RWMutex
+	createRouteArgsForCall []struct {
+		route models.Route
+	}
+	createRouteReturns struct {
+		result1 error
+	}
+	CreateTcpRouteMappingStub        func(tcpMapping models.TcpRouteMapping) error
+	createTcpRouteMappingMutex       sync.RWMutex
+	createTcpRouteMappingArgsForCall []struct {
+		tcpMapping models.TcpRouteMapping
+	}
+	createTcpRouteMappingReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -425,6 +695,1026 @@ func (fake *FakeDB) WatchChangesReturns(result1 <-chan db.Event, result2 <-chan
 	}{result1, result2, result3}
 }
 
+func (fake *FakeDB) ReadRoute(route models.Route) (models.Route, error) {
+	fake.readRouteMutex.Lock()
+	fake.readRouteArgsForCall = append(fake.readRouteArgsForCall, struct {
+		route models.Route
+	}{route})
+	fake.recordInvocation("ReadRoute", []interface{}{route})
+	fake.readRouteMutex.Unlock()
+	if fake.ReadRouteStub != nil {
+		return fake.ReadRouteStub(route)
+	} else {
+		return fake.readRouteReturns.result1, fake.readRouteReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadRouteCallCount() int {
+	fake.readRouteMutex.RLock()
+	defer fake.readRouteMutex.RUnlock()
+	return len(fake.readRouteArgsForCall)
+}
+
+func (fake *FakeDB) ReadRouteArgsForCall(i int) models.Route {
+	fake.readRouteMutex.RLock()
+	defer fake.readRouteMutex.RUnlock()
+	return fake.readRouteArgsForCall[i].route
+}
+
+func (fake *FakeDB) ReadRouteReturns(result1 models.Route, result2 error) {
+	fake.ReadRouteStub = nil
+	fake.readRouteReturns = struct {
+		result1 models.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) ReadTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error) {
+	fake.readTcpRouteMappingMutex.Lock()
+	fake.readTcpRouteMappingArgsForCall = append(fake.readTcpRouteMappingArgsForCall, struct {
+		tcpMapping models.TcpRouteMapping
+	}{tcpMapping})
+	fake.recordInvocation("ReadTcpRouteMapping", []interface{}{tcpMapping})
+	fake.readTcpRouteMappingMutex.Unlock()
+	if fake.ReadTcpRouteMappingStub != nil {
+		return fake.ReadTcpRouteMappingStub(tcpMapping)
+	} else {
+		return fake.readTcpRouteMappingReturns.result1, fake.readTcpRouteMappingReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingCallCount() int {
+	fake.readTcpRouteMappingMutex.RLock()
+	defer fake.readTcpRouteMappingMutex.RUnlock()
+	return len(fake.readTcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingArgsForCall(i int) models.TcpRouteMapping {
+	fake.readTcpRouteMappingMutex.RLock()
+	defer fake.readTcpRouteMappingMutex.RUnlock()
+	return fake.readTcpRouteMappingArgsForCall[i].tcpMapping
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingReturns(result1 models.TcpRouteMapping, result2 error) {
+	fake.ReadTcpRouteMappingStub = nil
+	fake.readTcpRouteMappingReturns = struct {
+		result1 models.TcpRouteMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) SaveAuditRecord(record models.AuditRecord) error {
+	fake.saveAuditRecordMutex.Lock()
+	fake.saveAuditRecordArgsForCall = append(fake.saveAuditRecordArgsForCall, struct {
+		record models.AuditRecord
+	}{record})
+	fake.recordInvocation("SaveAuditRecord", []interface{}{record})
+	fake.saveAuditRecordMutex.Unlock()
+	if fake.SaveAuditRecordStub != nil {
+		return fake.SaveAuditRecordStub(record)
+	} else {
+		return fake.saveAuditRecordReturns.result1
+	}
+}
+
+func (fake *FakeDB) SaveAuditRecordCallCount() int {
+	fake.saveAuditRecordMutex.RLock()
+	defer fake.saveAuditRecordMutex.RUnlock()
+	return len(fake.saveAuditRecordArgsForCall)
+}
+
+func (fake *FakeDB) SaveAuditRecordArgsForCall(i int) models.AuditRecord {
+	fake.saveAuditRecordMutex.RLock()
+	defer fake.saveAuditRecordMutex.RUnlock()
+	return fake.saveAuditRecordArgsForCall[i].record
+}
+
+func (fake *FakeDB) SaveAuditRecordReturns(result1 error) {
+	fake.SaveAuditRecordStub = nil
+	fake.saveAuditRecordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) ReadAuditRecords(filter models.AuditFilter) ([]models.AuditRecord, error) {
+	fake.readAuditRecordsMutex.Lock()
+	fake.readAuditRecordsArgsForCall = append(fake.readAuditRecordsArgsForCall, struct {
+		filter models.AuditFilter
+	}{filter})
+	fake.recordInvocation("ReadAuditRecords", []interface{}{filter})
+	fake.readAuditRecordsMutex.Unlock()
+	if fake.ReadAuditRecordsStub != nil {
+		return fake.ReadAuditRecordsStub(filter)
+	} else {
+		return fake.readAuditRecordsReturns.result1, fake.readAuditRecordsReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadAuditRecordsCallCount() int {
+	fake.readAuditRecordsMutex.RLock()
+	defer fake.readAuditRecordsMutex.RUnlock()
+	return len(fake.readAuditRecordsArgsForCall)
+}
+
+func (fake *FakeDB) ReadAuditRecordsArgsForCall(i int) models.AuditFilter {
+	fake.readAuditRecordsMutex.RLock()
+	defer fake.readAuditRecordsMutex.RUnlock()
+	return fake.readAuditRecordsArgsForCall[i].filter
+}
+
+func (fake *FakeDB) ReadAuditRecordsReturns(result1 []models.AuditRecord, result2 error) {
+	fake.ReadAuditRecordsStub = nil
+	fake.readAuditRecordsReturns = struct {
+		result1 []models.AuditRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) PruneAuditRecords(olderThan time.Time, maxRecords int) error {
+	fake.pruneAuditRecordsMutex.Lock()
+	fake.pruneAuditRecordsArgsForCall = append(fake.pruneAuditRecordsArgsForCall, struct {
+		olderThan  time.Time
+		maxRecords int
+	}{olderThan, maxRecords})
+	fake.recordInvocation("PruneAuditRecords", []interface{}{olderThan, maxRecords})
+	fake.pruneAuditRecordsMutex.Unlock()
+	if fake.PruneAuditRecordsStub != nil {
+		return fake.PruneAuditRecordsStub(olderThan, maxRecords)
+	} else {
+		return fake.pruneAuditRecordsReturns.result1
+	}
+}
+
+func (fake *FakeDB) PruneAuditRecordsCallCount() int {
+	fake.pruneAuditRecordsMutex.RLock()
+	defer fake.pruneAuditRecordsMutex.RUnlock()
+	return len(fake.pruneAuditRecordsArgsForCall)
+}
+
+func (fake *FakeDB) PruneAuditRecordsArgsForCall(i int) (time.Time, int) {
+	fake.pruneAuditRecordsMutex.RLock()
+	defer fake.pruneAuditRecordsMutex.RUnlock()
+	return fake.pruneAuditRecordsArgsForCall[i].olderThan, fake.pruneAuditRecordsArgsForCall[i].maxRecords
+}
+
+func (fake *FakeDB) PruneAuditRecordsReturns(result1 error) {
+	fake.PruneAuditRecordsStub = nil
+	fake.pruneAuditRecordsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) SaveRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	fake.saveRouteIfMatchMutex.Lock()
+	fake.saveRouteIfMatchArgsForCall = append(fake.saveRouteIfMatchArgsForCall, struct {
+		route    models.Route
+		expected models.ModificationTag
+	}{route, expected})
+	fake.recordInvocation("SaveRouteIfMatch", []interface{}{route, expected})
+	fake.saveRouteIfMatchMutex.Unlock()
+	if fake.SaveRouteIfMatchStub != nil {
+		return fake.SaveRouteIfMatchStub(route, expected)
+	} else {
+		return fake.saveRouteIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeDB) SaveRouteIfMatchCallCount() int {
+	fake.saveRouteIfMatchMutex.RLock()
+	defer fake.saveRouteIfMatchMutex.RUnlock()
+	return len(fake.saveRouteIfMatchArgsForCall)
+}
+
+func (fake *FakeDB) SaveRouteIfMatchArgsForCall(i int) (models.Route, models.ModificationTag) {
+	fake.saveRouteIfMatchMutex.RLock()
+	defer fake.saveRouteIfMatchMutex.RUnlock()
+	return fake.saveRouteIfMatchArgsForCall[i].route, fake.saveRouteIfMatchArgsForCall[i].expected
+}
+
+func (fake *FakeDB) SaveRouteIfMatchReturns(result1 error) {
+	fake.SaveRouteIfMatchStub = nil
+	fake.saveRouteIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) DeleteRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	fake.deleteRouteIfMatchMutex.Lock()
+	fake.deleteRouteIfMatchArgsForCall = append(fake.deleteRouteIfMatchArgsForCall, struct {
+		route    models.Route
+		expected models.ModificationTag
+	}{route, expected})
+	fake.recordInvocation("DeleteRouteIfMatch", []interface{}{route, expected})
+	fake.deleteRouteIfMatchMutex.Unlock()
+	if fake.DeleteRouteIfMatchStub != nil {
+		return fake.DeleteRouteIfMatchStub(route, expected)
+	} else {
+		return fake.deleteRouteIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeDB) DeleteRouteIfMatchCallCount() int {
+	fake.deleteRouteIfMatchMutex.RLock()
+	defer fake.deleteRouteIfMatchMutex.RUnlock()
+	return len(fake.deleteRouteIfMatchArgsForCall)
+}
+
+func (fake *FakeDB) DeleteRouteIfMatchArgsForCall(i int) (models.Route, models.ModificationTag) {
+	fake.deleteRouteIfMatchMutex.RLock()
+	defer fake.deleteRouteIfMatchMutex.RUnlock()
+	return fake.deleteRouteIfMatchArgsForCall[i].route, fake.deleteRouteIfMatchArgsForCall[i].expected
+}
+
+func (fake *FakeDB) DeleteRouteIfMatchReturns(result1 error) {
+	fake.DeleteRouteIfMatchStub = nil
+	fake.deleteRouteIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) SaveTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	fake.saveTcpRouteMappingIfMatchMutex.Lock()
+	fake.saveTcpRouteMappingIfMatchArgsForCall = append(fake.saveTcpRouteMappingIfMatchArgsForCall, struct {
+		tcpMapping models.TcpRouteMapping
+		expected   models.ModificationTag
+	}{tcpMapping, expected})
+	fake.recordInvocation("SaveTcpRouteMappingIfMatch", []interface{}{tcpMapping, expected})
+	fake.saveTcpRouteMappingIfMatchMutex.Unlock()
+	if fake.SaveTcpRouteMappingIfMatchStub != nil {
+		return fake.SaveTcpRouteMappingIfMatchStub(tcpMapping, expected)
+	} else {
+		return fake.saveTcpRouteMappingIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeDB) SaveTcpRouteMappingIfMatchCallCount() int {
+	fake.saveTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.saveTcpRouteMappingIfMatchMutex.RUnlock()
+	return len(fake.saveTcpRouteMappingIfMatchArgsForCall)
+}
+
+func (fake *FakeDB) SaveTcpRouteMappingIfMatchArgsForCall(i int) (models.TcpRouteMapping, models.ModificationTag) {
+	fake.saveTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.saveTcpRouteMappingIfMatchMutex.RUnlock()
+	return fake.saveTcpRouteMappingIfMatchArgsForCall[i].tcpMapping, fake.saveTcpRouteMappingIfMatchArgsForCall[i].expected
+}
+
+func (fake *FakeDB) SaveTcpRouteMappingIfMatchReturns(result1 error) {
+	fake.SaveTcpRouteMappingIfMatchStub = nil
+	fake.saveTcpRouteMappingIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	fake.deleteTcpRouteMappingIfMatchMutex.Lock()
+	fake.deleteTcpRouteMappingIfMatchArgsForCall = append(fake.deleteTcpRouteMappingIfMatchArgsForCall, struct {
+		tcpMapping models.TcpRouteMapping
+		expected   models.ModificationTag
+	}{tcpMapping, expected})
+	fake.recordInvocation("DeleteTcpRouteMappingIfMatch", []interface{}{tcpMapping, expected})
+	fake.deleteTcpRouteMappingIfMatchMutex.Unlock()
+	if fake.DeleteTcpRouteMappingIfMatchStub != nil {
+		return fake.DeleteTcpRouteMappingIfMatchStub(tcpMapping, expected)
+	} else {
+		return fake.deleteTcpRouteMappingIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingIfMatchCallCount() int {
+	fake.deleteTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.deleteTcpRouteMappingIfMatchMutex.RUnlock()
+	return len(fake.deleteTcpRouteMappingIfMatchArgsForCall)
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingIfMatchArgsForCall(i int) (models.TcpRouteMapping, models.ModificationTag) {
+	fake.deleteTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.deleteTcpRouteMappingIfMatchMutex.RUnlock()
+	return fake.deleteTcpRouteMappingIfMatchArgsForCall[i].tcpMapping, fake.deleteTcpRouteMappingIfMatchArgsForCall[i].expected
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingIfMatchReturns(result1 error) {
+	fake.DeleteTcpRouteMappingIfMatchStub = nil
+	fake.deleteTcpRouteMappingIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) ReadRevision(table string) (uint64, error) {
+	fake.readRevisionMutex.Lock()
+	fake.readRevisionArgsForCall = append(fake.readRevisionArgsForCall, struct {
+		table string
+	}{table})
+	fake.recordInvocation("ReadRevision", []interface{}{table})
+	fake.readRevisionMutex.Unlock()
+	if fake.ReadRevisionStub != nil {
+		return fake.ReadRevisionStub(table)
+	} else {
+		return fake.readRevisionReturns.result1, fake.readRevisionReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadRevisionCallCount() int {
+	fake.readRevisionMutex.RLock()
+	defer fake.readRevisionMutex.RUnlock()
+	return len(fake.readRevisionArgsForCall)
+}
+
+func (fake *FakeDB) ReadRevisionArgsForCall(i int) string {
+	fake.readRevisionMutex.RLock()
+	defer fake.readRevisionMutex.RUnlock()
+	return fake.readRevisionArgsForCall[i].table
+}
+
+func (fake *FakeDB) ReadRevisionReturns(result1 uint64, result2 error) {
+	fake.ReadRevisionStub = nil
+	fake.readRevisionReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) SaveRouteVersion(version models.RouteVersion, maxVersions int) error {
+	fake.saveRouteVersionMutex.Lock()
+	fake.saveRouteVersionArgsForCall = append(fake.saveRouteVersionArgsForCall, struct {
+		version     models.RouteVersion
+		maxVersions int
+	}{version, maxVersions})
+	fake.recordInvocation("SaveRouteVersion", []interface{}{version, maxVersions})
+	fake.saveRouteVersionMutex.Unlock()
+	if fake.SaveRouteVersionStub != nil {
+		return fake.SaveRouteVersionStub(version, maxVersions)
+	} else {
+		return fake.saveRouteVersionReturns.result1
+	}
+}
+
+func (fake *FakeDB) SaveRouteVersionCallCount() int {
+	fake.saveRouteVersionMutex.RLock()
+	defer fake.saveRouteVersionMutex.RUnlock()
+	return len(fake.saveRouteVersionArgsForCall)
+}
+
+func (fake *FakeDB) SaveRouteVersionArgsForCall(i int) (models.RouteVersion, int) {
+	fake.saveRouteVersionMutex.RLock()
+	defer fake.saveRouteVersionMutex.RUnlock()
+	return fake.saveRouteVersionArgsForCall[i].version, fake.saveRouteVersionArgsForCall[i].maxVersions
+}
+
+func (fake *FakeDB) SaveRouteVersionReturns(result1 error) {
+	fake.SaveRouteVersionStub = nil
+	fake.saveRouteVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) ReadRouteVersions(kind string, key string) ([]models.RouteVersion, error) {
+	fake.readRouteVersionsMutex.Lock()
+	fake.readRouteVersionsArgsForCall = append(fake.readRouteVersionsArgsForCall, struct {
+		kind string
+		key  string
+	}{kind, key})
+	fake.recordInvocation("ReadRouteVersions", []interface{}{kind, key})
+	fake.readRouteVersionsMutex.Unlock()
+	if fake.ReadRouteVersionsStub != nil {
+		return fake.ReadRouteVersionsStub(kind, key)
+	} else {
+		return fake.readRouteVersionsReturns.result1, fake.readRouteVersionsReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadRouteVersionsCallCount() int {
+	fake.readRouteVersionsMutex.RLock()
+	defer fake.readRouteVersionsMutex.RUnlock()
+	return len(fake.readRouteVersionsArgsForCall)
+}
+
+func (fake *FakeDB) ReadRouteVersionsArgsForCall(i int) (string, string) {
+	fake.readRouteVersionsMutex.RLock()
+	defer fake.readRouteVersionsMutex.RUnlock()
+	return fake.readRouteVersionsArgsForCall[i].kind, fake.readRouteVersionsArgsForCall[i].key
+}
+
+func (fake *FakeDB) ReadRouteVersionsReturns(result1 []models.RouteVersion, result2 error) {
+	fake.ReadRouteVersionsStub = nil
+	fake.readRouteVersionsReturns = struct {
+		result1 []models.RouteVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) DrainRoute(route models.Route, drainTTL int) error {
+	fake.drainRouteMutex.Lock()
+	fake.drainRouteArgsForCall = append(fake.drainRouteArgsForCall, struct {
+		route    models.Route
+		drainTTL int
+	}{route, drainTTL})
+	fake.recordInvocation("DrainRoute", []interface{}{route, drainTTL})
+	fake.drainRouteMutex.Unlock()
+	if fake.DrainRouteStub != nil {
+		return fake.DrainRouteStub(route, drainTTL)
+	} else {
+		return fake.drainRouteReturns.result1
+	}
+}
+
+func (fake *FakeDB) DrainRouteCallCount() int {
+	fake.drainRouteMutex.RLock()
+	defer fake.drainRouteMutex.RUnlock()
+	return len(fake.drainRouteArgsForCall)
+}
+
+func (fake *FakeDB) DrainRouteArgsForCall(i int) (models.Route, int) {
+	fake.drainRouteMutex.RLock()
+	defer fake.drainRouteMutex.RUnlock()
+	return fake.drainRouteArgsForCall[i].route, fake.drainRouteArgsForCall[i].drainTTL
+}
+
+func (fake *FakeDB) DrainRouteReturns(result1 error) {
+	fake.DrainRouteStub = nil
+	fake.drainRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) DrainRouteIfMatch(route models.Route, drainTTL int, expected models.ModificationTag) error {
+	fake.drainRouteIfMatchMutex.Lock()
+	fake.drainRouteIfMatchArgsForCall = append(fake.drainRouteIfMatchArgsForCall, struct {
+		route    models.Route
+		drainTTL int
+		expected models.ModificationTag
+	}{route, drainTTL, expected})
+	fake.recordInvocation("DrainRouteIfMatch", []interface{}{route, drainTTL, expected})
+	fake.drainRouteIfMatchMutex.Unlock()
+	if fake.DrainRouteIfMatchStub != nil {
+		return fake.DrainRouteIfMatchStub(route, drainTTL, expected)
+	} else {
+		return fake.drainRouteIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeDB) DrainRouteIfMatchCallCount() int {
+	fake.drainRouteIfMatchMutex.RLock()
+	defer fake.drainRouteIfMatchMutex.RUnlock()
+	return len(fake.drainRouteIfMatchArgsForCall)
+}
+
+func (fake *FakeDB) DrainRouteIfMatchArgsForCall(i int) (models.Route, int, models.ModificationTag) {
+	fake.drainRouteIfMatchMutex.RLock()
+	defer fake.drainRouteIfMatchMutex.RUnlock()
+	return fake.drainRouteIfMatchArgsForCall[i].route, fake.drainRouteIfMatchArgsForCall[i].drainTTL, fake.drainRouteIfMatchArgsForCall[i].expected
+}
+
+func (fake *FakeDB) DrainRouteIfMatchReturns(result1 error) {
+	fake.DrainRouteIfMatchStub = nil
+	fake.drainRouteIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) DrainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int) error {
+	fake.drainTcpRouteMappingMutex.Lock()
+	fake.drainTcpRouteMappingArgsForCall = append(fake.drainTcpRouteMappingArgsForCall, struct {
+		tcpMapping models.TcpRouteMapping
+		drainTTL   int
+	}{tcpMapping, drainTTL})
+	fake.recordInvocation("DrainTcpRouteMapping", []interface{}{tcpMapping, drainTTL})
+	fake.drainTcpRouteMappingMutex.Unlock()
+	if fake.DrainTcpRouteMappingStub != nil {
+		return fake.DrainTcpRouteMappingStub(tcpMapping, drainTTL)
+	} else {
+		return fake.drainTcpRouteMappingReturns.result1
+	}
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingCallCount() int {
+	fake.drainTcpRouteMappingMutex.RLock()
+	defer fake.drainTcpRouteMappingMutex.RUnlock()
+	return len(fake.drainTcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingArgsForCall(i int) (models.TcpRouteMapping, int) {
+	fake.drainTcpRouteMappingMutex.RLock()
+	defer fake.drainTcpRouteMappingMutex.RUnlock()
+	return fake.drainTcpRouteMappingArgsForCall[i].tcpMapping, fake.drainTcpRouteMappingArgsForCall[i].drainTTL
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingReturns(result1 error) {
+	fake.DrainTcpRouteMappingStub = nil
+	fake.drainTcpRouteMappingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, drainTTL int, expected models.ModificationTag) error {
+	fake.drainTcpRouteMappingIfMatchMutex.Lock()
+	fake.drainTcpRouteMappingIfMatchArgsForCall = append(fake.drainTcpRouteMappingIfMatchArgsForCall, struct {
+		tcpMapping models.TcpRouteMapping
+		drainTTL   int
+		expected   models.ModificationTag
+	}{tcpMapping, drainTTL, expected})
+	fake.recordInvocation("DrainTcpRouteMappingIfMatch", []interface{}{tcpMapping, drainTTL, expected})
+	fake.drainTcpRouteMappingIfMatchMutex.Unlock()
+	if fake.DrainTcpRouteMappingIfMatchStub != nil {
+		return fake.DrainTcpRouteMappingIfMatchStub(tcpMapping, drainTTL, expected)
+	} else {
+		return fake.drainTcpRouteMappingIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingIfMatchCallCount() int {
+	fake.drainTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.drainTcpRouteMappingIfMatchMutex.RUnlock()
+	return len(fake.drainTcpRouteMappingIfMatchArgsForCall)
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingIfMatchArgsForCall(i int) (models.TcpRouteMapping, int, models.ModificationTag) {
+	fake.drainTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.drainTcpRouteMappingIfMatchMutex.RUnlock()
+	return fake.drainTcpRouteMappingIfMatchArgsForCall[i].tcpMapping, fake.drainTcpRouteMappingIfMatchArgsForCall[i].drainTTL, fake.drainTcpRouteMappingIfMatchArgsForCall[i].expected
+}
+
+func (fake *FakeDB) DrainTcpRouteMappingIfMatchReturns(result1 error) {
+	fake.DrainTcpRouteMappingIfMatchStub = nil
+	fake.drainTcpRouteMappingIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) DeleteRoutesBySelector(selector models.RouteSelector, dryRun bool) ([]models.Route, error) {
+	fake.deleteRoutesBySelectorMutex.Lock()
+	fake.deleteRoutesBySelectorArgsForCall = append(fake.deleteRoutesBySelectorArgsForCall, struct {
+		selector models.RouteSelector
+		dryRun   bool
+	}{selector, dryRun})
+	fake.recordInvocation("DeleteRoutesBySelector", []interface{}{selector, dryRun})
+	fake.deleteRoutesBySelectorMutex.Unlock()
+	if fake.DeleteRoutesBySelectorStub != nil {
+		return fake.DeleteRoutesBySelectorStub(selector, dryRun)
+	} else {
+		return fake.deleteRoutesBySelectorReturns.result1, fake.deleteRoutesBySelectorReturns.result2
+	}
+}
+
+func (fake *FakeDB) DeleteRoutesBySelectorCallCount() int {
+	fake.deleteRoutesBySelectorMutex.RLock()
+	defer fake.deleteRoutesBySelectorMutex.RUnlock()
+	return len(fake.deleteRoutesBySelectorArgsForCall)
+}
+
+func (fake *FakeDB) DeleteRoutesBySelectorArgsForCall(i int) (models.RouteSelector, bool) {
+	fake.deleteRoutesBySelectorMutex.RLock()
+	defer fake.deleteRoutesBySelectorMutex.RUnlock()
+	return fake.deleteRoutesBySelectorArgsForCall[i].selector, fake.deleteRoutesBySelectorArgsForCall[i].dryRun
+}
+
+func (fake *FakeDB) DeleteRoutesBySelectorReturns(result1 []models.Route, result2 error) {
+	fake.DeleteRoutesBySelectorStub = nil
+	fake.deleteRoutesBySelectorReturns = struct {
+		result1 []models.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingsBySelector(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error) {
+	fake.deleteTcpRouteMappingsBySelectorMutex.Lock()
+	fake.deleteTcpRouteMappingsBySelectorArgsForCall = append(fake.deleteTcpRouteMappingsBySelectorArgsForCall, struct {
+		selector models.TcpRouteMappingSelector
+		dryRun   bool
+	}{selector, dryRun})
+	fake.recordInvocation("DeleteTcpRouteMappingsBySelector", []interface{}{selector, dryRun})
+	fake.deleteTcpRouteMappingsBySelectorMutex.Unlock()
+	if fake.DeleteTcpRouteMappingsBySelectorStub != nil {
+		return fake.DeleteTcpRouteMappingsBySelectorStub(selector, dryRun)
+	} else {
+		return fake.deleteTcpRouteMappingsBySelectorReturns.result1, fake.deleteTcpRouteMappingsBySelectorReturns.result2
+	}
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingsBySelectorCallCount() int {
+	fake.deleteTcpRouteMappingsBySelectorMutex.RLock()
+	defer fake.deleteTcpRouteMappingsBySelectorMutex.RUnlock()
+	return len(fake.deleteTcpRouteMappingsBySelectorArgsForCall)
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingsBySelectorArgsForCall(i int) (models.TcpRouteMappingSelector, bool) {
+	fake.deleteTcpRouteMappingsBySelectorMutex.RLock()
+	defer fake.deleteTcpRouteMappingsBySelectorMutex.RUnlock()
+	return fake.deleteTcpRouteMappingsBySelectorArgsForCall[i].selector, fake.deleteTcpRouteMappingsBySelectorArgsForCall[i].dryRun
+}
+
+func (fake *FakeDB) DeleteTcpRouteMappingsBySelectorReturns(result1 []models.TcpRouteMapping, result2 error) {
+	fake.DeleteTcpRouteMappingsBySelectorStub = nil
+	fake.deleteTcpRouteMappingsBySelectorReturns = struct {
+		result1 []models.TcpRouteMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) CountRoutes(selector models.RouteSelector) (int, error) {
+	fake.countRoutesMutex.Lock()
+	fake.countRoutesArgsForCall = append(fake.countRoutesArgsForCall, struct {
+		selector models.RouteSelector
+	}{selector})
+	fake.recordInvocation("CountRoutes", []interface{}{selector})
+	fake.countRoutesMutex.Unlock()
+	if fake.CountRoutesStub != nil {
+		return fake.CountRoutesStub(selector)
+	} else {
+		return fake.countRoutesReturns.result1, fake.countRoutesReturns.result2
+	}
+}
+
+func (fake *FakeDB) CountRoutesCallCount() int {
+	fake.countRoutesMutex.RLock()
+	defer fake.countRoutesMutex.RUnlock()
+	return len(fake.countRoutesArgsForCall)
+}
+
+func (fake *FakeDB) CountRoutesArgsForCall(i int) models.RouteSelector {
+	fake.countRoutesMutex.RLock()
+	defer fake.countRoutesMutex.RUnlock()
+	return fake.countRoutesArgsForCall[i].selector
+}
+
+func (fake *FakeDB) CountRoutesReturns(result1 int, result2 error) {
+	fake.CountRoutesStub = nil
+	fake.countRoutesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) CountTcpRouteMappings(selector models.TcpRouteMappingSelector) (int, error) {
+	fake.countTcpRouteMappingsMutex.Lock()
+	fake.countTcpRouteMappingsArgsForCall = append(fake.countTcpRouteMappingsArgsForCall, struct {
+		selector models.TcpRouteMappingSelector
+	}{selector})
+	fake.recordInvocation("CountTcpRouteMappings", []interface{}{selector})
+	fake.countTcpRouteMappingsMutex.Unlock()
+	if fake.CountTcpRouteMappingsStub != nil {
+		return fake.CountTcpRouteMappingsStub(selector)
+	} else {
+		return fake.countTcpRouteMappingsReturns.result1, fake.countTcpRouteMappingsReturns.result2
+	}
+}
+
+func (fake *FakeDB) CountTcpRouteMappingsCallCount() int {
+	fake.countTcpRouteMappingsMutex.RLock()
+	defer fake.countTcpRouteMappingsMutex.RUnlock()
+	return len(fake.countTcpRouteMappingsArgsForCall)
+}
+
+func (fake *FakeDB) CountTcpRouteMappingsArgsForCall(i int) models.TcpRouteMappingSelector {
+	fake.countTcpRouteMappingsMutex.RLock()
+	defer fake.countTcpRouteMappingsMutex.RUnlock()
+	return fake.countTcpRouteMappingsArgsForCall[i].selector
+}
+
+func (fake *FakeDB) CountTcpRouteMappingsReturns(result1 int, result2 error) {
+	fake.CountTcpRouteMappingsStub = nil
+	fake.countTcpRouteMappingsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) StreamRoutes(fn func(models.Route) error) error {
+	fake.streamRoutesMutex.Lock()
+	fake.streamRoutesArgsForCall = append(fake.streamRoutesArgsForCall, struct {
+		fn func(models.Route) error
+	}{fn})
+	fake.recordInvocation("StreamRoutes", []interface{}{fn})
+	fake.streamRoutesMutex.Unlock()
+	if fake.StreamRoutesStub != nil {
+		return fake.StreamRoutesStub(fn)
+	} else {
+		return fake.streamRoutesReturns.result1
+	}
+}
+
+func (fake *FakeDB) StreamRoutesCallCount() int {
+	fake.streamRoutesMutex.RLock()
+	defer fake.streamRoutesMutex.RUnlock()
+	return len(fake.streamRoutesArgsForCall)
+}
+
+func (fake *FakeDB) StreamRoutesArgsForCall(i int) func(models.Route) error {
+	fake.streamRoutesMutex.RLock()
+	defer fake.streamRoutesMutex.RUnlock()
+	return fake.streamRoutesArgsForCall[i].fn
+}
+
+func (fake *FakeDB) StreamRoutesReturns(result1 error) {
+	fake.StreamRoutesStub = nil
+	fake.streamRoutesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) StreamTcpRouteMappings(fn func(models.TcpRouteMapping) error) error {
+	fake.streamTcpRouteMappingsMutex.Lock()
+	fake.streamTcpRouteMappingsArgsForCall = append(fake.streamTcpRouteMappingsArgsForCall, struct {
+		fn func(models.TcpRouteMapping) error
+	}{fn})
+	fake.recordInvocation("StreamTcpRouteMappings", []interface{}{fn})
+	fake.streamTcpRouteMappingsMutex.Unlock()
+	if fake.StreamTcpRouteMappingsStub != nil {
+		return fake.StreamTcpRouteMappingsStub(fn)
+	} else {
+		return fake.streamTcpRouteMappingsReturns.result1
+	}
+}
+
+func (fake *FakeDB) StreamTcpRouteMappingsCallCount() int {
+	fake.streamTcpRouteMappingsMutex.RLock()
+	defer fake.streamTcpRouteMappingsMutex.RUnlock()
+	return len(fake.streamTcpRouteMappingsArgsForCall)
+}
+
+func (fake *FakeDB) StreamTcpRouteMappingsArgsForCall(i int) func(models.TcpRouteMapping) error {
+	fake.streamTcpRouteMappingsMutex.RLock()
+	defer fake.streamTcpRouteMappingsMutex.RUnlock()
+	return fake.streamTcpRouteMappingsArgsForCall[i].fn
+}
+
+func (fake *FakeDB) StreamTcpRouteMappingsReturns(result1 error) {
+	fake.StreamTcpRouteMappingsStub = nil
+	fake.streamTcpRouteMappingsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) ReadRouteByGuid(guid string) (models.Route, error) {
+	fake.readRouteByGuidMutex.Lock()
+	fake.readRouteByGuidArgsForCall = append(fake.readRouteByGuidArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("ReadRouteByGuid", []interface{}{guid})
+	fake.readRouteByGuidMutex.Unlock()
+	if fake.ReadRouteByGuidStub != nil {
+		return fake.ReadRouteByGuidStub(guid)
+	} else {
+		return fake.readRouteByGuidReturns.result1, fake.readRouteByGuidReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadRouteByGuidCallCount() int {
+	fake.readRouteByGuidMutex.RLock()
+	defer fake.readRouteByGuidMutex.RUnlock()
+	return len(fake.readRouteByGuidArgsForCall)
+}
+
+func (fake *FakeDB) ReadRouteByGuidArgsForCall(i int) string {
+	fake.readRouteByGuidMutex.RLock()
+	defer fake.readRouteByGuidMutex.RUnlock()
+	return fake.readRouteByGuidArgsForCall[i].guid
+}
+
+func (fake *FakeDB) ReadRouteByGuidReturns(result1 models.Route, result2 error) {
+	fake.ReadRouteByGuidStub = nil
+	fake.readRouteByGuidReturns = struct {
+		result1 models.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingByGuid(guid string) (models.TcpRouteMapping, error) {
+	fake.readTcpRouteMappingByGuidMutex.Lock()
+	fake.readTcpRouteMappingByGuidArgsForCall = append(fake.readTcpRouteMappingByGuidArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("ReadTcpRouteMappingByGuid", []interface{}{guid})
+	fake.readTcpRouteMappingByGuidMutex.Unlock()
+	if fake.ReadTcpRouteMappingByGuidStub != nil {
+		return fake.ReadTcpRouteMappingByGuidStub(guid)
+	} else {
+		return fake.readTcpRouteMappingByGuidReturns.result1, fake.readTcpRouteMappingByGuidReturns.result2
+	}
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingByGuidCallCount() int {
+	fake.readTcpRouteMappingByGuidMutex.RLock()
+	defer fake.readTcpRouteMappingByGuidMutex.RUnlock()
+	return len(fake.readTcpRouteMappingByGuidArgsForCall)
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingByGuidArgsForCall(i int) string {
+	fake.readTcpRouteMappingByGuidMutex.RLock()
+	defer fake.readTcpRouteMappingByGuidMutex.RUnlock()
+	return fake.readTcpRouteMappingByGuidArgsForCall[i].guid
+}
+
+func (fake *FakeDB) ReadTcpRouteMappingByGuidReturns(result1 models.TcpRouteMapping, result2 error) {
+	fake.ReadTcpRouteMappingByGuidStub = nil
+	fake.readTcpRouteMappingByGuidReturns = struct {
+		result1 models.TcpRouteMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) Ping() error {
+	fake.pingMutex.Lock()
+	fake.pingArgsForCall = append(fake.pingArgsForCall, struct{}{})
+	fake.recordInvocation("Ping", []interface{}{})
+	fake.pingMutex.Unlock()
+	if fake.PingStub != nil {
+		return fake.PingStub()
+	} else {
+		return fake.pingReturns.result1
+	}
+}
+
+func (fake *FakeDB) PingCallCount() int {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	return len(fake.pingArgsForCall)
+}
+
+func (fake *FakeDB) PingReturns(result1 error) {
+	fake.PingStub = nil
+	fake.pingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) PruneRouteVersions(olderThan time.Time) error {
+	fake.pruneRouteVersionsMutex.Lock()
+	fake.pruneRouteVersionsArgsForCall = append(fake.pruneRouteVersionsArgsForCall, struct {
+		olderThan time.Time
+	}{olderThan})
+	fake.recordInvocation("PruneRouteVersions", []interface{}{olderThan})
+	fake.pruneRouteVersionsMutex.Unlock()
+	if fake.PruneRouteVersionsStub != nil {
+		return fake.PruneRouteVersionsStub(olderThan)
+	} else {
+		return fake.pruneRouteVersionsReturns.result1
+	}
+}
+
+func (fake *FakeDB) PruneRouteVersionsCallCount() int {
+	fake.pruneRouteVersionsMutex.RLock()
+	defer fake.pruneRouteVersionsMutex.RUnlock()
+	return len(fake.pruneRouteVersionsArgsForCall)
+}
+
+func (fake *FakeDB) PruneRouteVersionsArgsForCall(i int) time.Time {
+	fake.pruneRouteVersionsMutex.RLock()
+	defer fake.pruneRouteVersionsMutex.RUnlock()
+	return fake.pruneRouteVersionsArgsForCall[i].olderThan
+}
+
+func (fake *FakeDB) PruneRouteVersionsReturns(result1 error) {
+	fake.PruneRouteVersionsStub = nil
+	fake.pruneRouteVersionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) CountRoutesPerOwner() (map[string]int, error) {
+	fake.countRoutesPerOwnerMutex.Lock()
+	fake.countRoutesPerOwnerArgsForCall = append(fake.countRoutesPerOwnerArgsForCall, struct{}{})
+	fake.recordInvocation("CountRoutesPerOwner", []interface{}{})
+	fake.countRoutesPerOwnerMutex.Unlock()
+	if fake.CountRoutesPerOwnerStub != nil {
+		return fake.CountRoutesPerOwnerStub()
+	} else {
+		return fake.countRoutesPerOwnerReturns.result1, fake.countRoutesPerOwnerReturns.result2
+	}
+}
+
+func (fake *FakeDB) CountRoutesPerOwnerCallCount() int {
+	fake.countRoutesPerOwnerMutex.RLock()
+	defer fake.countRoutesPerOwnerMutex.RUnlock()
+	return len(fake.countRoutesPerOwnerArgsForCall)
+}
+
+func (fake *FakeDB) CountRoutesPerOwnerReturns(result1 map[string]int, result2 error) {
+	fake.CountRoutesPerOwnerStub = nil
+	fake.countRoutesPerOwnerReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) CountRoutesPerLogGuid() (map[string]int, error) {
+	fake.countRoutesPerLogGuidMutex.Lock()
+	fake.countRoutesPerLogGuidArgsForCall = append(fake.countRoutesPerLogGuidArgsForCall, struct{}{})
+	fake.recordInvocation("CountRoutesPerLogGuid", []interface{}{})
+	fake.countRoutesPerLogGuidMutex.Unlock()
+	if fake.CountRoutesPerLogGuidStub != nil {
+		return fake.CountRoutesPerLogGuidStub()
+	} else {
+		return fake.countRoutesPerLogGuidReturns.result1, fake.countRoutesPerLogGuidReturns.result2
+	}
+}
+
+func (fake *FakeDB) CountRoutesPerLogGuidCallCount() int {
+	fake.countRoutesPerLogGuidMutex.RLock()
+	defer fake.countRoutesPerLogGuidMutex.RUnlock()
+	return len(fake.countRoutesPerLogGuidArgsForCall)
+}
+
+func (fake *FakeDB) CountRoutesPerLogGuidReturns(result1 map[string]int, result2 error) {
+	fake.CountRoutesPerLogGuidStub = nil
+	fake.countRoutesPerLogGuidReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) CountTcpRouteMappingsPerOwner() (map[string]int, error) {
+	fake.countTcpRouteMappingsPerOwnerMutex.Lock()
+	fake.countTcpRouteMappingsPerOwnerArgsForCall = append(fake.countTcpRouteMappingsPerOwnerArgsForCall, struct{}{})
+	fake.recordInvocation("CountTcpRouteMappingsPerOwner", []interface{}{})
+	fake.countTcpRouteMappingsPerOwnerMutex.Unlock()
+	if fake.CountTcpRouteMappingsPerOwnerStub != nil {
+		return fake.CountTcpRouteMappingsPerOwnerStub()
+	} else {
+		return fake.countTcpRouteMappingsPerOwnerReturns.result1, fake.countTcpRouteMappingsPerOwnerReturns.result2
+	}
+}
+
+func (fake *FakeDB) CountTcpRouteMappingsPerOwnerCallCount() int {
+	fake.countTcpRouteMappingsPerOwnerMutex.RLock()
+	defer fake.countTcpRouteMappingsPerOwnerMutex.RUnlock()
+	return len(fake.countTcpRouteMappingsPerOwnerArgsForCall)
+}
+
+func (fake *FakeDB) CountTcpRouteMappingsPerOwnerReturns(result1 map[string]int, result2 error) {
+	fake.CountTcpRouteMappingsPerOwnerStub = nil
+	fake.countTcpRouteMappingsPerOwnerReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDB) CreateRoute(route models.Route) error {
+	fake.createRouteMutex.Lock()
+	fake.createRouteArgsForCall = append(fake.createRouteArgsForCall, struct {
+		route models.Route
+	}{route})
+	fake.recordInvocation("CreateRoute", []interface{}{route})
+	fake.createRouteMutex.Unlock()
+	if fake.CreateRouteStub != nil {
+		return fake.CreateRouteStub(route)
+	} else {
+		return fake.createRouteReturns.result1
+	}
+}
+
+func (fake *FakeDB) CreateRouteCallCount() int {
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	return len(fake.createRouteArgsForCall)
+}
+
+func (fake *FakeDB) CreateRouteArgsForCall(i int) models.Route {
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	return fake.createRouteArgsForCall[i].route
+}
+
+func (fake *FakeDB) CreateRouteReturns(result1 error) {
+	fake.CreateRouteStub = nil
+	fake.createRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDB) CreateTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
+	fake.createTcpRouteMappingMutex.Lock()
+	fake.createTcpRouteMappingArgsForCall = append(fake.createTcpRouteMappingArgsForCall, struct {
+		tcpMapping models.TcpRouteMapping
+	}{tcpMapping})
+	fake.recordInvocation("CreateTcpRouteMapping", []interface{}{tcpMapping})
+	fake.createTcpRouteMappingMutex.Unlock()
+	if fake.CreateTcpRouteMappingStub != nil {
+		return fake.CreateTcpRouteMappingStub(tcpMapping)
+	} else {
+		return fake.createTcpRouteMappingReturns.result1
+	}
+}
+
+func (fake *FakeDB) CreateTcpRouteMappingCallCount() int {
+	fake.createTcpRouteMappingMutex.RLock()
+	defer fake.createTcpRouteMappingMutex.RUnlock()
+	return len(fake.createTcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeDB) CreateTcpRouteMappingArgsForCall(i int) models.TcpRouteMapping {
+	fake.createTcpRouteMappingMutex.RLock()
+	defer fake.createTcpRouteMappingMutex.RUnlock()
+	return fake.createTcpRouteMappingArgsForCall[i].tcpMapping
+}
+
+func (fake *FakeDB) CreateTcpRouteMappingReturns(result1 error) {
+	fake.CreateTcpRouteMappingStub = nil
+	fake.createTcpRouteMappingReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -450,6 +1740,68 @@ func (fake *FakeDB) Invocations() map[string][][]interface{} {
 	defer fake.cancelWatchesMutex.RUnlock()
 	fake.watchChangesMutex.RLock()
 	defer fake.watchChangesMutex.RUnlock()
+	fake.readRouteMutex.RLock()
+	defer fake.readRouteMutex.RUnlock()
+	fake.readTcpRouteMappingMutex.RLock()
+	defer fake.readTcpRouteMappingMutex.RUnlock()
+	fake.saveAuditRecordMutex.RLock()
+	defer fake.saveAuditRecordMutex.RUnlock()
+	fake.readAuditRecordsMutex.RLock()
+	defer fake.readAuditRecordsMutex.RUnlock()
+	fake.pruneAuditRecordsMutex.RLock()
+	defer fake.pruneAuditRecordsMutex.RUnlock()
+	fake.saveRouteIfMatchMutex.RLock()
+	defer fake.saveRouteIfMatchMutex.RUnlock()
+	fake.deleteRouteIfMatchMutex.RLock()
+	defer fake.deleteRouteIfMatchMutex.RUnlock()
+	fake.saveTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.saveTcpRouteMappingIfMatchMutex.RUnlock()
+	fake.deleteTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.deleteTcpRouteMappingIfMatchMutex.RUnlock()
+	fake.readRevisionMutex.RLock()
+	defer fake.readRevisionMutex.RUnlock()
+	fake.saveRouteVersionMutex.RLock()
+	defer fake.saveRouteVersionMutex.RUnlock()
+	fake.readRouteVersionsMutex.RLock()
+	defer fake.readRouteVersionsMutex.RUnlock()
+	fake.drainRouteMutex.RLock()
+	defer fake.drainRouteMutex.RUnlock()
+	fake.drainRouteIfMatchMutex.RLock()
+	defer fake.drainRouteIfMatchMutex.RUnlock()
+	fake.drainTcpRouteMappingMutex.RLock()
+	defer fake.drainTcpRouteMappingMutex.RUnlock()
+	fake.drainTcpRouteMappingIfMatchMutex.RLock()
+	defer fake.drainTcpRouteMappingIfMatchMutex.RUnlock()
+	fake.deleteRoutesBySelectorMutex.RLock()
+	defer fake.deleteRoutesBySelectorMutex.RUnlock()
+	fake.deleteTcpRouteMappingsBySelectorMutex.RLock()
+	defer fake.deleteTcpRouteMappingsBySelectorMutex.RUnlock()
+	fake.countRoutesMutex.RLock()
+	defer fake.countRoutesMutex.RUnlock()
+	fake.countTcpRouteMappingsMutex.RLock()
+	defer fake.countTcpRouteMappingsMutex.RUnlock()
+	fake.streamRoutesMutex.RLock()
+	defer fake.streamRoutesMutex.RUnlock()
+	fake.streamTcpRouteMappingsMutex.RLock()
+	defer fake.streamTcpRouteMappingsMutex.RUnlock()
+	fake.readRouteByGuidMutex.RLock()
+	defer fake.readRouteByGuidMutex.RUnlock()
+	fake.readTcpRouteMappingByGuidMutex.RLock()
+	defer fake.readTcpRouteMappingByGuidMutex.RUnlock()
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	fake.pruneRouteVersionsMutex.RLock()
+	defer fake.pruneRouteVersionsMutex.RUnlock()
+	fake.countRoutesPerOwnerMutex.RLock()
+	defer fake.countRoutesPerOwnerMutex.RUnlock()
+	fake.countRoutesPerLogGuidMutex.RLock()
+	defer fake.countRoutesPerLogGuidMutex.RUnlock()
+	fake.countTcpRouteMappingsPerOwnerMutex.RLock()
+	defer fake.countTcpRouteMappingsPerOwnerMutex.RUnlock()
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	fake.createTcpRouteMappingMutex.RLock()
+	defer fake.createTcpRouteMappingMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,316 @@
+package db
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/routing-api/models"
+	"github.com/coreos/etcd/Godeps/_workspace/src/golang.org/x/net/context"
+)
+
+// OperationObserver is called with the duration and the error of every
+// database operation.
+type OperationObserver func(operation string, duration time.Duration, err error)
+
+// NewInstrumentedDB returns a database that reports the duration of the
+// operations of database to observe. Watches are not operations and are not
+// reported.
+func NewInstrumentedDB(database DB, observe OperationObserver) DB {
+	return &instrumentedDB{db: database, observe: observe}
+}
+
+type instrumentedDB struct {
+	db      DB
+	observe OperationObserver
+}
+
+func (d *instrumentedDB) withRequestID(id string) DB {
+	return &instrumentedDB{db: WithRequestID(d.db, id), observe: d.observe}
+}
+
+func (d *instrumentedDB) ReadRoutes() ([]models.Route, error) {
+	start := time.Now()
+	result, err := d.db.ReadRoutes()
+	d.observe("ReadRoutes", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) StreamRoutes(fn func(models.Route) error) error {
+	start := time.Now()
+	err := d.db.StreamRoutes(fn)
+	d.observe("StreamRoutes", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) ReadRoute(route models.Route) (models.Route, error) {
+	start := time.Now()
+	result, err := d.db.ReadRoute(route)
+	d.observe("ReadRoute", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) ReadRouteByGuid(guid string) (models.Route, error) {
+	start := time.Now()
+	result, err := d.db.ReadRouteByGuid(guid)
+	d.observe("ReadRouteByGuid", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) SaveRoute(route models.Route) error {
+	start := time.Now()
+	err := d.db.SaveRoute(route)
+	d.observe("SaveRoute", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) CreateRoute(route models.Route) error {
+	start := time.Now()
+	err := d.db.CreateRoute(route)
+	d.observe("CreateRoute", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DeleteRoute(route models.Route) error {
+	start := time.Now()
+	err := d.db.DeleteRoute(route)
+	d.observe("DeleteRoute", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) SaveRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	start := time.Now()
+	err := d.db.SaveRouteIfMatch(route, expected)
+	d.observe("SaveRouteIfMatch", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DeleteRouteIfMatch(route models.Route, expected models.ModificationTag) error {
+	start := time.Now()
+	err := d.db.DeleteRouteIfMatch(route, expected)
+	d.observe("DeleteRouteIfMatch", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DrainRoute(route models.Route, drainTTL int) error {
+	start := time.Now()
+	err := d.db.DrainRoute(route, drainTTL)
+	d.observe("DrainRoute", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DrainRouteIfMatch(route models.Route, drainTTL int, expected models.ModificationTag) error {
+	start := time.Now()
+	err := d.db.DrainRouteIfMatch(route, drainTTL, expected)
+	d.observe("DrainRouteIfMatch", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DeleteRoutesBySelector(selector models.RouteSelector, dryRun bool) ([]models.Route, error) {
+	start := time.Now()
+	result, err := d.db.DeleteRoutesBySelector(selector, dryRun)
+	d.observe("DeleteRoutesBySelector", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) CountRoutes(selector models.RouteSelector) (int, error) {
+	start := time.Now()
+	result, err := d.db.CountRoutes(selector)
+	d.observe("CountRoutes", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) CountRoutesPerOwner() (map[string]int, error) {
+	start := time.Now()
+	result, err := d.db.CountRoutesPerOwner()
+	d.observe("CountRoutesPerOwner", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) CountRoutesPerLogGuid() (map[string]int, error) {
+	start := time.Now()
+	result, err := d.db.CountRoutesPerLogGuid()
+	d.observe("CountRoutesPerLogGuid", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) ReadTcpRouteMappings() ([]models.TcpRouteMapping, error) {
+	start := time.Now()
+	result, err := d.db.ReadTcpRouteMappings()
+	d.observe("ReadTcpRouteMappings", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) StreamTcpRouteMappings(fn func(models.TcpRouteMapping) error) error {
+	start := time.Now()
+	err := d.db.StreamTcpRouteMappings(fn)
+	d.observe("StreamTcpRouteMappings", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) ReadTcpRouteMapping(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error) {
+	start := time.Now()
+	result, err := d.db.ReadTcpRouteMapping(tcpMapping)
+	d.observe("ReadTcpRouteMapping", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) ReadTcpRouteMappingByGuid(guid string) (models.TcpRouteMapping, error) {
+	start := time.Now()
+	result, err := d.db.ReadTcpRouteMappingByGuid(guid)
+	d.observe("ReadTcpRouteMappingByGuid", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) SaveTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
+	start := time.Now()
+	err := d.db.SaveTcpRouteMapping(tcpMapping)
+	d.observe("SaveTcpRouteMapping", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) CreateTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
+	start := time.Now()
+	err := d.db.CreateTcpRouteMapping(tcpMapping)
+	d.observe("CreateTcpRouteMapping", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DeleteTcpRouteMapping(tcpMapping models.TcpRouteMapping) error {
+	start := time.Now()
+	err := d.db.DeleteTcpRouteMapping(tcpMapping)
+	d.observe("DeleteTcpRouteMapping", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) SaveTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	start := time.Now()
+	err := d.db.SaveTcpRouteMappingIfMatch(tcpMapping, expected)
+	d.observe("SaveTcpRouteMappingIfMatch", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DeleteTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, expected models.ModificationTag) error {
+	start := time.Now()
+	err := d.db.DeleteTcpRouteMappingIfMatch(tcpMapping, expected)
+	d.observe("DeleteTcpRouteMappingIfMatch", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DrainTcpRouteMapping(tcpMapping models.TcpRouteMapping, drainTTL int) error {
+	start := time.Now()
+	err := d.db.DrainTcpRouteMapping(tcpMapping, drainTTL)
+	d.observe("DrainTcpRouteMapping", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DrainTcpRouteMappingIfMatch(tcpMapping models.TcpRouteMapping, drainTTL int, expected models.ModificationTag) error {
+	start := time.Now()
+	err := d.db.DrainTcpRouteMappingIfMatch(tcpMapping, drainTTL, expected)
+	d.observe("DrainTcpRouteMappingIfMatch", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) DeleteTcpRouteMappingsBySelector(selector models.TcpRouteMappingSelector, dryRun bool) ([]models.TcpRouteMapping, error) {
+	start := time.Now()
+	result, err := d.db.DeleteTcpRouteMappingsBySelector(selector, dryRun)
+	d.observe("DeleteTcpRouteMappingsBySelector", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) CountTcpRouteMappings(selector models.TcpRouteMappingSelector) (int, error) {
+	start := time.Now()
+	result, err := d.db.CountTcpRouteMappings(selector)
+	d.observe("CountTcpRouteMappings", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) CountTcpRouteMappingsPerOwner() (map[string]int, error) {
+	start := time.Now()
+	result, err := d.db.CountTcpRouteMappingsPerOwner()
+	d.observe("CountTcpRouteMappingsPerOwner", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) ReadRouterGroups() (models.RouterGroups, error) {
+	start := time.Now()
+	result, err := d.db.ReadRouterGroups()
+	d.observe("ReadRouterGroups", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) ReadRouterGroup(guid string) (models.RouterGroup, error) {
+	start := time.Now()
+	result, err := d.db.ReadRouterGroup(guid)
+	d.observe("ReadRouterGroup", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) SaveRouterGroup(routerGroup models.RouterGroup) error {
+	start := time.Now()
+	err := d.db.SaveRouterGroup(routerGroup)
+	d.observe("SaveRouterGroup", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) SaveAuditRecord(record models.AuditRecord) error {
+	start := time.Now()
+	err := d.db.SaveAuditRecord(record)
+	d.observe("SaveAuditRecord", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) ReadAuditRecords(filter models.AuditFilter) ([]models.AuditRecord, error) {
+	start := time.Now()
+	result, err := d.db.ReadAuditRecords(filter)
+	d.observe("ReadAuditRecords", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) PruneAuditRecords(olderThan time.Time, maxRecords int) error {
+	start := time.Now()
+	err := d.db.PruneAuditRecords(olderThan, maxRecords)
+	d.observe("PruneAuditRecords", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) ReadRevision(table string) (uint64, error) {
+	start := time.Now()
+	result, err := d.db.ReadRevision(table)
+	d.observe("ReadRevision", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) SaveRouteVersion(version models.RouteVersion, maxVersions int) error {
+	start := time.Now()
+	err := d.db.SaveRouteVersion(version, maxVersions)
+	d.observe("SaveRouteVersion", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) ReadRouteVersions(kind, key string) ([]models.RouteVersion, error) {
+	start := time.Now()
+	result, err := d.db.ReadRouteVersions(kind, key)
+	d.observe("ReadRouteVersions", time.Since(start), err)
+	return result, err
+}
+
+func (d *instrumentedDB) PruneRouteVersions(olderThan time.Time) error {
+	start := time.Now()
+	err := d.db.PruneRouteVersions(olderThan)
+	d.observe("PruneRouteVersions", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) Ping() error {
+	start := time.Now()
+	err := d.db.Ping()
+	d.observe("Ping", time.Since(start), err)
+	return err
+}
+
+func (d *instrumentedDB) CancelWatches() {
+	d.db.CancelWatches()
+}
+
+func (d *instrumentedDB) WatchChanges(watchType string) (<-chan Event, <-chan error, context.CancelFunc) {
+	return d.db.WatchChanges(watchType)
+}
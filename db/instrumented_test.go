@@ -0,0 +1,64 @@
+package db_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InstrumentedDB", func() {
+	type observation struct {
+		operation string
+		err       error
+	}
+
+	var (
+		fakeDB       *fakes.FakeDB
+		database     db.DB
+		observations []observation
+	)
+
+	BeforeEach(func() {
+		fakeDB = &fakes.FakeDB{}
+		observations = nil
+		database = db.NewInstrumentedDB(fakeDB, func(operation string, duration time.Duration, err error) {
+			Expect(duration).To(BeNumerically(">=", 0))
+			observations = append(observations, observation{operation, err})
+		})
+	})
+
+	It("reports the operations and their results", func() {
+		fakeDB.ReadRoutesReturns([]models.Route{{Route: "a.example.com"}}, nil)
+		routes, err := database.ReadRoutes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(1))
+
+		saveErr := errors.New("boom")
+		fakeDB.SaveRouteReturns(saveErr)
+		Expect(database.SaveRoute(models.Route{})).To(Equal(saveErr))
+
+		Expect(observations).To(Equal([]observation{
+			{"ReadRoutes", nil},
+			{"SaveRoute", saveErr},
+		}))
+	})
+
+	It("does not report watches", func() {
+		database.WatchChanges(db.HTTP_WATCH)
+		database.CancelWatches()
+
+		Expect(fakeDB.WatchChangesCallCount()).To(Equal(1))
+		Expect(fakeDB.CancelWatchesCallCount()).To(Equal(1))
+		Expect(observations).To(BeEmpty())
+	})
+
+	It("keeps reporting with a request id", func() {
+		Expect(db.WithRequestID(database, "some-request-id").Ping()).To(Succeed())
+		Expect(observations).To(Equal([]observation{{"Ping", nil}}))
+	})
+})
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/coreos/etcd/client"
+)
+
+// tableRevisions holds the revision of each table for the SqlDB. Only the
+// routing-api instance holding the lock writes to the database, so like the
+// event hubs the revisions are kept in memory. They start at the time the
+// instance started, in nanoseconds, so that they keep increasing when another
+// instance takes over.
+type tableRevisions struct {
+	http         uint64
+	tcp          uint64
+	routerGroups uint64
+}
+
+func newTableRevisions(start uint64) *tableRevisions {
+	return &tableRevisions{
+		http:         start,
+		tcp:          start,
+		routerGroups: start,
+	}
+}
+
+func (r *tableRevisions) read(table string) (uint64, error) {
+	revision, err := r.revision(table)
+	if err != nil {
+		return 0, err
+	}
+	return atomic.LoadUint64(revision), nil
+}
+
+func (r *tableRevisions) increment(table string) uint64 {
+	revision, err := r.revision(table)
+	if err != nil {
+		return 0
+	}
+	return atomic.AddUint64(revision, 1)
+}
+
+func (r *tableRevisions) revision(table string) (*uint64, error) {
+	switch table {
+	case HTTP_ROUTES_TABLE:
+		return &r.http, nil
+	case TCP_ROUTES_TABLE:
+		return &r.tcp, nil
+	case ROUTER_GROUPS_TABLE:
+		return &r.routerGroups, nil
+	default:
+		return nil, fmt.Errorf("Invalid table: %s", table)
+	}
+}
+
+// etcdTableRevision is the revision of a table in etcd, derived from its keys.
+// Creates and updates raise the highest modified index of the keys, which is
+// then the revision. Deletes and expiries leave no key behind, but lower the
+// number of keys unless a create raised the highest index as well. When the
+// number of keys changed, or the table was not read before, the revision is
+// the current etcd index, as the index of the delete is not known.
+type etcdTableRevision struct {
+	index         uint64
+	modifiedIndex uint64
+	keys          int
+	revision      uint64
+}
+
+// addKeys counts the keys below node and keeps their highest modified index.
+func (r *etcdTableRevision) addKeys(node *client.Node) {
+	if node == nil {
+		return
+	}
+	if node.ModifiedIndex > r.modifiedIndex {
+		r.modifiedIndex = node.ModifiedIndex
+	}
+	if !node.Dir {
+		r.keys++
+	}
+	for _, child := range node.Nodes {
+		r.addKeys(child)
+	}
+}
+
+// next returns the revision of the table read as r after it was read as
+// previous, if it was read before.
+func (r etcdTableRevision) next(previous etcdTableRevision, ok bool) uint64 {
+	switch {
+	case !ok || r.keys != previous.keys:
+		return r.index
+	case r.modifiedIndex != previous.modifiedIndex:
+		return r.modifiedIndex
+	default:
+		return previous.revision
+	}
+}
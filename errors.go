@@ -1,9 +1,22 @@
 package routing_api
 
+import "code.cloudfoundry.org/routing-api/models"
+
 type Type string
 type Error struct {
 	Type    Type   `json:"name"`
 	Message string `json:"message"`
+
+	// ModificationTag is the current tag of the resource when a conditional
+	// write fails with a PreconditionFailedError.
+	ModificationTag *models.ModificationTag `json:"modification_tag,omitempty"`
+
+	// Details lists every violation of a request that failed validation.
+	Details []ErrorDetail `json:"details,omitempty"`
+
+	// RequestID is the id of the failed request, to find it in the logs of
+	// the server.
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func (err Error) Error() string {
@@ -17,6 +30,30 @@ func NewError(errType Type, message string) Error {
 	}
 }
 
+// ErrorDetail is a violation of a single field of an item of a request.
+// Index is the position of the item in the request body.
+type ErrorDetail struct {
+	Index   int        `json:"index"`
+	Field   string     `json:"field,omitempty"`
+	Code    DetailCode `json:"code"`
+	Message string     `json:"message"`
+}
+
+func (detail ErrorDetail) Error() string {
+	return detail.Message
+}
+
+// DetailCode identifies the kind of violation of an ErrorDetail.
+type DetailCode string
+
+const (
+	MissingFieldCode DetailCode = "missing"
+	InvalidFieldCode DetailCode = "invalid"
+	OutOfRangeCode   DetailCode = "out_of_range"
+	NotFoundCode     DetailCode = "not_found"
+	UnknownFieldCode DetailCode = "unknown"
+)
+
 const (
 	ResponseError               Type = "ResponseError"
 	ResourceNotFoundError       Type = "ResourceNotFoundError"
@@ -27,4 +64,8 @@ const (
 	UnauthorizedError           Type = "UnauthorizedError"
 	TcpRouteMappingInvalidError Type = "TcpRouteMappingInvalidError"
 	DBConflictError             Type = "DBConflictError"
+	PreconditionFailedError     Type = "PreconditionFailedError"
+	QuotaExceededError          Type = "QuotaExceededError"
+	PayloadTooLargeError        Type = "PayloadTooLargeError"
+	NotImplementedError         Type = "NotImplementedError"
 )
@@ -1,9 +1,12 @@
 package routing_api
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
 	trace "code.cloudfoundry.org/trace-logger"
 	"github.com/vito/go-sse/sse"
 )
@@ -23,9 +26,23 @@ type eventSource struct {
 	rawEventSource RawEventSource
 }
 
+// ResyncRequiredAction is the action of the events that tell a subscriber it
+// lost events because it fell behind. The subscriber must read all routes
+// again; the events after it are received as usual.
+const ResyncRequiredAction = "resync-required"
+
 type Event struct {
 	Route  models.Route
 	Action string
+	// Revision is the revision of the routes table after the event, or 0 if
+	// the server does not report it.
+	Revision uint64
+	// RequestID is the id of the request that caused the event, if the server
+	// reports it.
+	RequestID string
+	// Dropped is the number of events lost by the subscriber when Action is
+	// ResyncRequiredAction.
+	Dropped uint64
 }
 
 func NewEventSource(raw RawEventSource) EventSource {
@@ -43,6 +60,9 @@ type TcpEventSource interface {
 type TcpEvent struct {
 	TcpRouteMapping models.TcpRouteMapping
 	Action          string
+	Revision        uint64
+	RequestID       string
+	Dropped         uint64
 }
 
 type tcpEventSource struct {
@@ -105,6 +125,18 @@ func doClose(rawEventSource RawEventSource) error {
 }
 
 func convertRawEvent(event sse.Event) (Event, error) {
+	if !isJSONEvent(event) {
+		data, err := base64.StdEncoding.DecodeString(string(event.Data))
+		if err != nil {
+			return Event{}, err
+		}
+		route, metadata, err := protos.UnmarshalRouteEvent(data)
+		if err != nil {
+			return Event{}, err
+		}
+		return Event{Action: event.Name, Route: route, Revision: metadata.Revision, RequestID: metadata.RequestID, Dropped: metadata.Dropped}, nil
+	}
+
 	var route models.Route
 
 	err := json.Unmarshal(event.Data, &route)
@@ -112,10 +144,23 @@ func convertRawEvent(event sse.Event) (Event, error) {
 		return Event{}, err
 	}
 
-	return Event{Action: event.Name, Route: route}, nil
+	metadata := readEventMetadata(event)
+	return Event{Action: event.Name, Route: route, Revision: metadata.Revision, RequestID: metadata.RequestID, Dropped: metadata.Dropped}, nil
 }
 
 func convertRawToTcpEvent(event sse.Event) (TcpEvent, error) {
+	if !isJSONEvent(event) {
+		data, err := base64.StdEncoding.DecodeString(string(event.Data))
+		if err != nil {
+			return TcpEvent{}, err
+		}
+		route, metadata, err := protos.UnmarshalTcpRouteMappingEvent(data)
+		if err != nil {
+			return TcpEvent{}, err
+		}
+		return TcpEvent{Action: event.Name, TcpRouteMapping: route, Revision: metadata.Revision, RequestID: metadata.RequestID, Dropped: metadata.Dropped}, nil
+	}
+
 	var route models.TcpRouteMapping
 
 	err := json.Unmarshal(event.Data, &route)
@@ -123,5 +168,27 @@ func convertRawToTcpEvent(event sse.Event) (TcpEvent, error) {
 		return TcpEvent{}, err
 	}
 
-	return TcpEvent{Action: event.Name, TcpRouteMapping: route}, nil
+	metadata := readEventMetadata(event)
+	return TcpEvent{Action: event.Name, TcpRouteMapping: route, Revision: metadata.Revision, RequestID: metadata.RequestID, Dropped: metadata.Dropped}, nil
+}
+
+// isJSONEvent tells JSON event data apart from the base64 encoded protobuf
+// events sent to clients that accept protobuf.
+func isJSONEvent(event sse.Event) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(event.Data), []byte("{"))
+}
+
+type eventMetadata struct {
+	Revision  uint64 `json:"revision"`
+	RequestID string `json:"request_id"`
+	Dropped   uint64 `json:"dropped"`
+}
+
+// readEventMetadata reads the revision and request id the server adds next to
+// the fields of the route in the event data, and the number of events dropped
+// of resync-required events.
+func readEventMetadata(event sse.Event) eventMetadata {
+	var data eventMetadata
+	_ = json.Unmarshal(event.Data, &data)
+	return data
 }
@@ -4,12 +4,14 @@ import (
 	"errors"
 
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"io/ioutil"
 
 	"code.cloudfoundry.org/routing-api"
 	"code.cloudfoundry.org/routing-api/fake_routing_api"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
 	trace "code.cloudfoundry.org/trace-logger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -83,6 +85,71 @@ var _ = Describe("EventSource", func() {
 						Expect(err).ToNot(HaveOccurred())
 						Expect(event).To(Equal(expectedEvent))
 					})
+
+					It("returns the table revision carried by the event", func() {
+						rawEvent := sse.Event{
+							ID:    "1",
+							Name:  "Upsert",
+							Data:  []byte(`{"revision":5,"route":"jim.com","port":8080,"ip":"1.1.1.1","ttl":60,"log_guid":"logs"}`),
+							Retry: 1,
+						}
+
+						fakeRawEventSource.NextReturns(rawEvent, nil)
+						event, err := eventSource.Next()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.Revision).To(Equal(uint64(5)))
+						Expect(event.Route.Route).To(Equal("jim.com"))
+					})
+
+					It("returns the request id carried by the event", func() {
+						rawEvent := sse.Event{
+							ID:    "1",
+							Name:  "Upsert",
+							Data:  []byte(`{"revision":5,"request_id":"some-request-id","route":"jim.com","port":8080,"ip":"1.1.1.1","ttl":60,"log_guid":"logs"}`),
+							Retry: 1,
+						}
+
+						fakeRawEventSource.NextReturns(rawEvent, nil)
+						event, err := eventSource.Next()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.RequestID).To(Equal("some-request-id"))
+						Expect(event.Route.Route).To(Equal("jim.com"))
+					})
+
+					It("returns the events dropped before a resync-required event", func() {
+						rawEvent := sse.Event{
+							ID:   "1",
+							Name: routing_api.ResyncRequiredAction,
+							Data: []byte(`{"dropped":12}`),
+						}
+
+						fakeRawEventSource.NextReturns(rawEvent, nil)
+						event, err := eventSource.Next()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.Action).To(Equal("resync-required"))
+						Expect(event.Dropped).To(Equal(uint64(12)))
+						Expect(event.Route).To(Equal(models.Route{}))
+					})
+
+					It("decodes base64 encoded protobuf events", func() {
+						route := models.NewRoute("jim.com", 8080, "1.1.1.1", "logs", "", 60)
+						data, err := protos.MarshalRouteEvent(route, protos.EventMetadata{Revision: 7, RequestID: "request-id"})
+						Expect(err).ToNot(HaveOccurred())
+						rawEvent := sse.Event{
+							ID:    "1",
+							Name:  "Upsert",
+							Data:  []byte(base64.StdEncoding.EncodeToString(data)),
+							Retry: 1,
+						}
+
+						fakeRawEventSource.NextReturns(rawEvent, nil)
+						event, err := eventSource.Next()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.Action).To(Equal("Upsert"))
+						Expect(event.Revision).To(Equal(uint64(7)))
+						Expect(event.RequestID).To(Equal("request-id"))
+						Expect(event.Route).To(Equal(route))
+					})
 				})
 
 				Context("When the event is unmarshalled successfully", func() {
@@ -189,6 +256,25 @@ var _ = Describe("EventSource", func() {
 						Expect(err).ToNot(HaveOccurred())
 						Expect(event).To(Equal(expectedEvent))
 					})
+
+					It("decodes base64 encoded protobuf events", func() {
+						tcpMapping := models.NewTcpRouteMapping("rguid1", 52000, "1.1.1.1", 60000, 5)
+						data, err := protos.MarshalTcpRouteMappingEvent(tcpMapping, protos.EventMetadata{Revision: 3, RequestID: "request-id"})
+						Expect(err).ToNot(HaveOccurred())
+						rawEvent := sse.Event{
+							ID:    "1",
+							Name:  "Upsert",
+							Data:  []byte(base64.StdEncoding.EncodeToString(data)),
+							Retry: 1,
+						}
+
+						fakeRawEventSource.NextReturns(rawEvent, nil)
+						event, err := tcpEventSource.Next()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(event.Revision).To(Equal(uint64(3)))
+						Expect(event.RequestID).To(Equal("request-id"))
+						Expect(event.TcpRouteMapping).To(Equal(tcpMapping))
+					})
 				})
 
 				Context("When the event has invalid json", func() {
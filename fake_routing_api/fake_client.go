@@ -2,7 +2,9 @@
 package fake_routing_api
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	routing_api "code.cloudfoundry.org/routing-api"
 	"code.cloudfoundry.org/routing-api/models"
@@ -107,6 +109,187 @@ type FakeClient struct {
 		result1 routing_api.TcpEventSource
 		result2 error
 	}
+	AuditRecordsStub        func(models.AuditFilter) ([]models.AuditRecord, error)
+	auditRecordsMutex       sync.RWMutex
+	auditRecordsArgsForCall []struct {
+		arg1 models.AuditFilter
+	}
+	auditRecordsReturns struct {
+		result1 []models.AuditRecord
+		result2 error
+	}
+	UpsertRoutesIfMatchStub        func([]models.Route, models.ModificationTag) error
+	upsertRoutesIfMatchMutex       sync.RWMutex
+	upsertRoutesIfMatchArgsForCall []struct {
+		arg1 []models.Route
+		arg2 models.ModificationTag
+	}
+	upsertRoutesIfMatchReturns struct {
+		result1 error
+	}
+	DeleteRoutesIfMatchStub        func([]models.Route, models.ModificationTag) error
+	deleteRoutesIfMatchMutex       sync.RWMutex
+	deleteRoutesIfMatchArgsForCall []struct {
+		arg1 []models.Route
+		arg2 models.ModificationTag
+	}
+	deleteRoutesIfMatchReturns struct {
+		result1 error
+	}
+	UpsertTcpRouteMappingsIfMatchStub        func([]models.TcpRouteMapping, models.ModificationTag) error
+	upsertTcpRouteMappingsIfMatchMutex       sync.RWMutex
+	upsertTcpRouteMappingsIfMatchArgsForCall []struct {
+		arg1 []models.TcpRouteMapping
+		arg2 models.ModificationTag
+	}
+	upsertTcpRouteMappingsIfMatchReturns struct {
+		result1 error
+	}
+	DeleteTcpRouteMappingsIfMatchStub        func([]models.TcpRouteMapping, models.ModificationTag) error
+	deleteTcpRouteMappingsIfMatchMutex       sync.RWMutex
+	deleteTcpRouteMappingsIfMatchArgsForCall []struct {
+		arg1 []models.TcpRouteMapping
+		arg2 models.ModificationTag
+	}
+	deleteTcpRouteMappingsIfMatchReturns struct {
+		result1 error
+	}
+	RouteHistoryStub        func(models.Route) ([]models.RouteVersion, error)
+	routeHistoryMutex       sync.RWMutex
+	routeHistoryArgsForCall []struct {
+		arg1 models.Route
+	}
+	routeHistoryReturns struct {
+		result1 []models.RouteVersion
+		result2 error
+	}
+	TcpRouteMappingHistoryStub        func(models.TcpRouteMapping) ([]models.RouteVersion, error)
+	tcpRouteMappingHistoryMutex       sync.RWMutex
+	tcpRouteMappingHistoryArgsForCall []struct {
+		arg1 models.TcpRouteMapping
+	}
+	tcpRouteMappingHistoryReturns struct {
+		result1 []models.RouteVersion
+		result2 error
+	}
+	DrainRoutesStub        func([]models.Route, time.Duration) error
+	drainRoutesMutex       sync.RWMutex
+	drainRoutesArgsForCall []struct {
+		arg1 []models.Route
+		arg2 time.Duration
+	}
+	drainRoutesReturns struct {
+		result1 error
+	}
+	DrainTcpRouteMappingsStub        func([]models.TcpRouteMapping, time.Duration) error
+	drainTcpRouteMappingsMutex       sync.RWMutex
+	drainTcpRouteMappingsArgsForCall []struct {
+		arg1 []models.TcpRouteMapping
+		arg2 time.Duration
+	}
+	drainTcpRouteMappingsReturns struct {
+		result1 error
+	}
+	DeleteRoutesBySelectorStub        func(models.RouteSelector, bool) ([]models.Route, error)
+	deleteRoutesBySelectorMutex       sync.RWMutex
+	deleteRoutesBySelectorArgsForCall []struct {
+		arg1 models.RouteSelector
+		arg2 bool
+	}
+	deleteRoutesBySelectorReturns struct {
+		result1 []models.Route
+		result2 error
+	}
+	DeleteTcpRouteMappingsBySelectorStub        func(models.TcpRouteMappingSelector, bool) ([]models.TcpRouteMapping, error)
+	deleteTcpRouteMappingsBySelectorMutex       sync.RWMutex
+	deleteTcpRouteMappingsBySelectorArgsForCall []struct {
+		arg1 models.TcpRouteMappingSelector
+		arg2 bool
+	}
+	deleteTcpRouteMappingsBySelectorReturns struct {
+		result1 []models.TcpRouteMapping
+		result2 error
+	}
+	QuotaUsageStub        func() ([]models.QuotaUsage, error)
+	quotaUsageMutex       sync.RWMutex
+	quotaUsageArgsForCall []struct{}
+	quotaUsageReturns     struct {
+		result1 []models.QuotaUsage
+		result2 error
+	}
+	UpsertRoutesDryRunStub        func([]models.Route) ([]models.RouteChange, error)
+	upsertRoutesDryRunMutex       sync.RWMutex
+	upsertRoutesDryRunArgsForCall []struct {
+		arg1 []models.Route
+	}
+	upsertRoutesDryRunReturns struct {
+		result1 []models.RouteChange
+		result2 error
+	}
+	DeleteRoutesDryRunStub        func([]models.Route) ([]models.RouteChange, error)
+	deleteRoutesDryRunMutex       sync.RWMutex
+	deleteRoutesDryRunArgsForCall []struct {
+		arg1 []models.Route
+	}
+	deleteRoutesDryRunReturns struct {
+		result1 []models.RouteChange
+		result2 error
+	}
+	UpdateRouterGroupDryRunStub        func(models.RouterGroup) (models.RouterGroupChange, error)
+	updateRouterGroupDryRunMutex       sync.RWMutex
+	updateRouterGroupDryRunArgsForCall []struct {
+		arg1 models.RouterGroup
+	}
+	updateRouterGroupDryRunReturns struct {
+		result1 models.RouterGroupChange
+		result2 error
+	}
+	UpsertTcpRouteMappingsDryRunStub        func([]models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error)
+	upsertTcpRouteMappingsDryRunMutex       sync.RWMutex
+	upsertTcpRouteMappingsDryRunArgsForCall []struct {
+		arg1 []models.TcpRouteMapping
+	}
+	upsertTcpRouteMappingsDryRunReturns struct {
+		result1 []models.TcpRouteMappingChange
+		result2 error
+	}
+	DeleteTcpRouteMappingsDryRunStub        func([]models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error)
+	deleteTcpRouteMappingsDryRunMutex       sync.RWMutex
+	deleteTcpRouteMappingsDryRunArgsForCall []struct {
+		arg1 []models.TcpRouteMapping
+	}
+	deleteTcpRouteMappingsDryRunReturns struct {
+		result1 []models.TcpRouteMappingChange
+		result2 error
+	}
+	SubscribeToEventsWithWebSocketStub        func(filter models.EventFilter, window uint64) (routing_api.WebSocketEventSource, error)
+	subscribeToEventsWithWebSocketMutex       sync.RWMutex
+	subscribeToEventsWithWebSocketArgsForCall []struct {
+		filter models.EventFilter
+		window uint64
+	}
+	subscribeToEventsWithWebSocketReturns struct {
+		result1 routing_api.WebSocketEventSource
+		result2 error
+	}
+	SubscribeToTcpEventsWithWebSocketStub        func(filter models.EventFilter, window uint64) (routing_api.TcpWebSocketEventSource, error)
+	subscribeToTcpEventsWithWebSocketMutex       sync.RWMutex
+	subscribeToTcpEventsWithWebSocketArgsForCall []struct {
+		filter models.EventFilter
+		window uint64
+	}
+	subscribeToTcpEventsWithWebSocketReturns struct {
+		result1 routing_api.TcpWebSocketEventSource
+		result2 error
+	}
+	WithContextStub        func(arg1 context.Context) routing_api.Client
+	withContextMutex       sync.RWMutex
+	withContextArgsForCall []struct {
+		arg1 context.Context
+	}
+	withContextReturns struct {
+		result1 routing_api.Client
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -518,6 +701,731 @@ func (fake *FakeClient) SubscribeToTcpEventsWithMaxRetriesReturns(result1 routin
 	}{result1, result2}
 }
 
+func (fake *FakeClient) AuditRecords(arg1 models.AuditFilter) ([]models.AuditRecord, error) {
+	fake.auditRecordsMutex.Lock()
+	fake.auditRecordsArgsForCall = append(fake.auditRecordsArgsForCall, struct {
+		arg1 models.AuditFilter
+	}{arg1})
+	fake.recordInvocation("AuditRecords", []interface{}{arg1})
+	fake.auditRecordsMutex.Unlock()
+	if fake.AuditRecordsStub != nil {
+		return fake.AuditRecordsStub(arg1)
+	} else {
+		return fake.auditRecordsReturns.result1, fake.auditRecordsReturns.result2
+	}
+}
+
+func (fake *FakeClient) AuditRecordsCallCount() int {
+	fake.auditRecordsMutex.RLock()
+	defer fake.auditRecordsMutex.RUnlock()
+	return len(fake.auditRecordsArgsForCall)
+}
+
+func (fake *FakeClient) AuditRecordsArgsForCall(i int) models.AuditFilter {
+	fake.auditRecordsMutex.RLock()
+	defer fake.auditRecordsMutex.RUnlock()
+	return fake.auditRecordsArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) AuditRecordsReturns(result1 []models.AuditRecord, result2 error) {
+	fake.AuditRecordsStub = nil
+	fake.auditRecordsReturns = struct {
+		result1 []models.AuditRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpsertRoutesIfMatch(arg1 []models.Route, arg2 models.ModificationTag) error {
+	var arg1Copy []models.Route
+	if arg1 != nil {
+		arg1Copy = make([]models.Route, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.upsertRoutesIfMatchMutex.Lock()
+	fake.upsertRoutesIfMatchArgsForCall = append(fake.upsertRoutesIfMatchArgsForCall, struct {
+		arg1 []models.Route
+		arg2 models.ModificationTag
+	}{arg1Copy, arg2})
+	fake.recordInvocation("UpsertRoutesIfMatch", []interface{}{arg1Copy, arg2})
+	fake.upsertRoutesIfMatchMutex.Unlock()
+	if fake.UpsertRoutesIfMatchStub != nil {
+		return fake.UpsertRoutesIfMatchStub(arg1, arg2)
+	} else {
+		return fake.upsertRoutesIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeClient) UpsertRoutesIfMatchCallCount() int {
+	fake.upsertRoutesIfMatchMutex.RLock()
+	defer fake.upsertRoutesIfMatchMutex.RUnlock()
+	return len(fake.upsertRoutesIfMatchArgsForCall)
+}
+
+func (fake *FakeClient) UpsertRoutesIfMatchArgsForCall(i int) ([]models.Route, models.ModificationTag) {
+	fake.upsertRoutesIfMatchMutex.RLock()
+	defer fake.upsertRoutesIfMatchMutex.RUnlock()
+	return fake.upsertRoutesIfMatchArgsForCall[i].arg1, fake.upsertRoutesIfMatchArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) UpsertRoutesIfMatchReturns(result1 error) {
+	fake.UpsertRoutesIfMatchStub = nil
+	fake.upsertRoutesIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteRoutesIfMatch(arg1 []models.Route, arg2 models.ModificationTag) error {
+	var arg1Copy []models.Route
+	if arg1 != nil {
+		arg1Copy = make([]models.Route, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteRoutesIfMatchMutex.Lock()
+	fake.deleteRoutesIfMatchArgsForCall = append(fake.deleteRoutesIfMatchArgsForCall, struct {
+		arg1 []models.Route
+		arg2 models.ModificationTag
+	}{arg1Copy, arg2})
+	fake.recordInvocation("DeleteRoutesIfMatch", []interface{}{arg1Copy, arg2})
+	fake.deleteRoutesIfMatchMutex.Unlock()
+	if fake.DeleteRoutesIfMatchStub != nil {
+		return fake.DeleteRoutesIfMatchStub(arg1, arg2)
+	} else {
+		return fake.deleteRoutesIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeClient) DeleteRoutesIfMatchCallCount() int {
+	fake.deleteRoutesIfMatchMutex.RLock()
+	defer fake.deleteRoutesIfMatchMutex.RUnlock()
+	return len(fake.deleteRoutesIfMatchArgsForCall)
+}
+
+func (fake *FakeClient) DeleteRoutesIfMatchArgsForCall(i int) ([]models.Route, models.ModificationTag) {
+	fake.deleteRoutesIfMatchMutex.RLock()
+	defer fake.deleteRoutesIfMatchMutex.RUnlock()
+	return fake.deleteRoutesIfMatchArgsForCall[i].arg1, fake.deleteRoutesIfMatchArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) DeleteRoutesIfMatchReturns(result1 error) {
+	fake.DeleteRoutesIfMatchStub = nil
+	fake.deleteRoutesIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsIfMatch(arg1 []models.TcpRouteMapping, arg2 models.ModificationTag) error {
+	var arg1Copy []models.TcpRouteMapping
+	if arg1 != nil {
+		arg1Copy = make([]models.TcpRouteMapping, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.upsertTcpRouteMappingsIfMatchMutex.Lock()
+	fake.upsertTcpRouteMappingsIfMatchArgsForCall = append(fake.upsertTcpRouteMappingsIfMatchArgsForCall, struct {
+		arg1 []models.TcpRouteMapping
+		arg2 models.ModificationTag
+	}{arg1Copy, arg2})
+	fake.recordInvocation("UpsertTcpRouteMappingsIfMatch", []interface{}{arg1Copy, arg2})
+	fake.upsertTcpRouteMappingsIfMatchMutex.Unlock()
+	if fake.UpsertTcpRouteMappingsIfMatchStub != nil {
+		return fake.UpsertTcpRouteMappingsIfMatchStub(arg1, arg2)
+	} else {
+		return fake.upsertTcpRouteMappingsIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsIfMatchCallCount() int {
+	fake.upsertTcpRouteMappingsIfMatchMutex.RLock()
+	defer fake.upsertTcpRouteMappingsIfMatchMutex.RUnlock()
+	return len(fake.upsertTcpRouteMappingsIfMatchArgsForCall)
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsIfMatchArgsForCall(i int) ([]models.TcpRouteMapping, models.ModificationTag) {
+	fake.upsertTcpRouteMappingsIfMatchMutex.RLock()
+	defer fake.upsertTcpRouteMappingsIfMatchMutex.RUnlock()
+	return fake.upsertTcpRouteMappingsIfMatchArgsForCall[i].arg1, fake.upsertTcpRouteMappingsIfMatchArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsIfMatchReturns(result1 error) {
+	fake.UpsertTcpRouteMappingsIfMatchStub = nil
+	fake.upsertTcpRouteMappingsIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsIfMatch(arg1 []models.TcpRouteMapping, arg2 models.ModificationTag) error {
+	var arg1Copy []models.TcpRouteMapping
+	if arg1 != nil {
+		arg1Copy = make([]models.TcpRouteMapping, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteTcpRouteMappingsIfMatchMutex.Lock()
+	fake.deleteTcpRouteMappingsIfMatchArgsForCall = append(fake.deleteTcpRouteMappingsIfMatchArgsForCall, struct {
+		arg1 []models.TcpRouteMapping
+		arg2 models.ModificationTag
+	}{arg1Copy, arg2})
+	fake.recordInvocation("DeleteTcpRouteMappingsIfMatch", []interface{}{arg1Copy, arg2})
+	fake.deleteTcpRouteMappingsIfMatchMutex.Unlock()
+	if fake.DeleteTcpRouteMappingsIfMatchStub != nil {
+		return fake.DeleteTcpRouteMappingsIfMatchStub(arg1, arg2)
+	} else {
+		return fake.deleteTcpRouteMappingsIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsIfMatchCallCount() int {
+	fake.deleteTcpRouteMappingsIfMatchMutex.RLock()
+	defer fake.deleteTcpRouteMappingsIfMatchMutex.RUnlock()
+	return len(fake.deleteTcpRouteMappingsIfMatchArgsForCall)
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsIfMatchArgsForCall(i int) ([]models.TcpRouteMapping, models.ModificationTag) {
+	fake.deleteTcpRouteMappingsIfMatchMutex.RLock()
+	defer fake.deleteTcpRouteMappingsIfMatchMutex.RUnlock()
+	return fake.deleteTcpRouteMappingsIfMatchArgsForCall[i].arg1, fake.deleteTcpRouteMappingsIfMatchArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsIfMatchReturns(result1 error) {
+	fake.DeleteTcpRouteMappingsIfMatchStub = nil
+	fake.deleteTcpRouteMappingsIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RouteHistory(arg1 models.Route) ([]models.RouteVersion, error) {
+	fake.routeHistoryMutex.Lock()
+	fake.routeHistoryArgsForCall = append(fake.routeHistoryArgsForCall, struct {
+		arg1 models.Route
+	}{arg1})
+	fake.recordInvocation("RouteHistory", []interface{}{arg1})
+	fake.routeHistoryMutex.Unlock()
+	if fake.RouteHistoryStub != nil {
+		return fake.RouteHistoryStub(arg1)
+	} else {
+		return fake.routeHistoryReturns.result1, fake.routeHistoryReturns.result2
+	}
+}
+
+func (fake *FakeClient) RouteHistoryCallCount() int {
+	fake.routeHistoryMutex.RLock()
+	defer fake.routeHistoryMutex.RUnlock()
+	return len(fake.routeHistoryArgsForCall)
+}
+
+func (fake *FakeClient) RouteHistoryArgsForCall(i int) models.Route {
+	fake.routeHistoryMutex.RLock()
+	defer fake.routeHistoryMutex.RUnlock()
+	return fake.routeHistoryArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) RouteHistoryReturns(result1 []models.RouteVersion, result2 error) {
+	fake.RouteHistoryStub = nil
+	fake.routeHistoryReturns = struct {
+		result1 []models.RouteVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) TcpRouteMappingHistory(arg1 models.TcpRouteMapping) ([]models.RouteVersion, error) {
+	fake.tcpRouteMappingHistoryMutex.Lock()
+	fake.tcpRouteMappingHistoryArgsForCall = append(fake.tcpRouteMappingHistoryArgsForCall, struct {
+		arg1 models.TcpRouteMapping
+	}{arg1})
+	fake.recordInvocation("TcpRouteMappingHistory", []interface{}{arg1})
+	fake.tcpRouteMappingHistoryMutex.Unlock()
+	if fake.TcpRouteMappingHistoryStub != nil {
+		return fake.TcpRouteMappingHistoryStub(arg1)
+	} else {
+		return fake.tcpRouteMappingHistoryReturns.result1, fake.tcpRouteMappingHistoryReturns.result2
+	}
+}
+
+func (fake *FakeClient) TcpRouteMappingHistoryCallCount() int {
+	fake.tcpRouteMappingHistoryMutex.RLock()
+	defer fake.tcpRouteMappingHistoryMutex.RUnlock()
+	return len(fake.tcpRouteMappingHistoryArgsForCall)
+}
+
+func (fake *FakeClient) TcpRouteMappingHistoryArgsForCall(i int) models.TcpRouteMapping {
+	fake.tcpRouteMappingHistoryMutex.RLock()
+	defer fake.tcpRouteMappingHistoryMutex.RUnlock()
+	return fake.tcpRouteMappingHistoryArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) TcpRouteMappingHistoryReturns(result1 []models.RouteVersion, result2 error) {
+	fake.TcpRouteMappingHistoryStub = nil
+	fake.tcpRouteMappingHistoryReturns = struct {
+		result1 []models.RouteVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DrainRoutes(arg1 []models.Route, arg2 time.Duration) error {
+	var arg1Copy []models.Route
+	if arg1 != nil {
+		arg1Copy = make([]models.Route, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.drainRoutesMutex.Lock()
+	fake.drainRoutesArgsForCall = append(fake.drainRoutesArgsForCall, struct {
+		arg1 []models.Route
+		arg2 time.Duration
+	}{arg1Copy, arg2})
+	fake.recordInvocation("DrainRoutes", []interface{}{arg1Copy, arg2})
+	fake.drainRoutesMutex.Unlock()
+	if fake.DrainRoutesStub != nil {
+		return fake.DrainRoutesStub(arg1, arg2)
+	} else {
+		return fake.drainRoutesReturns.result1
+	}
+}
+
+func (fake *FakeClient) DrainRoutesCallCount() int {
+	fake.drainRoutesMutex.RLock()
+	defer fake.drainRoutesMutex.RUnlock()
+	return len(fake.drainRoutesArgsForCall)
+}
+
+func (fake *FakeClient) DrainRoutesArgsForCall(i int) ([]models.Route, time.Duration) {
+	fake.drainRoutesMutex.RLock()
+	defer fake.drainRoutesMutex.RUnlock()
+	return fake.drainRoutesArgsForCall[i].arg1, fake.drainRoutesArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) DrainRoutesReturns(result1 error) {
+	fake.DrainRoutesStub = nil
+	fake.drainRoutesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DrainTcpRouteMappings(arg1 []models.TcpRouteMapping, arg2 time.Duration) error {
+	var arg1Copy []models.TcpRouteMapping
+	if arg1 != nil {
+		arg1Copy = make([]models.TcpRouteMapping, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.drainTcpRouteMappingsMutex.Lock()
+	fake.drainTcpRouteMappingsArgsForCall = append(fake.drainTcpRouteMappingsArgsForCall, struct {
+		arg1 []models.TcpRouteMapping
+		arg2 time.Duration
+	}{arg1Copy, arg2})
+	fake.recordInvocation("DrainTcpRouteMappings", []interface{}{arg1Copy, arg2})
+	fake.drainTcpRouteMappingsMutex.Unlock()
+	if fake.DrainTcpRouteMappingsStub != nil {
+		return fake.DrainTcpRouteMappingsStub(arg1, arg2)
+	} else {
+		return fake.drainTcpRouteMappingsReturns.result1
+	}
+}
+
+func (fake *FakeClient) DrainTcpRouteMappingsCallCount() int {
+	fake.drainTcpRouteMappingsMutex.RLock()
+	defer fake.drainTcpRouteMappingsMutex.RUnlock()
+	return len(fake.drainTcpRouteMappingsArgsForCall)
+}
+
+func (fake *FakeClient) DrainTcpRouteMappingsArgsForCall(i int) ([]models.TcpRouteMapping, time.Duration) {
+	fake.drainTcpRouteMappingsMutex.RLock()
+	defer fake.drainTcpRouteMappingsMutex.RUnlock()
+	return fake.drainTcpRouteMappingsArgsForCall[i].arg1, fake.drainTcpRouteMappingsArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) DrainTcpRouteMappingsReturns(result1 error) {
+	fake.DrainTcpRouteMappingsStub = nil
+	fake.drainTcpRouteMappingsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteRoutesBySelector(arg1 models.RouteSelector, arg2 bool) ([]models.Route, error) {
+	fake.deleteRoutesBySelectorMutex.Lock()
+	fake.deleteRoutesBySelectorArgsForCall = append(fake.deleteRoutesBySelectorArgsForCall, struct {
+		arg1 models.RouteSelector
+		arg2 bool
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteRoutesBySelector", []interface{}{arg1, arg2})
+	fake.deleteRoutesBySelectorMutex.Unlock()
+	if fake.DeleteRoutesBySelectorStub != nil {
+		return fake.DeleteRoutesBySelectorStub(arg1, arg2)
+	} else {
+		return fake.deleteRoutesBySelectorReturns.result1, fake.deleteRoutesBySelectorReturns.result2
+	}
+}
+
+func (fake *FakeClient) DeleteRoutesBySelectorCallCount() int {
+	fake.deleteRoutesBySelectorMutex.RLock()
+	defer fake.deleteRoutesBySelectorMutex.RUnlock()
+	return len(fake.deleteRoutesBySelectorArgsForCall)
+}
+
+func (fake *FakeClient) DeleteRoutesBySelectorArgsForCall(i int) (models.RouteSelector, bool) {
+	fake.deleteRoutesBySelectorMutex.RLock()
+	defer fake.deleteRoutesBySelectorMutex.RUnlock()
+	return fake.deleteRoutesBySelectorArgsForCall[i].arg1, fake.deleteRoutesBySelectorArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) DeleteRoutesBySelectorReturns(result1 []models.Route, result2 error) {
+	fake.DeleteRoutesBySelectorStub = nil
+	fake.deleteRoutesBySelectorReturns = struct {
+		result1 []models.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsBySelector(arg1 models.TcpRouteMappingSelector, arg2 bool) ([]models.TcpRouteMapping, error) {
+	fake.deleteTcpRouteMappingsBySelectorMutex.Lock()
+	fake.deleteTcpRouteMappingsBySelectorArgsForCall = append(fake.deleteTcpRouteMappingsBySelectorArgsForCall, struct {
+		arg1 models.TcpRouteMappingSelector
+		arg2 bool
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteTcpRouteMappingsBySelector", []interface{}{arg1, arg2})
+	fake.deleteTcpRouteMappingsBySelectorMutex.Unlock()
+	if fake.DeleteTcpRouteMappingsBySelectorStub != nil {
+		return fake.DeleteTcpRouteMappingsBySelectorStub(arg1, arg2)
+	} else {
+		return fake.deleteTcpRouteMappingsBySelectorReturns.result1, fake.deleteTcpRouteMappingsBySelectorReturns.result2
+	}
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsBySelectorCallCount() int {
+	fake.deleteTcpRouteMappingsBySelectorMutex.RLock()
+	defer fake.deleteTcpRouteMappingsBySelectorMutex.RUnlock()
+	return len(fake.deleteTcpRouteMappingsBySelectorArgsForCall)
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsBySelectorArgsForCall(i int) (models.TcpRouteMappingSelector, bool) {
+	fake.deleteTcpRouteMappingsBySelectorMutex.RLock()
+	defer fake.deleteTcpRouteMappingsBySelectorMutex.RUnlock()
+	return fake.deleteTcpRouteMappingsBySelectorArgsForCall[i].arg1, fake.deleteTcpRouteMappingsBySelectorArgsForCall[i].arg2
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsBySelectorReturns(result1 []models.TcpRouteMapping, result2 error) {
+	fake.DeleteTcpRouteMappingsBySelectorStub = nil
+	fake.deleteTcpRouteMappingsBySelectorReturns = struct {
+		result1 []models.TcpRouteMapping
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) QuotaUsage() ([]models.QuotaUsage, error) {
+	fake.quotaUsageMutex.Lock()
+	fake.quotaUsageArgsForCall = append(fake.quotaUsageArgsForCall, struct{}{})
+	fake.recordInvocation("QuotaUsage", []interface{}{})
+	fake.quotaUsageMutex.Unlock()
+	if fake.QuotaUsageStub != nil {
+		return fake.QuotaUsageStub()
+	} else {
+		return fake.quotaUsageReturns.result1, fake.quotaUsageReturns.result2
+	}
+}
+
+func (fake *FakeClient) QuotaUsageCallCount() int {
+	fake.quotaUsageMutex.RLock()
+	defer fake.quotaUsageMutex.RUnlock()
+	return len(fake.quotaUsageArgsForCall)
+}
+
+func (fake *FakeClient) QuotaUsageReturns(result1 []models.QuotaUsage, result2 error) {
+	fake.QuotaUsageStub = nil
+	fake.quotaUsageReturns = struct {
+		result1 []models.QuotaUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpsertRoutesDryRun(arg1 []models.Route) ([]models.RouteChange, error) {
+	var arg1Copy []models.Route
+	if arg1 != nil {
+		arg1Copy = make([]models.Route, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.upsertRoutesDryRunMutex.Lock()
+	fake.upsertRoutesDryRunArgsForCall = append(fake.upsertRoutesDryRunArgsForCall, struct {
+		arg1 []models.Route
+	}{arg1Copy})
+	fake.recordInvocation("UpsertRoutesDryRun", []interface{}{arg1Copy})
+	fake.upsertRoutesDryRunMutex.Unlock()
+	if fake.UpsertRoutesDryRunStub != nil {
+		return fake.UpsertRoutesDryRunStub(arg1)
+	} else {
+		return fake.upsertRoutesDryRunReturns.result1, fake.upsertRoutesDryRunReturns.result2
+	}
+}
+
+func (fake *FakeClient) UpsertRoutesDryRunCallCount() int {
+	fake.upsertRoutesDryRunMutex.RLock()
+	defer fake.upsertRoutesDryRunMutex.RUnlock()
+	return len(fake.upsertRoutesDryRunArgsForCall)
+}
+
+func (fake *FakeClient) UpsertRoutesDryRunArgsForCall(i int) []models.Route {
+	fake.upsertRoutesDryRunMutex.RLock()
+	defer fake.upsertRoutesDryRunMutex.RUnlock()
+	return fake.upsertRoutesDryRunArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) UpsertRoutesDryRunReturns(result1 []models.RouteChange, result2 error) {
+	fake.UpsertRoutesDryRunStub = nil
+	fake.upsertRoutesDryRunReturns = struct {
+		result1 []models.RouteChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteRoutesDryRun(arg1 []models.Route) ([]models.RouteChange, error) {
+	var arg1Copy []models.Route
+	if arg1 != nil {
+		arg1Copy = make([]models.Route, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteRoutesDryRunMutex.Lock()
+	fake.deleteRoutesDryRunArgsForCall = append(fake.deleteRoutesDryRunArgsForCall, struct {
+		arg1 []models.Route
+	}{arg1Copy})
+	fake.recordInvocation("DeleteRoutesDryRun", []interface{}{arg1Copy})
+	fake.deleteRoutesDryRunMutex.Unlock()
+	if fake.DeleteRoutesDryRunStub != nil {
+		return fake.DeleteRoutesDryRunStub(arg1)
+	} else {
+		return fake.deleteRoutesDryRunReturns.result1, fake.deleteRoutesDryRunReturns.result2
+	}
+}
+
+func (fake *FakeClient) DeleteRoutesDryRunCallCount() int {
+	fake.deleteRoutesDryRunMutex.RLock()
+	defer fake.deleteRoutesDryRunMutex.RUnlock()
+	return len(fake.deleteRoutesDryRunArgsForCall)
+}
+
+func (fake *FakeClient) DeleteRoutesDryRunArgsForCall(i int) []models.Route {
+	fake.deleteRoutesDryRunMutex.RLock()
+	defer fake.deleteRoutesDryRunMutex.RUnlock()
+	return fake.deleteRoutesDryRunArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) DeleteRoutesDryRunReturns(result1 []models.RouteChange, result2 error) {
+	fake.DeleteRoutesDryRunStub = nil
+	fake.deleteRoutesDryRunReturns = struct {
+		result1 []models.RouteChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpdateRouterGroupDryRun(arg1 models.RouterGroup) (models.RouterGroupChange, error) {
+	fake.updateRouterGroupDryRunMutex.Lock()
+	fake.updateRouterGroupDryRunArgsForCall = append(fake.updateRouterGroupDryRunArgsForCall, struct {
+		arg1 models.RouterGroup
+	}{arg1})
+	fake.recordInvocation("UpdateRouterGroupDryRun", []interface{}{arg1})
+	fake.updateRouterGroupDryRunMutex.Unlock()
+	if fake.UpdateRouterGroupDryRunStub != nil {
+		return fake.UpdateRouterGroupDryRunStub(arg1)
+	} else {
+		return fake.updateRouterGroupDryRunReturns.result1, fake.updateRouterGroupDryRunReturns.result2
+	}
+}
+
+func (fake *FakeClient) UpdateRouterGroupDryRunCallCount() int {
+	fake.updateRouterGroupDryRunMutex.RLock()
+	defer fake.updateRouterGroupDryRunMutex.RUnlock()
+	return len(fake.updateRouterGroupDryRunArgsForCall)
+}
+
+func (fake *FakeClient) UpdateRouterGroupDryRunArgsForCall(i int) models.RouterGroup {
+	fake.updateRouterGroupDryRunMutex.RLock()
+	defer fake.updateRouterGroupDryRunMutex.RUnlock()
+	return fake.updateRouterGroupDryRunArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) UpdateRouterGroupDryRunReturns(result1 models.RouterGroupChange, result2 error) {
+	fake.UpdateRouterGroupDryRunStub = nil
+	fake.updateRouterGroupDryRunReturns = struct {
+		result1 models.RouterGroupChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsDryRun(arg1 []models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error) {
+	var arg1Copy []models.TcpRouteMapping
+	if arg1 != nil {
+		arg1Copy = make([]models.TcpRouteMapping, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.upsertTcpRouteMappingsDryRunMutex.Lock()
+	fake.upsertTcpRouteMappingsDryRunArgsForCall = append(fake.upsertTcpRouteMappingsDryRunArgsForCall, struct {
+		arg1 []models.TcpRouteMapping
+	}{arg1Copy})
+	fake.recordInvocation("UpsertTcpRouteMappingsDryRun", []interface{}{arg1Copy})
+	fake.upsertTcpRouteMappingsDryRunMutex.Unlock()
+	if fake.UpsertTcpRouteMappingsDryRunStub != nil {
+		return fake.UpsertTcpRouteMappingsDryRunStub(arg1)
+	} else {
+		return fake.upsertTcpRouteMappingsDryRunReturns.result1, fake.upsertTcpRouteMappingsDryRunReturns.result2
+	}
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsDryRunCallCount() int {
+	fake.upsertTcpRouteMappingsDryRunMutex.RLock()
+	defer fake.upsertTcpRouteMappingsDryRunMutex.RUnlock()
+	return len(fake.upsertTcpRouteMappingsDryRunArgsForCall)
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsDryRunArgsForCall(i int) []models.TcpRouteMapping {
+	fake.upsertTcpRouteMappingsDryRunMutex.RLock()
+	defer fake.upsertTcpRouteMappingsDryRunMutex.RUnlock()
+	return fake.upsertTcpRouteMappingsDryRunArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) UpsertTcpRouteMappingsDryRunReturns(result1 []models.TcpRouteMappingChange, result2 error) {
+	fake.UpsertTcpRouteMappingsDryRunStub = nil
+	fake.upsertTcpRouteMappingsDryRunReturns = struct {
+		result1 []models.TcpRouteMappingChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsDryRun(arg1 []models.TcpRouteMapping) ([]models.TcpRouteMappingChange, error) {
+	var arg1Copy []models.TcpRouteMapping
+	if arg1 != nil {
+		arg1Copy = make([]models.TcpRouteMapping, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteTcpRouteMappingsDryRunMutex.Lock()
+	fake.deleteTcpRouteMappingsDryRunArgsForCall = append(fake.deleteTcpRouteMappingsDryRunArgsForCall, struct {
+		arg1 []models.TcpRouteMapping
+	}{arg1Copy})
+	fake.recordInvocation("DeleteTcpRouteMappingsDryRun", []interface{}{arg1Copy})
+	fake.deleteTcpRouteMappingsDryRunMutex.Unlock()
+	if fake.DeleteTcpRouteMappingsDryRunStub != nil {
+		return fake.DeleteTcpRouteMappingsDryRunStub(arg1)
+	} else {
+		return fake.deleteTcpRouteMappingsDryRunReturns.result1, fake.deleteTcpRouteMappingsDryRunReturns.result2
+	}
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsDryRunCallCount() int {
+	fake.deleteTcpRouteMappingsDryRunMutex.RLock()
+	defer fake.deleteTcpRouteMappingsDryRunMutex.RUnlock()
+	return len(fake.deleteTcpRouteMappingsDryRunArgsForCall)
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsDryRunArgsForCall(i int) []models.TcpRouteMapping {
+	fake.deleteTcpRouteMappingsDryRunMutex.RLock()
+	defer fake.deleteTcpRouteMappingsDryRunMutex.RUnlock()
+	return fake.deleteTcpRouteMappingsDryRunArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) DeleteTcpRouteMappingsDryRunReturns(result1 []models.TcpRouteMappingChange, result2 error) {
+	fake.DeleteTcpRouteMappingsDryRunStub = nil
+	fake.deleteTcpRouteMappingsDryRunReturns = struct {
+		result1 []models.TcpRouteMappingChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) SubscribeToEventsWithWebSocket(filter models.EventFilter, window uint64) (routing_api.WebSocketEventSource, error) {
+	fake.subscribeToEventsWithWebSocketMutex.Lock()
+	fake.subscribeToEventsWithWebSocketArgsForCall = append(fake.subscribeToEventsWithWebSocketArgsForCall, struct {
+		filter models.EventFilter
+		window uint64
+	}{filter, window})
+	fake.recordInvocation("SubscribeToEventsWithWebSocket", []interface{}{filter, window})
+	fake.subscribeToEventsWithWebSocketMutex.Unlock()
+	if fake.SubscribeToEventsWithWebSocketStub != nil {
+		return fake.SubscribeToEventsWithWebSocketStub(filter, window)
+	} else {
+		return fake.subscribeToEventsWithWebSocketReturns.result1, fake.subscribeToEventsWithWebSocketReturns.result2
+	}
+}
+
+func (fake *FakeClient) SubscribeToEventsWithWebSocketCallCount() int {
+	fake.subscribeToEventsWithWebSocketMutex.RLock()
+	defer fake.subscribeToEventsWithWebSocketMutex.RUnlock()
+	return len(fake.subscribeToEventsWithWebSocketArgsForCall)
+}
+
+func (fake *FakeClient) SubscribeToEventsWithWebSocketArgsForCall(i int) (models.EventFilter, uint64) {
+	fake.subscribeToEventsWithWebSocketMutex.RLock()
+	defer fake.subscribeToEventsWithWebSocketMutex.RUnlock()
+	return fake.subscribeToEventsWithWebSocketArgsForCall[i].filter, fake.subscribeToEventsWithWebSocketArgsForCall[i].window
+}
+
+func (fake *FakeClient) SubscribeToEventsWithWebSocketReturns(result1 routing_api.WebSocketEventSource, result2 error) {
+	fake.SubscribeToEventsWithWebSocketStub = nil
+	fake.subscribeToEventsWithWebSocketReturns = struct {
+		result1 routing_api.WebSocketEventSource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) SubscribeToTcpEventsWithWebSocket(filter models.EventFilter, window uint64) (routing_api.TcpWebSocketEventSource, error) {
+	fake.subscribeToTcpEventsWithWebSocketMutex.Lock()
+	fake.subscribeToTcpEventsWithWebSocketArgsForCall = append(fake.subscribeToTcpEventsWithWebSocketArgsForCall, struct {
+		filter models.EventFilter
+		window uint64
+	}{filter, window})
+	fake.recordInvocation("SubscribeToTcpEventsWithWebSocket", []interface{}{filter, window})
+	fake.subscribeToTcpEventsWithWebSocketMutex.Unlock()
+	if fake.SubscribeToTcpEventsWithWebSocketStub != nil {
+		return fake.SubscribeToTcpEventsWithWebSocketStub(filter, window)
+	} else {
+		return fake.subscribeToTcpEventsWithWebSocketReturns.result1, fake.subscribeToTcpEventsWithWebSocketReturns.result2
+	}
+}
+
+func (fake *FakeClient) SubscribeToTcpEventsWithWebSocketCallCount() int {
+	fake.subscribeToTcpEventsWithWebSocketMutex.RLock()
+	defer fake.subscribeToTcpEventsWithWebSocketMutex.RUnlock()
+	return len(fake.subscribeToTcpEventsWithWebSocketArgsForCall)
+}
+
+func (fake *FakeClient) SubscribeToTcpEventsWithWebSocketArgsForCall(i int) (models.EventFilter, uint64) {
+	fake.subscribeToTcpEventsWithWebSocketMutex.RLock()
+	defer fake.subscribeToTcpEventsWithWebSocketMutex.RUnlock()
+	return fake.subscribeToTcpEventsWithWebSocketArgsForCall[i].filter, fake.subscribeToTcpEventsWithWebSocketArgsForCall[i].window
+}
+
+func (fake *FakeClient) SubscribeToTcpEventsWithWebSocketReturns(result1 routing_api.TcpWebSocketEventSource, result2 error) {
+	fake.SubscribeToTcpEventsWithWebSocketStub = nil
+	fake.subscribeToTcpEventsWithWebSocketReturns = struct {
+		result1 routing_api.TcpWebSocketEventSource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) WithContext(arg1 context.Context) routing_api.Client {
+	fake.withContextMutex.Lock()
+	fake.withContextArgsForCall = append(fake.withContextArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("WithContext", []interface{}{arg1})
+	fake.withContextMutex.Unlock()
+	if fake.WithContextStub != nil {
+		return fake.WithContextStub(arg1)
+	} else {
+		return fake.withContextReturns.result1
+	}
+}
+
+func (fake *FakeClient) WithContextCallCount() int {
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
+	return len(fake.withContextArgsForCall)
+}
+
+func (fake *FakeClient) WithContextArgsForCall(i int) context.Context {
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
+	return fake.withContextArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) WithContextReturns(result1 routing_api.Client) {
+	fake.WithContextStub = nil
+	fake.withContextReturns = struct {
+		result1 routing_api.Client
+	}{result1}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -547,6 +1455,46 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.subscribeToTcpEventsMutex.RUnlock()
 	fake.subscribeToTcpEventsWithMaxRetriesMutex.RLock()
 	defer fake.subscribeToTcpEventsWithMaxRetriesMutex.RUnlock()
+	fake.auditRecordsMutex.RLock()
+	defer fake.auditRecordsMutex.RUnlock()
+	fake.upsertRoutesIfMatchMutex.RLock()
+	defer fake.upsertRoutesIfMatchMutex.RUnlock()
+	fake.deleteRoutesIfMatchMutex.RLock()
+	defer fake.deleteRoutesIfMatchMutex.RUnlock()
+	fake.upsertTcpRouteMappingsIfMatchMutex.RLock()
+	defer fake.upsertTcpRouteMappingsIfMatchMutex.RUnlock()
+	fake.deleteTcpRouteMappingsIfMatchMutex.RLock()
+	defer fake.deleteTcpRouteMappingsIfMatchMutex.RUnlock()
+	fake.routeHistoryMutex.RLock()
+	defer fake.routeHistoryMutex.RUnlock()
+	fake.tcpRouteMappingHistoryMutex.RLock()
+	defer fake.tcpRouteMappingHistoryMutex.RUnlock()
+	fake.drainRoutesMutex.RLock()
+	defer fake.drainRoutesMutex.RUnlock()
+	fake.drainTcpRouteMappingsMutex.RLock()
+	defer fake.drainTcpRouteMappingsMutex.RUnlock()
+	fake.deleteRoutesBySelectorMutex.RLock()
+	defer fake.deleteRoutesBySelectorMutex.RUnlock()
+	fake.deleteTcpRouteMappingsBySelectorMutex.RLock()
+	defer fake.deleteTcpRouteMappingsBySelectorMutex.RUnlock()
+	fake.quotaUsageMutex.RLock()
+	defer fake.quotaUsageMutex.RUnlock()
+	fake.upsertRoutesDryRunMutex.RLock()
+	defer fake.upsertRoutesDryRunMutex.RUnlock()
+	fake.deleteRoutesDryRunMutex.RLock()
+	defer fake.deleteRoutesDryRunMutex.RUnlock()
+	fake.updateRouterGroupDryRunMutex.RLock()
+	defer fake.updateRouterGroupDryRunMutex.RUnlock()
+	fake.upsertTcpRouteMappingsDryRunMutex.RLock()
+	defer fake.upsertTcpRouteMappingsDryRunMutex.RUnlock()
+	fake.deleteTcpRouteMappingsDryRunMutex.RLock()
+	defer fake.deleteTcpRouteMappingsDryRunMutex.RUnlock()
+	fake.subscribeToEventsWithWebSocketMutex.RLock()
+	defer fake.subscribeToEventsWithWebSocketMutex.RUnlock()
+	fake.subscribeToTcpEventsWithWebSocketMutex.RLock()
+	defer fake.subscribeToTcpEventsWithWebSocketMutex.RUnlock()
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,491 @@
+// This file was generated by counterfeiter
+package fake_routing_api
+
+import (
+	"sync"
+
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type FakeClientV2 struct {
+	SetTokenStub        func(arg1 string)
+	setTokenMutex       sync.RWMutex
+	setTokenArgsForCall []struct {
+		arg1 string
+	}
+	RoutesStub        func() ([]models.RouteV2, error)
+	routesMutex       sync.RWMutex
+	routesArgsForCall []struct{}
+	routesReturns     struct {
+		result1 []models.RouteV2
+		result2 error
+	}
+	CreateRouteStub        func(arg1 models.RouteV2) (models.RouteV2, error)
+	createRouteMutex       sync.RWMutex
+	createRouteArgsForCall []struct {
+		arg1 models.RouteV2
+	}
+	createRouteReturns struct {
+		result1 models.RouteV2
+		result2 error
+	}
+	RouteStub        func(guid string) (models.RouteV2, error)
+	routeMutex       sync.RWMutex
+	routeArgsForCall []struct {
+		guid string
+	}
+	routeReturns struct {
+		result1 models.RouteV2
+		result2 error
+	}
+	UpdateRouteStub        func(arg1 models.RouteV2) (models.RouteV2, error)
+	updateRouteMutex       sync.RWMutex
+	updateRouteArgsForCall []struct {
+		arg1 models.RouteV2
+	}
+	updateRouteReturns struct {
+		result1 models.RouteV2
+		result2 error
+	}
+	DeleteRouteStub        func(guid string) error
+	deleteRouteMutex       sync.RWMutex
+	deleteRouteArgsForCall []struct {
+		guid string
+	}
+	deleteRouteReturns struct {
+		result1 error
+	}
+	TcpRouteMappingsStub        func() ([]models.TcpRouteMappingV2, error)
+	tcpRouteMappingsMutex       sync.RWMutex
+	tcpRouteMappingsArgsForCall []struct{}
+	tcpRouteMappingsReturns     struct {
+		result1 []models.TcpRouteMappingV2
+		result2 error
+	}
+	CreateTcpRouteMappingStub        func(arg1 models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error)
+	createTcpRouteMappingMutex       sync.RWMutex
+	createTcpRouteMappingArgsForCall []struct {
+		arg1 models.TcpRouteMappingV2
+	}
+	createTcpRouteMappingReturns struct {
+		result1 models.TcpRouteMappingV2
+		result2 error
+	}
+	TcpRouteMappingStub        func(guid string) (models.TcpRouteMappingV2, error)
+	tcpRouteMappingMutex       sync.RWMutex
+	tcpRouteMappingArgsForCall []struct {
+		guid string
+	}
+	tcpRouteMappingReturns struct {
+		result1 models.TcpRouteMappingV2
+		result2 error
+	}
+	UpdateTcpRouteMappingStub        func(arg1 models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error)
+	updateTcpRouteMappingMutex       sync.RWMutex
+	updateTcpRouteMappingArgsForCall []struct {
+		arg1 models.TcpRouteMappingV2
+	}
+	updateTcpRouteMappingReturns struct {
+		result1 models.TcpRouteMappingV2
+		result2 error
+	}
+	DeleteTcpRouteMappingStub        func(guid string) error
+	deleteTcpRouteMappingMutex       sync.RWMutex
+	deleteTcpRouteMappingArgsForCall []struct {
+		guid string
+	}
+	deleteTcpRouteMappingReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeClientV2) SetToken(arg1 string) {
+	fake.setTokenMutex.Lock()
+	fake.setTokenArgsForCall = append(fake.setTokenArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("SetToken", []interface{}{arg1})
+	fake.setTokenMutex.Unlock()
+	if fake.SetTokenStub != nil {
+		fake.SetTokenStub(arg1)
+	}
+}
+
+func (fake *FakeClientV2) SetTokenCallCount() int {
+	fake.setTokenMutex.RLock()
+	defer fake.setTokenMutex.RUnlock()
+	return len(fake.setTokenArgsForCall)
+}
+
+func (fake *FakeClientV2) SetTokenArgsForCall(i int) string {
+	fake.setTokenMutex.RLock()
+	defer fake.setTokenMutex.RUnlock()
+	return fake.setTokenArgsForCall[i].arg1
+}
+
+func (fake *FakeClientV2) Routes() ([]models.RouteV2, error) {
+	fake.routesMutex.Lock()
+	fake.routesArgsForCall = append(fake.routesArgsForCall, struct{}{})
+	fake.recordInvocation("Routes", []interface{}{})
+	fake.routesMutex.Unlock()
+	if fake.RoutesStub != nil {
+		return fake.RoutesStub()
+	} else {
+		return fake.routesReturns.result1, fake.routesReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) RoutesCallCount() int {
+	fake.routesMutex.RLock()
+	defer fake.routesMutex.RUnlock()
+	return len(fake.routesArgsForCall)
+}
+
+func (fake *FakeClientV2) RoutesReturns(result1 []models.RouteV2, result2 error) {
+	fake.RoutesStub = nil
+	fake.routesReturns = struct {
+		result1 []models.RouteV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) CreateRoute(arg1 models.RouteV2) (models.RouteV2, error) {
+	fake.createRouteMutex.Lock()
+	fake.createRouteArgsForCall = append(fake.createRouteArgsForCall, struct {
+		arg1 models.RouteV2
+	}{arg1})
+	fake.recordInvocation("CreateRoute", []interface{}{arg1})
+	fake.createRouteMutex.Unlock()
+	if fake.CreateRouteStub != nil {
+		return fake.CreateRouteStub(arg1)
+	} else {
+		return fake.createRouteReturns.result1, fake.createRouteReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) CreateRouteCallCount() int {
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	return len(fake.createRouteArgsForCall)
+}
+
+func (fake *FakeClientV2) CreateRouteArgsForCall(i int) models.RouteV2 {
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	return fake.createRouteArgsForCall[i].arg1
+}
+
+func (fake *FakeClientV2) CreateRouteReturns(result1 models.RouteV2, result2 error) {
+	fake.CreateRouteStub = nil
+	fake.createRouteReturns = struct {
+		result1 models.RouteV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) Route(guid string) (models.RouteV2, error) {
+	fake.routeMutex.Lock()
+	fake.routeArgsForCall = append(fake.routeArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("Route", []interface{}{guid})
+	fake.routeMutex.Unlock()
+	if fake.RouteStub != nil {
+		return fake.RouteStub(guid)
+	} else {
+		return fake.routeReturns.result1, fake.routeReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) RouteCallCount() int {
+	fake.routeMutex.RLock()
+	defer fake.routeMutex.RUnlock()
+	return len(fake.routeArgsForCall)
+}
+
+func (fake *FakeClientV2) RouteArgsForCall(i int) string {
+	fake.routeMutex.RLock()
+	defer fake.routeMutex.RUnlock()
+	return fake.routeArgsForCall[i].guid
+}
+
+func (fake *FakeClientV2) RouteReturns(result1 models.RouteV2, result2 error) {
+	fake.RouteStub = nil
+	fake.routeReturns = struct {
+		result1 models.RouteV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) UpdateRoute(arg1 models.RouteV2) (models.RouteV2, error) {
+	fake.updateRouteMutex.Lock()
+	fake.updateRouteArgsForCall = append(fake.updateRouteArgsForCall, struct {
+		arg1 models.RouteV2
+	}{arg1})
+	fake.recordInvocation("UpdateRoute", []interface{}{arg1})
+	fake.updateRouteMutex.Unlock()
+	if fake.UpdateRouteStub != nil {
+		return fake.UpdateRouteStub(arg1)
+	} else {
+		return fake.updateRouteReturns.result1, fake.updateRouteReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) UpdateRouteCallCount() int {
+	fake.updateRouteMutex.RLock()
+	defer fake.updateRouteMutex.RUnlock()
+	return len(fake.updateRouteArgsForCall)
+}
+
+func (fake *FakeClientV2) UpdateRouteArgsForCall(i int) models.RouteV2 {
+	fake.updateRouteMutex.RLock()
+	defer fake.updateRouteMutex.RUnlock()
+	return fake.updateRouteArgsForCall[i].arg1
+}
+
+func (fake *FakeClientV2) UpdateRouteReturns(result1 models.RouteV2, result2 error) {
+	fake.UpdateRouteStub = nil
+	fake.updateRouteReturns = struct {
+		result1 models.RouteV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) DeleteRoute(guid string) error {
+	fake.deleteRouteMutex.Lock()
+	fake.deleteRouteArgsForCall = append(fake.deleteRouteArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("DeleteRoute", []interface{}{guid})
+	fake.deleteRouteMutex.Unlock()
+	if fake.DeleteRouteStub != nil {
+		return fake.DeleteRouteStub(guid)
+	} else {
+		return fake.deleteRouteReturns.result1
+	}
+}
+
+func (fake *FakeClientV2) DeleteRouteCallCount() int {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return len(fake.deleteRouteArgsForCall)
+}
+
+func (fake *FakeClientV2) DeleteRouteArgsForCall(i int) string {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return fake.deleteRouteArgsForCall[i].guid
+}
+
+func (fake *FakeClientV2) DeleteRouteReturns(result1 error) {
+	fake.DeleteRouteStub = nil
+	fake.deleteRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClientV2) TcpRouteMappings() ([]models.TcpRouteMappingV2, error) {
+	fake.tcpRouteMappingsMutex.Lock()
+	fake.tcpRouteMappingsArgsForCall = append(fake.tcpRouteMappingsArgsForCall, struct{}{})
+	fake.recordInvocation("TcpRouteMappings", []interface{}{})
+	fake.tcpRouteMappingsMutex.Unlock()
+	if fake.TcpRouteMappingsStub != nil {
+		return fake.TcpRouteMappingsStub()
+	} else {
+		return fake.tcpRouteMappingsReturns.result1, fake.tcpRouteMappingsReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) TcpRouteMappingsCallCount() int {
+	fake.tcpRouteMappingsMutex.RLock()
+	defer fake.tcpRouteMappingsMutex.RUnlock()
+	return len(fake.tcpRouteMappingsArgsForCall)
+}
+
+func (fake *FakeClientV2) TcpRouteMappingsReturns(result1 []models.TcpRouteMappingV2, result2 error) {
+	fake.TcpRouteMappingsStub = nil
+	fake.tcpRouteMappingsReturns = struct {
+		result1 []models.TcpRouteMappingV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) CreateTcpRouteMapping(arg1 models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error) {
+	fake.createTcpRouteMappingMutex.Lock()
+	fake.createTcpRouteMappingArgsForCall = append(fake.createTcpRouteMappingArgsForCall, struct {
+		arg1 models.TcpRouteMappingV2
+	}{arg1})
+	fake.recordInvocation("CreateTcpRouteMapping", []interface{}{arg1})
+	fake.createTcpRouteMappingMutex.Unlock()
+	if fake.CreateTcpRouteMappingStub != nil {
+		return fake.CreateTcpRouteMappingStub(arg1)
+	} else {
+		return fake.createTcpRouteMappingReturns.result1, fake.createTcpRouteMappingReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) CreateTcpRouteMappingCallCount() int {
+	fake.createTcpRouteMappingMutex.RLock()
+	defer fake.createTcpRouteMappingMutex.RUnlock()
+	return len(fake.createTcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeClientV2) CreateTcpRouteMappingArgsForCall(i int) models.TcpRouteMappingV2 {
+	fake.createTcpRouteMappingMutex.RLock()
+	defer fake.createTcpRouteMappingMutex.RUnlock()
+	return fake.createTcpRouteMappingArgsForCall[i].arg1
+}
+
+func (fake *FakeClientV2) CreateTcpRouteMappingReturns(result1 models.TcpRouteMappingV2, result2 error) {
+	fake.CreateTcpRouteMappingStub = nil
+	fake.createTcpRouteMappingReturns = struct {
+		result1 models.TcpRouteMappingV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) TcpRouteMapping(guid string) (models.TcpRouteMappingV2, error) {
+	fake.tcpRouteMappingMutex.Lock()
+	fake.tcpRouteMappingArgsForCall = append(fake.tcpRouteMappingArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("TcpRouteMapping", []interface{}{guid})
+	fake.tcpRouteMappingMutex.Unlock()
+	if fake.TcpRouteMappingStub != nil {
+		return fake.TcpRouteMappingStub(guid)
+	} else {
+		return fake.tcpRouteMappingReturns.result1, fake.tcpRouteMappingReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) TcpRouteMappingCallCount() int {
+	fake.tcpRouteMappingMutex.RLock()
+	defer fake.tcpRouteMappingMutex.RUnlock()
+	return len(fake.tcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeClientV2) TcpRouteMappingArgsForCall(i int) string {
+	fake.tcpRouteMappingMutex.RLock()
+	defer fake.tcpRouteMappingMutex.RUnlock()
+	return fake.tcpRouteMappingArgsForCall[i].guid
+}
+
+func (fake *FakeClientV2) TcpRouteMappingReturns(result1 models.TcpRouteMappingV2, result2 error) {
+	fake.TcpRouteMappingStub = nil
+	fake.tcpRouteMappingReturns = struct {
+		result1 models.TcpRouteMappingV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) UpdateTcpRouteMapping(arg1 models.TcpRouteMappingV2) (models.TcpRouteMappingV2, error) {
+	fake.updateTcpRouteMappingMutex.Lock()
+	fake.updateTcpRouteMappingArgsForCall = append(fake.updateTcpRouteMappingArgsForCall, struct {
+		arg1 models.TcpRouteMappingV2
+	}{arg1})
+	fake.recordInvocation("UpdateTcpRouteMapping", []interface{}{arg1})
+	fake.updateTcpRouteMappingMutex.Unlock()
+	if fake.UpdateTcpRouteMappingStub != nil {
+		return fake.UpdateTcpRouteMappingStub(arg1)
+	} else {
+		return fake.updateTcpRouteMappingReturns.result1, fake.updateTcpRouteMappingReturns.result2
+	}
+}
+
+func (fake *FakeClientV2) UpdateTcpRouteMappingCallCount() int {
+	fake.updateTcpRouteMappingMutex.RLock()
+	defer fake.updateTcpRouteMappingMutex.RUnlock()
+	return len(fake.updateTcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeClientV2) UpdateTcpRouteMappingArgsForCall(i int) models.TcpRouteMappingV2 {
+	fake.updateTcpRouteMappingMutex.RLock()
+	defer fake.updateTcpRouteMappingMutex.RUnlock()
+	return fake.updateTcpRouteMappingArgsForCall[i].arg1
+}
+
+func (fake *FakeClientV2) UpdateTcpRouteMappingReturns(result1 models.TcpRouteMappingV2, result2 error) {
+	fake.UpdateTcpRouteMappingStub = nil
+	fake.updateTcpRouteMappingReturns = struct {
+		result1 models.TcpRouteMappingV2
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientV2) DeleteTcpRouteMapping(guid string) error {
+	fake.deleteTcpRouteMappingMutex.Lock()
+	fake.deleteTcpRouteMappingArgsForCall = append(fake.deleteTcpRouteMappingArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("DeleteTcpRouteMapping", []interface{}{guid})
+	fake.deleteTcpRouteMappingMutex.Unlock()
+	if fake.DeleteTcpRouteMappingStub != nil {
+		return fake.DeleteTcpRouteMappingStub(guid)
+	} else {
+		return fake.deleteTcpRouteMappingReturns.result1
+	}
+}
+
+func (fake *FakeClientV2) DeleteTcpRouteMappingCallCount() int {
+	fake.deleteTcpRouteMappingMutex.RLock()
+	defer fake.deleteTcpRouteMappingMutex.RUnlock()
+	return len(fake.deleteTcpRouteMappingArgsForCall)
+}
+
+func (fake *FakeClientV2) DeleteTcpRouteMappingArgsForCall(i int) string {
+	fake.deleteTcpRouteMappingMutex.RLock()
+	defer fake.deleteTcpRouteMappingMutex.RUnlock()
+	return fake.deleteTcpRouteMappingArgsForCall[i].guid
+}
+
+func (fake *FakeClientV2) DeleteTcpRouteMappingReturns(result1 error) {
+	fake.DeleteTcpRouteMappingStub = nil
+	fake.deleteTcpRouteMappingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClientV2) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.setTokenMutex.RLock()
+	defer fake.setTokenMutex.RUnlock()
+	fake.routesMutex.RLock()
+	defer fake.routesMutex.RUnlock()
+	fake.createRouteMutex.RLock()
+	defer fake.createRouteMutex.RUnlock()
+	fake.routeMutex.RLock()
+	defer fake.routeMutex.RUnlock()
+	fake.updateRouteMutex.RLock()
+	defer fake.updateRouteMutex.RUnlock()
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	fake.tcpRouteMappingsMutex.RLock()
+	defer fake.tcpRouteMappingsMutex.RUnlock()
+	fake.createTcpRouteMappingMutex.RLock()
+	defer fake.createTcpRouteMappingMutex.RUnlock()
+	fake.tcpRouteMappingMutex.RLock()
+	defer fake.tcpRouteMappingMutex.RUnlock()
+	fake.updateTcpRouteMappingMutex.RLock()
+	defer fake.updateTcpRouteMappingMutex.RUnlock()
+	fake.deleteTcpRouteMappingMutex.RLock()
+	defer fake.deleteTcpRouteMappingMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeClientV2) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing_api.ClientV2 = new(FakeClientV2)
@@ -0,0 +1,178 @@
+// This file was generated by counterfeiter
+package fake_routing_api
+
+import (
+	"sync"
+
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type FakeTcpWebSocketEventSource struct {
+	NextStub        func() (routing_api.TcpEvent, error)
+	nextMutex       sync.RWMutex
+	nextArgsForCall []struct{}
+	nextReturns     struct {
+		result1 routing_api.TcpEvent
+		result2 error
+	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct{}
+	closeReturns     struct {
+		result1 error
+	}
+	SetFilterStub        func(filter models.EventFilter) error
+	setFilterMutex       sync.RWMutex
+	setFilterArgsForCall []struct {
+		filter models.EventFilter
+	}
+	setFilterReturns struct {
+		result1 error
+	}
+	AckStub        func() error
+	ackMutex       sync.RWMutex
+	ackArgsForCall []struct{}
+	ackReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTcpWebSocketEventSource) Next() (routing_api.TcpEvent, error) {
+	fake.nextMutex.Lock()
+	fake.nextArgsForCall = append(fake.nextArgsForCall, struct{}{})
+	fake.recordInvocation("Next", []interface{}{})
+	fake.nextMutex.Unlock()
+	if fake.NextStub != nil {
+		return fake.NextStub()
+	} else {
+		return fake.nextReturns.result1, fake.nextReturns.result2
+	}
+}
+
+func (fake *FakeTcpWebSocketEventSource) NextCallCount() int {
+	fake.nextMutex.RLock()
+	defer fake.nextMutex.RUnlock()
+	return len(fake.nextArgsForCall)
+}
+
+func (fake *FakeTcpWebSocketEventSource) NextReturns(result1 routing_api.TcpEvent, result2 error) {
+	fake.NextStub = nil
+	fake.nextReturns = struct {
+		result1 routing_api.TcpEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTcpWebSocketEventSource) Close() error {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if fake.CloseStub != nil {
+		return fake.CloseStub()
+	} else {
+		return fake.closeReturns.result1
+	}
+}
+
+func (fake *FakeTcpWebSocketEventSource) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeTcpWebSocketEventSource) CloseReturns(result1 error) {
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTcpWebSocketEventSource) SetFilter(filter models.EventFilter) error {
+	fake.setFilterMutex.Lock()
+	fake.setFilterArgsForCall = append(fake.setFilterArgsForCall, struct {
+		filter models.EventFilter
+	}{filter})
+	fake.recordInvocation("SetFilter", []interface{}{filter})
+	fake.setFilterMutex.Unlock()
+	if fake.SetFilterStub != nil {
+		return fake.SetFilterStub(filter)
+	} else {
+		return fake.setFilterReturns.result1
+	}
+}
+
+func (fake *FakeTcpWebSocketEventSource) SetFilterCallCount() int {
+	fake.setFilterMutex.RLock()
+	defer fake.setFilterMutex.RUnlock()
+	return len(fake.setFilterArgsForCall)
+}
+
+func (fake *FakeTcpWebSocketEventSource) SetFilterArgsForCall(i int) models.EventFilter {
+	fake.setFilterMutex.RLock()
+	defer fake.setFilterMutex.RUnlock()
+	return fake.setFilterArgsForCall[i].filter
+}
+
+func (fake *FakeTcpWebSocketEventSource) SetFilterReturns(result1 error) {
+	fake.SetFilterStub = nil
+	fake.setFilterReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTcpWebSocketEventSource) Ack() error {
+	fake.ackMutex.Lock()
+	fake.ackArgsForCall = append(fake.ackArgsForCall, struct{}{})
+	fake.recordInvocation("Ack", []interface{}{})
+	fake.ackMutex.Unlock()
+	if fake.AckStub != nil {
+		return fake.AckStub()
+	} else {
+		return fake.ackReturns.result1
+	}
+}
+
+func (fake *FakeTcpWebSocketEventSource) AckCallCount() int {
+	fake.ackMutex.RLock()
+	defer fake.ackMutex.RUnlock()
+	return len(fake.ackArgsForCall)
+}
+
+func (fake *FakeTcpWebSocketEventSource) AckReturns(result1 error) {
+	fake.AckStub = nil
+	fake.ackReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTcpWebSocketEventSource) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.nextMutex.RLock()
+	defer fake.nextMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	fake.setFilterMutex.RLock()
+	defer fake.setFilterMutex.RUnlock()
+	fake.ackMutex.RLock()
+	defer fake.ackMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeTcpWebSocketEventSource) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing_api.TcpWebSocketEventSource = new(FakeTcpWebSocketEventSource)
@@ -0,0 +1,49 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// TokenSource returns the UAA token sent with each call.
+type TokenSource func() (string, error)
+
+type tokenCredentials struct {
+	tokenSource TokenSource
+}
+
+// NewTokenCredentials returns credentials sending the token of tokenSource
+// in the authorization metadata of each call.
+func NewTokenCredentials(tokenSource TokenSource) credentials.PerRPCCredentials {
+	return &tokenCredentials{tokenSource: tokenSource}
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.tokenSource()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "bearer " + token}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// Dial connects to the gRPC API at address over TLS, authorizing calls with
+// the tokens of tokenSource.
+func Dial(address string, tlsConfig *tls.Config, tokenSource TokenSource, opts ...grpc.DialOption) (RoutingAPIClient, *grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithPerRPCCredentials(NewTokenCredentials(tokenSource)),
+	}, opts...)
+
+	conn, err := grpc.Dial(address, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewRoutingAPIClient(conn), conn, nil
+}
@@ -0,0 +1,210 @@
+package grpcapi
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+)
+
+// routesToModels converts the routes of a request. A ttl of 0 is left unset,
+// so that the route gets the default TTL as when the ttl is omitted in JSON.
+func routesToModels(routes []*protos.Route) []models.Route {
+	result := []models.Route{}
+	for _, route := range routes {
+		model := route.ToModel()
+		if route.Ttl == 0 {
+			model.TTL = nil
+		}
+		result = append(result, model)
+	}
+	return result
+}
+
+// tcpRouteMappingsToModels converts the tcp route mappings of a request, see
+// routesToModels.
+func tcpRouteMappingsToModels(tcpMappings []*protos.TcpRouteMapping) []models.TcpRouteMapping {
+	result := []models.TcpRouteMapping{}
+	for _, tcpMapping := range tcpMappings {
+		model := tcpMapping.ToModel()
+		if tcpMapping.Ttl == 0 {
+			model.TTL = nil
+		}
+		result = append(result, model)
+	}
+	return result
+}
+
+// toModel converts the router group of an update. A ttl of 0 is left unset
+// unless the request clears it, see handlers.RouterGroupUpdate.
+func (r *UpdateRouterGroupRequest) toModel() handlers.RouterGroupUpdate {
+	update := handlers.RouterGroupUpdate{RouterGroup: r.RouterGroup.ToModel()}
+	if update.RouterGroup.MaxTTL != 0 || r.ClearMaxTtl {
+		maxTTL := update.RouterGroup.MaxTTL
+		update.MaxTTL = &maxTTL
+	}
+	if update.RouterGroup.DefaultTTL != 0 || r.ClearDefaultTtl {
+		defaultTTL := update.RouterGroup.DefaultTTL
+		update.DefaultTTL = &defaultTTL
+	}
+	return update
+}
+
+func newRoutes(routes []models.Route) *protos.Routes {
+	msg := &protos.Routes{}
+	for _, route := range routes {
+		msg.Routes = append(msg.Routes, protos.NewRoute(route))
+	}
+	return msg
+}
+
+func newTcpRouteMappings(tcpMappings []models.TcpRouteMapping) *protos.TcpRouteMappings {
+	msg := &protos.TcpRouteMappings{}
+	for _, tcpMapping := range tcpMappings {
+		msg.TcpRouteMappings = append(msg.TcpRouteMappings, protos.NewTcpRouteMapping(tcpMapping))
+	}
+	return msg
+}
+
+func newRouterGroups(routerGroups []models.RouterGroup) *protos.RouterGroups {
+	msg := &protos.RouterGroups{}
+	for _, routerGroup := range routerGroups {
+		msg.RouterGroups = append(msg.RouterGroups, protos.NewRouterGroup(routerGroup))
+	}
+	return msg
+}
+
+func newRouteChanges(changes []models.RouteChange) *RouteChanges {
+	msg := &RouteChanges{}
+	for _, change := range changes {
+		routeChange := &RouteChange{Action: change.Action}
+		if change.Before != nil {
+			routeChange.Before = protos.NewRoute(*change.Before)
+		}
+		if change.After != nil {
+			routeChange.After = protos.NewRoute(*change.After)
+		}
+		msg.Changes = append(msg.Changes, routeChange)
+	}
+	return msg
+}
+
+func newTcpRouteMappingChanges(changes []models.TcpRouteMappingChange) *TcpRouteMappingChanges {
+	msg := &TcpRouteMappingChanges{}
+	for _, change := range changes {
+		tcpChange := &TcpRouteMappingChange{Action: change.Action}
+		if change.Before != nil {
+			tcpChange.Before = protos.NewTcpRouteMapping(*change.Before)
+		}
+		if change.After != nil {
+			tcpChange.After = protos.NewTcpRouteMapping(*change.After)
+		}
+		msg.Changes = append(msg.Changes, tcpChange)
+	}
+	return msg
+}
+
+func newRouterGroupChange(change models.RouterGroupChange) *RouterGroupChange {
+	msg := &RouterGroupChange{Action: change.Action}
+	if change.Before != nil {
+		msg.Before = protos.NewRouterGroup(*change.Before)
+	}
+	if change.After != nil {
+		msg.After = protos.NewRouterGroup(*change.After)
+	}
+	for _, tcpMapping := range change.OutOfRangeTcpRoutes {
+		msg.OutOfRangeTcpRoutes = append(msg.OutOfRangeTcpRoutes, protos.NewTcpRouteMapping(tcpMapping))
+	}
+	return msg
+}
+
+// ifMatchTag returns the modification tag the writes of a request are
+// conditional on, or nil for unconditional writes.
+func ifMatchTag(tag *protos.ModificationTag) *models.ModificationTag {
+	if tag == nil {
+		return nil
+	}
+	model := tag.ToModel()
+	return &model
+}
+
+func (r *WatchRequest) toModel() models.EventFilter {
+	return models.EventFilter{
+		Route:           r.Route,
+		LogGuid:         r.LogGuid,
+		RouterGroupGuid: r.RouterGroupGuid,
+		ExternalPort:    uint16(r.Port),
+		Owner:           r.Owner,
+	}
+}
+
+func (f *AuditFilter) toModel() models.AuditFilter {
+	filter := models.AuditFilter{
+		Actor: f.Actor,
+		Key:   f.Key,
+		Limit: int(f.Limit),
+	}
+	if f.Since != 0 {
+		filter.Since = time.Unix(0, f.Since)
+	}
+	if f.Until != 0 {
+		filter.Until = time.Unix(0, f.Until)
+	}
+	return filter
+}
+
+func newAuditRecords(records []models.AuditRecord) *AuditRecords {
+	msg := &AuditRecords{}
+	for _, record := range records {
+		msg.AuditRecords = append(msg.AuditRecords, &AuditRecord{
+			Time:      record.Time.UnixNano(),
+			Action:    record.Action,
+			Kind:      record.Kind,
+			Key:       record.Key,
+			Actor:     record.Actor,
+			SourceIp:  record.SourceIP,
+			RequestId: record.RequestID,
+			Before:    string(record.Before),
+			After:     string(record.After),
+		})
+	}
+	return msg
+}
+
+func newRouteVersions(versions []models.RouteVersion) *RouteVersions {
+	msg := &RouteVersions{}
+	for _, version := range versions {
+		routeVersion := &RouteVersion{
+			Kind:            version.Kind,
+			Key:             version.Key,
+			Time:            version.Time.UnixNano(),
+			Action:          version.Action,
+			ModificationTag: protos.NewModificationTag(version.ModificationTag),
+			LogGuid:         version.LogGuid,
+			RouteServiceUrl: version.RouteServiceUrl,
+			Draining:        version.Draining,
+		}
+		if version.TTL != nil {
+			routeVersion.Ttl = int32(*version.TTL)
+		}
+		msg.RouteVersions = append(msg.RouteVersions, routeVersion)
+	}
+	return msg
+}
+
+func newQuotaUsages(usages []models.QuotaUsage) *QuotaUsages {
+	msg := &QuotaUsages{}
+	for _, usage := range usages {
+		quotaUsage := &QuotaUsage{
+			Quota: usage.Quota,
+			Limit: int32(usage.Limit),
+			Usage: map[string]int32{},
+		}
+		for key, count := range usage.Usage {
+			quotaUsage.Usage[key] = int32(count)
+		}
+		msg.QuotaUsages = append(msg.QuotaUsages, quotaUsage)
+	}
+	return msg
+}
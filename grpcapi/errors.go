@@ -0,0 +1,95 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/metrics"
+	"code.cloudfoundry.org/routing-api/quota"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errorCodes = map[routing_api.Type]codes.Code{
+	routing_api.UnauthorizedError:           codes.PermissionDenied,
+	"unauthorized":                          codes.PermissionDenied,
+	routing_api.ResourceNotFoundError:       codes.NotFound,
+	routing_api.ProcessRequestError:         codes.InvalidArgument,
+	routing_api.RouteInvalidError:           codes.InvalidArgument,
+	routing_api.RouteServiceUrlInvalidError: codes.InvalidArgument,
+	routing_api.TcpRouteMappingInvalidError: codes.InvalidArgument,
+	routing_api.DBCommunicationError:        codes.Unavailable,
+	routing_api.DBConflictError:             codes.Aborted,
+	routing_api.PreconditionFailedError:     codes.FailedPrecondition,
+	routing_api.QuotaExceededError:          codes.ResourceExhausted,
+}
+
+// toStatus converts the errors of the HTTP API to gRPC status errors.
+func toStatus(err error) error {
+	apiErr, ok := err.(routing_api.Error)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code, ok := errorCodes[apiErr.Type]
+	if !ok {
+		code = codes.Unknown
+	}
+	return status.Error(code, fmt.Sprintf("%s: %s", apiErr.Type, apiErr.Message))
+}
+
+// authError converts a failed token check. A token lacking the scope is
+// denied the call, any other token is not authenticated.
+func authError(err error, log lager.Logger) error {
+	log.Error("unauthorized", err)
+	metrics.IncrementTokenError()
+
+	message := err.Error()
+	if !handlers.MissingScope.MatchString(message) {
+		return status.Error(codes.Unauthenticated, message)
+	}
+	message = handlers.MissingScope.ReplaceAllString(message, "You are not authorized to perform the requested action")
+	return status.Error(codes.PermissionDenied, message)
+}
+
+// apiError converts the errors of the validator.
+func apiError(err *routing_api.Error, log lager.Logger) error {
+	log.Error("error", err)
+	return toStatus(*err)
+}
+
+func invalidArgumentError(err error, log lager.Logger) error {
+	log.Error("error", err)
+	return toStatus(routing_api.NewError(routing_api.ProcessRequestError, "Cannot process request: "+err.Error()))
+}
+
+func notFoundError(err error, log lager.Logger) error {
+	log.Error("error", err)
+	return toStatus(routing_api.NewError(routing_api.ResourceNotFoundError, err.Error()))
+}
+
+// dbError converts the errors of the database the way the HTTP API reports
+// them.
+func dbError(err error, log lager.Logger) error {
+	log.Error("error", err)
+
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+
+	errType := routing_api.DBCommunicationError
+	switch err.(type) {
+	case db.ModificationTagMismatchError:
+		errType = routing_api.PreconditionFailedError
+	case quota.ExceededError:
+		errType = routing_api.QuotaExceededError
+	}
+	if err == db.ErrorConflict {
+		errType = routing_api.DBConflictError
+	}
+	return toStatus(routing_api.NewError(errType, err.Error()))
+}
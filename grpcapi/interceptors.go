@@ -0,0 +1,144 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/handlers"
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const servicePrefix = "/grpcapi.RoutingAPI/"
+
+// methodScopes are the scopes of the methods the HTTP API authorizes with a
+// single scope. The other methods also accept router group scopes, which the
+// server checks against the router groups of the call.
+var methodScopes = map[string]string{
+	servicePrefix + "UpsertRoutes":           handlers.RoutingRoutesWriteScope,
+	servicePrefix + "DeleteRoutes":           handlers.RoutingRoutesWriteScope,
+	servicePrefix + "DeleteRoutesBySelector": handlers.RoutingRoutesWriteScope,
+	servicePrefix + "ListRoutes":             handlers.RoutingRoutesReadScope,
+	servicePrefix + "WatchRoutes":            handlers.RoutingRoutesReadScope,
+	servicePrefix + "ListAuditRecords":       handlers.AuditReadScope,
+	servicePrefix + "ListRouteHistory":       handlers.RoutingRoutesReadScope,
+	servicePrefix + "ListQuotas":             handlers.RoutingRoutesReadScope,
+}
+
+type (
+	tokenKey     struct{}
+	requestIDKey struct{}
+)
+
+// UnaryTokenInterceptor requires the UAA token in the authorization metadata
+// and checks the scope of methods listed in methodScopes.
+func UnaryTokenInterceptor(uaaClient uaaclient.Client, logger lager.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, uaaClient, info.FullMethod, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamTokenInterceptor is the UnaryTokenInterceptor of the watch methods.
+func StreamTokenInterceptor(uaaClient uaaclient.Client, logger lager.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), uaaClient, info.FullMethod, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tokenServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authorize(ctx context.Context, uaaClient uaaclient.Client, method string, logger lager.Logger) (context.Context, error) {
+	ctx = withRequestID(ctx, logger)
+	log := logger.Session("authorize", lager.Data{"method": method, "request-id": requestIDFromContext(ctx)})
+
+	var token string
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = values[0]
+		}
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	if scope, ok := methodScopes[method]; ok {
+		err := uaaClient.DecodeToken(token, scope)
+		if err != nil {
+			return nil, authError(err, log)
+		}
+	}
+
+	return context.WithValue(ctx, tokenKey{}, token), nil
+}
+
+// tokenFromContext returns the authorization metadata of the call, the token
+// with its bearer prefix.
+func tokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+// withRequestID takes the request id of the call from the metadata, or
+// generates one, and sends it back in the header of the response.
+func withRequestID(ctx context.Context, logger lager.Logger) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(routing_api.VcapRequestIDHeader); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		var err error
+		id, err = routing_api.NewRequestID()
+		if err != nil {
+			logger.Error("failed-to-generate-request-id", err)
+			return ctx
+		}
+	}
+
+	err := grpc.SetHeader(ctx, metadata.Pairs(routing_api.VcapRequestIDHeader, id))
+	if err != nil {
+		logger.Error("failed-to-set-request-id-header", err)
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// sourceIPFromContext returns the address of the peer of the call.
+func sourceIPFromContext(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+type tokenServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tokenServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,620 @@
+package grpcapi
+
+//go:generate protoc -I. -I../models/protos --gogo_out=plugins=grpc,Mrouting_api.proto=code.cloudfoundry.org/routing-api/models/protos:. routing_api_service.proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/audit"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	"code.cloudfoundry.org/routing-api/quota"
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements RoutingAPIServer on the database of the HTTP API, with
+// the same validation, quotas, TTL policies and audit records. It expects the
+// token interceptors to have checked the scopes of methodScopes and to have
+// put the token and request id of the call into its context.
+type Server struct {
+	db            db.DB
+	validator     handlers.RouteValidator
+	uaaClient     uaaclient.Client
+	quotas        quota.Enforcer
+	auditor       audit.Recorder
+	httpTTLPolicy models.TTLPolicy
+	tcpTTLPolicy  models.TTLPolicy
+	logger        lager.Logger
+}
+
+func NewServer(database db.DB, validator handlers.RouteValidator, uaaClient uaaclient.Client, quotas quota.Enforcer, auditor audit.Recorder, httpTTLPolicy, tcpTTLPolicy models.TTLPolicy, logger lager.Logger) *Server {
+	return &Server{
+		db:            database,
+		validator:     validator,
+		uaaClient:     uaaClient,
+		quotas:        quotas,
+		auditor:       auditor,
+		httpTTLPolicy: httpTTLPolicy,
+		tcpTTLPolicy:  tcpTTLPolicy,
+		logger:        logger,
+	}
+}
+
+// call holds what the methods need to know about the call they serve.
+type call struct {
+	ctx      context.Context
+	db       db.DB
+	log      lager.Logger
+	token    string
+	auditCtx handlers.AuditContext
+}
+
+func (s *Server) newCall(ctx context.Context, session string) *call {
+	id := requestIDFromContext(ctx)
+	token := tokenFromContext(ctx)
+	return &call{
+		ctx:      ctx,
+		db:       db.WithRequestID(s.db, id),
+		log:      s.logger.Session(session, lager.Data{"request-id": id}),
+		token:    token,
+		auditCtx: handlers.NewAuditContext(token, sourceIPFromContext(ctx), id),
+	}
+}
+
+func (s *Server) UpsertRoutes(ctx context.Context, req *UpsertRoutesRequest) (*RouteChanges, error) {
+	c := s.newCall(ctx, "grpc-upsert-routes")
+	routes := routesToModels(req.Routes)
+	ifMatch := ifMatchTag(req.IfMatch)
+
+	permanent := handlers.SetRouteDefaults(routes, s.httpTTLPolicy.DefaultTTL, handlers.TokenClientID(c.token))
+
+	c.log.Info("request", lager.Data{"route_creation": routes})
+
+	if permanent {
+		err := s.uaaClient.DecodeToken(c.token, handlers.RoutingRoutesPermanentScope)
+		if err != nil {
+			return nil, authError(err, c.log)
+		}
+	}
+
+	apiErr := s.validator.ValidateCreate(routes, s.httpTTLPolicy.MaxTTL)
+	if apiErr != nil {
+		return nil, apiError(apiErr, c.log)
+	}
+
+	err := s.quotas.CheckRoutes(routes)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	if req.DryRun {
+		changes, err := handlers.PlanRouteUpserts(c.db, routes, ifMatch, req.Conditional)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		return newRouteChanges(changes), nil
+	}
+
+	err = handlers.UpsertRoutes(c.ctx, c.db, s.auditor, c.auditCtx, routes, ifMatch, req.Conditional, c.log)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return &RouteChanges{}, nil
+}
+
+func (s *Server) DeleteRoutes(ctx context.Context, req *DeleteRoutesRequest) (*RouteChanges, error) {
+	c := s.newCall(ctx, "grpc-delete-routes")
+	routes := routesToModels(req.Routes)
+	ifMatch := ifMatchTag(req.IfMatch)
+
+	c.log.Info("request", lager.Data{"route_deletion": routes})
+
+	drain, err := drainTTL(req.DrainSeconds, s.httpTTLPolicy.MaxTTL)
+	if err != nil {
+		return nil, invalidArgumentError(err, c.log)
+	}
+
+	apiErr := s.validator.ValidateDelete(routes)
+	if apiErr != nil {
+		return nil, apiError(apiErr, c.log)
+	}
+
+	if req.DryRun {
+		changes, err := handlers.PlanRouteDeletes(c.db, routes, drain, ifMatch, req.Conditional)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		return newRouteChanges(changes), nil
+	}
+
+	err = handlers.DeleteRoutes(c.ctx, c.db, s.auditor, c.auditCtx, routes, drain, ifMatch, req.Conditional, c.log)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return &RouteChanges{}, nil
+}
+
+func (s *Server) DeleteRoutesBySelector(ctx context.Context, req *RouteSelector) (*protos.Routes, error) {
+	c := s.newCall(ctx, "grpc-delete-routes-by-selector")
+
+	selector := models.RouteSelector{
+		LogGuid: req.LogGuid,
+		IP:      req.Ip,
+		Owner:   req.Owner,
+	}
+	if selector.Empty() {
+		return nil, invalidArgumentError(errors.New("selector requires at least one of log_guid, ip or owner"), c.log)
+	}
+
+	c.log.Info("request", lager.Data{"selector": selector, "dry_run": req.DryRun})
+
+	routes, err := c.db.DeleteRoutesBySelector(selector, req.DryRun)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	if !req.DryRun {
+		for _, route := range routes {
+			s.auditor.Record(c.auditCtx.NewRecord(models.AuditActionDelete, models.AuditKindHttpRoute, route.AuditKey(), route, nil))
+		}
+	}
+	return newRoutes(routes), nil
+}
+
+func (s *Server) ListRoutes(ctx context.Context, req *ListRequest) (*protos.Routes, error) {
+	c := s.newCall(ctx, "grpc-list-routes")
+
+	routes, err := c.db.ReadRoutes()
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return newRoutes(routes), nil
+}
+
+func (s *Server) WatchRoutes(req *WatchRequest, stream RoutingAPI_WatchRoutesServer) error {
+	c := s.newCall(stream.Context(), "grpc-watch-routes")
+	filter := req.toModel()
+
+	return s.watch(c, db.HTTP_WATCH, func(event db.Event) error {
+		if event.Type == db.ResyncEvent {
+			return stream.Send(&protos.RouteEvent{Action: routing_api.ResyncRequiredAction, Dropped: event.Dropped()})
+		}
+
+		var route models.Route
+		err := json.Unmarshal([]byte(event.Value), &route)
+		if err != nil {
+			return err
+		}
+		if !filter.MatchesRoute(route) {
+			return nil
+		}
+		return stream.Send(&protos.RouteEvent{
+			Revision:  event.Revision,
+			Route:     protos.NewRoute(route),
+			Action:    event.Type.String(),
+			RequestId: event.RequestID,
+		})
+	})
+}
+
+func (s *Server) ListRouterGroups(ctx context.Context, req *ListRequest) (*protos.RouterGroups, error) {
+	c := s.newCall(ctx, "grpc-list-router-groups")
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, handlers.RouterGroupsReadScope, handlers.RouterGroupReadScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+
+	routerGroups, err := c.db.ReadRouterGroups()
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	authorized := models.RouterGroups{}
+	for _, routerGroup := range routerGroups {
+		if authorizer.Authorized(routerGroup.Name) {
+			policy := routerGroup.TTLPolicy(s.tcpTTLPolicy)
+			routerGroup.EffectiveTTL = &policy
+			authorized = append(authorized, routerGroup)
+		}
+	}
+	if len(authorized) == 0 && !authorizer.HasGlobalScope() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	return newRouterGroups(authorized), nil
+}
+
+func (s *Server) UpdateRouterGroup(ctx context.Context, req *UpdateRouterGroupRequest) (*RouterGroupChange, error) {
+	if req.RouterGroup == nil {
+		return nil, status.Error(codes.InvalidArgument, "router_group is required")
+	}
+
+	c := s.newCall(ctx, "grpc-update-router-group")
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, handlers.RouterGroupsWriteScope, handlers.RouterGroupWriteScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	updatedGroup := req.toModel()
+
+	rg, err := c.db.ReadRouterGroup(updatedGroup.Guid)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	if !authorizer.Authorized(rg.Name) {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	if rg == (models.RouterGroup{}) {
+		return nil, notFoundError(fmt.Errorf("Router Group '%s' does not exist", updatedGroup.Guid), c.log)
+	}
+
+	before := rg
+	if !handlers.MergeRouterGroup(&rg, updatedGroup) {
+		if req.DryRun {
+			return newRouterGroupChange(models.RouterGroupChange{Action: models.ChangeNone, Before: &rg}), nil
+		}
+		return &RouterGroupChange{}, nil
+	}
+
+	err = handlers.ValidateRouterGroup(rg, s.tcpTTLPolicy.MaxTTL)
+	if err != nil {
+		return nil, invalidArgumentError(err, c.log)
+	}
+
+	if req.DryRun {
+		change, err := handlers.PlanRouterGroupUpdate(c.db, before, rg)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		return newRouterGroupChange(change), nil
+	}
+
+	err = handlers.SaveRouterGroup(c.db, s.auditor, c.auditCtx, before, rg)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return &RouterGroupChange{}, nil
+}
+
+func (s *Server) UpsertTcpRouteMappings(ctx context.Context, req *UpsertTcpRouteMappingsRequest) (*TcpRouteMappingChanges, error) {
+	c := s.newCall(ctx, "grpc-upsert-tcp-route-mappings")
+	tcpMappings := tcpRouteMappingsToModels(req.TcpRouteMappings)
+	ifMatch := ifMatchTag(req.IfMatch)
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+
+	routerGroups, err := c.db.ReadRouterGroups()
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	permanent := handlers.SetTcpRouteMappingDefaults(tcpMappings, routerGroups, s.tcpTTLPolicy, handlers.TokenClientID(c.token))
+
+	c.log.Info("request", lager.Data{"tcp_mapping_creation": tcpMappings})
+
+	err = handlers.AuthorizeTcpRouteMappings(authorizer, tcpMappings, routerGroups)
+	if err != nil {
+		return nil, authError(err, c.log)
+	}
+
+	if permanent {
+		err = s.uaaClient.DecodeToken(c.token, handlers.RoutingRoutesPermanentScope)
+		if err != nil {
+			return nil, authError(err, c.log)
+		}
+	}
+
+	apiErr := s.validator.ValidateCreateTcpRouteMapping(tcpMappings, routerGroups, s.tcpTTLPolicy.MaxTTL)
+	if apiErr != nil {
+		return nil, invalidArgumentError(apiErr, c.log)
+	}
+
+	err = s.quotas.CheckTcpRouteMappings(tcpMappings)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	if req.DryRun {
+		changes, err := handlers.PlanTcpRouteMappingUpserts(c.db, tcpMappings, ifMatch, req.Conditional)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		return newTcpRouteMappingChanges(changes), nil
+	}
+
+	err = handlers.UpsertTcpRouteMappings(c.ctx, c.db, s.auditor, c.auditCtx, tcpMappings, ifMatch, req.Conditional, c.log)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return &TcpRouteMappingChanges{}, nil
+}
+
+func (s *Server) DeleteTcpRouteMappings(ctx context.Context, req *DeleteTcpRouteMappingsRequest) (*TcpRouteMappingChanges, error) {
+	c := s.newCall(ctx, "grpc-delete-tcp-route-mappings")
+	tcpMappings := tcpRouteMappingsToModels(req.TcpRouteMappings)
+	ifMatch := ifMatchTag(req.IfMatch)
+
+	c.log.Info("request", lager.Data{"tcp_mapping_deletion": tcpMappings})
+
+	drain, err := drainTTL(req.DrainSeconds, s.tcpTTLPolicy.MaxTTL)
+	if err != nil {
+		return nil, invalidArgumentError(err, c.log)
+	}
+
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	if !authorizer.HasGlobalScope() {
+		routerGroups, err := c.db.ReadRouterGroups()
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+
+		err = handlers.AuthorizeTcpRouteMappings(authorizer, tcpMappings, routerGroups)
+		if err != nil {
+			return nil, authError(err, c.log)
+		}
+	}
+
+	apiErr := s.validator.ValidateDeleteTcpRouteMapping(tcpMappings)
+	if apiErr != nil {
+		return nil, invalidArgumentError(apiErr, c.log)
+	}
+
+	if req.DryRun {
+		changes, err := handlers.PlanTcpRouteMappingDeletes(c.db, tcpMappings, drain, ifMatch, req.Conditional)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		return newTcpRouteMappingChanges(changes), nil
+	}
+
+	err = handlers.DeleteTcpRouteMappings(c.ctx, c.db, s.auditor, c.auditCtx, tcpMappings, drain, ifMatch, req.Conditional, c.log)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return &TcpRouteMappingChanges{}, nil
+}
+
+func (s *Server) DeleteTcpRouteMappingsBySelector(ctx context.Context, req *TcpRouteMappingSelector) (*protos.TcpRouteMappings, error) {
+	c := s.newCall(ctx, "grpc-delete-tcp-route-mappings-by-selector")
+
+	selector := models.TcpRouteMappingSelector{
+		RouterGroupGuid: req.RouterGroupGuid,
+		HostIP:          req.BackendIp,
+		Owner:           req.Owner,
+	}
+	if selector.Empty() {
+		return nil, invalidArgumentError(errors.New("selector requires at least one of router_group_guid, backend_ip or owner"), c.log)
+	}
+
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	if !authorizer.HasGlobalScope() {
+		if selector.RouterGroupGuid == "" {
+			return nil, authError(authorizer.Err(), c.log)
+		}
+
+		routerGroup, err := c.db.ReadRouterGroup(selector.RouterGroupGuid)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		if !authorizer.Authorized(routerGroup.Name) {
+			return nil, authError(authorizer.Err(), c.log)
+		}
+	}
+
+	c.log.Info("request", lager.Data{"selector": selector, "dry_run": req.DryRun})
+
+	tcpMappings, err := c.db.DeleteTcpRouteMappingsBySelector(selector, req.DryRun)
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	if !req.DryRun {
+		for _, tcpMapping := range tcpMappings {
+			s.auditor.Record(c.auditCtx.NewRecord(models.AuditActionDelete, models.AuditKindTcpRoute, tcpMapping.AuditKey(), tcpMapping, nil))
+		}
+	}
+	return newTcpRouteMappings(tcpMappings), nil
+}
+
+func (s *Server) ListTcpRouteMappings(ctx context.Context, req *ListRequest) (*protos.TcpRouteMappings, error) {
+	c := s.newCall(ctx, "grpc-list-tcp-route-mappings")
+
+	filter, err := s.tcpRouteMappingFilter(c, handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpMappings, err := c.db.ReadTcpRouteMappings()
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+
+	authorized := []models.TcpRouteMapping{}
+	for _, tcpMapping := range tcpMappings {
+		if filter == nil || filter.AllowsTcpRouteMapping(tcpMapping) {
+			authorized = append(authorized, tcpMapping)
+		}
+	}
+	return newTcpRouteMappings(authorized), nil
+}
+
+func (s *Server) WatchTcpRouteMappings(req *WatchRequest, stream RoutingAPI_WatchTcpRouteMappingsServer) error {
+	c := s.newCall(stream.Context(), "grpc-watch-tcp-route-mappings")
+	filter := req.toModel()
+
+	groupFilter, err := s.tcpRouteMappingFilter(c, handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope)
+	if err != nil {
+		return err
+	}
+
+	return s.watch(c, db.TCP_WATCH, func(event db.Event) error {
+		if event.Type == db.ResyncEvent {
+			return stream.Send(&protos.TcpRouteMappingEvent{Action: routing_api.ResyncRequiredAction, Dropped: event.Dropped()})
+		}
+
+		var tcpMapping models.TcpRouteMapping
+		err := json.Unmarshal([]byte(event.Value), &tcpMapping)
+		if err != nil {
+			return err
+		}
+		if !filter.MatchesTcpRouteMapping(tcpMapping) {
+			return nil
+		}
+		if groupFilter != nil && !groupFilter.AllowsTcpRouteMapping(tcpMapping) {
+			return nil
+		}
+		return stream.Send(&protos.TcpRouteMappingEvent{
+			Revision:        event.Revision,
+			TcpRouteMapping: protos.NewTcpRouteMapping(tcpMapping),
+			Action:          event.Type.String(),
+			RequestId:       event.RequestID,
+		})
+	})
+}
+
+func (s *Server) ListAuditRecords(ctx context.Context, req *AuditFilter) (*AuditRecords, error) {
+	c := s.newCall(ctx, "grpc-list-audit-records")
+
+	records, err := c.db.ReadAuditRecords(req.toModel())
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return newAuditRecords(records), nil
+}
+
+func (s *Server) ListRouteHistory(ctx context.Context, req *protos.Route) (*RouteVersions, error) {
+	c := s.newCall(ctx, "grpc-list-route-history")
+
+	if req.Route == "" || req.Ip == "" {
+		return nil, invalidArgumentError(errors.New("route, ip and port are required"), c.log)
+	}
+
+	versions, err := c.db.ReadRouteVersions(models.HistoryKindHttpRoute, req.ToModel().HistoryKey())
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return newRouteVersions(versions), nil
+}
+
+func (s *Server) ListTcpRouteHistory(ctx context.Context, req *protos.TcpRouteMapping) (*RouteVersions, error) {
+	c := s.newCall(ctx, "grpc-list-tcp-route-history")
+
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	if !authorizer.HasGlobalScope() {
+		routerGroup, err := c.db.ReadRouterGroup(req.RouterGroupGuid)
+		if err != nil {
+			return nil, dbError(err, c.log)
+		}
+		if !authorizer.Authorized(routerGroup.Name) {
+			return nil, authError(authorizer.Err(), c.log)
+		}
+	}
+
+	if req.RouterGroupGuid == "" || req.BackendIp == "" {
+		return nil, invalidArgumentError(errors.New("router_group_guid, port, backend_ip and backend_port are required"), c.log)
+	}
+
+	versions, err := c.db.ReadRouteVersions(models.HistoryKindTcpRoute, req.ToModel().HistoryKey())
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return newRouteVersions(versions), nil
+}
+
+func (s *Server) ListQuotas(ctx context.Context, req *ListRequest) (*QuotaUsages, error) {
+	c := s.newCall(ctx, "grpc-list-quotas")
+
+	usages, err := s.quotas.Usage()
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	return newQuotaUsages(usages), nil
+}
+
+// tcpRouteMappingFilter authorizes reading tcp route mappings. It returns the
+// filter dropping the mappings of router groups the token cannot read, or nil
+// when the token has the global scope.
+func (s *Server) tcpRouteMappingFilter(c *call, globalScope string, groupScope func(string) string) (*handlers.RouterGroupEventFilter, error) {
+	authorizer := handlers.NewRouterGroupAuthorizer(s.uaaClient, c.token, globalScope, groupScope)
+	if !authorizer.Authenticated() {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	if authorizer.HasGlobalScope() {
+		return nil, nil
+	}
+
+	routerGroups, err := c.db.ReadRouterGroups()
+	if err != nil {
+		return nil, dbError(err, c.log)
+	}
+	if !authorizer.AuthorizedAny(routerGroups.Names()) {
+		return nil, authError(authorizer.Err(), c.log)
+	}
+	return handlers.NewRouterGroupEventFilter(authorizer, s.db, routerGroups), nil
+}
+
+// watch sends the events of watchType to send until sending fails or the
+// call is cancelled, which stops the watch.
+func (s *Server) watch(c *call, watchType string, send func(db.Event) error) error {
+	events, errs, cancel := c.db.WatchChanges(watchType)
+	defer cancel()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch closed")
+			}
+			if event.Type == db.InvalidEvent {
+				c.log.Info("invalid-event", lager.Data{"event": event})
+				return status.Error(codes.Internal, "invalid event")
+			}
+			if event.Type == db.ResyncEvent {
+				c.log.Info("events-dropped", lager.Data{"event": event.Value})
+			}
+			err := send(event)
+			if err != nil {
+				c.log.Error("failed-to-send-event", err)
+				return err
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch closed")
+			}
+			c.log.Error("watch-failed", err)
+			return status.Error(codes.Unavailable, err.Error())
+		case <-c.ctx.Done():
+			return status.FromContextError(c.ctx.Err()).Err()
+		}
+	}
+}
+
+// drainTTL checks the drain_seconds of a delete request, see the drain query
+// parameter of the HTTP API.
+func drainTTL(seconds int64, maxTTL int) (int, error) {
+	if seconds < 0 {
+		return 0, fmt.Errorf("invalid drain: %d", seconds)
+	}
+	if seconds > int64(maxTTL) {
+		return 0, fmt.Errorf("drain cannot be greater than %d", maxTTL)
+	}
+	return int(seconds), nil
+}
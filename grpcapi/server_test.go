@@ -0,0 +1,500 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	routing_api "code.cloudfoundry.org/routing-api"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	"code.cloudfoundry.org/routing-api/db"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/grpcapi"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
+	fake_uaa "code.cloudfoundry.org/uaa-go-client/fakes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		listener  *bufconn.Listener
+		server    *grpc.Server
+		conn      *grpc.ClientConn
+		client    grpcapi.RoutingAPIClient
+		database  *fake_db.FakeDB
+		uaaClient *fake_uaa.FakeClient
+		auditor   *fake_audit.FakeRecorder
+		quotas    *fake_quota.FakeEnforcer
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		logger := lagertest.NewTestLogger("grpc-test")
+		database = &fake_db.FakeDB{}
+		uaaClient = &fake_uaa.FakeClient{}
+		auditor = &fake_audit.FakeRecorder{}
+		quotas = &fake_quota.FakeEnforcer{}
+		httpTTLPolicy := models.TTLPolicy{MaxTTL: 120, DefaultTTL: 60}
+		tcpTTLPolicy := models.TTLPolicy{MaxTTL: 120, DefaultTTL: 60}
+
+		listener = bufconn.Listen(1024 * 1024)
+		server = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(grpcapi.UnaryTokenInterceptor(uaaClient, logger)),
+			grpc.ChainStreamInterceptor(grpcapi.StreamTokenInterceptor(uaaClient, logger)),
+		)
+		grpcapi.RegisterRoutingAPIServer(server, grpcapi.NewServer(database, handlers.NewValidator(), uaaClient, quotas, auditor, httpTTLPolicy, tcpTTLPolicy, logger))
+		go func() {
+			defer GinkgoRecover()
+			_ = server.Serve(listener)
+		}()
+
+		var err error
+		conn, err = grpc.Dial("bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return listener.Dial()
+			}),
+			grpc.WithInsecure(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		client = grpcapi.NewRoutingAPIClient(conn)
+
+		ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer some-token")
+	})
+
+	AfterEach(func() {
+		Expect(conn.Close()).To(Succeed())
+		server.Stop()
+	})
+
+	Describe("authorization", func() {
+		It("rejects calls without a token", func() {
+			_, err := client.ListRoutes(context.Background(), &grpcapi.ListRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+			Expect(database.ReadRoutesCallCount()).To(Equal(0))
+		})
+
+		It("checks the scope of the method once", func() {
+			_, err := client.ListRoutes(ctx, &grpcapi.ListRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaClient.DecodeTokenCallCount()).To(Equal(1))
+			token, scopes := uaaClient.DecodeTokenArgsForCall(0)
+			Expect(token).To(Equal("bearer some-token"))
+			Expect(scopes).To(ConsistOf(handlers.RoutingRoutesReadScope))
+		})
+
+		It("denies tokens without the scope of the method", func() {
+			uaaClient.DecodeTokenReturns(errors.New("Token does not have 'routing.routes.write' scope"))
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(status.Convert(err).Message()).To(Equal("You are not authorized to perform the requested action"))
+			Expect(database.SaveRouteCallCount()).To(Equal(0))
+		})
+
+		It("rejects invalid tokens", func() {
+			uaaClient.DecodeTokenReturns(errors.New("token is expired"))
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		})
+
+		It("rejects invalid tokens before reading the router groups", func() {
+			uaaClient.DecodeTokenReturns(errors.New("token is expired"))
+
+			_, err := client.ListRouterGroups(ctx, &grpcapi.ListRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+			Expect(database.ReadRouterGroupsCallCount()).To(Equal(0))
+		})
+
+		It("reports database errors to tokens with router group scopes", func() {
+			database.ReadRouterGroupsReturns(nil, errors.New("db communication failed"))
+			uaaClient.DecodeTokenStub = func(token string, scopes ...string) error {
+				if scopes[0] == handlers.RouterGroupReadScope("default-tcp") {
+					return nil
+				}
+				return errors.New("Token does not have '" + scopes[0] + "' scope")
+			}
+
+			_, err := client.ListRouterGroups(ctx, &grpcapi.ListRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		})
+
+		It("checks the router group scopes of router group scoped methods once", func() {
+			database.ReadRouterGroupsReturns(models.RouterGroups{{Guid: "rg-guid", Name: "default-tcp", ReservablePorts: "1024-2048"}}, nil)
+			uaaClient.DecodeTokenStub = func(token string, scopes ...string) error {
+				if scopes[0] == handlers.RoutingRoutesGroupWriteScope("default-tcp") {
+					return nil
+				}
+				return errors.New("Token does not have '" + scopes[0] + "' scope")
+			}
+
+			_, err := client.UpsertTcpRouteMappings(ctx, &grpcapi.UpsertTcpRouteMappingsRequest{
+				TcpRouteMappings: []*protos.TcpRouteMapping{{RouterGroupGuid: "rg-guid", Port: 1100, BackendIp: "1.2.3.4", BackendPort: 8080}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaClient.DecodeTokenCallCount()).To(Equal(2))
+			Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(1))
+		})
+
+		It("denies router group scoped methods for other router groups", func() {
+			database.ReadRouterGroupsReturns(models.RouterGroups{{Guid: "rg-guid", Name: "default-tcp", ReservablePorts: "1024-2048"}}, nil)
+			uaaClient.DecodeTokenReturns(errors.New("Token does not have 'routing.routes.write' scope"))
+
+			_, err := client.UpsertTcpRouteMappings(ctx, &grpcapi.UpsertTcpRouteMappingsRequest{
+				TcpRouteMappings: []*protos.TcpRouteMapping{{RouterGroupGuid: "rg-guid", Port: 1100, BackendIp: "1.2.3.4", BackendPort: 8080}},
+			})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("UpsertRoutes", func() {
+		var route *protos.Route
+
+		BeforeEach(func() {
+			route = &protos.Route{Route: "a.example.com", Port: 8080, Ip: "1.2.3.4", LogGuid: "log-guid"}
+		})
+
+		It("saves the routes with the default ttl", func() {
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.SaveRouteCallCount()).To(Equal(1))
+			saved := database.SaveRouteArgsForCall(0)
+			Expect(saved.Route).To(Equal("a.example.com"))
+			Expect(saved.GetTTL()).To(Equal(60))
+		})
+
+		It("records the request id and the address of the client", func() {
+			auditor.EnabledReturns(true)
+			ctx = metadata.AppendToOutgoingContext(ctx, routing_api.VcapRequestIDHeader, "some-request-id")
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(auditor.RecordCallCount()).To(Equal(1))
+			record := auditor.RecordArgsForCall(0)
+			Expect(record.Action).To(Equal(models.AuditActionUpsert))
+			Expect(record.RequestID).To(Equal("some-request-id"))
+			Expect(record.SourceIP).To(Equal("bufconn"))
+		})
+
+		It("sends the generated request id back", func() {
+			var header metadata.MD
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}}, grpc.Header(&header))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header.Get(routing_api.VcapRequestIDHeader)).To(HaveLen(1))
+		})
+
+		It("returns the changes of a dry run", func() {
+			changes, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}, DryRun: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.SaveRouteCallCount()).To(Equal(0))
+			Expect(changes.Changes).To(HaveLen(1))
+			Expect(changes.Changes[0].Action).To(Equal(models.ChangeCreate))
+			Expect(changes.Changes[0].After.Route).To(Equal("a.example.com"))
+		})
+
+		Context("when a dry run is conditional", func() {
+			var existing models.Route
+
+			BeforeEach(func() {
+				existing = models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60)
+				existing.ModificationTag = models.ModificationTag{Guid: "guid", Index: 1}
+				database.ReadRouteReturns(existing, nil)
+			})
+
+			It("returns the changes when if_match matches", func() {
+				changes, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{
+					Routes:  []*protos.Route{route},
+					DryRun:  true,
+					IfMatch: &protos.ModificationTag{Guid: "guid", Index: 1},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.SaveRouteIfMatchCallCount()).To(Equal(0))
+				Expect(changes.Changes).To(HaveLen(1))
+				Expect(changes.Changes[0].Action).To(Equal(models.ChangeUpdate))
+			})
+
+			It("reports a modification tag mismatch as a failed precondition", func() {
+				_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{
+					Routes:  []*protos.Route{route},
+					DryRun:  true,
+					IfMatch: &protos.ModificationTag{Guid: "guid", Index: 2},
+				})
+				Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+				Expect(database.SaveRouteIfMatchCallCount()).To(Equal(0))
+			})
+		})
+
+		It("validates the routes", func() {
+			route.Ttl = 300
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(status.Convert(err).Message()).To(HavePrefix("RouteInvalidError: "))
+			Expect(database.SaveRouteCallCount()).To(Equal(0))
+		})
+
+		It("saves routes carrying a modification tag unconditionally", func() {
+			route.ModificationTag = &protos.ModificationTag{Guid: "guid", Index: 1}
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.SaveRouteCallCount()).To(Equal(1))
+			Expect(database.SaveRouteIfMatchCallCount()).To(Equal(0))
+		})
+
+		It("saves routes conditionally on their modification tag when conditional is set", func() {
+			route.ModificationTag = &protos.ModificationTag{Guid: "guid", Index: 1}
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{Routes: []*protos.Route{route}, Conditional: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.SaveRouteIfMatchCallCount()).To(Equal(1))
+			_, expected := database.SaveRouteIfMatchArgsForCall(0)
+			Expect(expected).To(Equal(models.ModificationTag{Guid: "guid", Index: 1}))
+		})
+
+		It("reports a modification tag mismatch as a failed precondition", func() {
+			database.SaveRouteIfMatchReturns(db.ModificationTagMismatchError{})
+
+			_, err := client.UpsertRoutes(ctx, &grpcapi.UpsertRoutesRequest{
+				Routes:  []*protos.Route{route},
+				IfMatch: &protos.ModificationTag{Guid: "guid", Index: 1},
+			})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		})
+	})
+
+	Describe("DeleteRoutes", func() {
+		It("validates the routes", func() {
+			_, err := client.DeleteRoutes(ctx, &grpcapi.DeleteRoutesRequest{Routes: []*protos.Route{{Route: "a.example.com"}}})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(database.DeleteRouteCallCount()).To(Equal(0))
+		})
+
+		It("drains the routes", func() {
+			route := &protos.Route{Route: "a.example.com", Port: 8080, Ip: "1.2.3.4"}
+			_, err := client.DeleteRoutes(ctx, &grpcapi.DeleteRoutesRequest{Routes: []*protos.Route{route}, DrainSeconds: 30})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.DrainRouteCallCount()).To(Equal(1))
+			_, drainTTL := database.DrainRouteArgsForCall(0)
+			Expect(drainTTL).To(Equal(30))
+		})
+
+		It("drains the routes conditionally on if_match", func() {
+			route := &protos.Route{Route: "a.example.com", Port: 8080, Ip: "1.2.3.4"}
+			_, err := client.DeleteRoutes(ctx, &grpcapi.DeleteRoutesRequest{
+				Routes:       []*protos.Route{route},
+				DrainSeconds: 30,
+				IfMatch:      &protos.ModificationTag{Guid: "guid", Index: 1},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.DrainRouteCallCount()).To(Equal(0))
+			Expect(database.DrainRouteIfMatchCallCount()).To(Equal(1))
+			_, drainTTL, expected := database.DrainRouteIfMatchArgsForCall(0)
+			Expect(drainTTL).To(Equal(30))
+			Expect(expected).To(Equal(models.ModificationTag{Guid: "guid", Index: 1}))
+		})
+
+		It("returns the changes of a conditional drain dry run", func() {
+			existing := models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60)
+			existing.ModificationTag = models.ModificationTag{Guid: "guid", Index: 1}
+			database.ReadRouteReturns(existing, nil)
+
+			route := &protos.Route{Route: "a.example.com", Port: 8080, Ip: "1.2.3.4"}
+			changes, err := client.DeleteRoutes(ctx, &grpcapi.DeleteRoutesRequest{
+				Routes:       []*protos.Route{route},
+				DryRun:       true,
+				DrainSeconds: 30,
+				IfMatch:      &protos.ModificationTag{Guid: "guid", Index: 1},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(database.DrainRouteIfMatchCallCount()).To(Equal(0))
+			Expect(changes.Changes).To(HaveLen(1))
+			Expect(changes.Changes[0].Action).To(Equal(models.ChangeDrain))
+		})
+
+		It("rejects drains longer than the max ttl", func() {
+			route := &protos.Route{Route: "a.example.com", Port: 8080, Ip: "1.2.3.4"}
+			_, err := client.DeleteRoutes(ctx, &grpcapi.DeleteRoutesRequest{Routes: []*protos.Route{route}, DrainSeconds: 300})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(database.DrainRouteCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("ListRoutes", func() {
+		It("returns the routes", func() {
+			database.ReadRoutesReturns([]models.Route{models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60)}, nil)
+
+			routes, err := client.ListRoutes(ctx, &grpcapi.ListRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes.Routes).To(HaveLen(1))
+			Expect(routes.Routes[0].Route).To(Equal("a.example.com"))
+			Expect(routes.Routes[0].Ttl).To(Equal(int32(60)))
+		})
+
+		It("reports database errors as unavailable", func() {
+			database.ReadRoutesReturns(nil, errors.New("boom"))
+
+			_, err := client.ListRoutes(ctx, &grpcapi.ListRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		})
+	})
+
+	Describe("UpdateRouterGroup", func() {
+		BeforeEach(func() {
+			database.ReadRouterGroupReturns(models.RouterGroup{
+				Guid:            "rg-guid",
+				Name:            "default-tcp",
+				Type:            "tcp",
+				ReservablePorts: "1024-2048",
+				MaxTTL:          100,
+				DefaultTTL:      30,
+			}, nil)
+		})
+
+		It("sets the ttl overrides and keeps those left at 0", func() {
+			_, err := client.UpdateRouterGroup(ctx, &grpcapi.UpdateRouterGroupRequest{
+				RouterGroup: &protos.RouterGroup{Guid: "rg-guid", MaxTtl: 90},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.SaveRouterGroupCallCount()).To(Equal(1))
+			savedGroup := database.SaveRouterGroupArgsForCall(0)
+			Expect(savedGroup.MaxTTL).To(Equal(90))
+			Expect(savedGroup.DefaultTTL).To(Equal(30))
+		})
+
+		It("clears the ttl overrides it is asked to", func() {
+			_, err := client.UpdateRouterGroup(ctx, &grpcapi.UpdateRouterGroupRequest{
+				RouterGroup: &protos.RouterGroup{Guid: "rg-guid"},
+				ClearMaxTtl: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.SaveRouterGroupCallCount()).To(Equal(1))
+			savedGroup := database.SaveRouterGroupArgsForCall(0)
+			Expect(savedGroup.MaxTTL).To(Equal(0))
+			Expect(savedGroup.DefaultTTL).To(Equal(30))
+		})
+	})
+
+	Describe("WatchRoutes", func() {
+		var (
+			events    chan db.Event
+			errs      chan error
+			cancelled chan struct{}
+		)
+
+		BeforeEach(func() {
+			events = make(chan db.Event, 2)
+			errs = make(chan error, 1)
+			cancelled = make(chan struct{})
+			database.WatchChangesReturns(events, errs, func() { close(cancelled) })
+		})
+
+		routeEvent := func(route models.Route) db.Event {
+			event, err := db.NewEventFromInterface(db.UpdateEvent, route)
+			Expect(err).NotTo(HaveOccurred())
+			event.Revision = 7
+			return event
+		}
+
+		It("streams the events until the call is cancelled", func() {
+			events <- routeEvent(models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60))
+
+			watchCtx, cancel := context.WithCancel(ctx)
+			stream, err := client.WatchRoutes(watchCtx, &grpcapi.WatchRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			event, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Action).To(Equal("Upsert"))
+			Expect(event.Revision).To(Equal(uint64(7)))
+			Expect(event.Route.Route).To(Equal("a.example.com"))
+
+			cancel()
+			Eventually(cancelled).Should(BeClosed())
+		})
+
+		It("only streams the events matching the filter", func() {
+			events <- routeEvent(models.NewRoute("a.example.com", 8080, "1.2.3.4", "other-log-guid", "", 60))
+			events <- routeEvent(models.NewRoute("b.example.com", 8080, "1.2.3.4", "log-guid", "", 60))
+
+			stream, err := client.WatchRoutes(ctx, &grpcapi.WatchRequest{LogGuid: "log-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			event, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Route.Route).To(Equal("b.example.com"))
+		})
+
+		It("tells the client to resync", func() {
+			events <- db.NewResyncEvent(3)
+
+			stream, err := client.WatchRoutes(ctx, &grpcapi.WatchRequest{LogGuid: "log-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			event, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Action).To(Equal(routing_api.ResyncRequiredAction))
+			Expect(event.Dropped).To(Equal(uint64(3)))
+		})
+
+		It("ends the stream when the watch fails", func() {
+			errs <- errors.New("boom")
+
+			stream, err := client.WatchRoutes(ctx, &grpcapi.WatchRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = stream.Recv()
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Eventually(cancelled).Should(BeClosed())
+		})
+	})
+
+	Describe("WatchTcpRouteMappings", func() {
+		It("only streams the mappings of router groups the token can read", func() {
+			database.ReadRouterGroupsReturns(models.RouterGroups{
+				{Guid: "rg-1", Name: "group-1"},
+				{Guid: "rg-2", Name: "group-2"},
+			}, nil)
+			uaaClient.DecodeTokenStub = func(token string, scopes ...string) error {
+				if scopes[0] == handlers.RoutingRoutesGroupReadScope("group-2") {
+					return nil
+				}
+				return errors.New("Token does not have '" + scopes[0] + "' scope")
+			}
+
+			events := make(chan db.Event, 2)
+			for _, guid := range []string{"rg-1", "rg-2"} {
+				event, err := db.NewEventFromInterface(db.UpdateEvent, models.NewTcpRouteMapping(guid, 1100, "1.2.3.4", 8080, 60))
+				Expect(err).NotTo(HaveOccurred())
+				events <- event
+			}
+			database.WatchChangesReturns(events, make(chan error), func() {})
+
+			stream, err := client.WatchTcpRouteMappings(ctx, &grpcapi.WatchRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			event, err := stream.Recv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.TcpRouteMapping.RouterGroupGuid).To(Equal("rg-2"))
+		})
+	})
+})
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+)
+
+const AuditReadScope = "routing.audit.read"
+
+type AuditHandler struct {
+	uaaClient uaaclient.Client
+	db        db.DB
+	logger    lager.Logger
+}
+
+func NewAuditHandler(uaaClient uaaclient.Client, database db.DB, logger lager.Logger) *AuditHandler {
+	return &AuditHandler{
+		uaaClient: uaaClient,
+		db:        database,
+		logger:    logger,
+	}
+}
+
+func (h *AuditHandler) List(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("list-audit-records", requestData(req))
+
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), AuditReadScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	filter, err := parseAuditFilter(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	records, err := requestDB(h.db, req).ReadAuditRecords(filter)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(records)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+	}
+}
+
+// parseAuditFilter reads the since, until, actor, route and limit query
+// parameters. Times are RFC3339 timestamps or unix seconds.
+func parseAuditFilter(req *http.Request) (models.AuditFilter, error) {
+	query := req.URL.Query()
+	filter := models.AuditFilter{
+		Actor: query.Get("actor"),
+		Key:   query.Get("route"),
+	}
+
+	var err error
+	if since := query.Get("since"); since != "" {
+		filter.Since, err = parseAuditTime(since)
+		if err != nil {
+			return filter, errors.New("invalid since: " + err.Error())
+		}
+	}
+	if until := query.Get("until"); until != "" {
+		filter.Until, err = parseAuditTime(until)
+		if err != nil {
+			return filter, errors.New("invalid until: " + err.Error())
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit < 0 {
+			return filter, errors.New("invalid limit: " + limit)
+		}
+	}
+	return filter, nil
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// AuditContext holds the details of the request that caused a mutation.
+type AuditContext struct {
+	actor     string
+	sourceIP  string
+	requestID string
+}
+
+func newAuditContext(req *http.Request) AuditContext {
+	return NewAuditContext(req.Header.Get("Authorization"), sourceIP(req), requestID(req))
+}
+
+// NewAuditContext returns the context of a mutation made by the bearer token
+// in authorization, for calls that do not come in as HTTP requests.
+func NewAuditContext(authorization, sourceIP, requestID string) AuditContext {
+	return AuditContext{
+		actor:     TokenActor(authorization),
+		sourceIP:  sourceIP,
+		requestID: requestID,
+	}
+}
+
+// NewRecord builds an audit record. A nil before or after value is recorded as
+// null, e.g. the before value of a route that is created.
+func (c AuditContext) NewRecord(action, kind, key string, before, after interface{}) models.AuditRecord {
+	return models.AuditRecord{
+		Action:    action,
+		Kind:      kind,
+		Key:       key,
+		Actor:     c.actor,
+		SourceIP:  c.sourceIP,
+		RequestID: c.requestID,
+		Before:    models.NewAuditValue(before),
+		After:     models.NewAuditValue(after),
+	}
+}
+
+// TokenActor returns the user name, or the client id for client credentials
+// tokens, from the claims of a bearer token. The token has already been
+// verified by the time a mutation is recorded, so the claims are only decoded.
+func TokenActor(authorization string) string {
+	claims := tokenClaims(authorization)
+	if userName, ok := claims["user_name"].(string); ok && userName != "" {
+		return userName
+	}
+	if clientID, ok := claims["client_id"].(string); ok {
+		return clientID
+	}
+	return ""
+}
+
+// TokenClientID returns the client id from the claims of a bearer token. It is
+// recorded as the owner of the routes the client registers.
+func TokenClientID(authorization string) string {
+	clientID, _ := tokenClaims(authorization)["client_id"].(string)
+	return clientID
+}
+
+func tokenClaims(authorization string) map[string]interface{} {
+	claims := map[string]interface{}{}
+
+	parts := strings.Fields(authorization)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return claims
+	}
+
+	segments := strings.Split(parts[1], ".")
+	if len(segments) != 3 {
+		return claims
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+	if err != nil {
+		return claims
+	}
+	_ = json.Unmarshal(payload, &claims)
+	return claims
+}
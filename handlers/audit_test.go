@@ -0,0 +1,145 @@
+package handlers_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// testToken returns an authorization header carrying the given claims. The
+// signature is not valid; token verification is faked in these tests.
+func testToken(claims string) string {
+	return "bearer eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2lnbmF0dXJl"
+}
+
+var _ = Describe("AuditHandler", func() {
+	var (
+		auditHandler     *handlers.AuditHandler
+		request          *http.Request
+		responseRecorder *httptest.ResponseRecorder
+		database         *fake_db.FakeDB
+		logger           *lagertest.TestLogger
+		fakeClient       *fake_client.FakeClient
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		fakeClient = &fake_client.FakeClient{}
+		logger = lagertest.NewTestLogger("routing-api-test")
+		auditHandler = handlers.NewAuditHandler(fakeClient, database, logger)
+		responseRecorder = httptest.NewRecorder()
+	})
+
+	Describe("List", func() {
+		var records []models.AuditRecord
+
+		BeforeEach(func() {
+			records = []models.AuditRecord{
+				{
+					Time:   time.Unix(1000, 0).UTC(),
+					Action: models.AuditActionDelete,
+					Kind:   models.AuditKindHttpRoute,
+					Key:    "a.example.com",
+					Actor:  "admin",
+					Before: models.AuditValue(`{"route":"a.example.com"}`),
+				},
+			}
+			database.ReadAuditRecordsReturns(records, nil)
+		})
+
+		It("checks for routing.audit.read scope", func() {
+			request = handlers.NewTestRequest("")
+			auditHandler.List(responseRecorder, request)
+
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.AuditReadScope))
+		})
+
+		It("returns the audit records", func() {
+			request = handlers.NewTestRequest("")
+			auditHandler.List(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`[{
+				"time": "1970-01-01T00:16:40Z",
+				"action": "delete",
+				"kind": "http_route",
+				"key": "a.example.com",
+				"actor": "admin",
+				"before": {"route": "a.example.com"},
+				"after": null
+			}]`))
+		})
+
+		It("passes the query parameters as filter", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/routing/v1/audit?since=2016-01-02T15:04:05Z&until=1500000000&actor=admin&route=a.example.com&limit=10", nil)
+			Expect(err).NotTo(HaveOccurred())
+			auditHandler.List(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.ReadAuditRecordsCallCount()).To(Equal(1))
+			filter := database.ReadAuditRecordsArgsForCall(0)
+			Expect(filter.Since).To(BeTemporally("==", time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC)))
+			Expect(filter.Until).To(BeTemporally("==", time.Unix(1500000000, 0)))
+			Expect(filter.Actor).To(Equal("admin"))
+			Expect(filter.Key).To(Equal("a.example.com"))
+			Expect(filter.Limit).To(Equal(10))
+		})
+
+		Context("when a query parameter is invalid", func() {
+			It("returns a bad request", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/routing/v1/audit?since=yesterday", nil)
+				Expect(err).NotTo(HaveOccurred())
+				auditHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("invalid since"))
+				Expect(database.ReadAuditRecordsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the UAA token is not valid", func() {
+			BeforeEach(func() {
+				fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+			})
+
+			It("returns an Unauthorized status code", func() {
+				request = handlers.NewTestRequest("")
+				auditHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(database.ReadAuditRecordsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the database returns an error", func() {
+			BeforeEach(func() {
+				database.ReadAuditRecordsReturns(nil, errors.New("stuff broke"))
+			})
+
+			It("returns an internal server error", func() {
+				request = handlers.NewTestRequest("")
+				auditHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+				var apiErr map[string]string
+				Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &apiErr)).To(Succeed())
+				Expect(apiErr["message"]).To(Equal("stuff broke"))
+			})
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// ifMatchTag parses the If-Match header of a write request. It returns nil
+// when the header is absent.
+func ifMatchTag(req *http.Request) (*models.ModificationTag, error) {
+	ifMatch := req.Header.Get("If-Match")
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	tag, err := models.ParseModificationTag(ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// parseConditional parses the conditional query parameter of a write
+// request, which makes the write of each item conditional on its
+// modification_tag.
+func parseConditional(req *http.Request) (bool, error) {
+	value := req.URL.Query().Get("conditional")
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("invalid conditional: " + value)
+	}
+	return parsed, nil
+}
+
+// ExpectedTag returns the modification tag a write of a single item is
+// conditional on, or nil for an unconditional write. The If-Match header
+// applies to every item in the request and takes precedence over the
+// modification_tag of the item, which is only used when the request asks for
+// conditional writes: clients send back the items they listed, tags included.
+func ExpectedTag(ifMatch *models.ModificationTag, itemTag models.ModificationTag, conditional bool) *models.ModificationTag {
+	if ifMatch != nil {
+		return ifMatch
+	}
+	if conditional && itemTag.Guid != "" {
+		return &itemTag
+	}
+	return nil
+}
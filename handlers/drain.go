@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// drainTTL parses the drain query parameter of a delete request, the number of
+// seconds the deleted items keep draining before they are removed. It returns
+// 0 when the parameter is absent and the items are removed immediately.
+func drainTTL(req *http.Request, maxTTL int) (int, error) {
+	value := req.URL.Query().Get("drain")
+	if value == "" {
+		return 0, nil
+	}
+
+	drain, err := strconv.Atoi(value)
+	if err != nil || drain <= 0 {
+		return 0, fmt.Errorf("invalid drain: %s", value)
+	}
+	if drain > maxTTL {
+		return 0, fmt.Errorf("drain cannot be greater than %d", maxTTL)
+	}
+	return drain, nil
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// PlanRouteUpsert returns the change registering the route would make. Like
+// SaveRouteIfMatch, it fails with a ModificationTagMismatchError when the
+// write is conditional and the stored route is missing or at another tag.
+// An update reports the route SaveRoute would store, with the stored guid and
+// the next modification tag.
+func PlanRouteUpsert(database db.DB, route models.Route, expected *models.ModificationTag) (models.RouteChange, error) {
+	existing, err := database.ReadRoute(route)
+	if err != nil {
+		return models.RouteChange{}, err
+	}
+	if expected != nil && (existing == (models.Route{}) || existing.ModificationTag != *expected) {
+		return models.RouteChange{}, db.ModificationTagMismatchError{Current: existing.ModificationTag}
+	}
+
+	if existing == (models.Route{}) {
+		return models.RouteChange{Action: models.ChangeCreate, After: &route}, nil
+	}
+	updated := db.UpdatedRoute(existing, route)
+	return models.RouteChange{Action: models.ChangeUpdate, Before: &existing, After: &updated}, nil
+}
+
+// PlanRouteDelete returns the change deleting, or with a positive drainTTL
+// draining, the route would make. Routes that do not exist are reported as
+// unchanged unless the write is conditional.
+func PlanRouteDelete(database db.DB, route models.Route, drainTTL int, expected *models.ModificationTag) (models.RouteChange, error) {
+	existing, err := database.ReadRoute(route)
+	if err != nil {
+		return models.RouteChange{}, err
+	}
+	if expected != nil && (existing == (models.Route{}) || existing.ModificationTag != *expected) {
+		return models.RouteChange{}, db.ModificationTagMismatchError{Current: existing.ModificationTag}
+	}
+
+	switch {
+	case existing == (models.Route{}):
+		return models.RouteChange{Action: models.ChangeNone}, nil
+	case drainTTL > 0:
+		drained := existing
+		drained.Drain(drainTTL)
+		return models.RouteChange{Action: models.ChangeDrain, Before: &existing, After: &drained}, nil
+	default:
+		return models.RouteChange{Action: models.ChangeDelete, Before: &existing}, nil
+	}
+}
+
+// PlanTcpRouteMappingUpsert returns the change registering the mapping would
+// make, see PlanRouteUpsert.
+func PlanTcpRouteMappingUpsert(database db.DB, tcpMapping models.TcpRouteMapping, expected *models.ModificationTag) (models.TcpRouteMappingChange, error) {
+	existing, err := database.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		return models.TcpRouteMappingChange{}, err
+	}
+	if expected != nil && (existing == (models.TcpRouteMapping{}) || existing.ModificationTag != *expected) {
+		return models.TcpRouteMappingChange{}, db.ModificationTagMismatchError{Current: existing.ModificationTag}
+	}
+
+	if existing == (models.TcpRouteMapping{}) {
+		return models.TcpRouteMappingChange{Action: models.ChangeCreate, After: &tcpMapping}, nil
+	}
+	updated := db.UpdatedTcpRouteMapping(existing, tcpMapping)
+	return models.TcpRouteMappingChange{Action: models.ChangeUpdate, Before: &existing, After: &updated}, nil
+}
+
+// PlanTcpRouteMappingDelete returns the change deleting or draining the
+// mapping would make, see PlanRouteDelete.
+func PlanTcpRouteMappingDelete(database db.DB, tcpMapping models.TcpRouteMapping, drainTTL int, expected *models.ModificationTag) (models.TcpRouteMappingChange, error) {
+	existing, err := database.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		return models.TcpRouteMappingChange{}, err
+	}
+	if expected != nil && (existing == (models.TcpRouteMapping{}) || existing.ModificationTag != *expected) {
+		return models.TcpRouteMappingChange{}, db.ModificationTagMismatchError{Current: existing.ModificationTag}
+	}
+
+	switch {
+	case existing == (models.TcpRouteMapping{}):
+		return models.TcpRouteMappingChange{Action: models.ChangeNone}, nil
+	case drainTTL > 0:
+		drained := existing
+		drained.Drain(drainTTL)
+		return models.TcpRouteMappingChange{Action: models.ChangeDrain, Before: &existing, After: &drained}, nil
+	default:
+		return models.TcpRouteMappingChange{Action: models.ChangeDelete, Before: &existing}, nil
+	}
+}
+
+// PlanRouterGroupUpdate returns the change updating the router group would
+// make, including the TCP route mappings left outside its reservable ports.
+func PlanRouterGroupUpdate(database db.DB, before, after models.RouterGroup) (models.RouterGroupChange, error) {
+	change := models.RouterGroupChange{Action: models.ChangeUpdate, Before: &before, After: &after}
+
+	ranges, err := after.ReservablePorts.Parse()
+	if err != nil {
+		return change, err
+	}
+
+	tcpMappings, err := database.ReadTcpRouteMappings()
+	if err != nil {
+		return change, err
+	}
+	for _, tcpMapping := range tcpMappings {
+		if tcpMapping.RouterGroupGuid == after.Guid && !ranges.Contains(uint64(tcpMapping.ExternalPort)) {
+			change.OutOfRangeTcpRoutes = append(change.OutOfRangeTcpRoutes, tcpMapping)
+		}
+	}
+	return change, nil
+}
+
+// handlePlanError reports an error of a dry run the same way the write
+// would have reported it.
+func handlePlanError(w http.ResponseWriter, err error, log lager.Logger) {
+	if mismatch, ok := err.(db.ModificationTagMismatchError); ok {
+		handlePreconditionFailedError(w, mismatch, log)
+		return
+	}
+	handleDBCommunicationError(w, err, log)
+}
+
+// writeDryRun responds with the changes a dry run request would have made.
+func writeDryRun(w http.ResponseWriter, changes interface{}, log lager.Logger) {
+	log.Info("dry-run", lager.Data{"changes": changes})
+
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(changes)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+	}
+}
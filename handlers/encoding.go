@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+)
+
+// acceptsProtobuf reports whether the Accept header of the request asks for
+// protobuf. JSON stays the default.
+func acceptsProtobuf(req *http.Request) bool {
+	for _, mediaRange := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(mediaRange, ";")[0])
+		if mediaType == protos.ContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// varyAccept tells caches that the response depends on the Accept header,
+// since lists are encoded as JSON or protobuf depending on it. The headers the
+// response already varies on, like Accept-Encoding, are kept.
+func varyAccept(w http.ResponseWriter) {
+	for _, header := range w.Header()["Vary"] {
+		if header == "Accept" {
+			return
+		}
+	}
+	w.Header().Add("Vary", "Accept")
+}
+
+// writeList encodes a list of routes, tcp route mappings or router groups as
+// protobuf if the request accepts it, and as JSON otherwise.
+func writeList(w http.ResponseWriter, req *http.Request, list interface{}, log lager.Logger) {
+	varyAccept(w)
+	if !acceptsProtobuf(req) {
+		encoder := json.NewEncoder(w)
+		err := encoder.Encode(list)
+		if err != nil {
+			handleProcessRequestError(w, err, log)
+		}
+		return
+	}
+
+	data, err := protos.Marshal(list)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+	w.Header().Set("Content-Type", protos.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(data)
+	if err != nil {
+		log.Error("failed-to-write-to-response", err)
+	}
+}
+
+// listEncoder writes the items of a list as they are read from the database.
+type listEncoder interface {
+	Encode(item interface{}) error
+	Close() error
+}
+
+// newListEncoder returns an encoder writing protobuf if the request accepts
+// it, and JSON otherwise.
+func newListEncoder(w http.ResponseWriter, req *http.Request) listEncoder {
+	varyAccept(w)
+	if acceptsProtobuf(req) {
+		w.Header().Set("Content-Type", protos.ContentType)
+		return protos.NewListEncoder(w)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return &jsonListEncoder{w: w}
+}
+
+// jsonListEncoder writes a JSON array one element at a time.
+type jsonListEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonListEncoder) Encode(item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	separator := byte(',')
+	if e.count == 0 {
+		separator = '['
+	}
+	_, err = e.w.Write(append([]byte{separator}, data...))
+	e.count++
+	return err
+}
+
+func (e *jsonListEncoder) Close() error {
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// streamList encodes the items stream passes to its callback as they are
+// read. A database error before the first item results in a 500. Once items
+// have been written the status can no longer change, and a list cut off
+// between two items still decodes, so the connection is aborted instead of
+// ending the response: the client sees a truncated body and caches nothing.
+func streamList(w http.ResponseWriter, req *http.Request, log lager.Logger, stream func(encode func(item interface{}) error) error) {
+	encoder := newListEncoder(w, req)
+	written := false
+	var encodeErr error
+	err := stream(func(item interface{}) error {
+		written = true
+		encodeErr = encoder.Encode(item)
+		return encodeErr
+	})
+	if encodeErr != nil {
+		log.Error("failed-to-write-to-response", encodeErr)
+		return
+	}
+	if err != nil {
+		if !written {
+			w.Header().Del("Content-Type")
+			handleDBCommunicationError(w, err, log)
+			return
+		}
+		log.Error("failed-to-read-list", err)
+		panic(http.ErrAbortHandler)
+	}
+	err = encoder.Close()
+	if err != nil {
+		log.Error("failed-to-write-to-response", err)
+	}
+}
+
+// protobufEventData encodes the JSON value of an event as a base64 encoded
+// protobuf event, so that it fits in the data field of a server-sent event.
+func protobufEventData(filterKey string, event db.Event) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	metadata := protos.EventMetadata{Revision: event.Revision, RequestID: event.RequestID}
+	if filterKey == db.TCP_WATCH {
+		var tcpMapping models.TcpRouteMapping
+		err = json.Unmarshal([]byte(event.Value), &tcpMapping)
+		if err != nil {
+			return nil, err
+		}
+		data, err = protos.MarshalTcpRouteMappingEvent(tcpMapping, metadata)
+	} else {
+		var route models.Route
+		err = json.Unmarshal([]byte(event.Value), &route)
+		if err != nil {
+			return nil, err
+		}
+		data, err = protos.MarshalRouteEvent(route, metadata)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(data)), nil
+}
@@ -1,20 +1,27 @@
 package handlers
 
 import (
-	"bytes"
 	"encoding/json"
 	"net/http"
-	"regexp"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/db"
 	"code.cloudfoundry.org/routing-api/metrics"
+	"code.cloudfoundry.org/routing-api/models"
 )
 
 func handleProcessRequestError(w http.ResponseWriter, procErr error, log lager.Logger) {
 	log.Error("error", procErr)
 	err := routing_api.NewError(routing_api.ProcessRequestError, "Cannot process request: "+procErr.Error())
-	retErr := marshalRoutingApiError(err, log)
+	// keep the violations of validation errors reported this way
+	switch apiErr := procErr.(type) {
+	case *routing_api.Error:
+		err.Details = apiErr.Details
+	case routing_api.Error:
+		err.Details = apiErr.Details
+	}
+	retErr := marshalRoutingApiError(w, err, log)
 
 	w.WriteHeader(http.StatusBadRequest)
 	_, writeErr := w.Write(retErr)
@@ -23,7 +30,7 @@ func handleProcessRequestError(w http.ResponseWriter, procErr error, log lager.L
 
 func handleNotFoundError(w http.ResponseWriter, err error, log lager.Logger) {
 	log.Error("error", err)
-	retErr := marshalRoutingApiError(routing_api.NewError(routing_api.ResourceNotFoundError, err.Error()), log)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.ResourceNotFoundError, err.Error()), log)
 
 	w.WriteHeader(http.StatusNotFound)
 	_, writeErr := w.Write(retErr)
@@ -32,7 +39,7 @@ func handleNotFoundError(w http.ResponseWriter, err error, log lager.Logger) {
 
 func handleApiError(w http.ResponseWriter, apiErr *routing_api.Error, log lager.Logger) {
 	log.Error("error", apiErr)
-	retErr := marshalRoutingApiError(*apiErr, log)
+	retErr := marshalRoutingApiError(w, *apiErr, log)
 
 	w.WriteHeader(http.StatusBadRequest)
 	_, writeErr := w.Write(retErr)
@@ -41,7 +48,7 @@ func handleApiError(w http.ResponseWriter, apiErr *routing_api.Error, log lager.
 
 func handleDBCommunicationError(w http.ResponseWriter, err error, log lager.Logger) {
 	log.Error("error", err)
-	retErr := marshalRoutingApiError(routing_api.NewError(routing_api.DBCommunicationError, err.Error()), log)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.DBCommunicationError, err.Error()), log)
 
 	w.WriteHeader(http.StatusInternalServerError)
 	_, writeErr := w.Write(retErr)
@@ -51,12 +58,11 @@ func handleDBCommunicationError(w http.ResponseWriter, err error, log lager.Logg
 func handleUnauthorizedError(w http.ResponseWriter, err error, log lager.Logger) {
 	log.Error("error", err)
 
-	retErr := marshalRoutingApiError(routing_api.NewError(routing_api.UnauthorizedError, err.Error()), log)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.UnauthorizedError, err.Error()), log)
 	metrics.IncrementTokenError()
 
-	if bytes.Contains(retErr, []byte("Token does not have ")) {
-		newRegEx := regexp.MustCompile("Token does not have .* scope")
-		retErr = newRegEx.ReplaceAll(retErr, []byte("You are not authorized to perform the requested action"))
+	if MissingScope.Match(retErr) {
+		retErr = MissingScope.ReplaceAll(retErr, []byte("You are not authorized to perform the requested action"))
 	}
 	w.WriteHeader(http.StatusUnauthorized)
 	_, writeErr := w.Write(retErr)
@@ -65,14 +71,59 @@ func handleUnauthorizedError(w http.ResponseWriter, err error, log lager.Logger)
 
 func handleDBConflictError(w http.ResponseWriter, err error, log lager.Logger) {
 	log.Error("error", err)
-	retErr := marshalRoutingApiError(routing_api.NewError(routing_api.DBConflictError, err.Error()), log)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.DBConflictError, err.Error()), log)
 
 	w.WriteHeader(http.StatusConflict)
 	_, writeErr := w.Write(retErr)
 	log.Error("error writing to request", writeErr)
 }
 
-func marshalRoutingApiError(err routing_api.Error, log lager.Logger) []byte {
+func handleQuotaExceededError(w http.ResponseWriter, err error, log lager.Logger) {
+	log.Error("error", err)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.QuotaExceededError, err.Error()), log)
+
+	w.WriteHeader(http.StatusForbidden)
+	_, writeErr := w.Write(retErr)
+	log.Error("error writing to request", writeErr)
+}
+
+func handlePayloadTooLargeError(w http.ResponseWriter, err error, log lager.Logger) {
+	log.Error("error", err)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.PayloadTooLargeError, err.Error()), log)
+
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_, writeErr := w.Write(retErr)
+	log.Error("error writing to request", writeErr)
+}
+
+func handleNotImplementedError(w http.ResponseWriter, err error, log lager.Logger) {
+	log.Error("error", err)
+	retErr := marshalRoutingApiError(w, routing_api.NewError(routing_api.NotImplementedError, err.Error()), log)
+
+	w.WriteHeader(http.StatusNotImplemented)
+	_, writeErr := w.Write(retErr)
+	log.Error("error writing to request", writeErr)
+}
+
+// handlePreconditionFailedError reports the current modification tag both in
+// the ETag header and in the body, so that the client can retry with it.
+func handlePreconditionFailedError(w http.ResponseWriter, err db.ModificationTagMismatchError, log lager.Logger) {
+	log.Error("error", err)
+	apiErr := routing_api.NewError(routing_api.PreconditionFailedError, err.Error())
+	if current := err.Current; current.Guid != "" {
+		apiErr.ModificationTag = &current
+		w.Header().Set("ETag", models.FormatModificationTag(current))
+	}
+	retErr := marshalRoutingApiError(w, apiErr, log)
+
+	w.WriteHeader(http.StatusPreconditionFailed)
+	_, writeErr := w.Write(retErr)
+	log.Error("error writing to request", writeErr)
+}
+
+// marshalRoutingApiError encodes err with the request id the response echoes.
+func marshalRoutingApiError(w http.ResponseWriter, err routing_api.Error, log lager.Logger) []byte {
+	err.RequestID = w.Header().Get(routing_api.VcapRequestIDHeader)
 	retErr, jsonErr := json.Marshal(err)
 	if jsonErr != nil {
 		log.Error("could-not-marshal-json", jsonErr)
@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/routing-api/db"
 	"code.cloudfoundry.org/routing-api/metrics"
+	"code.cloudfoundry.org/routing-api/models"
 	uaaclient "code.cloudfoundry.org/uaa-go-client"
 	"github.com/vito/go-sse/sse"
 )
@@ -39,7 +44,7 @@ func (h *EventStreamHandler) EventStream(w http.ResponseWriter, req *http.Reques
 			h.logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metrics.TotalHttpSubscriptions})
 		}
 	}()
-	log := h.logger.Session("event-stream-handler")
+	log := h.logger.Session("event-stream-handler", requestData(req))
 	h.handleEventStream(log, db.HTTP_WATCH, w, req)
 }
 
@@ -54,23 +59,21 @@ func (h *EventStreamHandler) TcpEventStream(w http.ResponseWriter, req *http.Req
 			h.logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metrics.TotalTcpSubscriptions})
 		}
 	}()
-	log := h.logger.Session("tcp-event-stream-handler")
+	log := h.logger.Session("tcp-event-stream-handler", requestData(req))
 	h.handleEventStream(log, db.TCP_WATCH, w, req)
 }
 
 func (h *EventStreamHandler) handleEventStream(log lager.Logger, filterKey string,
 	w http.ResponseWriter, req *http.Request) {
 
-	err := h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
-	if err != nil {
-		handleUnauthorizedError(w, err, log)
+	sub, ok := h.subscribe(log, filterKey, req.Header.Get("Authorization"), w, req)
+	if !ok {
 		return
 	}
+
 	flusher := w.(http.Flusher)
 	closeNotifier := w.(http.CloseNotifier).CloseNotify()
 
-	resultChan, errChan, cancelFunc := h.db.WatchChanges(filterKey)
-
 	w.Header().Add("Content-Type", "text/event-stream; charset=utf-8")
 	w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Add("Connection", "keep-alive")
@@ -82,17 +85,31 @@ func (h *EventStreamHandler) handleEventStream(log lager.Logger, filterKey strin
 	eventID := 0
 	for {
 		select {
-		case event := <-resultChan:
+		case event := <-sub.resultChan:
 			eventType := event.Type
 			if eventType == db.InvalidEvent {
 				h.logger.Info("invalid-event", lager.Data{"event": event})
 				return
 			}
 
+			if eventType == db.ResyncEvent {
+				log.Info("events-dropped", lager.Data{"event": event.Value})
+			}
+
+			if !sub.allows(event) {
+				continue
+			}
+
+			data, err := sub.data(event)
+			if err != nil {
+				log.Error("failed-to-encode-event", err, lager.Data{"event": event})
+				continue
+			}
+
 			err = sse.Event{
 				ID:   strconv.Itoa(eventID),
 				Name: eventType.String(),
-				Data: []byte(event.Value),
+				Data: data,
 			}.Write(w)
 
 			if err != nil {
@@ -100,15 +117,140 @@ func (h *EventStreamHandler) handleEventStream(log lager.Logger, filterKey strin
 			}
 
 			flusher.Flush()
+			metrics.IncrementEventsSent(sub.filterKey)
 
 			eventID++
-		case err := <-errChan:
+		case err := <-sub.errChan:
 			log.Error("watch-error", err)
 			return
 		case <-closeNotifier:
 			log.Info("connection-closed")
-			cancelFunc()
+			sub.cancel()
 			return
 		}
 	}
 }
+
+// eventSubscription is an authorized watch shared by the event streams and
+// their WebSocket equivalents.
+type eventSubscription struct {
+	filterKey   string
+	protobuf    bool
+	groupFilter *RouterGroupEventFilter
+	resultChan  <-chan db.Event
+	errChan     <-chan error
+	cancel      context.CancelFunc
+}
+
+// subscribe authorizes token to watch the changes of filterKey and starts
+// watching them. It writes the error response and returns false when the
+// subscription fails.
+func (h *EventStreamHandler) subscribe(log lager.Logger, filterKey, token string,
+	w http.ResponseWriter, req *http.Request) (*eventSubscription, bool) {
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), token, RoutingRoutesReadScope, RoutingRoutesGroupReadScope)
+	if !authenticated(w, authorizer, log) {
+		return nil, false
+	}
+
+	var groupFilter *RouterGroupEventFilter
+	if !authorizer.HasGlobalScope() {
+		// Only tcp route mappings belong to a router group
+		if filterKey != db.TCP_WATCH {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return nil, false
+		}
+
+		routerGroups, err := requestDB(h.db, req).ReadRouterGroups()
+		if err != nil {
+			handleDBCommunicationError(w, err, log)
+			return nil, false
+		}
+		if !authorizer.AuthorizedAny(routerGroups.Names()) {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return nil, false
+		}
+		groupFilter = NewRouterGroupEventFilter(authorizer, h.db, routerGroups)
+	}
+
+	resultChan, errChan, cancelFunc := h.db.WatchChanges(filterKey)
+	return &eventSubscription{
+		filterKey:   filterKey,
+		protobuf:    acceptsProtobuf(req),
+		groupFilter: groupFilter,
+		resultChan:  resultChan,
+		errChan:     errChan,
+		cancel:      cancelFunc,
+	}, true
+}
+
+// allows tells whether the subscriber may read the event. Every subscriber is
+// told to resync.
+func (s *eventSubscription) allows(event db.Event) bool {
+	return event.Type == db.ResyncEvent || s.groupFilter == nil || s.groupFilter.Allows(event)
+}
+
+// data encodes the event as JSON, or as base64 protobuf for clients that
+// accept protobuf. Resync events are always JSON. Events the database already
+// encoded as protobuf are not encoded again for every subscriber.
+func (s *eventSubscription) data(event db.Event) ([]byte, error) {
+	if s.protobuf && event.Type != db.ResyncEvent {
+		if event.Protobuf != nil {
+			return []byte(base64.StdEncoding.EncodeToString(event.Protobuf)), nil
+		}
+		return protobufEventData(s.filterKey, event)
+	}
+	return eventData(event), nil
+}
+
+// routerGroupRefreshInterval is the minimum time between two reads of the
+// router groups by the filter of a subscriber, so that a burst of events for
+// unknown router groups does not read them from the database for every event.
+const routerGroupRefreshInterval = time.Second
+
+// RouterGroupEventFilter drops tcp route mapping events for router groups the
+// subscriber is not authorized to read.
+type RouterGroupEventFilter struct {
+	authorizer  *RouterGroupAuthorizer
+	db          db.DB
+	groupNames  map[string]string
+	refreshedAt time.Time
+}
+
+// NewRouterGroupEventFilter returns a filter for a subscriber that was
+// authorized to read at least one of routerGroups.
+func NewRouterGroupEventFilter(authorizer *RouterGroupAuthorizer, database db.DB, routerGroups models.RouterGroups) *RouterGroupEventFilter {
+	return &RouterGroupEventFilter{
+		authorizer:  authorizer,
+		db:          database,
+		groupNames:  routerGroupNames(routerGroups),
+		refreshedAt: time.Now(),
+	}
+}
+
+func (f *RouterGroupEventFilter) Allows(event db.Event) bool {
+	var tcpMapping models.TcpRouteMapping
+	err := json.Unmarshal([]byte(event.Value), &tcpMapping)
+	if err != nil {
+		return false
+	}
+	return f.AllowsTcpRouteMapping(tcpMapping)
+}
+
+// AllowsTcpRouteMapping tells whether the subscriber may read the events of
+// tcpMapping.
+func (f *RouterGroupEventFilter) AllowsTcpRouteMapping(tcpMapping models.TcpRouteMapping) bool {
+	name, ok := f.groupNames[tcpMapping.RouterGroupGuid]
+	if !ok && time.Since(f.refreshedAt) >= routerGroupRefreshInterval {
+		// router group may have been created after the stream was opened
+		f.refreshedAt = time.Now()
+		routerGroups, err := f.db.ReadRouterGroups()
+		if err != nil {
+			return false
+		}
+		f.groupNames = routerGroupNames(routerGroups)
+		name = f.groupNames[tcpMapping.RouterGroupGuid]
+	}
+
+	return f.authorizer.Authorized(name)
+}
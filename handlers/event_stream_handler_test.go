@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"encoding/base64"
 	"errors"
 
 	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
@@ -8,16 +9,23 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
+	routing_api "code.cloudfoundry.org/routing-api"
 	"code.cloudfoundry.org/routing-api/db"
 	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
 	"code.cloudfoundry.org/routing-api/handlers"
 	"code.cloudfoundry.org/routing-api/metrics"
 	fake_statsd "code.cloudfoundry.org/routing-api/metrics/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/vito/go-sse/sse"
+	"golang.org/x/net/websocket"
 )
 
 var _ = Describe("EventsHandler", func() {
@@ -59,11 +67,20 @@ var _ = Describe("EventsHandler", func() {
 		var (
 			response        *http.Response
 			eventStreamDone chan struct{}
+			accept          string
 		)
 
+		BeforeEach(func() {
+			accept = ""
+		})
+
 		JustBeforeEach(func() {
-			var err error
-			response, err = http.Get(server.URL)
+			request, err := http.NewRequest("GET", server.URL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			if accept != "" {
+				request.Header.Set("Accept", accept)
+			}
+			response, err = http.DefaultClient.Do(request)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -204,6 +221,112 @@ var _ = Describe("EventsHandler", func() {
 					})
 				})
 
+				Context("when the event carries a table revision", func() {
+					BeforeEach(func() {
+						resultsChan := make(chan db.Event, 1)
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`, Revision: 42}
+						database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+					})
+
+					It("adds the revision to the event data", func() {
+						reader := sse.NewReadCloser(response.Body)
+						event, err := reader.Next()
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Data).To(MatchJSON(`{"revision":42,"route":"a.example.com"}`))
+					})
+
+					Context("when the client accepts protobuf", func() {
+						BeforeEach(func() {
+							accept = protos.ContentType
+						})
+
+						It("sends the event as base64 encoded protobuf", func() {
+							reader := sse.NewReadCloser(response.Body)
+							event, err := reader.Next()
+							Expect(err).NotTo(HaveOccurred())
+							Expect(event.Name).To(Equal("Upsert"))
+
+							data, err := base64.StdEncoding.DecodeString(string(event.Data))
+							Expect(err).NotTo(HaveOccurred())
+							route, revision, err := protos.UnmarshalRouteEvent(data)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(revision).To(Equal(uint64(42)))
+							Expect(route.Route).To(Equal("a.example.com"))
+						})
+					})
+				})
+
+				Context("when the database encoded the event as protobuf", func() {
+					var encoded []byte
+
+					BeforeEach(func() {
+						var err error
+						encoded, err = protos.MarshalRouteEvent(models.NewRoute("b.example.com", 80, "1.2.3.4", "", "", 60), protos.EventMetadata{Revision: 43})
+						Expect(err).NotTo(HaveOccurred())
+
+						resultsChan := make(chan db.Event, 1)
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`, Revision: 42, Protobuf: encoded}
+						database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+						accept = protos.ContentType
+					})
+
+					It("sends the encoded event without encoding it again", func() {
+						reader := sse.NewReadCloser(response.Body)
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(event.Data)).To(Equal(base64.StdEncoding.EncodeToString(encoded)))
+					})
+				})
+
+				Context("when the subscriber lost events", func() {
+					BeforeEach(func() {
+						resultsChan := make(chan db.Event, 1)
+						resultsChan <- db.NewResyncEvent(3)
+						database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+					})
+
+					It("emits a resync-required event", func() {
+						reader := sse.NewReadCloser(response.Body)
+						event, err := reader.Next()
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Name).To(Equal("resync-required"))
+						Expect(event.Data).To(MatchJSON(`{"dropped":3}`))
+					})
+
+					Context("when the client accepts protobuf", func() {
+						BeforeEach(func() {
+							accept = protos.ContentType
+						})
+
+						It("sends the resync-required event as JSON", func() {
+							reader := sse.NewReadCloser(response.Body)
+							event, err := reader.Next()
+
+							Expect(err).NotTo(HaveOccurred())
+							Expect(event.Name).To(Equal("resync-required"))
+							Expect(event.Data).To(MatchJSON(`{"dropped":3}`))
+						})
+					})
+				})
+
+				Context("when the event carries a request id", func() {
+					BeforeEach(func() {
+						resultsChan := make(chan db.Event, 1)
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`, Revision: 42, RequestID: "some-request-id"}
+						database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+					})
+
+					It("adds the request id to the event data", func() {
+						reader := sse.NewReadCloser(response.Body)
+						event, err := reader.Next()
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Data).To(MatchJSON(`{"revision":42,"request_id":"some-request-id","route":"a.example.com"}`))
+					})
+				})
+
 				Context("when the watch returns an error", func() {
 					var errChan chan error
 
@@ -273,6 +396,212 @@ var _ = Describe("EventsHandler", func() {
 					Expect(filterString).To(Equal(db.TCP_WATCH))
 				})
 			})
+
+			Context("when the token only has router group scopes", func() {
+				var (
+					group1Event string
+					group2Event string
+				)
+
+				BeforeEach(func() {
+					group1Event = `{"router_group_guid":"guid-1","port":52000,"backend_ip":"1.2.3.4","backend_port":60000}`
+					group2Event = `{"router_group_guid":"guid-2","port":52001,"backend_ip":"1.2.3.5","backend_port":60001}`
+
+					database.ReadRouterGroupsReturns(models.RouterGroups{
+						{Guid: "guid-1", Name: "group-1"},
+						{Guid: "guid-2", Name: "group-2"},
+					}, nil)
+					fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+						if len(desiredPermissions) == 1 && desiredPermissions[0] == "routing.routes.group-1.read" {
+							return nil
+						}
+						return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+					}
+
+					resultsChan := make(chan db.Event, 3)
+					resultsChan <- db.Event{Type: db.UpdateEvent, Value: group2Event}
+					resultsChan <- db.Event{Type: db.UpdateEvent, Value: group1Event}
+					resultsChan <- db.NewResyncEvent(1)
+					database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+				})
+
+				It("only emits events for authorized router groups", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					event, err := reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					expectedEvent := sse.Event{ID: "0", Name: "Upsert", Data: []byte(group1Event)}
+					Expect(event).To(Equal(expectedEvent))
+				})
+
+				Context("when events arrive for unknown router groups", func() {
+					BeforeEach(func() {
+						group3Event := `{"router_group_guid":"guid-3","port":52002,"backend_ip":"1.2.3.6","backend_port":60002}`
+						resultsChan := make(chan db.Event, 4)
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: group3Event}
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: group3Event}
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: group3Event}
+						resultsChan <- db.Event{Type: db.UpdateEvent, Value: group1Event}
+						database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+					})
+
+					It("does not re-read the router groups for every event", func() {
+						reader := sse.NewReadCloser(response.Body)
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Data).To(MatchJSON(group1Event))
+						Expect(database.ReadRouterGroupsCallCount()).To(Equal(1))
+					})
+				})
+
+				It("emits resync-required events", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					_, err := reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+					event, err := reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(event.Name).To(Equal("resync-required"))
+				})
+			})
+
+			Context("when the token has no scope for any router group", func() {
+				BeforeEach(func() {
+					database.ReadRouterGroupsReturns(models.RouterGroups{
+						{Guid: "guid-1", Name: "group-1"},
+					}, nil)
+					fakeClient.DecodeTokenReturns(errors.New("Token does not have 'routing.routes.read' scope"))
+				})
+
+				It("returns an Unauthorized status code", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+					Expect(database.WatchChangesCallCount()).To(Equal(0))
+				})
+			})
+		})
+	})
+
+	Describe("EventStreamWebSocket", func() {
+		var (
+			resultsChan chan db.Event
+			query       string
+			conn        *websocket.Conn
+		)
+
+		receive := func() routing_api.WebSocketEvent {
+			var event routing_api.WebSocketEvent
+			Expect(websocket.JSON.Receive(conn, &event)).To(Succeed())
+			return event
+		}
+
+		BeforeEach(func() {
+			query = ""
+			resultsChan = make(chan db.Event, 10)
+			database.WatchChangesReturns(resultsChan, nil, emptyCancelFunc)
+			server = httptest.NewServer(http.HandlerFunc(handler.EventStreamWebSocket))
+		})
+
+		JustBeforeEach(func() {
+			config, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http")+"/?"+query, server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			config.Header.Set("Authorization", "bearer some-token")
+			conn, err = websocket.DialConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(conn.Close()).To(Succeed())
+		})
+
+		It("sends the events of the db", func() {
+			resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`, Revision: 3}
+
+			event := receive()
+			Expect(event.ID).To(Equal(uint64(1)))
+			Expect(event.Name).To(Equal("Upsert"))
+			Expect(event.Data).To(MatchJSON(`{"revision":3,"route":"a.example.com"}`))
+
+			Expect(database.WatchChangesArgsForCall(0)).To(Equal(db.HTTP_WATCH))
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.RoutingRoutesReadScope))
+		})
+
+		Context("when the client sets a filter", func() {
+			BeforeEach(func() {
+				query = "filter=" + url.QueryEscape(`{"route":"b.example.com"}`)
+			})
+
+			It("only sends matching events", func() {
+				resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`}
+				resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"b.example.com"}`}
+
+				event := receive()
+				Expect(event.Data).To(MatchJSON(`{"route":"b.example.com"}`))
+			})
+
+			It("sends resync-required events", func() {
+				resultsChan <- db.NewResyncEvent(2)
+
+				event := receive()
+				Expect(event.Name).To(Equal("resync-required"))
+				Expect(event.Data).To(MatchJSON(`{"dropped":2}`))
+			})
+
+			It("replaces the filter on the live connection", func() {
+				Expect(websocket.JSON.Send(conn, routing_api.WebSocketControl{
+					Filter: &models.EventFilter{Route: "a.example.com"},
+				})).To(Succeed())
+				go func() {
+					for i := 0; i < 100; i++ {
+						select {
+						case resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`}:
+						default:
+						}
+						time.Sleep(10 * time.Millisecond)
+					}
+				}()
+
+				Expect(receive().Data).To(MatchJSON(`{"route":"a.example.com"}`))
+			})
+		})
+
+		Context("when the client sets a window", func() {
+			BeforeEach(func() {
+				query = "window=1"
+			})
+
+			It("waits for acknowledgements", func() {
+				resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"a.example.com"}`}
+				resultsChan <- db.Event{Type: db.UpdateEvent, Value: `{"route":"b.example.com"}`}
+
+				Expect(receive().ID).To(Equal(uint64(1)))
+				Consistently(resultsChan).Should(HaveLen(1))
+
+				Expect(websocket.JSON.Send(conn, routing_api.WebSocketControl{Ack: 1})).To(Succeed())
+				event := receive()
+				Expect(event.ID).To(Equal(uint64(2)))
+				Expect(event.Data).To(MatchJSON(`{"route":"b.example.com"}`))
+			})
+		})
+	})
+
+	Describe("EventStreamWebSocket authorization", func() {
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(handler.TcpEventStreamWebSocket))
+		})
+
+		It("accepts the token as a query parameter", func() {
+			fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+
+			response, err := http.Get(server.URL + "?access_token=some-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+
+			token, _ := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(token).To(Equal("bearer some-token"))
+			Expect(database.WatchChangesCallCount()).To(Equal(0))
 		})
 	})
 })
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/metrics"
+	"code.cloudfoundry.org/routing-api/models"
+	"golang.org/x/net/websocket"
+)
+
+// EventStreamWebSocket is the WebSocket equivalent of EventStream. Clients
+// can replace the filter of the subscription and acknowledge events on the
+// live connection.
+func (h *EventStreamHandler) EventStreamWebSocket(w http.ResponseWriter, req *http.Request) {
+	err := h.stats.GaugeDelta(metrics.TotalHttpSubscriptions, 1, 1.0)
+	if err != nil {
+		h.logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metrics.TotalHttpSubscriptions})
+	}
+	defer func() {
+		err = h.stats.GaugeDelta(metrics.TotalHttpSubscriptions, -1, 1.0)
+		if err != nil {
+			h.logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metrics.TotalHttpSubscriptions})
+		}
+	}()
+	log := h.logger.Session("event-stream-websocket-handler", requestData(req))
+	h.handleWebSocket(log, db.HTTP_WATCH, w, req)
+}
+
+// TcpEventStreamWebSocket is the WebSocket equivalent of TcpEventStream.
+func (h *EventStreamHandler) TcpEventStreamWebSocket(w http.ResponseWriter, req *http.Request) {
+	err := h.stats.GaugeDelta(metrics.TotalTcpSubscriptions, 1, 1.0)
+	if err != nil {
+		h.logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metrics.TotalTcpSubscriptions})
+	}
+	defer func() {
+		err = h.stats.GaugeDelta(metrics.TotalTcpSubscriptions, -1, 1.0)
+		if err != nil {
+			h.logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metrics.TotalTcpSubscriptions})
+		}
+	}()
+	log := h.logger.Session("tcp-event-stream-websocket-handler", requestData(req))
+	h.handleWebSocket(log, db.TCP_WATCH, w, req)
+}
+
+func (h *EventStreamHandler) handleWebSocket(log lager.Logger, filterKey string,
+	w http.ResponseWriter, req *http.Request) {
+
+	var filter models.EventFilter
+	if value := req.URL.Query().Get("filter"); value != "" {
+		err := json.Unmarshal([]byte(value), &filter)
+		if err != nil {
+			handleProcessRequestError(w, err, log)
+			return
+		}
+	}
+
+	var window uint64
+	if value := req.URL.Query().Get("window"); value != "" {
+		var err error
+		window, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			handleProcessRequestError(w, err, log)
+			return
+		}
+	}
+
+	// browsers cannot set the Authorization header of a WebSocket
+	token := req.Header.Get("Authorization")
+	if token == "" && req.URL.Query().Get("access_token") != "" {
+		token = "bearer " + req.URL.Query().Get("access_token")
+	}
+
+	sub, ok := h.subscribe(log, filterKey, token, w, req)
+	if !ok {
+		return
+	}
+	defer sub.cancel()
+
+	server := websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			h.serveWebSocket(log, sub, filter, window, conn)
+		},
+	}
+	server.ServeHTTP(w, req)
+}
+
+// serveWebSocket sends the events of sub matching filter until the connection
+// closes. When window is not 0, at most window events are sent before the
+// client acknowledges them.
+func (h *EventStreamHandler) serveWebSocket(log lager.Logger, sub *eventSubscription,
+	filter models.EventFilter, window uint64, conn *websocket.Conn) {
+
+	controlChan := make(chan routing_api.WebSocketControl)
+	closed := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(closed)
+		for {
+			var control routing_api.WebSocketControl
+			err := websocket.JSON.Receive(conn, &control)
+			if err != nil {
+				return
+			}
+			select {
+			case controlChan <- control:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var sent, acked uint64
+	for {
+		resultChan := sub.resultChan
+		if window != 0 && sent-acked >= window {
+			resultChan = nil
+		}
+
+		select {
+		case event := <-resultChan:
+			if event.Type == db.InvalidEvent {
+				h.logger.Info("invalid-event", lager.Data{"event": event})
+				return
+			}
+
+			if event.Type == db.ResyncEvent {
+				log.Info("events-dropped", lager.Data{"event": event.Value})
+			}
+
+			if !sub.allows(event) || !matchesFilter(sub.filterKey, event, filter) {
+				continue
+			}
+
+			data, err := sub.data(event)
+			if err != nil {
+				log.Error("failed-to-encode-event", err, lager.Data{"event": event})
+				continue
+			}
+
+			sent++
+			err = websocket.JSON.Send(conn, routing_api.WebSocketEvent{
+				ID:   sent,
+				Name: event.Type.String(),
+				Data: string(data),
+			})
+			if err != nil {
+				log.Info("connection-closed")
+				return
+			}
+			metrics.IncrementEventsSent(sub.filterKey)
+		case control := <-controlChan:
+			if control.Filter != nil {
+				filter = *control.Filter
+			}
+			if control.Ack > acked && control.Ack <= sent {
+				acked = control.Ack
+			}
+		case err := <-sub.errChan:
+			log.Error("watch-error", err)
+			return
+		case <-closed:
+			log.Info("connection-closed")
+			return
+		}
+	}
+}
+
+func matchesFilter(filterKey string, event db.Event, filter models.EventFilter) bool {
+	if filter == (models.EventFilter{}) || event.Type == db.ResyncEvent {
+		return true
+	}
+
+	if filterKey == db.TCP_WATCH {
+		var tcpMapping models.TcpRouteMapping
+		err := json.Unmarshal([]byte(event.Value), &tcpMapping)
+		return err == nil && filter.MatchesTcpRouteMapping(tcpMapping)
+	}
+
+	var route models.Route
+	err := json.Unmarshal([]byte(event.Value), &route)
+	return err == nil && filter.MatchesRoute(route)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+)
+
+type HistoryHandler struct {
+	uaaClient uaaclient.Client
+	db        db.DB
+	logger    lager.Logger
+}
+
+func NewHistoryHandler(uaaClient uaaclient.Client, database db.DB, logger lager.Logger) *HistoryHandler {
+	return &HistoryHandler{
+		uaaClient: uaaClient,
+		db:        database,
+		logger:    logger,
+	}
+}
+
+// ListRouteHistory returns the recorded versions of the http route identified
+// by the route, ip and port query parameters, most recent first.
+func (h *HistoryHandler) ListRouteHistory(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("list-route-history", requestData(req))
+
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	query := req.URL.Query()
+	if query.Get("route") == "" || query.Get("ip") == "" {
+		handleProcessRequestError(w, errors.New("route, ip and port are required"), log)
+		return
+	}
+	port, err := parsePort(query, "port")
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	route := models.NewRoute(query.Get("route"), port, query.Get("ip"), "", "", 0)
+	h.writeVersions(w, requestDB(h.db, req), models.HistoryKindHttpRoute, route.HistoryKey(), log)
+}
+
+// ListTcpRouteHistory returns the recorded versions of the tcp route mapping
+// identified by the router_group_guid, port, backend_ip and backend_port query
+// parameters, most recent first.
+func (h *HistoryHandler) ListTcpRouteHistory(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("list-tcp-route-history", requestData(req))
+
+	query := req.URL.Query()
+	routerGroupGuid := query.Get("router_group_guid")
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesReadScope, RoutingRoutesGroupReadScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+	if !authorizer.HasGlobalScope() {
+		routerGroup, err := requestDB(h.db, req).ReadRouterGroup(routerGroupGuid)
+		if err != nil {
+			handleDBCommunicationError(w, err, log)
+			return
+		}
+		if !authorizer.Authorized(routerGroup.Name) {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return
+		}
+	}
+
+	if routerGroupGuid == "" || query.Get("backend_ip") == "" {
+		handleProcessRequestError(w, errors.New("router_group_guid, port, backend_ip and backend_port are required"), log)
+		return
+	}
+	port, err := parsePort(query, "port")
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+	backendPort, err := parsePort(query, "backend_port")
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	tcpMapping := models.NewTcpRouteMapping(routerGroupGuid, port, query.Get("backend_ip"), backendPort, 0)
+	h.writeVersions(w, requestDB(h.db, req), models.HistoryKindTcpRoute, tcpMapping.HistoryKey(), log)
+}
+
+func (h *HistoryHandler) writeVersions(w http.ResponseWriter, database db.DB, kind, key string, log lager.Logger) {
+	versions, err := database.ReadRouteVersions(kind, key)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(versions)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+	}
+}
+
+func parsePort(query url.Values, name string) (uint16, error) {
+	value := query.Get(name)
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return 0, errors.New("invalid " + name + ": " + value)
+	}
+	return uint16(port), nil
+}
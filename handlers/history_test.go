@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HistoryHandler", func() {
+	var (
+		historyHandler   *handlers.HistoryHandler
+		request          *http.Request
+		responseRecorder *httptest.ResponseRecorder
+		database         *fake_db.FakeDB
+		logger           *lagertest.TestLogger
+		fakeClient       *fake_client.FakeClient
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		fakeClient = &fake_client.FakeClient{}
+		logger = lagertest.NewTestLogger("routing-api-test")
+		historyHandler = handlers.NewHistoryHandler(fakeClient, database, logger)
+		responseRecorder = httptest.NewRecorder()
+	})
+
+	Describe("ListRouteHistory", func() {
+		BeforeEach(func() {
+			ttl := 60
+			database.ReadRouteVersionsReturns([]models.RouteVersion{
+				{
+					Kind:            models.HistoryKindHttpRoute,
+					Key:             "a.example.com,1.2.3.4:8080",
+					Time:            time.Unix(1000, 0).UTC(),
+					Action:          models.RouteVersionUpdated,
+					ModificationTag: models.ModificationTag{Guid: "abc", Index: 3},
+					TTL:             &ttl,
+					LogGuid:         "log-guid",
+				},
+			}, nil)
+		})
+
+		It("checks for routing.routes.read scope", func() {
+			request = handlers.NewTestRequest("")
+			historyHandler.ListRouteHistory(responseRecorder, request)
+
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.RoutingRoutesReadScope))
+		})
+
+		It("returns the versions of the route", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/routing/v1/routes/history?route=a.example.com&ip=1.2.3.4&port=8080", nil)
+			Expect(err).NotTo(HaveOccurred())
+			historyHandler.ListRouteHistory(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			kind, key := database.ReadRouteVersionsArgsForCall(0)
+			Expect(kind).To(Equal(models.HistoryKindHttpRoute))
+			Expect(key).To(Equal("a.example.com,1.2.3.4:8080"))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`[{
+				"kind": "http_route",
+				"key": "a.example.com,1.2.3.4:8080",
+				"time": "1970-01-01T00:16:40Z",
+				"action": "updated",
+				"modification_tag": {"guid": "abc", "index": 3},
+				"ttl": 60,
+				"log_guid": "log-guid"
+			}]`))
+		})
+
+		Context("when the port is invalid", func() {
+			It("returns a bad request", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/routing/v1/routes/history?route=a.example.com&ip=1.2.3.4&port=http", nil)
+				Expect(err).NotTo(HaveOccurred())
+				historyHandler.ListRouteHistory(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("invalid port"))
+				Expect(database.ReadRouteVersionsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the route is missing", func() {
+			It("returns a bad request", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/routing/v1/routes/history?ip=1.2.3.4&port=8080", nil)
+				Expect(err).NotTo(HaveOccurred())
+				historyHandler.ListRouteHistory(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(database.ReadRouteVersionsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the database returns an error", func() {
+			BeforeEach(func() {
+				database.ReadRouteVersionsReturns(nil, errors.New("stuff broke"))
+			})
+
+			It("returns an internal server error", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/routing/v1/routes/history?route=a.example.com&ip=1.2.3.4&port=8080", nil)
+				Expect(err).NotTo(HaveOccurred())
+				historyHandler.ListRouteHistory(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("ListTcpRouteHistory", func() {
+		BeforeEach(func() {
+			database.ReadRouteVersionsReturns([]models.RouteVersion{}, nil)
+			database.ReadRouterGroupReturns(models.RouterGroup{Guid: "rg-guid", Name: "default-tcp"}, nil)
+		})
+
+		It("returns the versions of the tcp route mapping", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/routing/v1/tcp_routes/history?router_group_guid=rg-guid&port=52000&backend_ip=1.2.3.4&backend_port=60000", nil)
+			Expect(err).NotTo(HaveOccurred())
+			historyHandler.ListTcpRouteHistory(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			kind, key := database.ReadRouteVersionsArgsForCall(0)
+			Expect(kind).To(Equal(models.HistoryKindTcpRoute))
+			Expect(key).To(Equal("rg-guid/52000/1.2.3.4:60000"))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`[]`))
+		})
+
+		Context("when the token only has the scope of the router group", func() {
+			BeforeEach(func() {
+				fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+					if desiredPermissions[0] == handlers.RoutingRoutesGroupReadScope("default-tcp") {
+						return nil
+					}
+					return errors.New("Token does not have 'routing.routes.read' scope")
+				}
+			})
+
+			It("returns the versions", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/routing/v1/tcp_routes/history?router_group_guid=rg-guid&port=52000&backend_ip=1.2.3.4&backend_port=60000", nil)
+				Expect(err).NotTo(HaveOccurred())
+				historyHandler.ListTcpRouteHistory(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(database.ReadRouterGroupArgsForCall(0)).To(Equal("rg-guid"))
+			})
+		})
+
+		Context("when the UAA token is not valid", func() {
+			BeforeEach(func() {
+				fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+			})
+
+			It("returns an Unauthorized status code", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/routing/v1/tcp_routes/history?router_group_guid=rg-guid&port=52000&backend_ip=1.2.3.4&backend_port=60000", nil)
+				Expect(err).NotTo(HaveOccurred())
+				historyHandler.ListTcpRouteHistory(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(database.ReadRouteVersionsCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
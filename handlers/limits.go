@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+)
+
+// RequestLimits bounds the bodies of the requests of an endpoint. MaxBodySize
+// is in bytes and MaxBatchLength is the number of items of a batch request; a
+// limit of 0 disables it. Strict rejects fields the items do not have.
+type RequestLimits struct {
+	MaxBodySize    int64
+	MaxBatchLength int
+	Strict         bool
+}
+
+type requestLimitsKey struct{}
+
+// LimitWrap applies limits to the requests of handler. A request whose
+// Content-Length is over the maximum body size is rejected before it is read;
+// other bodies fail to decode once they exceed it.
+func LimitWrap(handler http.Handler, limits RequestLimits, logger lager.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if limits.MaxBodySize > 0 && req.Body != nil {
+			if req.ContentLength > limits.MaxBodySize {
+				log := logger.Session("limit-request", requestData(req))
+				handlePayloadTooLargeError(w, bodyTooLargeError(limits.MaxBodySize), log)
+				return
+			}
+			req.Body = &limitedBody{ReadCloser: req.Body, limit: limits.MaxBodySize, remaining: limits.MaxBodySize}
+		}
+		req = req.WithContext(context.WithValue(req.Context(), requestLimitsKey{}, limits))
+		handler.ServeHTTP(w, req)
+	}
+}
+
+func requestLimits(req *http.Request) RequestLimits {
+	limits, _ := req.Context().Value(requestLimitsKey{}).(RequestLimits)
+	return limits
+}
+
+// payloadTooLargeError is returned when decoding a body over the limits of the
+// endpoint.
+type payloadTooLargeError struct {
+	message string
+}
+
+func (e payloadTooLargeError) Error() string {
+	return e.message
+}
+
+func bodyTooLargeError(limit int64) payloadTooLargeError {
+	return payloadTooLargeError{fmt.Sprintf("Request body is larger than the maximum of %d bytes", limit)}
+}
+
+// limitedBody fails reads past limit bytes with a payloadTooLargeError.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, bodyTooLargeError(b.limit)
+	}
+	// read one byte more than remains to tell a body of exactly the limit
+	// apart from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+	n = int(b.remaining)
+	b.remaining = 0
+	b.exceeded = true
+	return n, bodyTooLargeError(b.limit)
+}
+
+// decodeBody decodes the JSON object in the body of req into v.
+func decodeBody(req *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(req.Body)
+	if requestLimits(req).Strict {
+		decoder.DisallowUnknownFields()
+	}
+	return fieldError(decoder.Decode(v), 0)
+}
+
+// decodeBatch decodes the JSON array in the body of req into items, a pointer
+// to a slice. Each item is decoded on its own, so that an unknown field is
+// reported with the index of its item.
+func decodeBatch(req *http.Request, items interface{}) error {
+	limits := requestLimits(req)
+
+	var raw []json.RawMessage
+	err := json.NewDecoder(req.Body).Decode(&raw)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+	if limits.MaxBatchLength > 0 && len(raw) > limits.MaxBatchLength {
+		return payloadTooLargeError{fmt.Sprintf("Request has %d items, more than the maximum of %d", len(raw), limits.MaxBatchLength)}
+	}
+
+	slice := reflect.ValueOf(items).Elem()
+	decoded := reflect.MakeSlice(slice.Type(), len(raw), len(raw))
+	for i, data := range raw {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		if limits.Strict {
+			decoder.DisallowUnknownFields()
+		}
+		err = decoder.Decode(decoded.Index(i).Addr().Interface())
+		if err != nil {
+			return fieldError(err, i)
+		}
+	}
+	slice.Set(decoded)
+	return nil
+}
+
+const unknownFieldPrefix = "json: unknown field "
+
+// fieldError reports an unknown field of the item at index as a violation of
+// that field. Other errors are returned as they are.
+func fieldError(err error, index int) error {
+	if err == nil || !strings.HasPrefix(err.Error(), unknownFieldPrefix) {
+		return err
+	}
+	field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+	if unquoteErr != nil {
+		return err
+	}
+
+	apiErr := routing_api.NewError(routing_api.ProcessRequestError, err.Error())
+	apiErr.Details = []routing_api.ErrorDetail{{
+		Index:   index,
+		Field:   field,
+		Code:    routing_api.UnknownFieldCode,
+		Message: "Unknown field " + strconv.Quote(field),
+	}}
+	return apiErr
+}
+
+// handleDecodeError responds to a body that failed to decode, with a 413 if it
+// is over the limits of the endpoint.
+func handleDecodeError(w http.ResponseWriter, err error, log lager.Logger) {
+	if _, ok := err.(payloadTooLargeError); ok {
+		handlePayloadTooLargeError(w, err, log)
+		return
+	}
+	handleProcessRequestError(w, err, log)
+}
@@ -1,27 +1,166 @@
 package handlers
 
 import (
+	"compress/gzip"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/metrics"
+	"code.cloudfoundry.org/routing-api/tracing"
 	"github.com/cloudfoundry/dropsonde"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func LogWrap(handler http.Handler, logger lager.Logger) http.HandlerFunc {
 	handler = dropsonde.InstrumentedHandler(handler)
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		id := ensureRequestID(w, r, logger)
 		requestLog := logger.Session("request", lager.Data{
-			"method":  r.Method,
-			"request": r.URL.String(),
+			"method":     r.Method,
+			"request":    filterURL(r.URL),
+			"request-id": id,
 		})
 
 		requestLog.Info("serving", lager.Data{"request-headers": filter(r.Header)})
-		handler.ServeHTTP(w, r)
-		requestLog.Info("done", lager.Data{"response-headers": w.Header()})
+		recorder := metrics.NewResponseRecorder(w)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				requestLog.Error("aborted", fmt.Errorf("%v", recovered), lager.Data{"status": metrics.AbortedStatus, "response-headers": w.Header()})
+				panic(recovered)
+			}
+		}()
+		handler.ServeHTTP(recorder, r)
+		requestLog.Info("done", lager.Data{"status": recorder.Status(), "response-headers": w.Header()})
 	}
 }
 
+// MetricsWrap reports the requests of the endpoint named route to statsd.
+// requests.<route>.<status class>, e.g. requests.UpsertRoute.5xx, counts the
+// responses, requests.<route>.latency times them and requests.<route>.in_flight
+// gauges the requests being served.
+func MetricsWrap(handler http.Handler, route string, stats metrics.RequestStatsdClient, logger lager.Logger) http.HandlerFunc {
+	prefix := metrics.RequestsPrefix + "." + route + "."
+	inFlight := prefix + "in_flight"
+	latency := prefix + "latency"
+	logMetricError(stats.Gauge(inFlight, 0, 1.0), inFlight, logger)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		logMetricError(stats.GaugeDelta(inFlight, 1, 1.0), inFlight, logger)
+
+		recorder := metrics.NewResponseRecorder(w)
+		defer func() {
+			recovered := recover()
+			code := recorder.Status()
+			if recovered != nil {
+				code = metrics.AbortedStatus
+			}
+			logMetricError(stats.GaugeDelta(inFlight, -1, 1.0), inFlight, logger)
+			logMetricError(stats.TimingDuration(latency, time.Since(start), 1.0), latency, logger)
+			status := fmt.Sprintf("%s%dxx", prefix, code/100)
+			logMetricError(stats.Inc(status, 1, 1.0), status, logger)
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+		handler.ServeHTTP(recorder, r)
+	}
+}
+
+// TraceWrap records a span for every request to the endpoint named route,
+// continuing the trace of the traceparent header of the request. It sets the
+// request id before the span starts, so that the span records the id the
+// request is logged with. The handlers record the token decoding and the
+// database calls of the request as children of the span.
+func TraceWrap(handler http.Handler, route string, tracer *tracing.Tracer, logger lager.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := ensureRequestID(w, r, logger)
+		ctx, span := tracer.StartRequest(r.Context(), route, r.Header)
+		span.SetAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.target", r.URL.Path),
+			attribute.String("request_id", id),
+		)
+
+		recorder := metrics.NewResponseRecorder(w)
+		defer func() {
+			recovered := recover()
+			code := recorder.Status()
+			if recovered != nil {
+				code = metrics.AbortedStatus
+			}
+			span.SetAttributes(attribute.Int("http.status_code", code))
+			if code >= http.StatusInternalServerError {
+				tracing.SetError(span, fmt.Errorf("responded with %d", code))
+			}
+			span.End()
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+		handler.ServeHTTP(recorder, r.WithContext(ctx))
+	}
+}
+
+func logMetricError(err error, metric string, logger lager.Logger) {
+	if err != nil {
+		logger.Info("error-sending-metrics", lager.Data{"error": err, "metric": metric})
+	}
+}
+
+// ensureRequestID generates an id for requests without one and echoes it in
+// the response. The id is set on the request, so that the handlers log it and
+// record it in the audit records and events of the request.
+func ensureRequestID(w http.ResponseWriter, r *http.Request, logger lager.Logger) string {
+	id := requestID(r)
+	if id == "" {
+		var err error
+		id, err = routing_api.NewRequestID()
+		if err != nil {
+			logger.Error("failed-to-generate-request-id", err)
+			return ""
+		}
+	}
+	r.Header.Set(routing_api.VcapRequestIDHeader, id)
+	w.Header().Set(routing_api.VcapRequestIDHeader, id)
+	if r.Header.Get(routing_api.RequestIDHeader) != "" {
+		w.Header().Set(routing_api.RequestIDHeader, id)
+	}
+	return id
+}
+
+func requestID(req *http.Request) string {
+	if id := req.Header.Get(routing_api.VcapRequestIDHeader); id != "" {
+		return id
+	}
+	return req.Header.Get(routing_api.RequestIDHeader)
+}
+
+// requestData is the data of the log sessions of the handlers, so that every
+// line of a request can be found by its id.
+func requestData(req *http.Request) lager.Data {
+	return lager.Data{"request-id": requestID(req)}
+}
+
+// filterURL removes the access token WebSocket clients may send as a query
+// parameter.
+func filterURL(u *url.URL) string {
+	query := u.Query()
+	if query.Get("access_token") == "" {
+		return u.String()
+	}
+	query.Set("access_token", "[REDACTED]")
+	filtered := *u
+	filtered.RawQuery = query.Encode()
+	return filtered.String()
+}
+
 func filter(header http.Header) http.Header {
 	filtered := make(http.Header)
 	for k, v := range header {
@@ -31,3 +170,87 @@ func filter(header http.Header) http.Header {
 	filtered.Del("Authorization")
 	return filtered
 }
+
+// GzipWrap compresses successful responses of clients that send
+// Accept-Encoding: gzip. Flushing the response flushes the compressed stream,
+// so it can wrap event streams.
+func GzipWrap(handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.Close()
+		handler.ServeHTTP(gw, r)
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, coding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(coding, ";")
+		if strings.TrimSpace(parts[0]) != "gzip" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if strings.Replace(param, " ", "", -1) == "q=0" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter only compresses responses with status 200, so that
+// empty 304 responses and error responses are sent as they are.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if code == http.StatusOK {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.gz.Write(data)
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) CloseNotify() <-chan bool {
+	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (w *gzipResponseWriter) Close() error {
+	if w.gz == nil {
+		return nil
+	}
+	return w.gz.Close()
+}
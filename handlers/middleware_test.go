@@ -1,15 +1,34 @@
 package handlers_test
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
 	"code.cloudfoundry.org/routing-api/handlers"
+	fake_validator "code.cloudfoundry.org/routing-api/handlers/fakes"
+	fake_statsd "code.cloudfoundry.org/routing-api/metrics/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
+	"code.cloudfoundry.org/routing-api/tracing"
+	fake_tracing "code.cloudfoundry.org/routing-api/tracing/fakes"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var _ = Describe("Middleware", func() {
@@ -74,4 +93,378 @@ var _ = Describe("Middleware", func() {
 		Expect(headers).ToNot(HaveKey("AUTHORIZATION"))
 		Expect(headers).ToNot(HaveKey("auThoRizaTion"))
 	})
+
+	Describe("request ids", func() {
+		It("generates a request id and echoes it in the response", func() {
+			resp, err := client.Get(ts.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+
+			id := resp.Header.Get("X-Vcap-Request-Id")
+			Expect(id).NotTo(BeEmpty())
+			Expect(testSink.Logs()[0].Data["request-id"]).To(Equal(id))
+		})
+
+		It("uses the X-Vcap-Request-Id of the request", func() {
+			req, err := http.NewRequest("GET", ts.URL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("X-Vcap-Request-Id", "some-request-id")
+
+			resp, err := client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+
+			Expect(resp.Header.Get("X-Vcap-Request-Id")).To(Equal("some-request-id"))
+			for _, log := range testSink.Logs() {
+				Expect(log.Data["request-id"]).To(Equal("some-request-id"))
+			}
+		})
+
+		It("uses and echoes the X-Request-Id of the request", func() {
+			req, err := http.NewRequest("GET", ts.URL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("X-Request-Id", "some-request-id")
+
+			resp, err := client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+
+			Expect(resp.Header.Get("X-Vcap-Request-Id")).To(Equal("some-request-id"))
+			Expect(resp.Header.Get("X-Request-Id")).To(Equal("some-request-id"))
+		})
+
+		It("passes the request id to the handler", func() {
+			var handlerID string
+			handler := handlers.LogWrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerID = r.Header.Get("X-Vcap-Request-Id")
+			}), lagertest.NewTestLogger("dummy-api"))
+
+			req, err := http.NewRequest("GET", "/", nil)
+			Expect(err).NotTo(HaveOccurred())
+			responseRecorder := httptest.NewRecorder()
+			handler(responseRecorder, req)
+
+			Expect(handlerID).NotTo(BeEmpty())
+			Expect(handlerID).To(Equal(responseRecorder.Header().Get("X-Vcap-Request-Id")))
+		})
+	})
+
+	It("logs and echoes the request id of requests rejected by the request limits", func() {
+		logger := lagertest.NewTestLogger("limits")
+		handler := handlers.LogWrap(handlers.LimitWrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Fail("the handler must not be called")
+		}), handlers.RequestLimits{MaxBodySize: 4}, logger), logger)
+
+		req, err := http.NewRequest("POST", "/routing/v1/routes", strings.NewReader("[{}, {}]"))
+		Expect(err).NotTo(HaveOccurred())
+		responseRecorder := httptest.NewRecorder()
+		handler(responseRecorder, req)
+
+		Expect(responseRecorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		id := responseRecorder.Header().Get("X-Vcap-Request-Id")
+		Expect(id).NotTo(BeEmpty())
+		Expect(responseRecorder.Body.String()).To(ContainSubstring(id))
+
+		Expect(logger).To(gbytes.Say("limits.limit-request.*" + id))
+		done := logger.Logs()[len(logger.Logs())-1]
+		Expect(done.Message).To(Equal("limits.request.done"))
+		Expect(done.Data["request-id"]).To(Equal(id))
+		Expect(done.Data["status"]).To(BeNumerically("==", http.StatusRequestEntityTooLarge))
+	})
+
+	It("logs the requests whose handler aborts the response", func() {
+		logger := lagertest.NewTestLogger("aborted")
+		handler := handlers.LogWrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic(http.ErrAbortHandler)
+		}), logger)
+
+		req, err := http.NewRequest("GET", "/routing/v1/routes", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(func() { handler(httptest.NewRecorder(), req) }).To(Panic())
+
+		aborted := logger.Logs()[len(logger.Logs())-1]
+		Expect(aborted.Message).To(Equal("aborted.request.aborted"))
+		Expect(aborted.Data["request-id"]).NotTo(BeEmpty())
+		Expect(aborted.Data["status"]).To(BeNumerically("==", http.StatusInternalServerError))
+	})
+
+	It("doesn't output the access token of the query", func() {
+		resp, err := client.Get(ts.URL + "/routing/v1/events/ws?access_token=this-is-a-secret")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		request := testSink.Logs()[0].Data["request"]
+		Expect(request).NotTo(ContainSubstring("this-is-a-secret"))
+		Expect(request).To(ContainSubstring("/routing/v1/events/ws"))
+	})
+})
+
+var _ = Describe("GzipWrap", func() {
+	var (
+		handler          http.HandlerFunc
+		request          *http.Request
+		responseRecorder *httptest.ResponseRecorder
+		status           int
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		handler = handlers.GzipWrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			fmt.Fprintf(w, "Dummy handler")
+			w.(http.Flusher).Flush()
+		}))
+
+		var err error
+		request, err = http.NewRequest("GET", "/", nil)
+		Expect(err).NotTo(HaveOccurred())
+		responseRecorder = httptest.NewRecorder()
+	})
+
+	Context("when the client accepts gzip", func() {
+		BeforeEach(func() {
+			request.Header.Set("Accept-Encoding", "deflate, gzip")
+		})
+
+		It("compresses the response", func() {
+			handler(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Header().Get("Content-Encoding")).To(Equal("gzip"))
+			Expect(responseRecorder.Header().Get("Vary")).To(Equal("Accept-Encoding"))
+
+			reader, err := gzip.NewReader(responseRecorder.Body)
+			Expect(err).NotTo(HaveOccurred())
+			body, err := ioutil.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal("Dummy handler"))
+		})
+
+		It("does not compress unsuccessful responses", func() {
+			status = http.StatusInternalServerError
+			handler(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(responseRecorder.Header().Get("Content-Encoding")).To(BeEmpty())
+			Expect(responseRecorder.Body.String()).To(Equal("Dummy handler"))
+		})
+	})
+
+	Context("when the client refuses gzip", func() {
+		BeforeEach(func() {
+			request.Header.Set("Accept-Encoding", "gzip;q=0")
+		})
+
+		It("does not compress the response", func() {
+			handler(responseRecorder, request)
+
+			Expect(responseRecorder.Header().Get("Content-Encoding")).To(BeEmpty())
+			Expect(responseRecorder.Body.String()).To(Equal("Dummy handler"))
+		})
+	})
+
+	Context("when the client does not send Accept-Encoding", func() {
+		It("does not compress the response", func() {
+			handler(responseRecorder, request)
+
+			Expect(responseRecorder.Header().Get("Content-Encoding")).To(BeEmpty())
+			Expect(responseRecorder.Body.String()).To(Equal("Dummy handler"))
+		})
+	})
+})
+
+var _ = Describe("MetricsWrap", func() {
+	var (
+		stats  *fake_statsd.FakeRequestStatsdClient
+		status int
+		served chan struct{}
+		wrap   http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		stats = &fake_statsd.FakeRequestStatsdClient{}
+		status = http.StatusOK
+		served = make(chan struct{}, 1)
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served <- struct{}{}
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+			}
+		})
+		wrap = handlers.MetricsWrap(handler, "UpsertTcpRouteMapping", stats, lagertest.NewTestLogger("metrics"))
+	})
+
+	It("resets the in-flight gauge of the endpoint", func() {
+		Expect(stats.GaugeCallCount()).To(Equal(1))
+		stat, value, _ := stats.GaugeArgsForCall(0)
+		Expect(stat).To(Equal("requests.UpsertTcpRouteMapping.in_flight"))
+		Expect(value).To(BeZero())
+	})
+
+	It("gauges the requests in flight", func() {
+		stats.GaugeDeltaStub = func(stat string, value int64, rate float32) error {
+			if value == 1 {
+				Expect(served).To(BeEmpty())
+			} else {
+				Expect(served).To(HaveLen(1))
+			}
+			return nil
+		}
+		wrap.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/routing/v1/tcp_routes/create", nil))
+
+		Expect(stats.GaugeDeltaCallCount()).To(Equal(2))
+		stat, value, _ := stats.GaugeDeltaArgsForCall(0)
+		Expect(stat).To(Equal("requests.UpsertTcpRouteMapping.in_flight"))
+		Expect(value).To(Equal(int64(1)))
+		stat, value, _ = stats.GaugeDeltaArgsForCall(1)
+		Expect(stat).To(Equal("requests.UpsertTcpRouteMapping.in_flight"))
+		Expect(value).To(Equal(int64(-1)))
+	})
+
+	It("times the requests", func() {
+		wrap.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/routing/v1/tcp_routes/create", nil))
+
+		Expect(stats.TimingDurationCallCount()).To(Equal(1))
+		stat, delta, _ := stats.TimingDurationArgsForCall(0)
+		Expect(stat).To(Equal("requests.UpsertTcpRouteMapping.latency"))
+		Expect(delta).To(BeNumerically(">", 0))
+	})
+
+	It("counts the responses by status class", func() {
+		wrap.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/routing/v1/tcp_routes/create", nil))
+		status = http.StatusInternalServerError
+		wrap.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/routing/v1/tcp_routes/create", nil))
+
+		Expect(stats.IncCallCount()).To(Equal(2))
+		stat, value, _ := stats.IncArgsForCall(0)
+		Expect(stat).To(Equal("requests.UpsertTcpRouteMapping.2xx"))
+		Expect(value).To(Equal(int64(1)))
+		stat, _, _ = stats.IncArgsForCall(1)
+		Expect(stat).To(Equal("requests.UpsertTcpRouteMapping.5xx"))
+	})
+
+	It("counts the responses the handler aborts as failures", func() {
+		wrap = handlers.MetricsWrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic(http.ErrAbortHandler)
+		}), "ListRoute", stats, lagertest.NewTestLogger("metrics"))
+
+		Expect(func() {
+			wrap.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/routing/v1/routes", nil))
+		}).To(Panic())
+
+		Expect(stats.GaugeDeltaCallCount()).To(Equal(2))
+		Expect(stats.TimingDurationCallCount()).To(Equal(1))
+		Expect(stats.IncCallCount()).To(Equal(1))
+		stat, _, _ := stats.IncArgsForCall(0)
+		Expect(stat).To(Equal("requests.ListRoute.5xx"))
+	})
+})
+
+var _ = Describe("TraceWrap", func() {
+	var (
+		exporter   *fake_tracing.FakeExporter
+		tracer     *tracing.Tracer
+		process    ifrit.Process
+		database   *fake_db.FakeDB
+		fakeClient *fake_client.FakeClient
+		handler    http.Handler
+	)
+
+	BeforeEach(func() {
+		exporter = &fake_tracing.FakeExporter{}
+		logger := lagertest.NewTestLogger("tracing")
+		tracer = tracing.NewTracer(exporter, sdktrace.AlwaysSample(), time.Hour, logger)
+		process = ifrit.Invoke(tracer)
+
+		database = &fake_db.FakeDB{}
+		fakeClient = &fake_client.FakeClient{}
+		routesHandler := handlers.NewRoutesHandler(fakeClient, models.TTLPolicy{MaxTTL: 50, DefaultTTL: 50}, &fake_validator.FakeRouteValidator{}, database, logger, &fake_audit.FakeRecorder{}, &fake_quota.FakeEnforcer{})
+		handler = handlers.TraceWrap(http.HandlerFunc(routesHandler.ListV2), "ListRoutesV2", tracer, logger)
+	})
+
+	exportedSpans := func() []sdktrace.ReadOnlySpan {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		spans := []sdktrace.ReadOnlySpan{}
+		for i := 0; i < exporter.ExportSpansCallCount(); i++ {
+			_, exported := exporter.ExportSpansArgsForCall(i)
+			spans = append(spans, exported...)
+		}
+		return spans
+	}
+
+	attributes := func(span sdktrace.ReadOnlySpan) map[string]interface{} {
+		values := map[string]interface{}{}
+		for _, attr := range span.Attributes() {
+			values[string(attr.Key)] = attr.Value.AsInterface()
+		}
+		return values
+	}
+
+	It("records a span for the request with the token decoding and database calls as children", func() {
+		request := handlers.NewTestRequest("")
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		request.Header.Set("X-Vcap-Request-Id", "some-request-id")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		spans := exportedSpans()
+		Expect(spans).To(HaveLen(3))
+		decodeToken, readRoutes, requestSpan := spans[0], spans[1], spans[2]
+
+		Expect(requestSpan.Name()).To(Equal("ListRoutesV2"))
+		Expect(requestSpan.SpanKind()).To(Equal(trace.SpanKindServer))
+		Expect(requestSpan.SpanContext().TraceID().String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+		Expect(requestSpan.Parent().SpanID().String()).To(Equal("00f067aa0ba902b7"))
+		Expect(attributes(requestSpan)).To(HaveKeyWithValue("http.status_code", int64(200)))
+		Expect(attributes(requestSpan)).To(HaveKeyWithValue("request_id", "some-request-id"))
+		Expect(requestSpan.Status().Code).To(Equal(codes.Unset))
+
+		Expect(decodeToken.Name()).To(Equal("uaa.DecodeToken"))
+		Expect(decodeToken.Parent().SpanID()).To(Equal(requestSpan.SpanContext().SpanID()))
+		Expect(readRoutes.Name()).To(Equal("db.ReadRoutes"))
+		Expect(readRoutes.Parent().SpanID()).To(Equal(requestSpan.SpanContext().SpanID()))
+		Expect(fakeClient.DecodeTokenCallCount()).To(Equal(1))
+		Expect(database.ReadRoutesCallCount()).To(Equal(1))
+	})
+
+	It("records the request id it generates for requests without one", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, handlers.NewTestRequest(""))
+
+		id := recorder.Header().Get("X-Vcap-Request-Id")
+		Expect(id).NotTo(BeEmpty())
+
+		spans := exportedSpans()
+		Expect(spans).To(HaveLen(3))
+		Expect(attributes(spans[2])).To(HaveKeyWithValue("request_id", id))
+	})
+
+	It("marks the spans of failures", func() {
+		database.ReadRoutesReturns(nil, errors.New("db is down"))
+		handler.ServeHTTP(httptest.NewRecorder(), handlers.NewTestRequest(""))
+
+		spans := exportedSpans()
+		Expect(spans).To(HaveLen(3))
+		Expect(spans[1].Status().Code).To(Equal(codes.Error))
+		Expect(spans[1].Status().Description).To(Equal("db is down"))
+		Expect(attributes(spans[2])).To(HaveKeyWithValue("http.status_code", int64(500)))
+		Expect(spans[2].Status().Description).To(Equal("responded with 500"))
+	})
+
+	It("does not record the spans of requests that are not sampled", func() {
+		request := handlers.NewTestRequest("")
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		tracer = tracing.NewTracer(exporter, sdktrace.ParentBased(sdktrace.AlwaysSample()), time.Hour, lagertest.NewTestLogger("tracing"))
+		process = ifrit.Invoke(tracer)
+		handler = handlers.TraceWrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(tracing.IsTraced(r.Context())).To(BeFalse())
+		}), "ListRoutesV2", tracer, lagertest.NewTestLogger("tracing"))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		Expect(exportedSpans()).To(BeEmpty())
+	})
 })
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/quota"
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+)
+
+type QuotaHandler struct {
+	uaaClient uaaclient.Client
+	quotas    quota.Enforcer
+	logger    lager.Logger
+}
+
+func NewQuotaHandler(uaaClient uaaclient.Client, quotas quota.Enforcer, logger lager.Logger) *QuotaHandler {
+	return &QuotaHandler{
+		uaaClient: uaaClient,
+		quotas:    quotas,
+		logger:    logger,
+	}
+}
+
+// List returns the limit and current usage of every enabled quota.
+func (h *QuotaHandler) List(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("list-quotas", requestData(req))
+
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	usages, err := h.quotas.Usage()
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(usages)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+	}
+}
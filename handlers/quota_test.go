@@ -0,0 +1,87 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuotaHandler", func() {
+	var (
+		quotaHandler     *handlers.QuotaHandler
+		request          *http.Request
+		responseRecorder *httptest.ResponseRecorder
+		quotas           *fake_quota.FakeEnforcer
+		logger           *lagertest.TestLogger
+		fakeClient       *fake_client.FakeClient
+	)
+
+	BeforeEach(func() {
+		quotas = &fake_quota.FakeEnforcer{}
+		fakeClient = &fake_client.FakeClient{}
+		logger = lagertest.NewTestLogger("routing-api-test")
+		quotaHandler = handlers.NewQuotaHandler(fakeClient, quotas, logger)
+		responseRecorder = httptest.NewRecorder()
+		request = handlers.NewTestRequest("")
+	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			quotas.UsageReturns([]models.QuotaUsage{
+				{Quota: models.QuotaHttpRoutesPerOwner, Limit: 10, Usage: map[string]int{"cf": 3}},
+			}, nil)
+		})
+
+		It("checks for routing.routes.read scope", func() {
+			quotaHandler.List(responseRecorder, request)
+
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.RoutingRoutesReadScope))
+		})
+
+		It("returns the usage of the quotas", func() {
+			quotaHandler.List(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`[{
+				"quota": "http_routes_per_owner",
+				"limit": 10,
+				"usage": {"cf": 3}
+			}]`))
+		})
+
+		Context("when reading the usage fails", func() {
+			BeforeEach(func() {
+				quotas.UsageReturns(nil, errors.New("stuff broke"))
+			})
+
+			It("returns an internal server error", func() {
+				quotaHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when the UAA token is not valid", func() {
+			BeforeEach(func() {
+				fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+			})
+
+			It("returns an Unauthorized status code", func() {
+				quotaHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(quotas.UsageCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/routing-api/db"
+)
+
+func revisionETag(revision uint64) string {
+	return `"` + strconv.FormatUint(revision, 10) + `"`
+}
+
+// notModified sets the table revision as the ETag of the response and writes
+// a 304 if the If-None-Match header of the request already names it. The
+// revision must be read before the table, so that a concurrent write can only
+// make the ETag older than the data, never newer.
+func notModified(w http.ResponseWriter, req *http.Request, revision uint64) bool {
+	etag := revisionETag(revision)
+	w.Header().Set("ETag", etag)
+	varyAccept(w)
+
+	for _, candidate := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// eventData adds the table revision and the id of the request that caused the
+// event to the JSON object of an event, so that subscribers know which
+// revision of the table the event leads to and can correlate it with the
+// request.
+func eventData(event db.Event) []byte {
+	value := event.Value
+	if !strings.HasPrefix(value, "{") {
+		return []byte(value)
+	}
+
+	var fields []string
+	if event.Revision != 0 {
+		fields = append(fields, `"revision":`+strconv.FormatUint(event.Revision, 10))
+	}
+	if event.RequestID != "" {
+		id, _ := json.Marshal(event.RequestID)
+		fields = append(fields, `"request_id":`+string(id))
+	}
+	if len(fields) == 0 {
+		return []byte(value)
+	}
+
+	data := "{" + strings.Join(fields, ",")
+	if rest := strings.TrimSpace(value[1:]); rest != "}" {
+		data += ","
+	}
+	return []byte(data + value[1:])
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/audit"
 	"code.cloudfoundry.org/routing-api/db"
 	"code.cloudfoundry.org/routing-api/models"
 	uaaclient "code.cloudfoundry.org/uaa-go-client"
@@ -22,33 +23,67 @@ type RouterGroupsHandler struct {
 	uaaClient uaaclient.Client
 	logger    lager.Logger
 	db        db.DB
+	auditor   audit.Recorder
+	ttlPolicy models.TTLPolicy
 }
 
-func NewRouteGroupsHandler(uaaClient uaaclient.Client, logger lager.Logger, db db.DB) *RouterGroupsHandler {
+func NewRouteGroupsHandler(uaaClient uaaclient.Client, logger lager.Logger, db db.DB, auditor audit.Recorder, ttlPolicy models.TTLPolicy) *RouterGroupsHandler {
 	return &RouterGroupsHandler{
 		uaaClient: uaaClient,
 		logger:    logger,
 		db:        db,
+		auditor:   auditor,
+		ttlPolicy: ttlPolicy,
 	}
 }
 
 func (h *RouterGroupsHandler) ListRouterGroups(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("list-router-groups")
+	log := h.logger.Session("list-router-groups", requestData(req))
 	log.Debug("started")
 	defer log.Debug("completed")
 
-	err := h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RouterGroupsReadScope)
-	if err != nil {
-		handleUnauthorizedError(w, err, log)
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RouterGroupsReadScope, RouterGroupReadScope)
+	if !authenticated(w, authorizer, log) {
 		return
 	}
 
-	routerGroups, err := h.db.ReadRouterGroups()
+	revision, err := requestDB(h.db, req).ReadRevision(db.ROUTER_GROUPS_TABLE)
+	var routerGroups models.RouterGroups
+	if err == nil {
+		routerGroups, err = requestDB(h.db, req).ReadRouterGroups()
+	}
 	if err != nil {
 		handleDBCommunicationError(w, err, log)
 		return
 	}
 
+	if !authorizer.HasGlobalScope() {
+		authorized := models.RouterGroups{}
+		for _, routerGroup := range routerGroups {
+			if authorizer.Authorized(routerGroup.Name) {
+				authorized = append(authorized, routerGroup)
+			}
+		}
+		if len(authorized) == 0 {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return
+		}
+		routerGroups = authorized
+	}
+
+	if notModified(w, req, revision) {
+		return
+	}
+
+	for i := range routerGroups {
+		h.setEffectiveTTL(&routerGroups[i])
+	}
+
+	if acceptsProtobuf(req) {
+		writeList(w, req, routerGroups, log)
+		return
+	}
+
 	jsonBytes, err := json.Marshal(routerGroups)
 	if err != nil {
 		log.Error("failed-to-marshal", err)
@@ -63,7 +98,7 @@ func (h *RouterGroupsHandler) ListRouterGroups(w http.ResponseWriter, req *http.
 }
 
 func (h *RouterGroupsHandler) UpdateRouterGroup(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("update-router-group")
+	log := h.logger.Session("update-router-group", requestData(req))
 	log.Debug("started")
 	defer log.Debug("completed")
 	defer func() {
@@ -71,24 +106,26 @@ func (h *RouterGroupsHandler) UpdateRouterGroup(w http.ResponseWriter, req *http
 		log.Error("failed-to-close-request-body", err)
 	}()
 
-	err := h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RouterGroupsWriteScope)
-	if err != nil {
-		handleUnauthorizedError(w, err, log)
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RouterGroupsWriteScope, RouterGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
 		return
 	}
 
-	bodyDecoder := json.NewDecoder(req.Body)
-	var updatedGroup models.RouterGroup
-	err = bodyDecoder.Decode(&updatedGroup)
+	// the router group is read first, so that a token scoped to router groups
+	// is authorized before the request is processed any further
+	guid := rata.Param(req, "guid")
+	rg, err := requestDB(h.db, req).ReadRouterGroup(guid)
 	if err != nil {
-		handleProcessRequestError(w, err, log)
+		handleDBCommunicationError(w, err, log)
 		return
 	}
 
-	guid := rata.Param(req, "guid")
-	rg, err := h.db.ReadRouterGroup(guid)
-	if err != nil {
-		handleDBCommunicationError(w, err, log)
+	if !authorizer.Authorized(rg.Name) {
+		err = authorizer.Err()
+		if rg.Name != "" {
+			err = unauthorizedItemsError{cause: err, items: []string{"RouterGroup=[" + rg.Name + "]"}}
+		}
+		handleUnauthorizedError(w, err, log)
 		return
 	}
 
@@ -97,21 +134,48 @@ func (h *RouterGroupsHandler) UpdateRouterGroup(w http.ResponseWriter, req *http
 		return
 	}
 
-	if updatedGroup.ReservablePorts != "" && rg.ReservablePorts != updatedGroup.ReservablePorts {
-		rg.ReservablePorts = updatedGroup.ReservablePorts
-		err = rg.Validate()
+	var updatedGroup RouterGroupUpdate
+	err = decodeBody(req, &updatedGroup)
+	if err != nil {
+		handleDecodeError(w, err, log)
+		return
+	}
+
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	before := rg
+	if MergeRouterGroup(&rg, updatedGroup) {
+		err = ValidateRouterGroup(rg, h.ttlPolicy.MaxTTL)
 		if err != nil {
 			handleProcessRequestError(w, err, log)
 			return
 		}
 
-		err = h.db.SaveRouterGroup(rg)
+		if dryRun {
+			change, err := PlanRouterGroupUpdate(requestDB(h.db, req), before, rg)
+			if err != nil {
+				handleDBCommunicationError(w, err, log)
+				return
+			}
+			writeDryRun(w, change, log)
+			return
+		}
+
+		err = SaveRouterGroup(requestDB(h.db, req), h.auditor, newAuditContext(req), before, rg)
 		if err != nil {
 			handleDBCommunicationError(w, err, log)
 			return
 		}
+	} else if dryRun {
+		writeDryRun(w, models.RouterGroupChange{Action: models.ChangeNone, Before: &rg}, log)
+		return
 	}
 
+	h.setEffectiveTTL(&rg)
 	jsonBytes, err := json.Marshal(rg)
 	if err != nil {
 		log.Error("failed-to-marshal", err)
@@ -127,6 +191,13 @@ func (h *RouterGroupsHandler) UpdateRouterGroup(w http.ResponseWriter, req *http
 	w.Header().Set("Content-Length", strconv.Itoa(len(jsonBytes)))
 }
 
+// setEffectiveTTL reports the ttl policy that applies to the tcp routes of a
+// router group, taking the configured policy into account.
+func (h *RouterGroupsHandler) setEffectiveTTL(routerGroup *models.RouterGroup) {
+	policy := routerGroup.TTLPolicy(h.ttlPolicy)
+	routerGroup.EffectiveTTL = &policy
+}
+
 func addWarningsHeader(w http.ResponseWriter) {
 	w.Header().Set("X-Cf-Warnings", url.QueryEscape(portWarning))
 }
@@ -12,10 +12,13 @@ import (
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/routing-api"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	"code.cloudfoundry.org/routing-api/db"
 	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
 	"code.cloudfoundry.org/routing-api/handlers"
 	"code.cloudfoundry.org/routing-api/metrics"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
 	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -37,13 +40,15 @@ var _ = Describe("RouterGroupsHandler", func() {
 		fakeClient         *fake_client.FakeClient
 		fakeDb             *fake_db.FakeDB
 		logger             *lagertest.TestLogger
+		auditor            *fake_audit.FakeRecorder
 	)
 
 	BeforeEach(func() {
 		logger = lagertest.NewTestLogger("test-router-group")
 		fakeClient = &fake_client.FakeClient{}
 		fakeDb = &fake_db.FakeDB{}
-		routerGroupHandler = handlers.NewRouteGroupsHandler(fakeClient, logger, fakeDb)
+		auditor = &fake_audit.FakeRecorder{}
+		routerGroupHandler = handlers.NewRouteGroupsHandler(fakeClient, logger, fakeDb, auditor, models.TTLPolicy{MaxTTL: 120, DefaultTTL: 60})
 		responseRecorder = httptest.NewRecorder()
 
 		fakeRouterGroups := []models.RouterGroup{
@@ -70,10 +75,29 @@ var _ = Describe("RouterGroupsHandler", func() {
 				"guid": "bad25cff-9332-48a6-8603-b619858e7992",
 				"name": "default-tcp",
 				"type": "tcp",
-				"reservable_ports": "1024-65535"
+				"reservable_ports": "1024-65535",
+				"effective_ttl": {"max_ttl": 120, "default_ttl": 60}
 			}]`))
 		})
 
+		It("returns the router groups as protobuf when the client accepts it", func() {
+			var err error
+			request, err = http.NewRequest("GET", routing_api.ListRouterGroups, nil)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Set("Accept", protos.ContentType)
+			routerGroupHandler.ListRouterGroups(responseRecorder, request)
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Header().Get("Content-Type")).To(Equal(protos.ContentType))
+
+			var routerGroups []models.RouterGroup
+			err = protos.Unmarshal(responseRecorder.Body.Bytes(), &routerGroups)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routerGroups).To(HaveLen(1))
+			Expect(routerGroups[0].Guid).To(Equal("bad25cff-9332-48a6-8603-b619858e7992"))
+			Expect(routerGroups[0].Name).To(Equal("default-tcp"))
+			Expect(routerGroups[0].EffectiveTTL).To(Equal(&models.TTLPolicy{MaxTTL: 120, DefaultTTL: 60}))
+		})
+
 		It("checks for routing.router_groups.read scope", func() {
 			var err error
 			request, err = http.NewRequest("GET", routing_api.ListRouterGroups, nil)
@@ -83,6 +107,25 @@ var _ = Describe("RouterGroupsHandler", func() {
 			Expect(permission).To(ConsistOf(handlers.RouterGroupsReadScope))
 		})
 
+		Context("when If-None-Match names the current revision", func() {
+			BeforeEach(func() {
+				fakeDb.ReadRevisionReturns(7, nil)
+			})
+
+			It("responds with 304 Not Modified", func() {
+				var err error
+				request, err = http.NewRequest("GET", routing_api.ListRouterGroups, nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("If-None-Match", `"7"`)
+				routerGroupHandler.ListRouterGroups(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotModified))
+				Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"7"`))
+				Expect(responseRecorder.Body.String()).To(BeEmpty())
+				Expect(fakeDb.ReadRevisionArgsForCall(0)).To(Equal(db.ROUTER_GROUPS_TABLE))
+			})
+		})
+
 		Context("when the db fails to save router group", func() {
 			BeforeEach(func() {
 				fakeDb.ReadRouterGroupsReturns([]models.RouterGroup{}, errors.New("db communication failed"))
@@ -119,9 +162,54 @@ var _ = Describe("RouterGroupsHandler", func() {
 				routerGroupHandler.ListRouterGroups(responseRecorder, request)
 				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
 				Expect(metrics.GetTokenErrors()).To(Equal(currentCount + 1))
+				Expect(fakeDb.ReadRevisionCallCount()).To(Equal(0))
+				Expect(fakeDb.ReadRouterGroupsCallCount()).To(Equal(0))
 			})
 		})
 
+		Context("when the token only has router group scopes", func() {
+			BeforeEach(func() {
+				fakeDb.ReadRouterGroupsReturns(models.RouterGroups{
+					{Guid: "guid-1", Name: "group-1", Type: "tcp", ReservablePorts: "1024"},
+					{Guid: "guid-2", Name: "group-2", Type: "tcp", ReservablePorts: "1025"},
+				}, nil)
+				fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+					if len(desiredPermissions) == 1 && desiredPermissions[0] == "routing.router_groups.group-1.read" {
+						return nil
+					}
+					return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+				}
+			})
+
+			It("returns only the authorized router groups", func() {
+				var err error
+				request, err = http.NewRequest("GET", routing_api.ListRouterGroups, nil)
+				Expect(err).NotTo(HaveOccurred())
+				routerGroupHandler.ListRouterGroups(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Body.String()).To(MatchJSON(`[{
+					"guid": "guid-1",
+					"name": "group-1",
+					"type": "tcp",
+					"reservable_ports": "1024",
+					"effective_ttl": {"max_ttl": 120, "default_ttl": 60}
+				}]`))
+			})
+
+			Context("when the db fails to read the router groups", func() {
+				BeforeEach(func() {
+					fakeDb.ReadRouterGroupsReturns(nil, errors.New("db communication failed"))
+				})
+
+				It("returns a DB communication error", func() {
+					var err error
+					request, err = http.NewRequest("GET", routing_api.ListRouterGroups, nil)
+					Expect(err).NotTo(HaveOccurred())
+					routerGroupHandler.ListRouterGroups(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
 	})
 
 	Describe("UpdateRouterGroup", func() {
@@ -188,10 +276,153 @@ var _ = Describe("RouterGroupsHandler", func() {
 			"guid": "bad25cff-9332-48a6-8603-b619858e7992",
 			"name": "default-tcp",
 			"type": "tcp",
-			"reservable_ports": "8000"
+			"reservable_ports": "8000",
+			"effective_ttl": {"max_ttl": 120, "default_ttl": 60}
 			}`))
 		})
 
+		It("records the update", func() {
+			var err error
+			request, err = http.NewRequest(
+				"PUT",
+				fmt.Sprintf("/routing/v1/router_groups/%s", DefaultRouterGroupGuid),
+				body,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(responseRecorder, request)
+
+			Expect(auditor.RecordCallCount()).To(Equal(1))
+			record := auditor.RecordArgsForCall(0)
+			Expect(record.Action).To(Equal(models.AuditActionUpdateRouterGroup))
+			Expect(record.Kind).To(Equal(models.AuditKindRouterGroup))
+			Expect(record.Key).To(Equal(DefaultRouterGroupName))
+			Expect(string(record.Before)).To(ContainSubstring(`"reservable_ports":"1024-65535"`))
+			Expect(string(record.After)).To(ContainSubstring(`"reservable_ports":"8000"`))
+		})
+
+		Context("when the ttl policy is changed", func() {
+			BeforeEach(func() {
+				bodyBytes, err := json.Marshal(models.RouterGroup{MaxTTL: 100, DefaultTTL: 30})
+				Expect(err).ToNot(HaveOccurred())
+				body = bytes.NewReader(bodyBytes)
+			})
+
+			It("saves the ttl policy and responds with the effective ttl", func() {
+				request, err := http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s", DefaultRouterGroupGuid),
+					body,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(responseRecorder, request)
+
+				Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(1))
+				savedGroup := fakeDb.SaveRouterGroupArgsForCall(0)
+				Expect(savedGroup.ReservablePorts).To(Equal(models.ReservablePorts("1024-65535")))
+				Expect(savedGroup.MaxTTL).To(Equal(100))
+				Expect(savedGroup.DefaultTTL).To(Equal(30))
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Body.String()).To(MatchJSON(`{
+					"guid": "bad25cff-9332-48a6-8603-b619858e7992",
+					"name": "default-tcp",
+					"type": "tcp",
+					"reservable_ports": "1024-65535",
+					"max_ttl": 100,
+					"default_ttl": 30,
+					"effective_ttl": {"max_ttl": 100, "default_ttl": 30}
+				}`))
+			})
+		})
+
+		Context("when the router group has ttl overrides", func() {
+			BeforeEach(func() {
+				existingRouterGroup.MaxTTL = 100
+				existingRouterGroup.DefaultTTL = 30
+				fakeDb.ReadRouterGroupReturns(existingRouterGroup, nil)
+			})
+
+			JustBeforeEach(func() {
+				request, err := http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s", DefaultRouterGroupGuid),
+					body,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				handler.ServeHTTP(responseRecorder, request)
+			})
+
+			It("keeps the overrides the body leaves out", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				savedGroup := fakeDb.SaveRouterGroupArgsForCall(0)
+				Expect(savedGroup.ReservablePorts).To(Equal(models.ReservablePorts("8000")))
+				Expect(savedGroup.MaxTTL).To(Equal(100))
+				Expect(savedGroup.DefaultTTL).To(Equal(30))
+			})
+
+			Context("when the body sets a ttl to 0", func() {
+				BeforeEach(func() {
+					body = bytes.NewReader([]byte(`{"max_ttl": 0}`))
+				})
+
+				It("clears that override", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					savedGroup := fakeDb.SaveRouterGroupArgsForCall(0)
+					Expect(savedGroup.MaxTTL).To(Equal(0))
+					Expect(savedGroup.DefaultTTL).To(Equal(30))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring(`"effective_ttl":{"max_ttl":120,"default_ttl":30}`))
+				})
+			})
+		})
+
+		Context("when the max ttl is greater than the global max ttl", func() {
+			BeforeEach(func() {
+				bodyBytes, err := json.Marshal(models.RouterGroup{MaxTTL: 300})
+				Expect(err).ToNot(HaveOccurred())
+				body = bytes.NewReader(bodyBytes)
+			})
+
+			It("does not save the router group and returns a bad request response", func() {
+				request, err := http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s", DefaultRouterGroupGuid),
+					body,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(responseRecorder, request)
+
+				Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("TTLs cannot be greater than the global max_ttl of 120"))
+			})
+		})
+
+		Context("when the default ttl is greater than the max ttl", func() {
+			BeforeEach(func() {
+				bodyBytes, err := json.Marshal(models.RouterGroup{MaxTTL: 30, DefaultTTL: 300})
+				Expect(err).ToNot(HaveOccurred())
+				body = bytes.NewReader(bodyBytes)
+			})
+
+			It("does not save the router group and returns a bad request response", func() {
+				request, err := http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s", DefaultRouterGroupGuid),
+					body,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(responseRecorder, request)
+
+				Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("default_ttl cannot be greater than max_ttl"))
+			})
+		})
+
 		It("adds X-Cf-Warnings header", func() {
 			var err error
 			request, err = http.NewRequest(
@@ -206,6 +437,94 @@ var _ = Describe("RouterGroupsHandler", func() {
 			Expect(url.QueryUnescape(warning)).To(ContainSubstring("routes becoming inaccessible"))
 		})
 
+		Context("when dry_run is set", func() {
+			BeforeEach(func() {
+				fakeDb.ReadTcpRouteMappingsReturns([]models.TcpRouteMapping{
+					models.NewTcpRouteMapping(DefaultRouterGroupGuid, 8000, "1.2.3.4", 60000, 60),
+					models.NewTcpRouteMapping(DefaultRouterGroupGuid, 9000, "1.2.3.4", 60000, 60),
+					models.NewTcpRouteMapping("other-guid", 9000, "1.2.3.4", 60000, 60),
+				}, nil)
+			})
+
+			It("responds with the change and the mappings outside the new range and saves nothing", func() {
+				var err error
+				request, err = http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s?dry_run=true", DefaultRouterGroupGuid),
+					body,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
+				Expect(auditor.RecordCallCount()).To(Equal(0))
+
+				var change models.RouterGroupChange
+				Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &change)).To(Succeed())
+				Expect(change.Action).To(Equal(models.ChangeUpdate))
+				Expect(change.Before.ReservablePorts).To(Equal(models.ReservablePorts("1024-65535")))
+				Expect(change.After.ReservablePorts).To(Equal(models.ReservablePorts("8000")))
+				Expect(change.OutOfRangeTcpRoutes).To(HaveLen(1))
+				Expect(change.OutOfRangeTcpRoutes[0].ExternalPort).To(Equal(uint16(9000)))
+				Expect(change.OutOfRangeTcpRoutes[0].RouterGroupGuid).To(Equal(DefaultRouterGroupGuid))
+			})
+
+			It("reports no change when the reservable ports are unchanged", func() {
+				bodyBytes, err := json.Marshal(models.RouterGroup{ReservablePorts: "1024-65535"})
+				Expect(err).ToNot(HaveOccurred())
+				request, err = http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s?dry_run=true", DefaultRouterGroupGuid),
+					bytes.NewReader(bodyBytes),
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				var change models.RouterGroupChange
+				Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &change)).To(Succeed())
+				Expect(change.Action).To(Equal(models.ChangeNone))
+			})
+
+			It("validates the reservable ports", func() {
+				bodyBytes, err := json.Marshal(models.RouterGroup{ReservablePorts: "fadfadfasdf"})
+				Expect(err).ToNot(HaveOccurred())
+				request, err = http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s?dry_run=true", DefaultRouterGroupGuid),
+					bytes.NewReader(bodyBytes),
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			})
+
+			Context("when the db fails to read the tcp route mappings", func() {
+				BeforeEach(func() {
+					fakeDb.ReadTcpRouteMappingsReturns(nil, errors.New("stuff broke"))
+				})
+
+				It("returns an internal server error", func() {
+					var err error
+					request, err = http.NewRequest(
+						"PUT",
+						fmt.Sprintf("/routing/v1/router_groups/%s?dry_run=true", DefaultRouterGroupGuid),
+						body,
+					)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.ServeHTTP(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
 		Context("when reservable port field is invalid", func() {
 			BeforeEach(func() {
 				queryGroup := models.RouterGroup{
@@ -284,7 +603,8 @@ var _ = Describe("RouterGroupsHandler", func() {
 				"guid": "bad25cff-9332-48a6-8603-b619858e7992",
 				"name": "default-tcp",
 				"type": "tcp",
-				"reservable_ports": "1024-65535"
+				"reservable_ports": "1024-65535",
+				"effective_ttl": {"max_ttl": 120, "default_ttl": 60}
 				}`))
 			})
 		})
@@ -320,7 +640,8 @@ var _ = Describe("RouterGroupsHandler", func() {
 				"guid": "bad25cff-9332-48a6-8603-b619858e7992",
 				"name": "default-tcp",
 				"type": "tcp",
-				"reservable_ports": "1024-65535"
+				"reservable_ports": "1024-65535",
+				"effective_ttl": {"max_ttl": 120, "default_ttl": 60}
 				}`))
 			})
 		})
@@ -344,6 +665,86 @@ var _ = Describe("RouterGroupsHandler", func() {
 			Expect(permission).To(ConsistOf(handlers.RouterGroupsWriteScope))
 		})
 
+		Context("when the token only has router group scopes", func() {
+			var grantedScope string
+
+			BeforeEach(func() {
+				fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+					if len(desiredPermissions) == 1 && desiredPermissions[0] == grantedScope {
+						return nil
+					}
+					return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+				}
+			})
+
+			JustBeforeEach(func() {
+				var err error
+				request, err = http.NewRequest(
+					"PUT",
+					fmt.Sprintf("/routing/v1/router_groups/%s", DefaultRouterGroupGuid),
+					body,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				handler.ServeHTTP(responseRecorder, request)
+			})
+
+			Context("when the scope matches the router group", func() {
+				BeforeEach(func() {
+					grantedScope = "routing.router_groups.default-tcp.write"
+				})
+
+				It("saves the router group", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(1))
+				})
+
+				Context("when the request body is invalid", func() {
+					BeforeEach(func() {
+						body = bytes.NewReader([]byte("invalid json"))
+					})
+
+					It("returns a bad request response", func() {
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when the db fails to read router group", func() {
+				BeforeEach(func() {
+					grantedScope = "routing.router_groups.default-tcp.write"
+					fakeDb.ReadRouterGroupReturns(models.RouterGroup{}, errors.New("db communication failed"))
+				})
+
+				It("returns a DB communication error", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the scope is for another router group and the request body is invalid", func() {
+				BeforeEach(func() {
+					grantedScope = "routing.router_groups.other-group.write"
+					body = bytes.NewReader([]byte("invalid json"))
+				})
+
+				It("returns an Unauthorized error", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			Context("when the scope is for another router group", func() {
+				BeforeEach(func() {
+					grantedScope = "routing.router_groups.other-group.write"
+				})
+
+				It("returns an Unauthorized error naming the router group", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("RouterGroup=[default-tcp]"))
+					Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when the router group does not exist", func() {
 			BeforeEach(func() {
 				fakeDb.ReadRouterGroupReturns(models.RouterGroup{}, nil)
@@ -385,7 +786,7 @@ var _ = Describe("RouterGroupsHandler", func() {
 				)
 				Expect(err).NotTo(HaveOccurred())
 				handler.ServeHTTP(responseRecorder, request)
-				Expect(fakeDb.ReadRouterGroupCallCount()).To(Equal(0))
+				Expect(fakeDb.ReadRouterGroupCallCount()).To(Equal(1))
 				Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
 				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
 			})
@@ -482,6 +883,7 @@ var _ = Describe("RouterGroupsHandler", func() {
 				Expect(fakeDb.SaveRouterGroupCallCount()).To(Equal(0))
 				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
 				Expect(metrics.GetTokenErrors()).To(Equal(currentCount + 1))
+				Expect(fakeDb.ReadRouterGroupCallCount()).To(Equal(0))
 			})
 		})
 	})
@@ -5,109 +5,179 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/audit"
 	"code.cloudfoundry.org/routing-api/db"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/quota"
 	uaaclient "code.cloudfoundry.org/uaa-go-client"
 )
 
 type RoutesHandler struct {
 	uaaClient uaaclient.Client
-	maxTTL    int
+	ttlPolicy models.TTLPolicy
 	validator RouteValidator
 	db        db.DB
 	logger    lager.Logger
+	auditor   audit.Recorder
+	quotas    quota.Enforcer
 }
 
-func NewRoutesHandler(uaaClient uaaclient.Client, maxTTL int, validator RouteValidator, database db.DB, logger lager.Logger) *RoutesHandler {
+func NewRoutesHandler(uaaClient uaaclient.Client, ttlPolicy models.TTLPolicy, validator RouteValidator, database db.DB, logger lager.Logger, auditor audit.Recorder, quotas quota.Enforcer) *RoutesHandler {
 	return &RoutesHandler{
 		uaaClient: uaaClient,
-		maxTTL:    maxTTL,
+		ttlPolicy: ttlPolicy,
 		validator: validator,
 		db:        database,
 		logger:    logger,
+		auditor:   auditor,
+		quotas:    quotas,
 	}
 }
 
 func (h *RoutesHandler) List(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("list-routes")
+	log := h.logger.Session("list-routes", requestData(req))
 
-	err := h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
 	if err != nil {
 		handleUnauthorizedError(w, err, log)
 		return
 	}
-	routes, err := h.db.ReadRoutes()
+
+	revision, err := requestDB(h.db, req).ReadRevision(db.HTTP_ROUTES_TABLE)
 	if err != nil {
 		handleDBCommunicationError(w, err, log)
 		return
 	}
-	encoder := json.NewEncoder(w)
-	err = encoder.Encode(routes)
-	if err != nil {
-		handleProcessRequestError(w, err, log)
+	if notModified(w, req, revision) {
 		return
 	}
+
+	streamList(w, req, log, func(encode func(item interface{}) error) error {
+		return requestDB(h.db, req).StreamRoutes(func(route models.Route) error {
+			return encode(route)
+		})
+	})
 }
 
 func (h *RoutesHandler) Upsert(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("create-route")
-	decoder := json.NewDecoder(req.Body)
+	log := h.logger.Session("create-route", requestData(req))
+	database := requestDB(h.db, req)
 
 	var routes []models.Route
-	err := decoder.Decode(&routes)
+	err := decodeBatch(req, &routes)
 	if err != nil {
-		handleProcessRequestError(w, err, log)
+		handleDecodeError(w, err, log)
 		return
 	}
 
 	log.Info("request", lager.Data{"route_creation": routes})
 
-	err = h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	ifMatch, err := ifMatchTag(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	conditional, err := parseConditional(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
 	if err != nil {
 		handleUnauthorizedError(w, err, log)
 		return
 	}
 
-	// set defaults
-	for i := 0; i < len(routes); i++ {
-		routes[i].SetDefaults(h.maxTTL)
+	permanent := SetRouteDefaults(routes, h.ttlPolicy.DefaultTTL, TokenClientID(req.Header.Get("Authorization")))
+	if permanent {
+		err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesPermanentScope)
+		if err != nil {
+			handleUnauthorizedError(w, err, log)
+			return
+		}
 	}
 
-	apiErr := h.validator.ValidateCreate(routes, h.maxTTL)
+	apiErr := h.validator.ValidateCreate(routes, h.ttlPolicy.MaxTTL)
 	if apiErr != nil {
 		handleApiError(w, apiErr, log)
 		return
 	}
 
-	for _, route := range routes {
-		err = h.db.SaveRoute(route)
+	err = h.quotas.CheckRoutes(routes)
+	if err != nil {
+		if _, ok := err.(quota.ExceededError); ok {
+			handleQuotaExceededError(w, err, log)
+		} else {
+			handleDBCommunicationError(w, err, log)
+		}
+		return
+	}
+
+	if dryRun {
+		changes, err := PlanRouteUpserts(database, routes, ifMatch, conditional)
 		if err != nil {
-			if err == db.ErrorConflict {
-				handleDBConflictError(w, err, log)
-			} else {
-				handleDBCommunicationError(w, err, log)
-			}
+			handlePlanError(w, err, log)
 			return
 		}
+		writeDryRun(w, changes, log)
+		return
+	}
+
+	err = UpsertRoutes(req.Context(), database, h.auditor, newAuditContext(req), routes, ifMatch, conditional, log)
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
 func (h *RoutesHandler) Delete(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("delete-route")
-	decoder := json.NewDecoder(req.Body)
+	log := h.logger.Session("delete-route", requestData(req))
+	database := requestDB(h.db, req)
 
 	var routes []models.Route
-	err := decoder.Decode(&routes)
+	err := decodeBatch(req, &routes)
 	if err != nil {
-		handleProcessRequestError(w, err, log)
+		handleDecodeError(w, err, log)
 		return
 	}
 
 	log.Info("request", lager.Data{"route_deletion": routes})
 
-	err = h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	ifMatch, err := ifMatchTag(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	conditional, err := parseConditional(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	drain, err := drainTTL(req, h.ttlPolicy.MaxTTL)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
 	if err != nil {
 		handleUnauthorizedError(w, err, log)
 		return
@@ -119,15 +189,68 @@ func (h *RoutesHandler) Delete(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	for _, route := range routes {
-		err = h.db.DeleteRoute(route)
+	if dryRun {
+		changes, err := PlanRouteDeletes(database, routes, drain, ifMatch, conditional)
 		if err != nil {
-			if dberr, ok := err.(db.DBError); !ok || dberr.Type != db.KeyNotFound {
-				handleDBCommunicationError(w, err, log)
-				return
-			}
+			handlePlanError(w, err, log)
+			return
 		}
+		writeDryRun(w, changes, log)
+		return
+	}
+
+	err = DeleteRoutes(req.Context(), database, h.auditor, newAuditContext(req), routes, drain, ifMatch, conditional, log)
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// DeleteBySelector deletes every route matched by the selector in the query
+// parameters and responds with the deleted routes. With dry_run=true it
+// responds with the routes that would be deleted.
+func (h *RoutesHandler) DeleteBySelector(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("delete-routes-by-selector", requestData(req))
+	database := requestDB(h.db, req)
+
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	selector, err := parseRouteSelector(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	log.Info("request", lager.Data{"selector": selector, "dry_run": dryRun})
+
+	routes, err := database.DeleteRoutesBySelector(selector, dryRun)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	if !dryRun {
+		auditCtx := newAuditContext(req)
+		for _, route := range routes {
+			h.auditor.Record(auditCtx.NewRecord(models.AuditActionDelete, models.AuditKindHttpRoute, route.AuditKey(), route, nil))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(routes)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+	}
+}
@@ -3,18 +3,23 @@ package handlers_test
 import (
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/routing-api"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
 	"code.cloudfoundry.org/routing-api/db"
 	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
 	"code.cloudfoundry.org/routing-api/handlers"
 	fake_validator "code.cloudfoundry.org/routing-api/handlers/fakes"
 	"code.cloudfoundry.org/routing-api/metrics"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	"code.cloudfoundry.org/routing-api/quota"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
 	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
 
 	. "github.com/onsi/ginkgo"
@@ -30,6 +35,8 @@ var _ = Describe("RoutesHandler", func() {
 		logger           *lagertest.TestLogger
 		validator        *fake_validator.FakeRouteValidator
 		fakeClient       *fake_client.FakeClient
+		auditor          *fake_audit.FakeRecorder
+		quotas           *fake_quota.FakeEnforcer
 		defaultTTL       int
 	)
 
@@ -38,8 +45,10 @@ var _ = Describe("RoutesHandler", func() {
 		validator = &fake_validator.FakeRouteValidator{}
 		fakeClient = &fake_client.FakeClient{}
 		logger = lagertest.NewTestLogger("routing-api-test")
+		auditor = &fake_audit.FakeRecorder{}
+		quotas = &fake_quota.FakeEnforcer{}
 		defaultTTL = 50
-		routesHandler = handlers.NewRoutesHandler(fakeClient, defaultTTL, validator, database, logger)
+		routesHandler = handlers.NewRoutesHandler(fakeClient, models.TTLPolicy{MaxTTL: defaultTTL, DefaultTTL: defaultTTL}, validator, database, logger, auditor, quotas)
 		responseRecorder = httptest.NewRecorder()
 	})
 
@@ -97,6 +106,56 @@ var _ = Describe("RoutesHandler", func() {
 			})
 		})
 
+		Context("when the routes table has a revision", func() {
+			BeforeEach(func() {
+				database.ReadRevisionReturns(42, nil)
+			})
+
+			It("returns the revision as ETag", func() {
+				request = handlers.NewTestRequest("")
+				routesHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"42"`))
+				Expect(database.ReadRevisionArgsForCall(0)).To(Equal(db.HTTP_ROUTES_TABLE))
+			})
+
+			It("varies on the Accept header as well as the headers of the middleware", func() {
+				request = handlers.NewTestRequest("")
+				handlers.GzipWrap(http.HandlerFunc(routesHandler.List)).ServeHTTP(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Header()["Vary"]).To(ConsistOf("Accept-Encoding", "Accept"))
+			})
+
+			It("returns a 304 without reading the routes when If-None-Match matches", func() {
+				request = handlers.NewTestRequest("")
+				request.Header.Set("If-None-Match", `"41", "42"`)
+				routesHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotModified))
+				Expect(responseRecorder.Body.String()).To(BeEmpty())
+				Expect(database.StreamRoutesCallCount()).To(Equal(0))
+			})
+
+			It("returns the routes when If-None-Match is outdated", func() {
+				request = handlers.NewTestRequest("")
+				request.Header.Set("If-None-Match", `"41"`)
+				routesHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(database.StreamRoutesCallCount()).To(Equal(1))
+			})
+
+			It("returns a 500 when the revision cannot be read", func() {
+				database.ReadRevisionReturns(0, errors.New("stuff broke"))
+				request = handlers.NewTestRequest("")
+				routesHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
 		Context("when the database is empty", func() {
 			var (
 				routes []models.Route
@@ -105,7 +164,7 @@ var _ = Describe("RoutesHandler", func() {
 			BeforeEach(func() {
 				routes = []models.Route{}
 
-				database.ReadRoutesReturns(routes, nil)
+				database.StreamRoutesStub = streamRoutes(routes, nil)
 			})
 
 			It("returns an empty set", func() {
@@ -126,7 +185,7 @@ var _ = Describe("RoutesHandler", func() {
 				route := models.NewRoute("post_here", 7000, "1.2.3.4", "log", "rsurl", 60)
 				routes = []models.Route{route}
 
-				database.ReadRoutesReturns(routes, nil)
+				database.StreamRoutesStub = streamRoutes(routes, nil)
 			})
 
 			It("returns a single route", func() {
@@ -161,7 +220,7 @@ var _ = Describe("RoutesHandler", func() {
 				route2 := models.NewRoute("post_there", 2000, "1.2.3.5", "Something", "", 23)
 				routes = []models.Route{route1, route2}
 
-				database.ReadRoutesReturns(routes, nil)
+				database.StreamRoutesStub = streamRoutes(routes, nil)
 			})
 
 			It("returns a single route", func() {
@@ -196,11 +255,28 @@ var _ = Describe("RoutesHandler", func() {
 							}
 						]`))
 			})
+
+			Context("when the client accepts protobuf", func() {
+				It("returns the routes encoded as protobuf", func() {
+					request = handlers.NewTestRequest("")
+					request.Header.Set("Accept", "application/x-protobuf, application/json;q=0.5")
+
+					routesHandler.List(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(responseRecorder.Header().Get("Content-Type")).To(Equal(protos.ContentType))
+
+					var decoded []models.Route
+					err := protos.Unmarshal(responseRecorder.Body.Bytes(), &decoded)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decoded).To(Equal(routes))
+				})
+			})
 		})
 
 		Context("when the database errors out", func() {
 			BeforeEach(func() {
-				database.ReadRoutesReturns(nil, errors.New("some bad thing happened"))
+				database.StreamRoutesStub = streamRoutes(nil, errors.New("some bad thing happened"))
 			})
 
 			It("returns a 500 Internal Server Error", func() {
@@ -210,6 +286,37 @@ var _ = Describe("RoutesHandler", func() {
 
 				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
 			})
+
+			Context("when routes have already been written", func() {
+				var server *httptest.Server
+
+				BeforeEach(func() {
+					database.StreamRoutesStub = streamRoutes([]models.Route{
+						models.NewRoute("post_here", 7000, "1.2.3.4", "", "", 0),
+					}, errors.New("some bad thing happened"))
+					server = httptest.NewServer(http.HandlerFunc(routesHandler.List))
+				})
+
+				AfterEach(func() {
+					server.Close()
+				})
+
+				It("aborts the response so that the client does not take the routes for the complete list", func() {
+					for _, accept := range []string{"application/json", protos.ContentType} {
+						request, err := http.NewRequest("GET", server.URL, nil)
+						Expect(err).NotTo(HaveOccurred())
+						request.Header.Set("Authorization", "bearer token")
+						request.Header.Set("Accept", accept)
+
+						response, err := http.DefaultClient.Do(request)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+						_, err = ioutil.ReadAll(response.Body)
+						Expect(err).To(HaveOccurred())
+						response.Body.Close()
+					}
+				})
+			})
 		})
 	})
 
@@ -256,6 +363,62 @@ var _ = Describe("RoutesHandler", func() {
 				Expect(database.DeleteRouteArgsForCall(1)).To(Equal(routes[1]))
 			})
 
+			Context("when auditing is enabled", func() {
+				var existing models.Route
+
+				BeforeEach(func() {
+					auditor.EnabledReturns(true)
+					existing = routes[0]
+					existing.LogGuid = "existing-log-guid"
+					database.ReadRouteReturns(existing, nil)
+				})
+
+				It("records the deletion with the stored route as before value", func() {
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("Authorization", testToken(`{"client_id":"some-client"}`))
+					request.Header.Set("X-Vcap-Request-Id", "some-request-id")
+					request.RemoteAddr = "10.0.0.1:1234"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+					Expect(database.ReadRouteCallCount()).To(Equal(1))
+					Expect(auditor.RecordCallCount()).To(Equal(1))
+
+					record := auditor.RecordArgsForCall(0)
+					Expect(record.Action).To(Equal(models.AuditActionDelete))
+					Expect(record.Kind).To(Equal(models.AuditKindHttpRoute))
+					Expect(record.Key).To(Equal("post_here"))
+					Expect(record.Actor).To(Equal("some-client"))
+					Expect(record.SourceIP).To(Equal("10.0.0.1"))
+					Expect(record.RequestID).To(Equal("some-request-id"))
+					Expect(string(record.Before)).To(ContainSubstring(`"log_guid":"existing-log-guid"`))
+					Expect(record.After).To(BeEmpty())
+				})
+
+				Context("when the route does not exist", func() {
+					BeforeEach(func() {
+						database.DeleteRouteReturns(db.DBError{Type: db.KeyNotFound, Message: "not found"})
+					})
+
+					It("does not record the deletion", func() {
+						request = handlers.NewTestRequest(routes)
+						routesHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+						Expect(auditor.RecordCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when auditing is disabled", func() {
+				It("does not read the stored route", func() {
+					request = handlers.NewTestRequest(routes)
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(database.ReadRouteCallCount()).To(Equal(0))
+				})
+			})
+
 			It("logs the routes deletion", func() {
 				request = handlers.NewTestRequest(routes)
 				routesHandler.Delete(responseRecorder, request)
@@ -296,6 +459,212 @@ var _ = Describe("RoutesHandler", func() {
 					Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
 					Expect(responseRecorder.Body.String()).To(ContainSubstring("stuff broke"))
 				})
+
+				It("includes the request id echoed by the response in the error", func() {
+					database.DeleteRouteReturns(errors.New("stuff broke"))
+
+					request = handlers.NewTestRequest(routes)
+					responseRecorder.Header().Set("X-Vcap-Request-Id", "some-request-id")
+					routesHandler.Delete(responseRecorder, request)
+
+					var apiErr routing_api.Error
+					Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &apiErr)).To(Succeed())
+					Expect(apiErr.Type).To(Equal(routing_api.DBCommunicationError))
+					Expect(apiErr.RequestID).To(Equal("some-request-id"))
+				})
+
+				It("logs the request id of the request", func() {
+					database.DeleteRouteReturns(errors.New("stuff broke"))
+
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("X-Vcap-Request-Id", "some-request-id")
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(logger.Logs()).NotTo(BeEmpty())
+					for _, log := range logger.Logs() {
+						Expect(log.Data["request-id"]).To(Equal("some-request-id"))
+					}
+				})
+			})
+
+			Context("when the If-Match header is set", func() {
+				It("deletes the routes conditionally", func() {
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("If-Match", `"some-guid:5"`)
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+					Expect(database.DeleteRouteCallCount()).To(Equal(0))
+					Expect(database.DeleteRouteIfMatchCallCount()).To(Equal(1))
+					deletedRoute, expected := database.DeleteRouteIfMatchArgsForCall(0)
+					Expect(deletedRoute).To(Equal(routes[0]))
+					Expect(expected).To(Equal(models.ModificationTag{Guid: "some-guid", Index: 5}))
+				})
+
+				Context("when the modification tag does not match", func() {
+					BeforeEach(func() {
+						database.DeleteRouteIfMatchReturns(db.ModificationTagMismatchError{})
+					})
+
+					It("responds with a 412", func() {
+						request = handlers.NewTestRequest(routes)
+						request.Header.Set("If-Match", `"some-guid:5"`)
+						routesHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+						Expect(responseRecorder.Header().Get("ETag")).To(BeEmpty())
+						Expect(responseRecorder.Body.String()).To(ContainSubstring("route does not exist"))
+					})
+				})
+			})
+
+			Context("when a drain duration is given", func() {
+				It("drains the routes instead of deleting them", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=30"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+					Expect(database.DeleteRouteCallCount()).To(Equal(0))
+					Expect(database.DrainRouteCallCount()).To(Equal(1))
+					drainedRoute, drain := database.DrainRouteArgsForCall(0)
+					Expect(drainedRoute).To(Equal(routes[0]))
+					Expect(drain).To(Equal(30))
+				})
+
+				It("records the drain", func() {
+					auditor.EnabledReturns(true)
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=30"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(auditor.RecordCallCount()).To(Equal(1))
+					Expect(auditor.RecordArgsForCall(0).Action).To(Equal(models.AuditActionDrain))
+				})
+
+				It("drains conditionally when the If-Match header is set", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=30"
+					request.Header.Set("If-Match", `"some-guid:5"`)
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+					Expect(database.DrainRouteIfMatchCallCount()).To(Equal(1))
+					_, drain, expected := database.DrainRouteIfMatchArgsForCall(0)
+					Expect(drain).To(Equal(30))
+					Expect(expected).To(Equal(models.ModificationTag{Guid: "some-guid", Index: 5}))
+				})
+
+				It("returns a 204 if the route does not exist", func() {
+					database.DrainRouteReturns(db.DBError{Type: db.KeyNotFound, Message: "The specified route could not be found."})
+
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=30"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+				})
+
+				It("returns a conflict if the route changed concurrently", func() {
+					database.DrainRouteReturns(db.ErrorConflict)
+
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=30"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+				})
+
+				It("returns a bad request if the drain duration is invalid", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=-1"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("invalid drain"))
+					Expect(database.DrainRouteCallCount()).To(Equal(0))
+				})
+
+				It("returns a bad request if the drain duration exceeds the max ttl", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=51"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(database.DrainRouteCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when dry_run is set", func() {
+				var existing models.Route
+
+				BeforeEach(func() {
+					existing = routes[0]
+					existing.ModificationTag = models.ModificationTag{Guid: "some-guid", Index: 5}
+					database.ReadRouteReturns(existing, nil)
+				})
+
+				It("responds with the routes that would be deleted and deletes nothing", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(database.DeleteRouteCallCount()).To(Equal(0))
+					Expect(auditor.RecordCallCount()).To(Equal(0))
+
+					var changes []models.RouteChange
+					Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+					Expect(changes).To(HaveLen(1))
+					Expect(changes[0].Action).To(Equal(models.ChangeDelete))
+					Expect(*changes[0].Before).To(Equal(existing))
+					Expect(changes[0].After).To(BeNil())
+				})
+
+				It("reports routes that do not exist as unchanged", func() {
+					database.ReadRouteReturns(models.Route{}, nil)
+
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(responseRecorder.Body.String()).To(MatchJSON(`[{"action": "none"}]`))
+				})
+
+				It("reports a drain when a drain duration is given", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "drain=30&dry_run=true"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(database.DrainRouteCallCount()).To(Equal(0))
+
+					var changes []models.RouteChange
+					Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+					Expect(changes[0].Action).To(Equal(models.ChangeDrain))
+					Expect(changes[0].After.Draining).To(BeTrue())
+					Expect(*changes[0].After.TTL).To(Equal(30))
+				})
+
+				It("responds with a 412 when the If-Match header does not match", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					request.Header.Set("If-Match", `"some-guid:4"`)
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+					Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"some-guid:5"`))
+				})
+
+				It("returns a bad request when dry_run is not a boolean", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=maybe"
+					routesHandler.Delete(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("invalid dry_run"))
+				})
 			})
 		})
 
@@ -329,6 +698,110 @@ var _ = Describe("RoutesHandler", func() {
 		})
 	})
 
+	Describe(".DeleteBySelector", func() {
+		var deletedRoute models.Route
+
+		BeforeEach(func() {
+			deletedRoute = models.NewRoute("post_here", 7000, "1.2.3.4", "my-app", "", 50)
+			database.DeleteRoutesBySelectorReturns([]models.Route{deletedRoute}, nil)
+		})
+
+		It("checks for routing.routes.write scope", func() {
+			request = handlers.NewTestRequest("")
+			request.URL.RawQuery = "log_guid=my-app"
+			routesHandler.DeleteBySelector(responseRecorder, request)
+
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.RoutingRoutesWriteScope))
+		})
+
+		It("deletes the selected routes and responds with them", func() {
+			request = handlers.NewTestRequest("")
+			request.URL.RawQuery = "log_guid=my-app&ip=1.2.3.4&owner=cf"
+			routesHandler.DeleteBySelector(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.DeleteRoutesBySelectorCallCount()).To(Equal(1))
+			selector, dryRun := database.DeleteRoutesBySelectorArgsForCall(0)
+			Expect(selector).To(Equal(models.RouteSelector{LogGuid: "my-app", IP: "1.2.3.4", Owner: "cf"}))
+			Expect(dryRun).To(BeFalse())
+
+			var routes []models.Route
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &routes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routes).To(Equal([]models.Route{deletedRoute}))
+		})
+
+		It("records a delete for each route", func() {
+			request = handlers.NewTestRequest("")
+			request.URL.RawQuery = "log_guid=my-app"
+			routesHandler.DeleteBySelector(responseRecorder, request)
+
+			Expect(auditor.RecordCallCount()).To(Equal(1))
+			record := auditor.RecordArgsForCall(0)
+			Expect(record.Action).To(Equal(models.AuditActionDelete))
+			Expect(record.Key).To(Equal("post_here"))
+		})
+
+		Context("when dry_run is set", func() {
+			It("passes dry run to the database and records nothing", func() {
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "log_guid=my-app&dry_run=true"
+				routesHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				_, dryRun := database.DeleteRoutesBySelectorArgsForCall(0)
+				Expect(dryRun).To(BeTrue())
+				Expect(auditor.RecordCallCount()).To(Equal(0))
+			})
+
+			It("returns a bad request when dry_run is not a boolean", func() {
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "log_guid=my-app&dry_run=maybe"
+				routesHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(database.DeleteRoutesBySelectorCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when no selector is given", func() {
+			It("returns a bad request and deletes nothing", func() {
+				request = handlers.NewTestRequest("")
+				routesHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("selector requires at least one of"))
+				Expect(database.DeleteRoutesBySelectorCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the database deletion fails", func() {
+			It("returns an internal server error", func() {
+				database.DeleteRoutesBySelectorReturns(nil, errors.New("stuff broke"))
+
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "log_guid=my-app"
+				routesHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when the UAA token is not valid", func() {
+			It("returns an Unauthorized status code", func() {
+				fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "log_guid=my-app"
+				routesHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(database.DeleteRoutesBySelectorCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe(".Upsert", func() {
 		Context("POST", func() {
 			var (
@@ -350,6 +823,72 @@ var _ = Describe("RoutesHandler", func() {
 				Expect(permission).To(ConsistOf(handlers.RoutingRoutesWriteScope))
 			})
 
+			Context("when the request has limits", func() {
+				var limits handlers.RequestLimits
+
+				upsert := func(body string) {
+					request = handlers.NewTestRequest(body)
+					handlers.LimitWrap(http.HandlerFunc(routesHandler.Upsert), limits, logger)(responseRecorder, request)
+				}
+
+				BeforeEach(func() {
+					limits = handlers.RequestLimits{}
+				})
+
+				It("rejects a batch with more routes than the maximum", func() {
+					limits.MaxBatchLength = 1
+					upsert(`[{"route":"a.example.com","port":80,"ip":"1.2.3.4"},{"route":"b.example.com","port":80,"ip":"1.2.3.4"}]`)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("PayloadTooLargeError"))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("Request has 2 items, more than the maximum of 1"))
+					Expect(database.SaveRouteCallCount()).To(Equal(0))
+				})
+
+				It("rejects a body with a Content-Length over the maximum", func() {
+					limits.MaxBodySize = 10
+					upsert(`[{"route":"a.example.com","port":80,"ip":"1.2.3.4"}]`)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+					Expect(fakeClient.DecodeTokenCallCount()).To(Equal(0))
+				})
+
+				It("rejects a body without a Content-Length once it exceeds the maximum", func() {
+					limits.MaxBodySize = 10
+					request = handlers.NewTestRequest(`[{"route":"a.example.com","port":80,"ip":"1.2.3.4"}]`)
+					request.ContentLength = -1
+					handlers.LimitWrap(http.HandlerFunc(routesHandler.Upsert), limits, logger)(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("larger than the maximum of 10 bytes"))
+				})
+
+				It("accepts unknown fields", func() {
+					upsert(`[{"route":"a.example.com","port":80,"ip":"1.2.3.4","tll":30}]`)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+				})
+
+				Context("when decoding is strict", func() {
+					BeforeEach(func() {
+						limits.Strict = true
+					})
+
+					It("reports an unknown field with the index of its route", func() {
+						upsert(`[{"route":"a.example.com","port":80,"ip":"1.2.3.4"},{"route":"b.example.com","port":80,"ip":"1.2.3.4","tll":30}]`)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						var apiErr routing_api.Error
+						Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &apiErr)).To(Succeed())
+						Expect(apiErr.Type).To(Equal(routing_api.ProcessRequestError))
+						Expect(apiErr.Details).To(Equal([]routing_api.ErrorDetail{
+							{Index: 1, Field: "tll", Code: routing_api.UnknownFieldCode, Message: `Unknown field "tll"`},
+						}))
+						Expect(database.SaveRouteCallCount()).To(Equal(0))
+					})
+				})
+			})
+
 			Context("when TTL is not set", func() {
 				BeforeEach(func() {
 					route.TTL = nil
@@ -362,6 +901,59 @@ var _ = Describe("RoutesHandler", func() {
 					Expect(database.SaveRouteCallCount()).To(Equal(1))
 					Expect(*database.SaveRouteArgsForCall(0).TTL).To(Equal(defaultTTL))
 				})
+
+				Context("when the ttl policy has a lower default ttl", func() {
+					BeforeEach(func() {
+						routesHandler = handlers.NewRoutesHandler(fakeClient, models.TTLPolicy{MaxTTL: defaultTTL, DefaultTTL: 20}, validator, database, logger, auditor, quotas)
+					})
+
+					It("sets the default TTL of the policy and validates against the max TTL", func() {
+						request = handlers.NewTestRequest([]models.Route{route})
+						routesHandler.Upsert(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+						Expect(*database.SaveRouteArgsForCall(0).TTL).To(Equal(20))
+
+						_, maxTTL := validator.ValidateCreateArgsForCall(0)
+						Expect(maxTTL).To(Equal(defaultTTL))
+					})
+				})
+			})
+
+			Context("when the route is permanent", func() {
+				BeforeEach(func() {
+					*route.TTL = models.PermanentTTL
+				})
+
+				It("checks for routing.routes.permanent scope and saves the route as permanent", func() {
+					request = handlers.NewTestRequest([]models.Route{route})
+					routesHandler.Upsert(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+
+					Expect(fakeClient.DecodeTokenCallCount()).To(Equal(2))
+					_, permission := fakeClient.DecodeTokenArgsForCall(1)
+					Expect(permission).To(ConsistOf(handlers.RoutingRoutesPermanentScope))
+
+					Expect(database.SaveRouteCallCount()).To(Equal(1))
+					Expect(database.SaveRouteArgsForCall(0).Permanent).To(BeTrue())
+				})
+
+				Context("when the token does not have routing.routes.permanent scope", func() {
+					BeforeEach(func() {
+						fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+							if desiredPermissions[0] == handlers.RoutingRoutesPermanentScope {
+								return errors.New("Token does not have 'routing.routes.permanent' scope")
+							}
+							return nil
+						}
+					})
+
+					It("returns an Unauthorized error and saves nothing", func() {
+						request = handlers.NewTestRequest([]models.Route{route})
+						routesHandler.Upsert(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+						Expect(database.SaveRouteCallCount()).To(Equal(0))
+					})
+				})
 			})
 
 			Context("when all inputs are present and correct", func() {
@@ -372,6 +964,30 @@ var _ = Describe("RoutesHandler", func() {
 					Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
 				})
 
+				It("records the upsert", func() {
+					auditor.EnabledReturns(true)
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("Authorization", testToken(`{"user_name":"admin","client_id":"cf"}`))
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(auditor.RecordCallCount()).To(Equal(1))
+					record := auditor.RecordArgsForCall(0)
+					Expect(record.Action).To(Equal(models.AuditActionUpsert))
+					Expect(record.Key).To(Equal("post_here"))
+					Expect(record.Actor).To(Equal("admin"))
+					Expect(record.Before).To(BeEmpty())
+					Expect(string(record.After)).To(ContainSubstring(`"route":"post_here"`))
+				})
+
+				It("records the client id of the token as the owner", func() {
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("Authorization", testToken(`{"user_name":"admin","client_id":"cf"}`))
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(database.SaveRouteCallCount()).To(Equal(1))
+					Expect(database.SaveRouteArgsForCall(0).Owner).To(Equal("cf"))
+				})
+
 				It("accepts a list of routes in the body", func() {
 					route.IP = "5.4.3.2"
 					routes = append(routes, route)
@@ -451,6 +1067,205 @@ var _ = Describe("RoutesHandler", func() {
 						Expect(responseRecorder.Body.String()).To(ContainSubstring("DBConflictError"))
 					})
 				})
+
+				Context("when the If-Match header is set", func() {
+					It("saves the routes conditionally", func() {
+						request = handlers.NewTestRequest(routes)
+						request.Header.Set("If-Match", `"some-guid:5"`)
+						routesHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+						Expect(database.SaveRouteCallCount()).To(Equal(0))
+						Expect(database.SaveRouteIfMatchCallCount()).To(Equal(1))
+						savedRoute, expected := database.SaveRouteIfMatchArgsForCall(0)
+						Expect(savedRoute).To(Equal(routes[0]))
+						Expect(expected).To(Equal(models.ModificationTag{Guid: "some-guid", Index: 5}))
+					})
+
+					It("returns a bad request when the header cannot be parsed", func() {
+						request = handlers.NewTestRequest(routes)
+						request.Header.Set("If-Match", "some-guid")
+						routesHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(database.SaveRouteIfMatchCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when a route carries a modification tag", func() {
+					BeforeEach(func() {
+						routes[0].ModificationTag = models.ModificationTag{Guid: "some-guid", Index: 5}
+					})
+
+					It("saves the route unconditionally, as listed routes carry their tag", func() {
+						request = handlers.NewTestRequest(routes)
+						routesHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+						Expect(database.SaveRouteIfMatchCallCount()).To(Equal(0))
+						Expect(database.SaveRouteCallCount()).To(Equal(1))
+					})
+
+					It("saves the route conditionally when conditional is set", func() {
+						request = handlers.NewTestRequest(routes)
+						request.URL.RawQuery = "conditional=true"
+						routesHandler.Upsert(responseRecorder, request)
+
+						Expect(database.SaveRouteIfMatchCallCount()).To(Equal(1))
+						_, expected := database.SaveRouteIfMatchArgsForCall(0)
+						Expect(expected).To(Equal(routes[0].ModificationTag))
+					})
+
+					Context("when the modification tag does not match", func() {
+						BeforeEach(func() {
+							database.SaveRouteIfMatchReturns(db.ModificationTagMismatchError{
+								Current: models.ModificationTag{Guid: "some-guid", Index: 7},
+							})
+						})
+
+						It("responds with a 412 and the current tag", func() {
+							request = handlers.NewTestRequest(routes)
+							request.URL.RawQuery = "conditional=true"
+							routesHandler.Upsert(responseRecorder, request)
+
+							Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+							Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"some-guid:7"`))
+							Expect(responseRecorder.Body.String()).To(MatchJSON(`{
+								"name": "PreconditionFailedError",
+								"message": "Modification tag mismatch: current tag is \"some-guid:7\"",
+								"modification_tag": {"guid": "some-guid", "index": 7}
+							}`))
+						})
+
+						It("does not record the upsert", func() {
+							auditor.EnabledReturns(true)
+							request = handlers.NewTestRequest(routes)
+							request.URL.RawQuery = "conditional=true"
+							routesHandler.Upsert(responseRecorder, request)
+
+							Expect(auditor.RecordCallCount()).To(Equal(0))
+						})
+					})
+				})
+			})
+
+			Context("when dry_run is set", func() {
+				It("responds with the routes that would be created and saves nothing", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(database.SaveRouteCallCount()).To(Equal(0))
+					Expect(auditor.RecordCallCount()).To(Equal(0))
+
+					var changes []models.RouteChange
+					Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+					Expect(changes).To(HaveLen(1))
+					Expect(changes[0].Action).To(Equal(models.ChangeCreate))
+					Expect(changes[0].Before).To(BeNil())
+					Expect(changes[0].After.Route).To(Equal("post_here"))
+				})
+
+				It("reports an update when the route exists", func() {
+					existing := route
+					existing.ModificationTag = models.ModificationTag{Guid: "some-guid", Index: 5}
+					database.ReadRouteReturns(existing, nil)
+
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					routesHandler.Upsert(responseRecorder, request)
+
+					var changes []models.RouteChange
+					Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+					Expect(changes[0].Action).To(Equal(models.ChangeUpdate))
+					Expect(changes[0].Before.ModificationTag).To(Equal(existing.ModificationTag))
+					Expect(changes[0].After.ModificationTag).To(Equal(models.ModificationTag{Guid: "some-guid", Index: 6}))
+				})
+
+				It("validates the routes", func() {
+					validator.ValidateCreateReturns(&routing_api.Error{Type: "a type", Message: "error message"})
+
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				})
+
+				It("checks the quotas", func() {
+					quotas.CheckRoutesReturns(quota.ExceededError{Quota: models.QuotaHttpRoutesPerOwner, Key: "cf", Limit: 10})
+
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusForbidden))
+				})
+
+				It("responds with a 412 when a conditional write would fail", func() {
+					request = handlers.NewTestRequest(routes)
+					request.URL.RawQuery = "dry_run=true"
+					request.Header.Set("If-Match", `"some-guid:5"`)
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("route does not exist"))
+				})
+
+				Context("when reading the route fails", func() {
+					BeforeEach(func() {
+						database.ReadRouteReturns(models.Route{}, errors.New("stuff broke"))
+					})
+
+					It("responds with a server error", func() {
+						request = handlers.NewTestRequest(routes)
+						request.URL.RawQuery = "dry_run=true"
+						routesHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the routes exceed a quota", func() {
+				BeforeEach(func() {
+					quotas.CheckRoutesReturns(quota.ExceededError{Quota: models.QuotaHttpRoutesPerOwner, Key: "cf", Limit: 10})
+				})
+
+				It("responds with a 403 quota exceeded error", func() {
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("Authorization", testToken(`{"client_id":"cf"}`))
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusForbidden))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("QuotaExceededError"))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("Quota http_routes_per_owner exceeded for cf: limit is 10"))
+					Expect(database.SaveRouteCallCount()).To(Equal(0))
+				})
+
+				It("checks the routes with their owner", func() {
+					request = handlers.NewTestRequest(routes)
+					request.Header.Set("Authorization", testToken(`{"client_id":"cf"}`))
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(quotas.CheckRoutesCallCount()).To(Equal(1))
+					Expect(quotas.CheckRoutesArgsForCall(0)[0].Owner).To(Equal("cf"))
+				})
+			})
+
+			Context("when checking quotas fails", func() {
+				BeforeEach(func() {
+					quotas.CheckRoutesReturns(errors.New("stuff broke"))
+				})
+
+				It("responds with a server error", func() {
+					request = handlers.NewTestRequest(routes)
+					routesHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+					Expect(database.SaveRouteCallCount()).To(Equal(0))
+				})
 			})
 
 			Context("when there are errors with the input", func() {
@@ -503,3 +1318,16 @@ var _ = Describe("RoutesHandler", func() {
 		})
 	})
 })
+
+// streamRoutes returns a stub for StreamRoutes passing the routes to the
+// callback before failing with err.
+func streamRoutes(routes []models.Route, err error) func(func(models.Route) error) error {
+	return func(fn func(models.Route) error) error {
+		for _, route := range routes {
+			if fnErr := fn(route); fnErr != nil {
+				return fnErr
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/quota"
+	"github.com/tedsuo/rata"
+)
+
+var errRouteNotFound = errors.New("The specified route could not be found.")
+
+// ListV2 responds with the routes including their guids and timestamps.
+func (h *RoutesHandler) ListV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("list-routes-v2", requestData(req))
+
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	routes, err := requestDB(h.db, req).ReadRoutes()
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	result := make([]models.RouteV2, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, models.NewRouteV2(route))
+	}
+	writeV2(w, http.StatusOK, result, log)
+}
+
+// CreateV2 registers a route that does not exist yet and responds with it.
+// Registering an existing route is a conflict; it is updated through its guid.
+func (h *RoutesHandler) CreateV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("create-route-v2", requestData(req))
+	database := requestDB(h.db, req)
+
+	var body models.RouteV2
+	err := decodeBody(req, &body)
+	if err != nil {
+		handleDecodeError(w, err, log)
+		return
+	}
+
+	log.Info("request", lager.Data{"route_creation": body})
+
+	err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	route := models.Route{RouteEntity: body.RouteEntity}
+	if !h.authorizeRouteWrite(w, req, &route, log) {
+		return
+	}
+
+	existing, err := database.ReadRoute(route)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+	if existing != (models.Route{}) {
+		handleDBConflictError(w, errors.New("The route already exists with guid "+existing.Guid), log)
+		return
+	}
+
+	err = h.quotas.CheckRoutes([]models.Route{route})
+	if err != nil {
+		if _, ok := err.(quota.ExceededError); ok {
+			handleQuotaExceededError(w, err, log)
+		} else {
+			handleDBCommunicationError(w, err, log)
+		}
+		return
+	}
+
+	err = database.CreateRoute(route)
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
+	}
+	h.auditor.Record(newAuditContext(req).NewRecord(models.AuditActionUpsert, models.AuditKindHttpRoute, route.AuditKey(), nil, route))
+
+	created, err := database.ReadRoute(route)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	// etcd does not store guids, so the created route cannot be located
+	if created.Guid != "" {
+		path, err := routing_api.RoutesMap[routing_api.GetRouteV2].CreatePath(rata.Params{"guid": created.Guid})
+		if err == nil {
+			w.Header().Set("Location", path)
+		}
+	}
+	writeV2(w, http.StatusCreated, models.NewRouteV2(created), log)
+}
+
+// GetV2 responds with the route with the guid in the path.
+func (h *RoutesHandler) GetV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("get-route-v2", requestData(req))
+
+	err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	route, ok := h.readRouteByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	writeV2(w, http.StatusOK, models.NewRouteV2(route), log)
+}
+
+// UpdateV2 updates the ttl and log guid of the route with the guid in the
+// path. A body without a ttl keeps the ttl of the route. The write is
+// conditional on the If-Match header or, without it, on the modification tag
+// of the body if one is given.
+func (h *RoutesHandler) UpdateV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("update-route-v2", requestData(req))
+	database := requestDB(h.db, req)
+
+	var body models.RouteV2
+	err := decodeBody(req, &body)
+	if err != nil {
+		handleDecodeError(w, err, log)
+		return
+	}
+
+	log.Info("request", lager.Data{"route_update": body})
+
+	ifMatch, err := ifMatchTag(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	existing, ok := h.readRouteByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	if !existing.SameRoute(body.RouteEntity) {
+		apiErr := routing_api.NewError(routing_api.RouteInvalidError, "The route, port, ip and route_service_url of a route cannot be changed")
+		handleApiError(w, &apiErr, log)
+		return
+	}
+
+	route := existing
+	if body.TTL != nil {
+		route.TTL = body.TTL
+	}
+	route.LogGuid = body.LogGuid
+	if !h.authorizeRouteWrite(w, req, &route, log) {
+		return
+	}
+
+	if expected := ExpectedTag(ifMatch, body.ModificationTag, true); expected != nil {
+		err = database.SaveRouteIfMatch(route, *expected)
+	} else {
+		err = database.SaveRoute(route)
+	}
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
+	}
+	h.auditor.Record(newAuditContext(req).NewRecord(models.AuditActionUpsert, models.AuditKindHttpRoute, route.AuditKey(), existing, route))
+
+	updated, ok := h.readRouteByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	writeV2(w, http.StatusOK, models.NewRouteV2(updated), log)
+}
+
+// DeleteV2 deletes the route with the guid in the path. The delete is
+// conditional on the If-Match header if one is given.
+func (h *RoutesHandler) DeleteV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("delete-route-v2", requestData(req))
+	database := requestDB(h.db, req)
+
+	ifMatch, err := ifMatchTag(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	route, ok := h.readRouteByGuid(w, req, log)
+	if !ok {
+		return
+	}
+
+	if ifMatch != nil {
+		err = database.DeleteRouteIfMatch(route, *ifMatch)
+	} else {
+		err = database.DeleteRoute(route)
+	}
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
+	}
+	h.auditor.Record(newAuditContext(req).NewRecord(models.AuditActionDelete, models.AuditKindHttpRoute, route.AuditKey(), route, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeRouteWrite sets the defaults and owner of the route, checks the
+// permanent scope for permanent routes and validates it. The caller checks
+// the write scope. It responds with the error and returns false if the route
+// cannot be written.
+func (h *RoutesHandler) authorizeRouteWrite(w http.ResponseWriter, req *http.Request, route *models.Route, log lager.Logger) bool {
+	route.SetDefaults(h.ttlPolicy.DefaultTTL)
+	route.Owner = TokenClientID(req.Header.Get("Authorization"))
+	if route.Permanent {
+		err := requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesPermanentScope)
+		if err != nil {
+			handleUnauthorizedError(w, err, log)
+			return false
+		}
+	}
+
+	apiErr := h.validator.ValidateCreate([]models.Route{*route}, h.ttlPolicy.MaxTTL)
+	if apiErr != nil {
+		handleApiError(w, apiErr, log)
+		return false
+	}
+	return true
+}
+
+// readRouteByGuid reads the route with the guid in the path. It responds with
+// a 404 and returns false if the route does not exist, and with a 501 if the
+// database does not store guids.
+func (h *RoutesHandler) readRouteByGuid(w http.ResponseWriter, req *http.Request, log lager.Logger) (models.Route, bool) {
+	route, err := requestDB(h.db, req).ReadRouteByGuid(rata.Param(req, "guid"))
+	if err == db.ErrGuidsNotSupported {
+		handleNotImplementedError(w, err, log)
+		return models.Route{}, false
+	}
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return models.Route{}, false
+	}
+	if route == (models.Route{}) {
+		handleNotFoundError(w, errRouteNotFound, log)
+		return models.Route{}, false
+	}
+	return route, true
+}
+
+// handleWriteError responds to a failed write of a single route or tcp route
+// mapping.
+func handleWriteError(w http.ResponseWriter, err error, log lager.Logger) {
+	if mismatch, ok := err.(db.ModificationTagMismatchError); ok {
+		handlePreconditionFailedError(w, mismatch, log)
+	} else if err == db.ErrorConflict {
+		handleDBConflictError(w, err, log)
+	} else if dberr, ok := err.(db.DBError); ok && dberr.Type == db.KeyNotFound {
+		handleNotFoundError(w, err, log)
+	} else {
+		handleDBCommunicationError(w, err, log)
+	}
+}
+
+// writeV2 writes a v2 response body as JSON with the status code.
+func writeV2(w http.ResponseWriter, status int, body interface{}, log lager.Logger) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(append(data, '\n'))
+	if err != nil {
+		log.Error("failed-to-write-to-response", err)
+	}
+}
@@ -0,0 +1,346 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	routing_api "code.cloudfoundry.org/routing-api"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	"code.cloudfoundry.org/routing-api/db"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/handlers"
+	fake_validator "code.cloudfoundry.org/routing-api/handlers/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoutesHandler v2", func() {
+	var (
+		routesHandler    *handlers.RoutesHandler
+		handler          http.Handler
+		responseRecorder *httptest.ResponseRecorder
+		database         *fake_db.FakeDB
+		validator        *fake_validator.FakeRouteValidator
+		fakeClient       *fake_client.FakeClient
+		auditor          *fake_audit.FakeRecorder
+		quotas           *fake_quota.FakeEnforcer
+		storedRoute      models.Route
+	)
+
+	serve := func(method, path, body string) {
+		request, err := http.NewRequest(method, path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		handler.ServeHTTP(responseRecorder, request)
+	}
+
+	decodeRoute := func() models.RouteV2 {
+		var route models.RouteV2
+		Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &route)).To(Succeed())
+		return route
+	}
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		validator = &fake_validator.FakeRouteValidator{}
+		fakeClient = &fake_client.FakeClient{}
+		auditor = &fake_audit.FakeRecorder{}
+		quotas = &fake_quota.FakeEnforcer{}
+		routesHandler = handlers.NewRoutesHandler(fakeClient, models.TTLPolicy{MaxTTL: 120, DefaultTTL: 50}, validator, database, lagertest.NewTestLogger("routing-api-test"), auditor, quotas)
+		responseRecorder = httptest.NewRecorder()
+
+		var err error
+		handler, err = rata.NewRouter(rata.Routes{
+			routing_api.RoutesMap[routing_api.ListRoutesV2],
+			routing_api.RoutesMap[routing_api.CreateRouteV2],
+			routing_api.RoutesMap[routing_api.GetRouteV2],
+			routing_api.RoutesMap[routing_api.UpdateRouteV2],
+			routing_api.RoutesMap[routing_api.DeleteRouteV2],
+		}, rata.Handlers{
+			routing_api.ListRoutesV2:  http.HandlerFunc(routesHandler.ListV2),
+			routing_api.CreateRouteV2: http.HandlerFunc(routesHandler.CreateV2),
+			routing_api.GetRouteV2:    http.HandlerFunc(routesHandler.GetV2),
+			routing_api.UpdateRouteV2: http.HandlerFunc(routesHandler.UpdateV2),
+			routing_api.DeleteRouteV2: http.HandlerFunc(routesHandler.DeleteV2),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		storedRoute = models.NewRoute("foo.example.com", 8080, "1.2.3.4", "log-guid", "", 50)
+		storedRoute.Guid = "route-guid"
+		storedRoute.CreatedAt = time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+		storedRoute.UpdatedAt = storedRoute.CreatedAt
+		storedRoute.ModificationTag = models.ModificationTag{Guid: "tag", Index: 3}
+	})
+
+	Describe("ListV2", func() {
+		It("responds with the routes including their guids and timestamps", func() {
+			database.ReadRoutesReturns([]models.Route{storedRoute}, nil)
+
+			serve("GET", "/routing/v2/routes", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`[{
+				"guid": "route-guid",
+				"created_at": "2016-01-02T03:04:05Z",
+				"updated_at": "2016-01-02T03:04:05Z",
+				"route": "foo.example.com",
+				"port": 8080,
+				"ip": "1.2.3.4",
+				"ttl": 50,
+				"log_guid": "log-guid",
+				"modification_tag": {"guid": "tag", "index": 3}
+			}]`))
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.RoutingRoutesReadScope))
+		})
+	})
+
+	Describe("CreateV2", func() {
+		BeforeEach(func() {
+			database.ReadRouteStub = func(route models.Route) (models.Route, error) {
+				if database.CreateRouteCallCount() == 0 {
+					return models.Route{}, nil
+				}
+				return storedRoute, nil
+			}
+		})
+
+		It("saves the route and responds with 201 and its location", func() {
+			serve("POST", "/routing/v2/routes", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+			Expect(responseRecorder.Header().Get("Location")).To(Equal("/routing/v2/routes/route-guid"))
+			Expect(decodeRoute().Guid).To(Equal("route-guid"))
+
+			Expect(database.CreateRouteCallCount()).To(Equal(1))
+			saved := database.CreateRouteArgsForCall(0)
+			Expect(saved.Route).To(Equal("foo.example.com"))
+			Expect(*saved.TTL).To(Equal(50))
+			Expect(auditor.RecordCallCount()).To(Equal(1))
+		})
+
+		Context("when the route already exists", func() {
+			BeforeEach(func() {
+				database.ReadRouteReturns(storedRoute, nil)
+				database.ReadRouteStub = nil
+			})
+
+			It("responds with 409 Conflict", func() {
+				serve("POST", "/routing/v2/routes", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("route-guid"))
+				Expect(database.CreateRouteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the route is created concurrently", func() {
+			BeforeEach(func() {
+				database.CreateRouteReturns(db.ErrorConflict)
+			})
+
+			It("responds with 409 Conflict", func() {
+				serve("POST", "/routing/v2/routes", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+				Expect(auditor.RecordCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the database does not store guids", func() {
+			BeforeEach(func() {
+				database.ReadRouteStub = func(route models.Route) (models.Route, error) {
+					if database.CreateRouteCallCount() == 0 {
+						return models.Route{}, nil
+					}
+					return models.Route{RouteEntity: storedRoute.RouteEntity}, nil
+				}
+			})
+
+			It("responds with 201 without a location", func() {
+				serve("POST", "/routing/v2/routes", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+				Expect(responseRecorder.Header()).NotTo(HaveKey("Location"))
+			})
+		})
+
+		Context("when the route is invalid", func() {
+			BeforeEach(func() {
+				validator.ValidateCreateReturns(&routing_api.Error{Type: routing_api.RouteInvalidError, Message: "invalid"})
+			})
+
+			It("responds with 400 Bad Request", func() {
+				serve("POST", "/routing/v2/routes", `{"route": "foo.example.com", "port": 8080}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(database.CreateRouteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("GetV2", func() {
+		It("responds with the route with the guid", func() {
+			database.ReadRouteByGuidReturns(storedRoute, nil)
+
+			serve("GET", "/routing/v2/routes/route-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(decodeRoute()).To(Equal(models.NewRouteV2(storedRoute)))
+			Expect(database.ReadRouteByGuidArgsForCall(0)).To(Equal("route-guid"))
+		})
+
+		It("responds with 404 Not Found when the route does not exist", func() {
+			serve("GET", "/routing/v2/routes/unknown", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			Expect(responseRecorder.Body.String()).To(ContainSubstring(string(routing_api.ResourceNotFoundError)))
+		})
+
+		It("responds with 500 when the database fails", func() {
+			database.ReadRouteByGuidReturns(models.Route{}, errors.New("connection refused"))
+
+			serve("GET", "/routing/v2/routes/route-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+
+		It("responds with 501 Not Implemented when the database does not store guids", func() {
+			database.ReadRouteByGuidReturns(models.Route{}, db.ErrGuidsNotSupported)
+
+			serve("GET", "/routing/v2/routes/route-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotImplemented))
+			Expect(responseRecorder.Body.String()).To(ContainSubstring(string(routing_api.NotImplementedError)))
+		})
+	})
+
+	Describe("UpdateV2", func() {
+		BeforeEach(func() {
+			database.ReadRouteByGuidReturns(storedRoute, nil)
+		})
+
+		It("updates the ttl and log guid and responds with the route", func() {
+			serve("PUT", "/routing/v2/routes/route-guid", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4", "ttl": 100, "log_guid": "new-log-guid"}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.SaveRouteCallCount()).To(Equal(1))
+			saved := database.SaveRouteArgsForCall(0)
+			Expect(*saved.TTL).To(Equal(100))
+			Expect(saved.LogGuid).To(Equal("new-log-guid"))
+			Expect(saved.Guid).To(Equal("route-guid"))
+			Expect(database.CreateRouteCallCount()).To(Equal(0))
+		})
+
+		It("keeps the ttl of the route when the body leaves it out", func() {
+			ttl := 80
+			storedRoute.TTL = &ttl
+			database.ReadRouteByGuidReturns(storedRoute, nil)
+
+			serve("PUT", "/routing/v2/routes/route-guid", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4", "log_guid": "new-log-guid"}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.SaveRouteCallCount()).To(Equal(1))
+			Expect(*database.SaveRouteArgsForCall(0).TTL).To(Equal(80))
+		})
+
+		It("is conditional on the modification tag of the body", func() {
+			serve("PUT", "/routing/v2/routes/route-guid", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4", "modification_tag": {"guid": "tag", "index": 3}}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.SaveRouteIfMatchCallCount()).To(Equal(1))
+			_, expected := database.SaveRouteIfMatchArgsForCall(0)
+			Expect(expected).To(Equal(models.ModificationTag{Guid: "tag", Index: 3}))
+		})
+
+		Context("when the modification tag does not match", func() {
+			BeforeEach(func() {
+				database.SaveRouteIfMatchReturns(db.ModificationTagMismatchError{Current: models.ModificationTag{Guid: "tag", Index: 4}})
+			})
+
+			It("responds with 412 Precondition Failed", func() {
+				serve("PUT", "/routing/v2/routes/route-guid", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4", "modification_tag": {"guid": "tag", "index": 3}}`)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+			})
+		})
+
+		It("responds with 400 Bad Request when the body changes the route", func() {
+			serve("PUT", "/routing/v2/routes/route-guid", `{"route": "bar.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(responseRecorder.Body.String()).To(ContainSubstring(string(routing_api.RouteInvalidError)))
+			Expect(database.SaveRouteCallCount()).To(Equal(0))
+		})
+
+		It("responds with 404 Not Found when the route does not exist", func() {
+			database.ReadRouteByGuidReturns(models.Route{}, nil)
+
+			serve("PUT", "/routing/v2/routes/unknown", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("responds with 401 Unauthorized before looking the route up when the token is not valid", func() {
+			fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+
+			serve("PUT", "/routing/v2/routes/route-guid", `{"route": "foo.example.com", "port": 8080, "ip": "1.2.3.4"}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(database.ReadRouteByGuidCallCount()).To(Equal(0))
+			Expect(database.SaveRouteCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("DeleteV2", func() {
+		BeforeEach(func() {
+			database.ReadRouteByGuidReturns(storedRoute, nil)
+		})
+
+		It("deletes the route and responds with 204 No Content", func() {
+			serve("DELETE", "/routing/v2/routes/route-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+			Expect(database.DeleteRouteCallCount()).To(Equal(1))
+			Expect(database.DeleteRouteArgsForCall(0).Guid).To(Equal("route-guid"))
+			_, permission := fakeClient.DecodeTokenArgsForCall(0)
+			Expect(permission).To(ConsistOf(handlers.RoutingRoutesWriteScope))
+		})
+
+		It("responds with 404 Not Found when the route does not exist", func() {
+			database.ReadRouteByGuidReturns(models.Route{}, nil)
+
+			serve("DELETE", "/routing/v2/routes/unknown", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			Expect(database.DeleteRouteCallCount()).To(Equal(0))
+		})
+
+		It("responds with 404 Not Found when the route is deleted after it was read", func() {
+			database.DeleteRouteReturns(db.DBError{Type: db.KeyNotFound, Message: db.DeleteError})
+
+			serve("DELETE", "/routing/v2/routes/route-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			Expect(database.DeleteRouteCallCount()).To(Equal(1))
+		})
+
+		It("responds with 401 Unauthorized when the token is not valid", func() {
+			fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+
+			serve("DELETE", "/routing/v2/routes/route-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(database.DeleteRouteCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -1,8 +1,137 @@
 package handlers
 
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+)
+
 const (
 	RouterGroupsReadScope   = "routing.router_groups.read"
 	RouterGroupsWriteScope  = "routing.router_groups.write"
 	RoutingRoutesReadScope  = "routing.routes.read"
 	RoutingRoutesWriteScope = "routing.routes.write"
+
+	// RoutingRoutesPermanentScope is required in addition to a write scope to
+	// register routes with models.PermanentTTL, which never expire.
+	RoutingRoutesPermanentScope = "routing.routes.permanent"
 )
+
+// RouterGroupReadScope returns the scope granting read access to a single
+// router group, e.g. routing.router_groups.default-tcp.read.
+func RouterGroupReadScope(name string) string {
+	return fmt.Sprintf("routing.router_groups.%s.read", name)
+}
+
+// RouterGroupWriteScope returns the scope granting write access to a single
+// router group, e.g. routing.router_groups.default-tcp.write.
+func RouterGroupWriteScope(name string) string {
+	return fmt.Sprintf("routing.router_groups.%s.write", name)
+}
+
+// RoutingRoutesGroupReadScope returns the scope granting read access to the
+// routes of a single router group, e.g. routing.routes.default-tcp.read.
+func RoutingRoutesGroupReadScope(name string) string {
+	return fmt.Sprintf("routing.routes.%s.read", name)
+}
+
+// RoutingRoutesGroupWriteScope returns the scope granting write access to the
+// routes of a single router group, e.g. routing.routes.default-tcp.write.
+func RoutingRoutesGroupWriteScope(name string) string {
+	return fmt.Sprintf("routing.routes.%s.write", name)
+}
+
+// MissingScope matches the errors of tokens that are valid but lack the scope
+// they were checked for. The uaa client only tells them from invalid tokens
+// by their message, so every check for a missing scope goes through it.
+var MissingScope = regexp.MustCompile("Token does not have .* scope")
+
+// RouterGroupAuthorizer checks a token against a global scope and, when the
+// token is valid but the global scope is missing, against the
+// per-router-group scope of each group it is asked about. Results are cached
+// for the lifetime of the authorizer, which is a single request or event
+// stream.
+type RouterGroupAuthorizer struct {
+	uaaClient  uaaclient.Client
+	token      string
+	groupScope func(string) string
+	globalErr  error
+	granted    map[string]bool
+}
+
+func NewRouterGroupAuthorizer(uaaClient uaaclient.Client, token, globalScope string, groupScope func(string) string) *RouterGroupAuthorizer {
+	return &RouterGroupAuthorizer{
+		uaaClient:  uaaClient,
+		token:      token,
+		groupScope: groupScope,
+		globalErr:  uaaClient.DecodeToken(token, globalScope),
+		granted:    map[string]bool{},
+	}
+}
+
+func (a *RouterGroupAuthorizer) HasGlobalScope() bool {
+	return a.globalErr == nil
+}
+
+// Authenticated reports whether the token is valid, i.e. whether it has the
+// global scope or only lacks it. Requests with an invalid token are rejected
+// before the router groups are read.
+func (a *RouterGroupAuthorizer) Authenticated() bool {
+	return a.globalErr == nil || MissingScope.MatchString(a.globalErr.Error())
+}
+
+// Err returns the error from the global scope check.
+func (a *RouterGroupAuthorizer) Err() error {
+	return a.globalErr
+}
+
+func (a *RouterGroupAuthorizer) Authorized(routerGroupName string) bool {
+	if a.globalErr == nil {
+		return true
+	}
+	if routerGroupName == "" || !a.Authenticated() {
+		return false
+	}
+
+	granted, ok := a.granted[routerGroupName]
+	if !ok {
+		granted = a.uaaClient.DecodeToken(a.token, a.groupScope(routerGroupName)) == nil
+		a.granted[routerGroupName] = granted
+	}
+	return granted
+}
+
+// AuthorizedAny reports whether the token grants access to at least one of the
+// given router groups.
+func (a *RouterGroupAuthorizer) AuthorizedAny(routerGroupNames []string) bool {
+	for _, name := range routerGroupNames {
+		if a.Authorized(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticated responds with 401 Unauthorized and returns false if the token
+// of authorizer is not valid.
+func authenticated(w http.ResponseWriter, authorizer *RouterGroupAuthorizer, log lager.Logger) bool {
+	if authorizer.Authenticated() {
+		return true
+	}
+	handleUnauthorizedError(w, authorizer.Err(), log)
+	return false
+}
+
+type unauthorizedItemsError struct {
+	cause error
+	items []string
+}
+
+func (e unauthorizedItemsError) Error() string {
+	return fmt.Sprintf("%s. Not authorized for: %s", e.cause.Error(), strings.Join(e.items, ", "))
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// parseRouteSelector reads the log_guid, ip and owner query parameters of a
+// bulk delete. At least one of them is required so that a request without
+// parameters does not delete every route.
+func parseRouteSelector(req *http.Request) (models.RouteSelector, error) {
+	query := req.URL.Query()
+	selector := models.RouteSelector{
+		LogGuid: query.Get("log_guid"),
+		IP:      query.Get("ip"),
+		Owner:   query.Get("owner"),
+	}
+	if selector.Empty() {
+		return selector, errors.New("selector requires at least one of log_guid, ip or owner")
+	}
+	return selector, nil
+}
+
+// parseTcpRouteMappingSelector reads the router_group_guid, backend_ip and
+// owner query parameters of a bulk delete, see parseRouteSelector.
+func parseTcpRouteMappingSelector(req *http.Request) (models.TcpRouteMappingSelector, error) {
+	query := req.URL.Query()
+	selector := models.TcpRouteMappingSelector{
+		RouterGroupGuid: query.Get("router_group_guid"),
+		HostIP:          query.Get("backend_ip"),
+		Owner:           query.Get("owner"),
+	}
+	if selector.Empty() {
+		return selector, errors.New("selector requires at least one of router_group_guid, backend_ip or owner")
+	}
+	return selector, nil
+}
+
+// parseDryRun parses the dry_run query parameter. It is false when absent.
+func parseDryRun(req *http.Request) (bool, error) {
+	value := req.URL.Query().Get("dry_run")
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("invalid dry_run: " + value)
+	}
+	return parsed, nil
+}
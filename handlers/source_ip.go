@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type sourceIPKey struct{}
+
+// SourceIPWrap resolves the source IP recorded in the audit records of
+// requests. The X-Forwarded-For header is only read when the request comes
+// from one of the trusted proxies, since any client can set it.
+func SourceIPWrap(handler http.Handler, trustedProxies []*net.IPNet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := resolveSourceIP(r, trustedProxies)
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sourceIPKey{}, ip)))
+	}
+}
+
+// resolveSourceIP returns the address of the peer of req unless it is a
+// trusted proxy. Otherwise it returns the right-most address of the
+// X-Forwarded-For header that is not a trusted proxy, since the entries left
+// of it may have been forged by the client.
+func resolveSourceIP(req *http.Request, trustedProxies []*net.IPNet) string {
+	ip := remoteIP(req)
+	if !trusted(ip, trustedProxies) {
+		return ip
+	}
+
+	hops := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !trusted(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+func trusted(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// sourceIP is the source IP of req resolved by SourceIPWrap, or the address
+// of its peer.
+func sourceIP(req *http.Request) string {
+	if ip, ok := req.Context().Value(sourceIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(req)
+}
@@ -0,0 +1,66 @@
+package handlers_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/handlers"
+	fake_validator "code.cloudfoundry.org/routing-api/handlers/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SourceIPWrap", func() {
+	var (
+		auditor *fake_audit.FakeRecorder
+		handler http.Handler
+		request *http.Request
+	)
+
+	BeforeEach(func() {
+		auditor = &fake_audit.FakeRecorder{}
+		auditor.EnabledReturns(true)
+		database := &fake_db.FakeDB{}
+		database.ReadRouteReturns(models.NewRoute("post_here", 7000, "1.2.3.4", "", "", 60), nil)
+		routesHandler := handlers.NewRoutesHandler(&fake_client.FakeClient{}, models.TTLPolicy{MaxTTL: 50, DefaultTTL: 50}, &fake_validator.FakeRouteValidator{}, database, lagertest.NewTestLogger("source-ip"), auditor, &fake_quota.FakeEnforcer{})
+
+		_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+		Expect(err).NotTo(HaveOccurred())
+		handler = handlers.SourceIPWrap(http.HandlerFunc(routesHandler.Delete), []*net.IPNet{proxies})
+
+		request = handlers.NewTestRequest([]models.Route{models.NewRoute("post_here", 7000, "1.2.3.4", "", "", 60)})
+	})
+
+	recordedSourceIP := func() string {
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		Expect(auditor.RecordCallCount()).To(Equal(1))
+		return auditor.RecordArgsForCall(0).SourceIP
+	}
+
+	It("ignores the X-Forwarded-For header of untrusted clients", func() {
+		request.RemoteAddr = "192.168.1.1:1234"
+		request.Header.Set("X-Forwarded-For", "1.1.1.1")
+
+		Expect(recordedSourceIP()).To(Equal("192.168.1.1"))
+	})
+
+	It("takes the right-most untrusted address forwarded by trusted proxies", func() {
+		request.RemoteAddr = "10.0.0.2:1234"
+		request.Header.Set("X-Forwarded-For", "1.1.1.1, 192.168.1.1, 10.0.0.3")
+
+		Expect(recordedSourceIP()).To(Equal("192.168.1.1"))
+	})
+
+	It("takes the address of a trusted proxy that forwards nothing", func() {
+		request.RemoteAddr = "10.0.0.2:1234"
+
+		Expect(recordedSourceIP()).To(Equal("10.0.0.2"))
+	})
+})
@@ -5,8 +5,10 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/audit"
 	"code.cloudfoundry.org/routing-api/db"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/quota"
 	uaaclient "code.cloudfoundry.org/uaa-go-client"
 )
 
@@ -15,125 +17,334 @@ type TcpRouteMappingsHandler struct {
 	validator RouteValidator
 	db        db.DB
 	logger    lager.Logger
-	maxTTL    int
+	ttlPolicy models.TTLPolicy
+	auditor   audit.Recorder
+	quotas    quota.Enforcer
 }
 
-func NewTcpRouteMappingsHandler(uaaClient uaaclient.Client, validator RouteValidator, database db.DB, ttl int, logger lager.Logger) *TcpRouteMappingsHandler {
+func NewTcpRouteMappingsHandler(uaaClient uaaclient.Client, validator RouteValidator, database db.DB, ttlPolicy models.TTLPolicy, logger lager.Logger, auditor audit.Recorder, quotas quota.Enforcer) *TcpRouteMappingsHandler {
 	return &TcpRouteMappingsHandler{
 		uaaClient: uaaClient,
 		validator: validator,
 		db:        database,
 		logger:    logger,
-		maxTTL:    ttl,
+		ttlPolicy: ttlPolicy,
+		auditor:   auditor,
+		quotas:    quotas,
 	}
 }
 
 func (h *TcpRouteMappingsHandler) List(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("list-tcp-route-mappings")
+	log := h.logger.Session("list-tcp-route-mappings", requestData(req))
 
-	err := h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesReadScope)
-	if err != nil {
-		handleUnauthorizedError(w, err, log)
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesReadScope, RoutingRoutesGroupReadScope)
+	if !authenticated(w, authorizer, log) {
 		return
 	}
-	routes, err := h.db.ReadTcpRouteMappings()
+	var groupNames map[string]string
+	if !authorizer.HasGlobalScope() {
+		routerGroups, err := requestDB(h.db, req).ReadRouterGroups()
+		if err != nil {
+			handleDBCommunicationError(w, err, log)
+			return
+		}
+		groupNames = routerGroupNames(routerGroups)
+		if !authorizer.AuthorizedAny(routerGroups.Names()) {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return
+		}
+	}
+
+	revision, err := requestDB(h.db, req).ReadRevision(db.TCP_ROUTES_TABLE)
 	if err != nil {
 		handleDBCommunicationError(w, err, log)
 		return
 	}
-	encoder := json.NewEncoder(w)
-	err = encoder.Encode(routes)
-	if err != nil {
-		handleProcessRequestError(w, err, log)
+	if notModified(w, req, revision) {
+		return
 	}
+
+	streamList(w, req, log, func(encode func(item interface{}) error) error {
+		return requestDB(h.db, req).StreamTcpRouteMappings(func(route models.TcpRouteMapping) error {
+			if !authorizer.Authorized(groupNames[route.RouterGroupGuid]) {
+				return nil
+			}
+			return encode(route)
+		})
+	})
 }
 
 func (h *TcpRouteMappingsHandler) Upsert(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("create-tcp-route-mappings")
-	decoder := json.NewDecoder(req.Body)
+	log := h.logger.Session("create-tcp-route-mappings", requestData(req))
+	database := requestDB(h.db, req)
 
 	var tcpMappings []models.TcpRouteMapping
-	err := decoder.Decode(&tcpMappings)
+	err := decodeBatch(req, &tcpMappings)
+	if err != nil {
+		handleDecodeError(w, err, log)
+		return
+	}
+
+	ifMatch, err := ifMatchTag(req)
 	if err != nil {
 		handleProcessRequestError(w, err, log)
 		return
 	}
 
-	err = h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	conditional, err := parseConditional(req)
 	if err != nil {
-		handleUnauthorizedError(w, err, log)
+		handleProcessRequestError(w, err, log)
 		return
 	}
 
-	// set defaults
-	for i := 0; i < len(tcpMappings); i++ {
-		tcpMappings[i].SetDefaults(h.maxTTL)
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
 	}
 
-	log.Info("request", lager.Data{"tcp_mapping_creation": tcpMappings})
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesWriteScope, RoutingRoutesGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
 
 	// fetch current router groups
-	routerGroups, err := h.db.ReadRouterGroups()
+	routerGroups, err := database.ReadRouterGroups()
 	if err != nil {
 		handleDBCommunicationError(w, err, log)
 		return
 	}
 
-	apiErr := h.validator.ValidateCreateTcpRouteMapping(tcpMappings, routerGroups, h.maxTTL)
+	permanent := SetTcpRouteMappingDefaults(tcpMappings, routerGroups, h.ttlPolicy, TokenClientID(req.Header.Get("Authorization")))
+
+	log.Info("request", lager.Data{"tcp_mapping_creation": tcpMappings})
+
+	err = AuthorizeTcpRouteMappings(authorizer, tcpMappings, routerGroups)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return
+	}
+
+	if permanent {
+		err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesPermanentScope)
+		if err != nil {
+			handleUnauthorizedError(w, err, log)
+			return
+		}
+	}
+
+	apiErr := h.validator.ValidateCreateTcpRouteMapping(tcpMappings, routerGroups, h.ttlPolicy.MaxTTL)
 	if apiErr != nil {
 		handleProcessRequestError(w, apiErr, log)
 		return
 	}
 
-	for _, tcpMapping := range tcpMappings {
-		err = h.db.SaveTcpRouteMapping(tcpMapping)
+	err = h.quotas.CheckTcpRouteMappings(tcpMappings)
+	if err != nil {
+		if _, ok := err.(quota.ExceededError); ok {
+			handleQuotaExceededError(w, err, log)
+		} else {
+			handleDBCommunicationError(w, err, log)
+		}
+		return
+	}
+
+	if dryRun {
+		changes, err := PlanTcpRouteMappingUpserts(database, tcpMappings, ifMatch, conditional)
 		if err != nil {
-			if err == db.ErrorConflict {
-				handleDBConflictError(w, err, log)
-			} else {
-				handleDBCommunicationError(w, err, log)
-			}
+			handlePlanError(w, err, log)
 			return
 		}
+		writeDryRun(w, changes, log)
+		return
+	}
+
+	err = UpsertTcpRouteMappings(req.Context(), database, h.auditor, newAuditContext(req), tcpMappings, ifMatch, conditional, log)
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
 func (h *TcpRouteMappingsHandler) Delete(w http.ResponseWriter, req *http.Request) {
-	log := h.logger.Session("delete-tcp-route-mappings")
-	decoder := json.NewDecoder(req.Body)
+	log := h.logger.Session("delete-tcp-route-mappings", requestData(req))
+	database := requestDB(h.db, req)
 
 	var tcpMappings []models.TcpRouteMapping
-	err := decoder.Decode(&tcpMappings)
+	err := decodeBatch(req, &tcpMappings)
 	if err != nil {
-		handleProcessRequestError(w, err, log)
+		handleDecodeError(w, err, log)
 		return
 	}
 
 	log.Info("request", lager.Data{"tcp_mapping_deletion": tcpMappings})
 
-	err = h.uaaClient.DecodeToken(req.Header.Get("Authorization"), RoutingRoutesWriteScope)
+	ifMatch, err := ifMatchTag(req)
 	if err != nil {
-		handleUnauthorizedError(w, err, log)
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	conditional, err := parseConditional(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	drain, err := drainTTL(req, h.ttlPolicy.MaxTTL)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
 		return
 	}
 
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesWriteScope, RoutingRoutesGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+	if !authorizer.HasGlobalScope() {
+		routerGroups, err := database.ReadRouterGroups()
+		if err != nil {
+			handleDBCommunicationError(w, err, log)
+			return
+		}
+
+		err = AuthorizeTcpRouteMappings(authorizer, tcpMappings, routerGroups)
+		if err != nil {
+			handleUnauthorizedError(w, err, log)
+			return
+		}
+	}
+
 	apiErr := h.validator.ValidateDeleteTcpRouteMapping(tcpMappings)
 	if apiErr != nil {
 		handleProcessRequestError(w, apiErr, log)
 		return
 	}
 
-	for _, tcpMapping := range tcpMappings {
-		err = h.db.DeleteTcpRouteMapping(tcpMapping)
+	if dryRun {
+		changes, err := PlanTcpRouteMappingDeletes(database, tcpMappings, drain, ifMatch, conditional)
 		if err != nil {
-			if dberr, ok := err.(db.DBError); !ok || dberr.Type != db.KeyNotFound {
-				handleDBCommunicationError(w, err, log)
-				return
-			}
+			handlePlanError(w, err, log)
+			return
 		}
+		writeDryRun(w, changes, log)
+		return
+	}
+
+	err = DeleteTcpRouteMappings(req.Context(), database, h.auditor, newAuditContext(req), tcpMappings, drain, ifMatch, conditional, log)
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// DeleteBySelector deletes every mapping matched by the selector in the query
+// parameters and responds with the deleted mappings. With dry_run=true it
+// responds with the mappings that would be deleted. Tokens with only
+// per-router-group scopes must select a router group they can write to.
+func (h *TcpRouteMappingsHandler) DeleteBySelector(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("delete-tcp-route-mappings-by-selector", requestData(req))
+	database := requestDB(h.db, req)
+
+	selector, err := parseTcpRouteMappingSelector(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	dryRun, err := parseDryRun(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesWriteScope, RoutingRoutesGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+	if !authorizer.HasGlobalScope() {
+		if selector.RouterGroupGuid == "" {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return
+		}
+
+		routerGroup, err := database.ReadRouterGroup(selector.RouterGroupGuid)
+		if err != nil {
+			handleDBCommunicationError(w, err, log)
+			return
+		}
+		if !authorizer.Authorized(routerGroup.Name) {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return
+		}
+	}
+
+	log.Info("request", lager.Data{"selector": selector, "dry_run": dryRun})
+
+	tcpMappings, err := database.DeleteTcpRouteMappingsBySelector(selector, dryRun)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	if !dryRun {
+		auditCtx := newAuditContext(req)
+		for _, tcpMapping := range tcpMappings {
+			h.auditor.Record(auditCtx.NewRecord(models.AuditActionDelete, models.AuditKindTcpRoute, tcpMapping.AuditKey(), tcpMapping, nil))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(tcpMappings)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+	}
+}
+
+// AuthorizeTcpRouteMappings returns an error listing every mapping whose router
+// group the token is not allowed to write to.
+func AuthorizeTcpRouteMappings(authorizer *RouterGroupAuthorizer, tcpMappings []models.TcpRouteMapping, routerGroups models.RouterGroups) error {
+	if authorizer.HasGlobalScope() {
+		return nil
+	}
+
+	groupNames := routerGroupNames(routerGroups)
+	var unauthorized []string
+	for _, tcpMapping := range tcpMappings {
+		if !authorizer.Authorized(groupNames[tcpMapping.RouterGroupGuid]) {
+			unauthorized = append(unauthorized, "RouteMapping=["+tcpMapping.String()+"]")
+		}
+	}
+
+	if len(unauthorized) > 0 {
+		return unauthorizedItemsError{cause: authorizer.Err(), items: unauthorized}
+	}
+	return nil
+}
+
+func routerGroupNames(routerGroups models.RouterGroups) map[string]string {
+	names := make(map[string]string, len(routerGroups))
+	for _, routerGroup := range routerGroups {
+		names[routerGroup.Guid] = routerGroup.Name
+	}
+	return names
+}
+
+func FindRouterGroup(routerGroups models.RouterGroups, guid string) (models.RouterGroup, bool) {
+	for _, routerGroup := range routerGroups {
+		if routerGroup.Guid == guid {
+			return routerGroup, true
+		}
+	}
+	return models.RouterGroup{}, false
+}
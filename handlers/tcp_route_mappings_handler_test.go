@@ -1,17 +1,22 @@
 package handlers_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/routing-api"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
 	"code.cloudfoundry.org/routing-api/db"
 	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
 	fake_validator "code.cloudfoundry.org/routing-api/handlers/fakes"
 	"code.cloudfoundry.org/routing-api/metrics"
 	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	"code.cloudfoundry.org/routing-api/quota"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
 	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
 
 	"code.cloudfoundry.org/routing-api/handlers"
@@ -36,6 +41,8 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 		database                *fake_db.FakeDB
 		logger                  *lagertest.TestLogger
 		fakeClient              *fake_client.FakeClient
+		auditor                 *fake_audit.FakeRecorder
+		quotas                  *fake_quota.FakeEnforcer
 		maxTTL                  int
 	)
 
@@ -44,8 +51,10 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 		fakeClient = &fake_client.FakeClient{}
 		validator = &fake_validator.FakeRouteValidator{}
 		logger = lagertest.NewTestLogger("routing-api-test")
+		auditor = &fake_audit.FakeRecorder{}
+		quotas = &fake_quota.FakeEnforcer{}
 		maxTTL = 120
-		tcpRouteMappingsHandler = handlers.NewTcpRouteMappingsHandler(fakeClient, validator, database, maxTTL, logger)
+		tcpRouteMappingsHandler = handlers.NewTcpRouteMappingsHandler(fakeClient, validator, database, models.TTLPolicy{MaxTTL: maxTTL, DefaultTTL: maxTTL}, logger, auditor, quotas)
 		responseRecorder = httptest.NewRecorder()
 	})
 
@@ -90,6 +99,33 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 
 				})
 
+				Context("when the router group has a ttl policy", func() {
+					BeforeEach(func() {
+						database.ReadRouterGroupsReturns(models.RouterGroups{
+							{Guid: "router-group-guid-001", Name: "default-tcp", MaxTTL: 90, DefaultTTL: 30},
+						}, nil)
+					})
+
+					It("sets the default ttl of the router group", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+
+						Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(1))
+						Expect(*database.SaveTcpRouteMappingArgsForCall(0).TTL).To(Equal(30))
+					})
+
+					It("validates against the configured max ttl", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+						Expect(validator.ValidateCreateTcpRouteMappingCallCount()).To(Equal(1))
+						_, _, ttl := validator.ValidateCreateTcpRouteMappingArgsForCall(0)
+						Expect(ttl).To(Equal(maxTTL))
+					})
+				})
 			})
 
 			Context("when ttl is present", func() {
@@ -109,6 +145,50 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 					Expect(permission).To(ConsistOf(handlers.RoutingRoutesWriteScope))
 				})
 
+				Context("when dry_run is set", func() {
+					It("responds with the mappings that would be created and saves nothing", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "dry_run=true"
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+						Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+						Expect(validator.ValidateCreateTcpRouteMappingCallCount()).To(Equal(1))
+						Expect(database.ReadRouterGroupsCallCount()).To(Equal(1))
+
+						var changes []models.TcpRouteMappingChange
+						Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+						Expect(changes).To(HaveLen(1))
+						Expect(changes[0].Action).To(Equal(models.ChangeCreate))
+						Expect(changes[0].After.ExternalPort).To(Equal(uint16(52000)))
+					})
+
+					It("reports an update when the mapping exists", func() {
+						existing := tcpMapping
+						existing.ModificationTag = models.ModificationTag{Guid: "some-guid", Index: 5}
+						database.ReadTcpRouteMappingReturns(existing, nil)
+
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "dry_run=true"
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+						var changes []models.TcpRouteMappingChange
+						Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+						Expect(changes[0].Action).To(Equal(models.ChangeUpdate))
+						Expect(changes[0].Before).NotTo(BeNil())
+						Expect(changes[0].After.ModificationTag).To(Equal(models.ModificationTag{Guid: "some-guid", Index: 6}))
+					})
+
+					It("responds with a 412 when a conditional write would fail", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "dry_run=true"
+						request.Header.Set("If-Match", `"some-guid:5"`)
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+					})
+				})
+
 				Context("when all inputs are present and correct", func() {
 					It("returns an http status created", func() {
 						request = handlers.NewTestRequest(tcpMappings)
@@ -175,6 +255,39 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 							Expect(responseRecorder.Body.String()).To(ContainSubstring("DBConflictError"))
 						})
 					})
+
+					Context("when the If-Match header is set", func() {
+						It("saves the mappings conditionally", func() {
+							request = handlers.NewTestRequest(tcpMappings)
+							request.Header.Set("If-Match", `"some-guid:2"`)
+							tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+							Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+							Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+							Expect(database.SaveTcpRouteMappingIfMatchCallCount()).To(Equal(1))
+							savedMapping, expected := database.SaveTcpRouteMappingIfMatchArgsForCall(0)
+							Expect(savedMapping).To(Equal(tcpMappings[0]))
+							Expect(expected).To(Equal(models.ModificationTag{Guid: "some-guid", Index: 2}))
+						})
+
+						Context("when the modification tag does not match", func() {
+							BeforeEach(func() {
+								database.SaveTcpRouteMappingIfMatchReturns(db.ModificationTagMismatchError{
+									Current: models.ModificationTag{Guid: "some-guid", Index: 3},
+								})
+							})
+
+							It("responds with a 412 and the current tag", func() {
+								request = handlers.NewTestRequest(tcpMappings)
+								request.Header.Set("If-Match", `"some-guid:2"`)
+								tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+								Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+								Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"some-guid:3"`))
+								Expect(responseRecorder.Body.String()).To(ContainSubstring("PreconditionFailedError"))
+							})
+						})
+					})
 				})
 			})
 
@@ -223,9 +336,27 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 				})
 			})
 
+			Context("when the mappings exceed a quota", func() {
+				BeforeEach(func() {
+					quotas.CheckTcpRouteMappingsReturns(quota.ExceededError{Quota: models.QuotaTcpRoutesPerOwner, Key: "cf", Limit: 5})
+				})
+
+				It("responds with a 403 quota exceeded error", func() {
+					request = handlers.NewTestRequest(tcpMappings)
+					tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusForbidden))
+					Expect(responseRecorder.Body.String()).To(ContainSubstring("QuotaExceededError"))
+					Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when validator returns error", func() {
 				BeforeEach(func() {
 					err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError, "Each tcp mapping requires a valid router group guid")
+					err.Details = []routing_api.ErrorDetail{
+						{Index: 0, Field: "router_group_guid", Code: routing_api.MissingFieldCode, Message: "Each tcp mapping requires a valid router group guid"},
+					}
 					validator.ValidateCreateTcpRouteMappingReturns(&err)
 				})
 
@@ -237,6 +368,20 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 					Expect(database.SaveRouteCallCount()).To(Equal(0))
 					Expect(logger.Logs()[1].Message).To(ContainSubstring("error"))
 				})
+
+				It("returns the details of the violations", func() {
+					request = handlers.NewTestRequest(`[{"router_group_guid": "", "port": 52000, "backend_ip": "10.1.1.12", "backend_port": 60000}]`)
+					tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+					var apiErr routing_api.Error
+					Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &apiErr)).To(Succeed())
+					Expect(apiErr.Details).To(ConsistOf(routing_api.ErrorDetail{
+						Index:   0,
+						Field:   "router_group_guid",
+						Code:    routing_api.MissingFieldCode,
+						Message: "Each tcp mapping requires a valid router group guid",
+					}))
+				})
 			})
 
 			Context("when the UAA token is not valid", func() {
@@ -254,6 +399,93 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 
 					Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
 					Expect(metrics.GetTokenErrors()).To(Equal(currentCount + 1))
+					Expect(database.ReadRouterGroupsCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when a mapping is permanent", func() {
+				BeforeEach(func() {
+					tcpMappings = []models.TcpRouteMapping{
+						models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, models.PermanentTTL),
+					}
+				})
+
+				It("checks for routing.routes.permanent scope and saves the mapping as permanent", func() {
+					request = handlers.NewTestRequest(tcpMappings)
+					tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+
+					Expect(fakeClient.DecodeTokenCallCount()).To(Equal(2))
+					_, permission := fakeClient.DecodeTokenArgsForCall(1)
+					Expect(permission).To(ConsistOf(handlers.RoutingRoutesPermanentScope))
+
+					Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(1))
+					Expect(database.SaveTcpRouteMappingArgsForCall(0).Permanent).To(BeTrue())
+				})
+
+				Context("when the token does not have routing.routes.permanent scope", func() {
+					BeforeEach(func() {
+						fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+							if desiredPermissions[0] == handlers.RoutingRoutesPermanentScope {
+								return errors.New("Token does not have 'routing.routes.permanent' scope")
+							}
+							return nil
+						}
+					})
+
+					It("returns an Unauthorized error and saves nothing", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+						Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when the token only has router group scopes", func() {
+				BeforeEach(func() {
+					database.ReadRouterGroupsReturns(models.RouterGroups{
+						{Guid: "router-group-guid-001", Name: "group-1"},
+						{Guid: "router-group-guid-002", Name: "group-2"},
+					}, nil)
+					fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+						if len(desiredPermissions) == 1 && desiredPermissions[0] == "routing.routes.group-1.write" {
+							return nil
+						}
+						return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+					}
+					tcpMappings = []models.TcpRouteMapping{
+						models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60),
+					}
+				})
+
+				It("saves mappings in the authorized router group", func() {
+					request = handlers.NewTestRequest(tcpMappings)
+					tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+					Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+					Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(1))
+				})
+
+				Context("when a mapping is in another router group", func() {
+					BeforeEach(func() {
+						tcpMappings = append(tcpMappings,
+							models.NewTcpRouteMapping("router-group-guid-002", 52001, "1.2.3.5", 60001, 60),
+							models.NewTcpRouteMapping("router-group-guid-unknown", 52002, "1.2.3.6", 60002, 60),
+						)
+					})
+
+					It("reports each unauthorized mapping and saves nothing", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						tcpRouteMappingsHandler.Upsert(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+						Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+						body := responseRecorder.Body.String()
+						Expect(body).To(ContainSubstring("RouteMapping=[router-group-guid-002:52001<->1.2.3.5:60001]"))
+						Expect(body).To(ContainSubstring("RouteMapping=[router-group-guid-unknown:52002<->1.2.3.6:60002]"))
+						Expect(body).NotTo(ContainSubstring("router-group-guid-001"))
+					})
 				})
 			})
 		})
@@ -271,6 +503,26 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 			Expect(permission).To(ConsistOf(handlers.RoutingRoutesReadScope))
 		})
 
+		It("returns the revision of the tcp routes table as ETag", func() {
+			database.ReadRevisionReturns(12, nil)
+			request = handlers.NewTestRequest("")
+
+			tcpRouteMappingsHandler.List(responseRecorder, request)
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"12"`))
+			Expect(database.ReadRevisionArgsForCall(0)).To(Equal(db.TCP_ROUTES_TABLE))
+		})
+
+		It("returns a 304 when If-None-Match names the current revision", func() {
+			database.ReadRevisionReturns(12, nil)
+			request = handlers.NewTestRequest("")
+			request.Header.Set("If-None-Match", `W/"12"`)
+
+			tcpRouteMappingsHandler.List(responseRecorder, request)
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotModified))
+			Expect(database.StreamTcpRouteMappingsCallCount()).To(Equal(0))
+		})
+
 		Context("when db returns tcp route mappings", func() {
 			var (
 				tcpRoutes []models.TcpRouteMapping
@@ -280,7 +532,7 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 				mapping1 := models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 55)
 				mapping2 := models.NewTcpRouteMapping("router-group-guid-001", 52001, "1.2.3.5", 60001, 55)
 				tcpRoutes = []models.TcpRouteMapping{mapping1, mapping2}
-				database.ReadTcpRouteMappingsReturns(tcpRoutes, nil)
+				database.StreamTcpRouteMappingsStub = streamTcpRouteMappings(tcpRoutes, nil)
 			})
 
 			It("returns tcp route mappings", func() {
@@ -313,11 +565,25 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 							}]`
 				Expect(responseRecorder.Body.String()).To(MatchJSON(expectedJson))
 			})
+
+			It("returns tcp route mappings as protobuf when the client accepts it", func() {
+				request = handlers.NewTestRequest("")
+				request.Header.Set("Accept", protos.ContentType)
+				tcpRouteMappingsHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Header().Get("Content-Type")).To(Equal(protos.ContentType))
+
+				var decoded []models.TcpRouteMapping
+				err := protos.Unmarshal(responseRecorder.Body.Bytes(), &decoded)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decoded).To(Equal(tcpRoutes))
+			})
 		})
 
 		Context("when db returns empty tcp route mappings", func() {
 			BeforeEach(func() {
-				database.ReadTcpRouteMappingsReturns([]models.TcpRouteMapping{}, nil)
+				database.StreamTcpRouteMappingsStub = streamTcpRouteMappings([]models.TcpRouteMapping{}, nil)
 			})
 
 			It("returns empty response", func() {
@@ -331,7 +597,7 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 
 		Context("when db returns error", func() {
 			BeforeEach(func() {
-				database.ReadTcpRouteMappingsReturns(nil, errors.New("something bad"))
+				database.StreamTcpRouteMappingsStub = streamTcpRouteMappings(nil, errors.New("something bad"))
 			})
 			It("returns internal server error", func() {
 				request = handlers.NewTestRequest("")
@@ -356,9 +622,37 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 
 				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
 				Expect(metrics.GetTokenErrors()).To(Equal(currentCount + 1))
+				Expect(database.ReadRouterGroupsCallCount()).To(Equal(0))
 			})
 		})
 
+		Context("when the token only has router group scopes", func() {
+			BeforeEach(func() {
+				database.ReadRouterGroupsReturns(models.RouterGroups{
+					{Guid: "router-group-guid-001", Name: "group-1"},
+					{Guid: "router-group-guid-002", Name: "group-2"},
+				}, nil)
+				database.StreamTcpRouteMappingsStub = streamTcpRouteMappings([]models.TcpRouteMapping{
+					models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 55),
+					models.NewTcpRouteMapping("router-group-guid-002", 52001, "1.2.3.5", 60001, 55),
+				}, nil)
+				fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+					if len(desiredPermissions) == 1 && desiredPermissions[0] == "routing.routes.group-2.read" {
+						return nil
+					}
+					return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+				}
+			})
+
+			It("returns only the mappings of authorized router groups", func() {
+				request = handlers.NewTestRequest("")
+				tcpRouteMappingsHandler.List(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring("router-group-guid-002"))
+				Expect(responseRecorder.Body.String()).NotTo(ContainSubstring("router-group-guid-001"))
+			})
+		})
 	})
 
 	Describe("Delete", func() {
@@ -404,6 +698,24 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 					Expect(database.DeleteTcpRouteMappingArgsForCall(1)).To(Equal(tcpMappings[1]))
 				})
 
+				It("records the deletion", func() {
+					auditor.EnabledReturns(true)
+					database.ReadTcpRouteMappingReturns(tcpMapping, nil)
+					request = handlers.NewTestRequest(tcpMappings)
+					request.Header.Set("Authorization", testToken(`{"client_id":"tcp-emitter"}`))
+					tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+					Expect(database.ReadTcpRouteMappingCallCount()).To(Equal(1))
+					Expect(auditor.RecordCallCount()).To(Equal(1))
+					record := auditor.RecordArgsForCall(0)
+					Expect(record.Action).To(Equal(models.AuditActionDelete))
+					Expect(record.Kind).To(Equal(models.AuditKindTcpRoute))
+					Expect(record.Key).To(Equal("router-group-guid-002:52001"))
+					Expect(record.Actor).To(Equal("tcp-emitter"))
+					Expect(string(record.Before)).To(ContainSubstring(`"backend_ip":"1.2.3.4"`))
+					Expect(record.After).To(BeEmpty())
+				})
+
 				It("logs the route deletion", func() {
 					request = handlers.NewTestRequest(tcpMappings)
 					tcpRouteMappingsHandler.Delete(responseRecorder, request)
@@ -435,6 +747,72 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 					})
 				})
 
+				Context("when a drain duration is given", func() {
+					It("drains the mappings instead of deleting them", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "drain=60"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+						Expect(database.DeleteTcpRouteMappingCallCount()).To(Equal(0))
+						Expect(database.DrainTcpRouteMappingCallCount()).To(Equal(1))
+						drainedMapping, drain := database.DrainTcpRouteMappingArgsForCall(0)
+						Expect(drainedMapping).To(Equal(tcpMappings[0]))
+						Expect(drain).To(Equal(60))
+					})
+
+					It("returns a bad request if the drain duration is invalid", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "drain=soon"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(database.DrainTcpRouteMappingCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when dry_run is set", func() {
+					It("responds with the mappings that would be deleted and deletes nothing", func() {
+						database.ReadTcpRouteMappingReturns(tcpMappings[0], nil)
+
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "dry_run=true"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+						Expect(database.DeleteTcpRouteMappingCallCount()).To(Equal(0))
+
+						var changes []models.TcpRouteMappingChange
+						Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+						Expect(changes).To(HaveLen(1))
+						Expect(changes[0].Action).To(Equal(models.ChangeDelete))
+						Expect(*changes[0].Before).To(Equal(tcpMappings[0]))
+					})
+
+					It("reports a drain when a drain duration is given", func() {
+						database.ReadTcpRouteMappingReturns(tcpMappings[0], nil)
+
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "drain=60&dry_run=true"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(database.DrainTcpRouteMappingCallCount()).To(Equal(0))
+						var changes []models.TcpRouteMappingChange
+						Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &changes)).To(Succeed())
+						Expect(changes[0].Action).To(Equal(models.ChangeDrain))
+						Expect(changes[0].After.Draining).To(BeTrue())
+					})
+
+					It("reports mappings that do not exist as unchanged", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "dry_run=true"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+						Expect(responseRecorder.Body.String()).To(MatchJSON(`[{"action": "none"}]`))
+					})
+				})
+
 				Context("when route to be deleted is not present", func() {
 					BeforeEach(func() {
 						database.DeleteTcpRouteMappingReturns(db.DBError{Type: db.KeyNotFound, Message: "The specified key is not found"})
@@ -446,6 +824,45 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 						Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
 					})
 				})
+
+				Context("when a mapping carries a modification tag", func() {
+					BeforeEach(func() {
+						tcpMappings[0].ModificationTag = models.ModificationTag{Guid: "some-guid", Index: 2}
+					})
+
+					It("deletes the mapping unconditionally, as listed mappings carry their tag", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+						Expect(database.DeleteTcpRouteMappingIfMatchCallCount()).To(Equal(0))
+						Expect(database.DeleteTcpRouteMappingCallCount()).To(Equal(1))
+					})
+
+					It("deletes the mapping conditionally when conditional is set", func() {
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "conditional=true"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+						Expect(database.DeleteTcpRouteMappingCallCount()).To(Equal(0))
+						Expect(database.DeleteTcpRouteMappingIfMatchCallCount()).To(Equal(1))
+						_, expected := database.DeleteTcpRouteMappingIfMatchArgsForCall(0)
+						Expect(expected).To(Equal(tcpMappings[0].ModificationTag))
+					})
+
+					It("responds with a 412 when conditional is set and the modification tag does not match", func() {
+						database.DeleteTcpRouteMappingIfMatchReturns(db.ModificationTagMismatchError{
+							Current: models.ModificationTag{Guid: "some-guid", Index: 3},
+						})
+						request = handlers.NewTestRequest(tcpMappings)
+						request.URL.RawQuery = "conditional=true"
+						tcpRouteMappingsHandler.Delete(responseRecorder, request)
+
+						Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+						Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"some-guid:3"`))
+					})
+				})
 			})
 
 			Context("when there are errors with the input ports", func() {
@@ -531,4 +948,96 @@ var _ = Describe("TcpRouteMappingsHandler", func() {
 		})
 	})
 
+	Describe("DeleteBySelector", func() {
+		var deletedMapping models.TcpRouteMapping
+
+		BeforeEach(func() {
+			deletedMapping = models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+			database.DeleteTcpRouteMappingsBySelectorReturns([]models.TcpRouteMapping{deletedMapping}, nil)
+		})
+
+		It("deletes the selected mappings and responds with them", func() {
+			request = handlers.NewTestRequest("")
+			request.URL.RawQuery = "router_group_guid=router-group-guid-001&backend_ip=1.2.3.4"
+			tcpRouteMappingsHandler.DeleteBySelector(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.DeleteTcpRouteMappingsBySelectorCallCount()).To(Equal(1))
+			selector, dryRun := database.DeleteTcpRouteMappingsBySelectorArgsForCall(0)
+			Expect(selector).To(Equal(models.TcpRouteMappingSelector{RouterGroupGuid: "router-group-guid-001", HostIP: "1.2.3.4"}))
+			Expect(dryRun).To(BeFalse())
+
+			var tcpMappings []models.TcpRouteMapping
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &tcpMappings)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tcpMappings).To(Equal([]models.TcpRouteMapping{deletedMapping}))
+			Expect(auditor.RecordCallCount()).To(Equal(1))
+		})
+
+		It("returns a bad request when no selector is given", func() {
+			request = handlers.NewTestRequest("")
+			tcpRouteMappingsHandler.DeleteBySelector(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(database.DeleteTcpRouteMappingsBySelectorCallCount()).To(Equal(0))
+		})
+
+		Context("when the token only has router group scopes", func() {
+			BeforeEach(func() {
+				database.ReadRouterGroupReturns(models.RouterGroup{Guid: "router-group-guid-001", Name: "group-1"}, nil)
+				fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+					if len(desiredPermissions) == 1 && desiredPermissions[0] == "routing.routes.group-1.write" {
+						return nil
+					}
+					return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+				}
+			})
+
+			It("deletes mappings in the authorized router group", func() {
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "router_group_guid=router-group-guid-001&dry_run=true"
+				tcpRouteMappingsHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(database.ReadRouterGroupArgsForCall(0)).To(Equal("router-group-guid-001"))
+				_, dryRun := database.DeleteTcpRouteMappingsBySelectorArgsForCall(0)
+				Expect(dryRun).To(BeTrue())
+				Expect(auditor.RecordCallCount()).To(Equal(0))
+			})
+
+			It("requires a router group to be selected", func() {
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "backend_ip=1.2.3.4"
+				tcpRouteMappingsHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(database.DeleteTcpRouteMappingsBySelectorCallCount()).To(Equal(0))
+			})
+
+			It("rejects other router groups", func() {
+				database.ReadRouterGroupReturns(models.RouterGroup{Guid: "router-group-guid-002", Name: "group-2"}, nil)
+
+				request = handlers.NewTestRequest("")
+				request.URL.RawQuery = "router_group_guid=router-group-guid-002"
+				tcpRouteMappingsHandler.DeleteBySelector(responseRecorder, request)
+
+				Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(database.DeleteTcpRouteMappingsBySelectorCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 })
+
+// streamTcpRouteMappings returns a stub for StreamTcpRouteMappings passing the
+// mappings to the callback before failing with err.
+func streamTcpRouteMappings(tcpMappings []models.TcpRouteMapping, err error) func(func(models.TcpRouteMapping) error) error {
+	return func(fn func(models.TcpRouteMapping) error) error {
+		for _, tcpMapping := range tcpMappings {
+			if fnErr := fn(tcpMapping); fnErr != nil {
+				return fnErr
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/quota"
+	"github.com/tedsuo/rata"
+)
+
+var errTcpRouteMappingNotFound = errors.New("The specified tcp route mapping could not be found.")
+
+// ListV2 responds with the mappings of the router groups the token can read,
+// including their guids and timestamps.
+func (h *TcpRouteMappingsHandler) ListV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("list-tcp-route-mappings-v2", requestData(req))
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesReadScope, RoutingRoutesGroupReadScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+	var groupNames map[string]string
+	if !authorizer.HasGlobalScope() {
+		routerGroups, err := requestDB(h.db, req).ReadRouterGroups()
+		if err != nil {
+			handleDBCommunicationError(w, err, log)
+			return
+		}
+		groupNames = routerGroupNames(routerGroups)
+		if !authorizer.AuthorizedAny(routerGroups.Names()) {
+			handleUnauthorizedError(w, authorizer.Err(), log)
+			return
+		}
+	}
+
+	tcpMappings, err := requestDB(h.db, req).ReadTcpRouteMappings()
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	result := make([]models.TcpRouteMappingV2, 0, len(tcpMappings))
+	for _, tcpMapping := range tcpMappings {
+		if authorizer.Authorized(groupNames[tcpMapping.RouterGroupGuid]) {
+			result = append(result, models.NewTcpRouteMappingV2(tcpMapping))
+		}
+	}
+	writeV2(w, http.StatusOK, result, log)
+}
+
+// CreateV2 registers a mapping that does not exist yet and responds with it.
+// Registering an existing mapping is a conflict; it is updated through its
+// guid.
+func (h *TcpRouteMappingsHandler) CreateV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("create-tcp-route-mapping-v2", requestData(req))
+	database := requestDB(h.db, req)
+
+	var body models.TcpRouteMappingV2
+	err := decodeBody(req, &body)
+	if err != nil {
+		handleDecodeError(w, err, log)
+		return
+	}
+
+	log.Info("request", lager.Data{"tcp_mapping_creation": body})
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesWriteScope, RoutingRoutesGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+
+	tcpMapping := models.TcpRouteMapping{TcpMappingEntity: body.TcpMappingEntity}
+	if !h.authorizeTcpRouteMappingWrite(w, req, authorizer, &tcpMapping, log) {
+		return
+	}
+
+	existing, err := database.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+	if existing != (models.TcpRouteMapping{}) {
+		handleDBConflictError(w, errors.New("The tcp route mapping already exists with guid "+existing.Guid), log)
+		return
+	}
+
+	err = h.quotas.CheckTcpRouteMappings([]models.TcpRouteMapping{tcpMapping})
+	if err != nil {
+		if _, ok := err.(quota.ExceededError); ok {
+			handleQuotaExceededError(w, err, log)
+		} else {
+			handleDBCommunicationError(w, err, log)
+		}
+		return
+	}
+
+	err = database.CreateTcpRouteMapping(tcpMapping)
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
+	}
+	h.auditor.Record(newAuditContext(req).NewRecord(models.AuditActionUpsert, models.AuditKindTcpRoute, tcpMapping.AuditKey(), nil, tcpMapping))
+
+	created, err := database.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return
+	}
+
+	// etcd does not store guids, so the created mapping cannot be located
+	if created.Guid != "" {
+		path, err := routing_api.RoutesMap[routing_api.GetTcpRouteMappingV2].CreatePath(rata.Params{"guid": created.Guid})
+		if err == nil {
+			w.Header().Set("Location", path)
+		}
+	}
+	writeV2(w, http.StatusCreated, models.NewTcpRouteMappingV2(created), log)
+}
+
+// GetV2 responds with the mapping with the guid in the path.
+func (h *TcpRouteMappingsHandler) GetV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("get-tcp-route-mapping-v2", requestData(req))
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesReadScope, RoutingRoutesGroupReadScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+
+	tcpMapping, ok := h.readTcpRouteMappingByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	if !h.authorizedForRouterGroup(w, requestDB(h.db, req), authorizer, tcpMapping, log) {
+		return
+	}
+	writeV2(w, http.StatusOK, models.NewTcpRouteMappingV2(tcpMapping), log)
+}
+
+// UpdateV2 updates the ttl of the mapping with the guid in the path. A body
+// without a ttl keeps the ttl of the mapping. The write is conditional on the
+// If-Match header or, without it, on the modification tag of the body if one
+// is given.
+func (h *TcpRouteMappingsHandler) UpdateV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("update-tcp-route-mapping-v2", requestData(req))
+	database := requestDB(h.db, req)
+
+	var body models.TcpRouteMappingV2
+	err := decodeBody(req, &body)
+	if err != nil {
+		handleDecodeError(w, err, log)
+		return
+	}
+
+	log.Info("request", lager.Data{"tcp_mapping_update": body})
+
+	ifMatch, err := ifMatchTag(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesWriteScope, RoutingRoutesGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+
+	existing, ok := h.readTcpRouteMappingByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	if !existing.SameMapping(body.TcpMappingEntity) {
+		err = errors.New("The router_group_guid, port, backend_ip and backend_port of a tcp route mapping cannot be changed")
+		handleProcessRequestError(w, routing_api.NewError(routing_api.TcpRouteMappingInvalidError, err.Error()), log)
+		return
+	}
+
+	tcpMapping := existing
+	if body.TTL != nil {
+		tcpMapping.TTL = body.TTL
+	}
+	if !h.authorizeTcpRouteMappingWrite(w, req, authorizer, &tcpMapping, log) {
+		return
+	}
+
+	if expected := ExpectedTag(ifMatch, body.ModificationTag, true); expected != nil {
+		err = database.SaveTcpRouteMappingIfMatch(tcpMapping, *expected)
+	} else {
+		err = database.SaveTcpRouteMapping(tcpMapping)
+	}
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
+	}
+	h.auditor.Record(newAuditContext(req).NewRecord(models.AuditActionUpsert, models.AuditKindTcpRoute, tcpMapping.AuditKey(), existing, tcpMapping))
+
+	updated, ok := h.readTcpRouteMappingByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	writeV2(w, http.StatusOK, models.NewTcpRouteMappingV2(updated), log)
+}
+
+// DeleteV2 deletes the mapping with the guid in the path. The delete is
+// conditional on the If-Match header if one is given.
+func (h *TcpRouteMappingsHandler) DeleteV2(w http.ResponseWriter, req *http.Request) {
+	log := h.logger.Session("delete-tcp-route-mapping-v2", requestData(req))
+	database := requestDB(h.db, req)
+
+	ifMatch, err := ifMatchTag(req)
+	if err != nil {
+		handleProcessRequestError(w, err, log)
+		return
+	}
+
+	authorizer := NewRouterGroupAuthorizer(requestUAAClient(h.uaaClient, req), req.Header.Get("Authorization"), RoutingRoutesWriteScope, RoutingRoutesGroupWriteScope)
+	if !authenticated(w, authorizer, log) {
+		return
+	}
+
+	tcpMapping, ok := h.readTcpRouteMappingByGuid(w, req, log)
+	if !ok {
+		return
+	}
+	if !h.authorizedForRouterGroup(w, database, authorizer, tcpMapping, log) {
+		return
+	}
+
+	if ifMatch != nil {
+		err = database.DeleteTcpRouteMappingIfMatch(tcpMapping, *ifMatch)
+	} else {
+		err = database.DeleteTcpRouteMapping(tcpMapping)
+	}
+	if err != nil {
+		handleWriteError(w, err, log)
+		return
+	}
+	h.auditor.Record(newAuditContext(req).NewRecord(models.AuditActionDelete, models.AuditKindTcpRoute, tcpMapping.AuditKey(), tcpMapping, nil))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeTcpRouteMappingWrite checks the write scopes of authorizer for the
+// router group of the mapping, sets its defaults and owner and validates it.
+// It responds with the error and returns false if the mapping cannot be
+// written.
+func (h *TcpRouteMappingsHandler) authorizeTcpRouteMappingWrite(w http.ResponseWriter, req *http.Request, authorizer *RouterGroupAuthorizer, tcpMapping *models.TcpRouteMapping, log lager.Logger) bool {
+	routerGroups, err := requestDB(h.db, req).ReadRouterGroups()
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return false
+	}
+
+	policy := h.ttlPolicy
+	if group, ok := FindRouterGroup(routerGroups, tcpMapping.RouterGroupGuid); ok {
+		policy = group.TTLPolicy(h.ttlPolicy)
+	}
+	tcpMapping.SetDefaults(policy.DefaultTTL)
+	tcpMapping.Owner = TokenClientID(req.Header.Get("Authorization"))
+
+	err = AuthorizeTcpRouteMappings(authorizer, []models.TcpRouteMapping{*tcpMapping}, routerGroups)
+	if err != nil {
+		handleUnauthorizedError(w, err, log)
+		return false
+	}
+
+	if tcpMapping.Permanent {
+		err = requestUAAClient(h.uaaClient, req).DecodeToken(req.Header.Get("Authorization"), RoutingRoutesPermanentScope)
+		if err != nil {
+			handleUnauthorizedError(w, err, log)
+			return false
+		}
+	}
+
+	apiErr := h.validator.ValidateCreateTcpRouteMapping([]models.TcpRouteMapping{*tcpMapping}, routerGroups, h.ttlPolicy.MaxTTL)
+	if apiErr != nil {
+		handleProcessRequestError(w, apiErr, log)
+		return false
+	}
+	return true
+}
+
+// authorizedForRouterGroup responds with an unauthorized error and returns
+// false if the authorizer does not allow the router group of the mapping.
+func (h *TcpRouteMappingsHandler) authorizedForRouterGroup(w http.ResponseWriter, database db.DB, authorizer *RouterGroupAuthorizer, tcpMapping models.TcpRouteMapping, log lager.Logger) bool {
+	if authorizer.HasGlobalScope() {
+		return true
+	}
+
+	routerGroup, err := database.ReadRouterGroup(tcpMapping.RouterGroupGuid)
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return false
+	}
+	if !authorizer.Authorized(routerGroup.Name) {
+		handleUnauthorizedError(w, authorizer.Err(), log)
+		return false
+	}
+	return true
+}
+
+// readTcpRouteMappingByGuid reads the mapping with the guid in the path. It
+// responds with a 404 and returns false if the mapping does not exist, and
+// with a 501 if the database does not store guids.
+func (h *TcpRouteMappingsHandler) readTcpRouteMappingByGuid(w http.ResponseWriter, req *http.Request, log lager.Logger) (models.TcpRouteMapping, bool) {
+	tcpMapping, err := requestDB(h.db, req).ReadTcpRouteMappingByGuid(rata.Param(req, "guid"))
+	if err == db.ErrGuidsNotSupported {
+		handleNotImplementedError(w, err, log)
+		return models.TcpRouteMapping{}, false
+	}
+	if err != nil {
+		handleDBCommunicationError(w, err, log)
+		return models.TcpRouteMapping{}, false
+	}
+	if tcpMapping == (models.TcpRouteMapping{}) {
+		handleNotFoundError(w, errTcpRouteMappingNotFound, log)
+		return models.TcpRouteMapping{}, false
+	}
+	return tcpMapping, true
+}
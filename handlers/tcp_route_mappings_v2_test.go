@@ -0,0 +1,270 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	routing_api "code.cloudfoundry.org/routing-api"
+	fake_audit "code.cloudfoundry.org/routing-api/audit/fakes"
+	"code.cloudfoundry.org/routing-api/db"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/handlers"
+	fake_validator "code.cloudfoundry.org/routing-api/handlers/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
+	fake_client "code.cloudfoundry.org/uaa-go-client/fakes"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TcpRouteMappingsHandler v2", func() {
+	var (
+		tcpRouteMappingsHandler *handlers.TcpRouteMappingsHandler
+		handler                 http.Handler
+		responseRecorder        *httptest.ResponseRecorder
+		database                *fake_db.FakeDB
+		validator               *fake_validator.FakeRouteValidator
+		fakeClient              *fake_client.FakeClient
+		storedMapping           models.TcpRouteMapping
+	)
+
+	serve := func(method, path, body string) {
+		request, err := http.NewRequest(method, path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		handler.ServeHTTP(responseRecorder, request)
+	}
+
+	groupScopeOnly := func(scope string) {
+		fakeClient.DecodeTokenStub = func(token string, desiredPermissions ...string) error {
+			if len(desiredPermissions) == 1 && desiredPermissions[0] == scope {
+				return nil
+			}
+			return errors.New("Token does not have '" + desiredPermissions[0] + "' scope")
+		}
+	}
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		validator = &fake_validator.FakeRouteValidator{}
+		fakeClient = &fake_client.FakeClient{}
+		tcpRouteMappingsHandler = handlers.NewTcpRouteMappingsHandler(fakeClient, validator, database, models.TTLPolicy{MaxTTL: 120, DefaultTTL: 60}, lagertest.NewTestLogger("routing-api-test"), &fake_audit.FakeRecorder{}, &fake_quota.FakeEnforcer{})
+		responseRecorder = httptest.NewRecorder()
+
+		var err error
+		handler, err = rata.NewRouter(rata.Routes{
+			routing_api.RoutesMap[routing_api.ListTcpRouteMappingsV2],
+			routing_api.RoutesMap[routing_api.CreateTcpRouteMappingV2],
+			routing_api.RoutesMap[routing_api.GetTcpRouteMappingV2],
+			routing_api.RoutesMap[routing_api.UpdateTcpRouteMappingV2],
+			routing_api.RoutesMap[routing_api.DeleteTcpRouteMappingV2],
+		}, rata.Handlers{
+			routing_api.ListTcpRouteMappingsV2:  http.HandlerFunc(tcpRouteMappingsHandler.ListV2),
+			routing_api.CreateTcpRouteMappingV2: http.HandlerFunc(tcpRouteMappingsHandler.CreateV2),
+			routing_api.GetTcpRouteMappingV2:    http.HandlerFunc(tcpRouteMappingsHandler.GetV2),
+			routing_api.UpdateTcpRouteMappingV2: http.HandlerFunc(tcpRouteMappingsHandler.UpdateV2),
+			routing_api.DeleteTcpRouteMappingV2: http.HandlerFunc(tcpRouteMappingsHandler.DeleteV2),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		database.ReadRouterGroupsReturns(models.RouterGroups{
+			{Guid: "router-group-guid-001", Name: "group-1"},
+			{Guid: "router-group-guid-002", Name: "group-2"},
+		}, nil)
+		database.ReadRouterGroupReturns(models.RouterGroup{Guid: "router-group-guid-001", Name: "group-1"}, nil)
+
+		storedMapping = models.NewTcpRouteMapping("router-group-guid-001", 52000, "1.2.3.4", 60000, 60)
+		storedMapping.Guid = "mapping-guid"
+	})
+
+	Describe("ListV2", func() {
+		BeforeEach(func() {
+			other := models.NewTcpRouteMapping("router-group-guid-002", 52001, "1.2.3.5", 60001, 60)
+			other.Guid = "other-guid"
+			database.ReadTcpRouteMappingsReturns([]models.TcpRouteMapping{storedMapping, other}, nil)
+		})
+
+		It("responds with the mappings including their guids", func() {
+			serve("GET", "/routing/v2/tcp_routes", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			var tcpMappings []models.TcpRouteMappingV2
+			Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &tcpMappings)).To(Succeed())
+			Expect(tcpMappings).To(HaveLen(2))
+			Expect(tcpMappings[0].Guid).To(Equal("mapping-guid"))
+		})
+
+		It("responds only with the mappings of the router groups the token can read", func() {
+			groupScopeOnly("routing.routes.group-2.read")
+
+			serve("GET", "/routing/v2/tcp_routes", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			var tcpMappings []models.TcpRouteMappingV2
+			Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &tcpMappings)).To(Succeed())
+			Expect(tcpMappings).To(HaveLen(1))
+			Expect(tcpMappings[0].Guid).To(Equal("other-guid"))
+		})
+	})
+
+	Describe("CreateV2", func() {
+		BeforeEach(func() {
+			database.ReadTcpRouteMappingStub = func(tcpMapping models.TcpRouteMapping) (models.TcpRouteMapping, error) {
+				if database.CreateTcpRouteMappingCallCount() == 0 {
+					return models.TcpRouteMapping{}, nil
+				}
+				return storedMapping, nil
+			}
+		})
+
+		It("saves the mapping and responds with 201 and its location", func() {
+			serve("POST", "/routing/v2/tcp_routes", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+			Expect(responseRecorder.Header().Get("Location")).To(Equal("/routing/v2/tcp_routes/mapping-guid"))
+			Expect(database.CreateTcpRouteMappingCallCount()).To(Equal(1))
+			Expect(*database.CreateTcpRouteMappingArgsForCall(0).TTL).To(Equal(60))
+		})
+
+		It("responds with 401 Unauthorized when the token cannot write to the router group", func() {
+			groupScopeOnly("routing.routes.group-2.write")
+
+			serve("POST", "/routing/v2/tcp_routes", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(database.CreateTcpRouteMappingCallCount()).To(Equal(0))
+		})
+
+		It("responds with 409 Conflict when the mapping already exists", func() {
+			database.ReadTcpRouteMappingStub = nil
+			database.ReadTcpRouteMappingReturns(storedMapping, nil)
+
+			serve("POST", "/routing/v2/tcp_routes", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+			Expect(database.CreateTcpRouteMappingCallCount()).To(Equal(0))
+		})
+
+		It("responds with 409 Conflict when the mapping is created concurrently", func() {
+			database.CreateTcpRouteMappingReturns(db.ErrorConflict)
+
+			serve("POST", "/routing/v2/tcp_routes", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Describe("GetV2", func() {
+		It("responds with the mapping with the guid", func() {
+			database.ReadTcpRouteMappingByGuidReturns(storedMapping, nil)
+
+			serve("GET", "/routing/v2/tcp_routes/mapping-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			var tcpMapping models.TcpRouteMappingV2
+			Expect(json.Unmarshal(responseRecorder.Body.Bytes(), &tcpMapping)).To(Succeed())
+			Expect(tcpMapping).To(Equal(models.NewTcpRouteMappingV2(storedMapping)))
+		})
+
+		It("responds with 401 Unauthorized when the token cannot read the router group", func() {
+			database.ReadTcpRouteMappingByGuidReturns(storedMapping, nil)
+			groupScopeOnly("routing.routes.group-2.read")
+
+			serve("GET", "/routing/v2/tcp_routes/mapping-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("responds with 404 Not Found when the mapping does not exist", func() {
+			serve("GET", "/routing/v2/tcp_routes/unknown", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("responds with 501 Not Implemented when the database does not store guids", func() {
+			database.ReadTcpRouteMappingByGuidReturns(models.TcpRouteMapping{}, db.ErrGuidsNotSupported)
+
+			serve("GET", "/routing/v2/tcp_routes/mapping-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+
+	Describe("UpdateV2", func() {
+		BeforeEach(func() {
+			database.ReadTcpRouteMappingByGuidReturns(storedMapping, nil)
+		})
+
+		It("updates the ttl and responds with the mapping", func() {
+			serve("PUT", "/routing/v2/tcp_routes/mapping-guid", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000, "ttl": 100}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(1))
+			Expect(*database.SaveTcpRouteMappingArgsForCall(0).TTL).To(Equal(100))
+			Expect(database.CreateTcpRouteMappingCallCount()).To(Equal(0))
+		})
+
+		It("keeps the ttl of the mapping when the body leaves it out", func() {
+			ttl := 80
+			storedMapping.TTL = &ttl
+			database.ReadTcpRouteMappingByGuidReturns(storedMapping, nil)
+
+			serve("PUT", "/routing/v2/tcp_routes/mapping-guid", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(1))
+			Expect(*database.SaveTcpRouteMappingArgsForCall(0).TTL).To(Equal(80))
+		})
+
+		It("responds with 400 Bad Request when the body changes the backend", func() {
+			serve("PUT", "/routing/v2/tcp_routes/mapping-guid", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.5", "backend_port": 60000}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(responseRecorder.Body.String()).To(ContainSubstring("cannot be changed"))
+			Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+		})
+
+		It("responds with 401 Unauthorized before looking the mapping up when the token is not valid", func() {
+			fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+
+			serve("PUT", "/routing/v2/tcp_routes/mapping-guid", `{"router_group_guid": "router-group-guid-001", "port": 52000, "backend_ip": "1.2.3.4", "backend_port": 60000, "ttl": 100}`)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(database.ReadTcpRouteMappingByGuidCallCount()).To(Equal(0))
+			Expect(database.SaveTcpRouteMappingCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("DeleteV2", func() {
+		It("deletes the mapping and responds with 204 No Content", func() {
+			database.ReadTcpRouteMappingByGuidReturns(storedMapping, nil)
+
+			serve("DELETE", "/routing/v2/tcp_routes/mapping-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+			Expect(database.DeleteTcpRouteMappingCallCount()).To(Equal(1))
+			Expect(database.DeleteTcpRouteMappingArgsForCall(0).Guid).To(Equal("mapping-guid"))
+		})
+
+		It("responds with 404 Not Found when the mapping does not exist", func() {
+			serve("DELETE", "/routing/v2/tcp_routes/unknown", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			Expect(database.DeleteTcpRouteMappingCallCount()).To(Equal(0))
+		})
+
+		It("responds with 401 Unauthorized before looking the mapping up when the token is not valid", func() {
+			fakeClient.DecodeTokenReturns(errors.New("Not valid"))
+
+			serve("DELETE", "/routing/v2/tcp_routes/mapping-guid", "")
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(database.ReadTcpRouteMappingByGuidCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/tracing"
+	uaaclient "code.cloudfoundry.org/uaa-go-client"
+)
+
+// requestDB is the database of the handlers of req. It records the request id
+// in the events it emits and, when req is traced, records every call as a
+// span.
+func requestDB(database db.DB, req *http.Request) db.DB {
+	database = db.WithRequestID(database, requestID(req))
+	if !tracing.IsTraced(req.Context()) {
+		return database
+	}
+	return db.NewInstrumentedDB(database, tracing.Observer(req.Context(), "db."))
+}
+
+// requestUAAClient records the token decoding of req as a span when req is
+// traced.
+func requestUAAClient(client uaaclient.Client, req *http.Request) uaaclient.Client {
+	if !tracing.IsTraced(req.Context()) {
+		return client
+	}
+	return &tracedUAAClient{Client: client, req: req}
+}
+
+type tracedUAAClient struct {
+	uaaclient.Client
+	req *http.Request
+}
+
+func (c *tracedUAAClient) DecodeToken(uaaToken string, desiredPermissions ...string) error {
+	_, span := tracing.Start(c.req.Context(), "uaa.DecodeToken")
+	err := c.Client.DecodeToken(uaaToken, desiredPermissions...)
+	tracing.SetError(span, err)
+	span.End()
+	return err
+}
@@ -26,92 +26,69 @@ func NewValidator() Validator {
 	return Validator{}
 }
 
+// ValidateCreate validates every route and reports all violations. The
+// message and type of the error are those of the first violation.
 func (v Validator) ValidateCreate(routes []models.Route, maxTTL int) *routing_api.Error {
-	for _, route := range routes {
-		err := requiredValidation(route)
-		if err != nil {
-			return err
-		}
+	var errs violations
+	for i, route := range routes {
+		requiredValidation(&errs, i, route)
 
-		if *route.TTL > maxTTL {
-			err := routing_api.NewError(routing_api.RouteInvalidError, fmt.Sprintf("Max ttl is %d", maxTTL))
-			return &err
+		if *route.TTL == models.PermanentTTL {
+			continue
 		}
 
-		if *route.TTL <= 0 {
-			err := routing_api.NewError(routing_api.RouteInvalidError, "Request requires a ttl greater than 0")
-			return &err
+		if *route.TTL > maxTTL {
+			errs.add(routing_api.RouteInvalidError, i, "ttl", routing_api.OutOfRangeCode, fmt.Sprintf("Max ttl is %d", maxTTL))
+		} else if *route.TTL <= 0 {
+			errs.add(routing_api.RouteInvalidError, i, "ttl", routing_api.OutOfRangeCode, "Request requires a ttl greater than 0")
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 func (v Validator) ValidateDelete(routes []models.Route) *routing_api.Error {
-	for _, route := range routes {
-		err := requiredValidation(route)
-		if err != nil {
-			return err
-		}
+	var errs violations
+	for i, route := range routes {
+		requiredValidation(&errs, i, route)
 	}
-	return nil
+	return errs.err()
 }
 
-func requiredValidation(route models.Route) *routing_api.Error {
-	err := validateRouteUrl(route.Route)
+func requiredValidation(errs *violations, index int, route models.Route) {
+	err := validateUrl(route.Route)
 	if err != nil {
-		return err
+		errs.add(routing_api.RouteInvalidError, index, "route", routing_api.InvalidFieldCode, err.Error())
 	}
 
-	err = validateRouteServiceUrl(route.RouteServiceUrl)
-	if err != nil {
-		return err
-	}
+	validateRouteServiceUrl(errs, index, route.RouteServiceUrl)
 
 	if route.Port <= 0 {
-		err := routing_api.NewError(routing_api.RouteInvalidError, "Each route request requires a port greater than 0")
-		return &err
+		errs.add(routing_api.RouteInvalidError, index, "port", routing_api.OutOfRangeCode, "Each route request requires a port greater than 0")
 	}
 
 	if route.Route == "" {
-		err := routing_api.NewError(routing_api.RouteInvalidError, "Each route request requires a valid route")
-		return &err
+		errs.add(routing_api.RouteInvalidError, index, "route", routing_api.MissingFieldCode, "Each route request requires a valid route")
 	}
 
 	if route.IP == "" {
-		err := routing_api.NewError(routing_api.RouteInvalidError, "Each route request requires an IP")
-		return &err
+		errs.add(routing_api.RouteInvalidError, index, "ip", routing_api.MissingFieldCode, "Each route request requires an IP")
 	}
-
-	return nil
 }
 
-func validateRouteUrl(route string) *routing_api.Error {
-	err := validateUrl(route)
-	if err != nil {
-		err := routing_api.NewError(routing_api.RouteInvalidError, err.Error())
-		return &err
-	}
-
-	return nil
-}
-
-func validateRouteServiceUrl(routeService string) *routing_api.Error {
+func validateRouteServiceUrl(errs *violations, index int, routeService string) {
 	if routeService == "" {
-		return nil
+		return
 	}
 
 	if !strings.HasPrefix(routeService, "https://") {
-		err := routing_api.NewError(routing_api.RouteServiceUrlInvalidError, "Route service url must use HTTPS.")
-		return &err
+		errs.add(routing_api.RouteServiceUrlInvalidError, index, "route_service_url", routing_api.InvalidFieldCode, "Route service url must use HTTPS.")
+		return
 	}
 
 	err := validateUrl(routeService)
 	if err != nil {
-		err := routing_api.NewError(routing_api.RouteServiceUrlInvalidError, err.Error())
-		return &err
+		errs.add(routing_api.RouteServiceUrlInvalidError, index, "route_service_url", routing_api.InvalidFieldCode, err.Error())
 	}
-
-	return nil
 }
 
 func validateUrl(urlToValidate string) error {
@@ -132,76 +109,93 @@ func validateUrl(urlToValidate string) error {
 	return nil
 }
 
+// ValidateCreateTcpRouteMapping validates every mapping and reports all
+// violations. The message of the error is that of the first violation.
 func (v Validator) ValidateCreateTcpRouteMapping(tcpRouteMappings []models.TcpRouteMapping, routerGroups models.RouterGroups, maxTTL int) *routing_api.Error {
-	for _, tcpRouteMapping := range tcpRouteMappings {
-		err := validateTcpRouteMapping(tcpRouteMapping, true, maxTTL)
-		if err != nil {
-			return err
-		}
-
-		validGuid := false
-		for _, routerGroup := range routerGroups {
-			if tcpRouteMapping.RouterGroupGuid == routerGroup.Guid {
-				validGuid = true
-				break
-			}
-		}
-
-		if !validGuid {
-			err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
+	var errs violations
+	defaults := models.TTLPolicy{MaxTTL: maxTTL, DefaultTTL: maxTTL}
+	for i, tcpRouteMapping := range tcpRouteMappings {
+		// router groups can lower the max ttl of their mappings
+		routerGroup, validGuid := FindRouterGroup(routerGroups, tcpRouteMapping.RouterGroupGuid)
+		validateTcpRouteMapping(&errs, i, tcpRouteMapping, true, routerGroup.TTLPolicy(defaults).MaxTTL)
+
+		if !validGuid && tcpRouteMapping.RouterGroupGuid != "" {
+			errs.add(routing_api.TcpRouteMappingInvalidError, i, "router_group_guid", routing_api.NotFoundCode,
 				"router_group_guid: "+tcpRouteMapping.RouterGroupGuid+" not found")
-			return &err
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 func (v Validator) ValidateDeleteTcpRouteMapping(tcpRouteMappings []models.TcpRouteMapping) *routing_api.Error {
-	for _, tcpRouteMapping := range tcpRouteMappings {
-		err := validateTcpRouteMapping(tcpRouteMapping, false, 0)
-		if err != nil {
-			return err
-		}
+	var errs violations
+	for i, tcpRouteMapping := range tcpRouteMappings {
+		validateTcpRouteMapping(&errs, i, tcpRouteMapping, false, 0)
 	}
-	return nil
+	return errs.err()
 }
 
-func validateTcpRouteMapping(tcpRouteMapping models.TcpRouteMapping, checkTTL bool, maxTTL int) *routing_api.Error {
+func validateTcpRouteMapping(errs *violations, index int, tcpRouteMapping models.TcpRouteMapping, checkTTL bool, maxTTL int) {
+	suffix := ". RouteMapping=[" + tcpRouteMapping.String() + "]"
+
 	if tcpRouteMapping.RouterGroupGuid == "" {
-		err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
-			"Each tcp mapping requires a non empty router group guid. RouteMapping=["+tcpRouteMapping.String()+"]")
-		return &err
+		errs.add(routing_api.TcpRouteMappingInvalidError, index, "router_group_guid", routing_api.MissingFieldCode,
+			"Each tcp mapping requires a non empty router group guid"+suffix)
 	}
 
 	if tcpRouteMapping.ExternalPort <= 0 {
-		err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
-			"Each tcp mapping requires a positive external port. RouteMapping=["+tcpRouteMapping.String()+"]")
-		return &err
+		errs.add(routing_api.TcpRouteMappingInvalidError, index, "port", routing_api.OutOfRangeCode,
+			"Each tcp mapping requires a positive external port"+suffix)
 	}
 
 	if tcpRouteMapping.HostIP == "" {
-		err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
-			"Each tcp mapping requires a non empty backend ip. RouteMapping=["+tcpRouteMapping.String()+"]")
-		return &err
+		errs.add(routing_api.TcpRouteMappingInvalidError, index, "backend_ip", routing_api.MissingFieldCode,
+			"Each tcp mapping requires a non empty backend ip"+suffix)
 	}
 
 	if tcpRouteMapping.HostPort <= 0 {
-		err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
-			"Each tcp mapping requires a positive backend port. RouteMapping=["+tcpRouteMapping.String()+"]")
-		return &err
+		errs.add(routing_api.TcpRouteMappingInvalidError, index, "backend_port", routing_api.OutOfRangeCode,
+			"Each tcp mapping requires a positive backend port"+suffix)
 	}
 
-	if checkTTL && *tcpRouteMapping.TTL > maxTTL {
-		err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
-			"Each tcp mapping requires TTL to be less than or equal to "+strconv.Itoa(int(maxTTL))+". RouteMapping=["+tcpRouteMapping.String()+"]")
-		return &err
+	if !checkTTL || *tcpRouteMapping.TTL == models.PermanentTTL {
+		return
 	}
 
-	if checkTTL && *tcpRouteMapping.TTL <= 0 {
-		err := routing_api.NewError(routing_api.TcpRouteMappingInvalidError,
+	if *tcpRouteMapping.TTL > maxTTL {
+		errs.add(routing_api.TcpRouteMappingInvalidError, index, "ttl", routing_api.OutOfRangeCode,
+			"Each tcp mapping requires TTL to be less than or equal to "+strconv.Itoa(int(maxTTL))+suffix)
+	} else if *tcpRouteMapping.TTL <= 0 {
+		errs.add(routing_api.TcpRouteMappingInvalidError, index, "ttl", routing_api.OutOfRangeCode,
 			"Each tcp route mapping requires a ttl greater than 0")
-		return &err
 	}
+}
 
-	return nil
+// violations collects the violations of the items of a request.
+type violations struct {
+	first   *routing_api.Error
+	details []routing_api.ErrorDetail
+}
+
+func (v *violations) add(errType routing_api.Type, index int, field string, code routing_api.DetailCode, message string) {
+	if v.first == nil {
+		err := routing_api.NewError(errType, message)
+		v.first = &err
+	}
+	v.details = append(v.details, routing_api.ErrorDetail{
+		Index:   index,
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// err returns nil if there were no violations, and otherwise an error with
+// the type and message of the first violation and the details of all.
+func (v *violations) err() *routing_api.Error {
+	if v.first == nil {
+		return nil
+	}
+	v.first.Details = v.details
+	return v.first
 }
@@ -32,6 +32,12 @@ var _ = Describe("Validator", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("does not return an error for permanent routes", func() {
+			*routes[0].TTL = models.PermanentTTL
+			err := validator.ValidateCreate(routes, maxTTL)
+			Expect(err).To(BeNil())
+		})
+
 		Context("when any route has an invalid value", func() {
 			BeforeEach(func() {
 				routes = append(routes, routes[0])
@@ -166,6 +172,23 @@ var _ = Describe("Validator", func() {
 				Expect(err.Type).To(Equal(routing_api.RouteInvalidError))
 				Expect(err.Error()).To(Equal("Each route request requires an IP"))
 			})
+
+			It("reports every violation with the index and field of the route", func() {
+				routes[0] = models.NewRoute("", 0, "127.0.0.1", "log_guid", "http://my-rs.com", maxTTL)
+				routes[1] = models.NewRoute("http://127.0.0.1/a/valid/route", 8080, "", "log_guid", "", maxTTL+1)
+
+				err := validator.ValidateCreate(routes, maxTTL)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Type).To(Equal(routing_api.RouteServiceUrlInvalidError))
+				Expect(err.Error()).To(Equal("Route service url must use HTTPS."))
+				Expect(err.Details).To(Equal([]routing_api.ErrorDetail{
+					{Index: 0, Field: "route_service_url", Code: routing_api.InvalidFieldCode, Message: "Route service url must use HTTPS."},
+					{Index: 0, Field: "port", Code: routing_api.OutOfRangeCode, Message: "Each route request requires a port greater than 0"},
+					{Index: 0, Field: "route", Code: routing_api.MissingFieldCode, Message: "Each route request requires a valid route"},
+					{Index: 1, Field: "ip", Code: routing_api.MissingFieldCode, Message: "Each route request requires an IP"},
+					{Index: 1, Field: "ttl", Code: routing_api.OutOfRangeCode, Message: fmt.Sprintf("Max ttl is %d", maxTTL)},
+				}))
+			})
 		})
 	})
 
@@ -231,6 +254,14 @@ var _ = Describe("Validator", func() {
 			})
 		})
 
+		Context("when a permanent tcp mapping is passed", func() {
+			It("does not return error", func() {
+				*tcpMapping.TTL = models.PermanentTTL
+				err := validator.ValidateCreateTcpRouteMapping([]models.TcpRouteMapping{tcpMapping}, routerGroups, 120)
+				Expect(err).To(BeNil())
+			})
+		})
+
 		Context("when invalid tcp route mappings are passed", func() {
 
 			It("blows up when a backend port is zero", func() {
@@ -288,6 +319,39 @@ var _ = Describe("Validator", func() {
 				Expect(err.Type).To(Equal(routing_api.TcpRouteMappingInvalidError))
 				Expect(err.Error()).To(ContainSubstring("Each tcp route mapping requires a ttl greater than 0"))
 			})
+
+			It("reports every violation with the index and field of the mapping", func() {
+				otherMapping := models.NewTcpRouteMapping("unknown-router-group-guid", 0, "1.2.3.4", 60000, 60)
+				tcpMapping.HostIP = ""
+
+				err := validator.ValidateCreateTcpRouteMapping([]models.TcpRouteMapping{tcpMapping, otherMapping}, routerGroups, 120)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("Each tcp mapping requires a non empty backend ip"))
+
+				var fields []string
+				for _, detail := range err.Details {
+					fields = append(fields, fmt.Sprintf("%d:%s:%s", detail.Index, detail.Field, detail.Code))
+				}
+				Expect(fields).To(Equal([]string{
+					"0:backend_ip:missing",
+					"1:port:out_of_range",
+					"1:router_group_guid:not_found",
+				}))
+			})
+		})
+
+		Context("when the router group has a max ttl", func() {
+			It("validates the TTL against the max ttl of the router group", func() {
+				routerGroups[0].MaxTTL = 300
+				*tcpMapping.TTL = 200
+				err := validator.ValidateCreateTcpRouteMapping([]models.TcpRouteMapping{tcpMapping}, routerGroups, 120)
+				Expect(err).To(BeNil())
+
+				*tcpMapping.TTL = 301
+				err = validator.ValidateCreateTcpRouteMapping([]models.TcpRouteMapping{tcpMapping}, routerGroups, 120)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("Each tcp mapping requires TTL to be less than or equal to 300"))
+			})
 		})
 	})
 
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/audit"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// The writes below are shared by the HTTP API and the gRPC API, which only
+// differ in how they decode requests and report errors.
+
+// SetRouteDefaults sets the default ttl and the owner of routes. It reports
+// whether any of them is permanent, which requires
+// RoutingRoutesPermanentScope.
+func SetRouteDefaults(routes []models.Route, defaultTTL int, owner string) bool {
+	permanent := false
+	for i := 0; i < len(routes); i++ {
+		routes[i].SetDefaults(defaultTTL)
+		routes[i].Owner = owner
+		permanent = permanent || routes[i].Permanent
+	}
+	return permanent
+}
+
+// SetTcpRouteMappingDefaults sets the defaults and owner of tcpMappings, using
+// the ttl policy of the router group of each mapping, see SetRouteDefaults.
+func SetTcpRouteMappingDefaults(tcpMappings []models.TcpRouteMapping, routerGroups models.RouterGroups, ttlPolicy models.TTLPolicy, owner string) bool {
+	permanent := false
+	for i := 0; i < len(tcpMappings); i++ {
+		policy := ttlPolicy
+		if group, ok := FindRouterGroup(routerGroups, tcpMappings[i].RouterGroupGuid); ok {
+			policy = group.TTLPolicy(ttlPolicy)
+		}
+		tcpMappings[i].SetDefaults(policy.DefaultTTL)
+		tcpMappings[i].Owner = owner
+		permanent = permanent || tcpMappings[i].Permanent
+	}
+	return permanent
+}
+
+// PlanRouteUpserts returns the changes registering routes would make, see
+// PlanRouteUpsert.
+func PlanRouteUpserts(database db.DB, routes []models.Route, ifMatch *models.ModificationTag, conditional bool) ([]models.RouteChange, error) {
+	changes := []models.RouteChange{}
+	for _, route := range routes {
+		change, err := PlanRouteUpsert(database, route, ExpectedTag(ifMatch, route.ModificationTag, conditional))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// PlanRouteDeletes returns the changes deleting or draining routes would
+// make, see PlanRouteDelete.
+func PlanRouteDeletes(database db.DB, routes []models.Route, drainTTL int, ifMatch *models.ModificationTag, conditional bool) ([]models.RouteChange, error) {
+	changes := []models.RouteChange{}
+	for _, route := range routes {
+		change, err := PlanRouteDelete(database, route, drainTTL, ExpectedTag(ifMatch, route.ModificationTag, conditional))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// PlanTcpRouteMappingUpserts returns the changes registering tcpMappings would
+// make, see PlanTcpRouteMappingUpsert.
+func PlanTcpRouteMappingUpserts(database db.DB, tcpMappings []models.TcpRouteMapping, ifMatch *models.ModificationTag, conditional bool) ([]models.TcpRouteMappingChange, error) {
+	changes := []models.TcpRouteMappingChange{}
+	for _, tcpMapping := range tcpMappings {
+		change, err := PlanTcpRouteMappingUpsert(database, tcpMapping, ExpectedTag(ifMatch, tcpMapping.ModificationTag, conditional))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// PlanTcpRouteMappingDeletes returns the changes deleting or draining
+// tcpMappings would make, see PlanTcpRouteMappingDelete.
+func PlanTcpRouteMappingDeletes(database db.DB, tcpMappings []models.TcpRouteMapping, drainTTL int, ifMatch *models.ModificationTag, conditional bool) ([]models.TcpRouteMappingChange, error) {
+	changes := []models.TcpRouteMappingChange{}
+	for _, tcpMapping := range tcpMappings {
+		change, err := PlanTcpRouteMappingDelete(database, tcpMapping, drainTTL, ExpectedTag(ifMatch, tcpMapping.ModificationTag, conditional))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// UpsertRoutes saves routes, each conditional on the tag ExpectedTag returns
+// for it, and records an audit record for every route saved. It stops at the
+// first failed write, or with the error of ctx once ctx is done.
+func UpsertRoutes(ctx context.Context, database db.DB, auditor audit.Recorder, auditCtx AuditContext, routes []models.Route, ifMatch *models.ModificationTag, conditional bool, log lager.Logger) error {
+	for _, route := range routes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		before := currentRoute(database, auditor, route, log)
+		var err error
+		if expected := ExpectedTag(ifMatch, route.ModificationTag, conditional); expected != nil {
+			err = database.SaveRouteIfMatch(route, *expected)
+		} else {
+			err = database.SaveRoute(route)
+		}
+		if err != nil {
+			return err
+		}
+		auditor.Record(auditCtx.NewRecord(models.AuditActionUpsert, models.AuditKindHttpRoute, route.AuditKey(), before, route))
+	}
+	return nil
+}
+
+// DeleteRoutes deletes routes, or drains them for a positive drainTTL, see
+// UpsertRoutes. Routes that do not exist are skipped.
+func DeleteRoutes(ctx context.Context, database db.DB, auditor audit.Recorder, auditCtx AuditContext, routes []models.Route, drainTTL int, ifMatch *models.ModificationTag, conditional bool, log lager.Logger) error {
+	action := models.AuditActionDelete
+	if drainTTL > 0 {
+		action = models.AuditActionDrain
+	}
+
+	for _, route := range routes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		before := currentRoute(database, auditor, route, log)
+		expected := ExpectedTag(ifMatch, route.ModificationTag, conditional)
+		var err error
+		switch {
+		case drainTTL > 0 && expected != nil:
+			err = database.DrainRouteIfMatch(route, drainTTL, *expected)
+		case drainTTL > 0:
+			err = database.DrainRoute(route, drainTTL)
+		case expected != nil:
+			err = database.DeleteRouteIfMatch(route, *expected)
+		default:
+			err = database.DeleteRoute(route)
+		}
+		if err != nil {
+			if keyNotFound(err) {
+				continue
+			}
+			return err
+		}
+		auditor.Record(auditCtx.NewRecord(action, models.AuditKindHttpRoute, route.AuditKey(), before, nil))
+	}
+	return nil
+}
+
+// UpsertTcpRouteMappings saves tcpMappings, see UpsertRoutes.
+func UpsertTcpRouteMappings(ctx context.Context, database db.DB, auditor audit.Recorder, auditCtx AuditContext, tcpMappings []models.TcpRouteMapping, ifMatch *models.ModificationTag, conditional bool, log lager.Logger) error {
+	for _, tcpMapping := range tcpMappings {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		before := currentTcpRouteMapping(database, auditor, tcpMapping, log)
+		var err error
+		if expected := ExpectedTag(ifMatch, tcpMapping.ModificationTag, conditional); expected != nil {
+			err = database.SaveTcpRouteMappingIfMatch(tcpMapping, *expected)
+		} else {
+			err = database.SaveTcpRouteMapping(tcpMapping)
+		}
+		if err != nil {
+			return err
+		}
+		auditor.Record(auditCtx.NewRecord(models.AuditActionUpsert, models.AuditKindTcpRoute, tcpMapping.AuditKey(), before, tcpMapping))
+	}
+	return nil
+}
+
+// DeleteTcpRouteMappings deletes or drains tcpMappings, see DeleteRoutes.
+func DeleteTcpRouteMappings(ctx context.Context, database db.DB, auditor audit.Recorder, auditCtx AuditContext, tcpMappings []models.TcpRouteMapping, drainTTL int, ifMatch *models.ModificationTag, conditional bool, log lager.Logger) error {
+	action := models.AuditActionDelete
+	if drainTTL > 0 {
+		action = models.AuditActionDrain
+	}
+
+	for _, tcpMapping := range tcpMappings {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		before := currentTcpRouteMapping(database, auditor, tcpMapping, log)
+		expected := ExpectedTag(ifMatch, tcpMapping.ModificationTag, conditional)
+		var err error
+		switch {
+		case drainTTL > 0 && expected != nil:
+			err = database.DrainTcpRouteMappingIfMatch(tcpMapping, drainTTL, *expected)
+		case drainTTL > 0:
+			err = database.DrainTcpRouteMapping(tcpMapping, drainTTL)
+		case expected != nil:
+			err = database.DeleteTcpRouteMappingIfMatch(tcpMapping, *expected)
+		default:
+			err = database.DeleteTcpRouteMapping(tcpMapping)
+		}
+		if err != nil {
+			if keyNotFound(err) {
+				continue
+			}
+			return err
+		}
+		auditor.Record(auditCtx.NewRecord(action, models.AuditKindTcpRoute, tcpMapping.AuditKey(), before, nil))
+	}
+	return nil
+}
+
+// RouterGroupUpdate is the body of a router group update. Only the reservable
+// ports and the TTL overrides are updated. A TTL that is left out keeps its
+// override and a TTL of 0 clears it, so that the configured policy applies.
+type RouterGroupUpdate struct {
+	models.RouterGroup
+	MaxTTL     *int `json:"max_ttl,omitempty"`
+	DefaultTTL *int `json:"default_ttl,omitempty"`
+}
+
+// MergeRouterGroup applies the fields set in update to rg. It reports
+// whether rg changed.
+func MergeRouterGroup(rg *models.RouterGroup, update RouterGroupUpdate) bool {
+	changed := false
+	if update.ReservablePorts != "" && rg.ReservablePorts != update.ReservablePorts {
+		rg.ReservablePorts = update.ReservablePorts
+		changed = true
+	}
+	if update.MaxTTL != nil && rg.MaxTTL != *update.MaxTTL {
+		rg.MaxTTL = *update.MaxTTL
+		changed = true
+	}
+	if update.DefaultTTL != nil && rg.DefaultTTL != *update.DefaultTTL {
+		rg.DefaultTTL = *update.DefaultTTL
+		changed = true
+	}
+	return changed
+}
+
+// ValidateRouterGroup validates a router group that is about to be saved,
+// including its ttl overrides against maxTTL.
+func ValidateRouterGroup(rg models.RouterGroup, maxTTL int) error {
+	err := rg.Validate()
+	if err != nil {
+		return err
+	}
+	return rg.ValidateTTLs(maxTTL)
+}
+
+// SaveRouterGroup saves the router group updated from before to after and
+// records an audit record for it.
+func SaveRouterGroup(database db.DB, auditor audit.Recorder, auditCtx AuditContext, before, after models.RouterGroup) error {
+	err := database.SaveRouterGroup(after)
+	if err != nil {
+		return err
+	}
+	auditor.Record(auditCtx.NewRecord(models.AuditActionUpdateRouterGroup, models.AuditKindRouterGroup, after.AuditKey(), before, after))
+	return nil
+}
+
+// currentRoute returns the stored route to be recorded as the before value of
+// a mutation, or nil if it does not exist or auditing is disabled.
+func currentRoute(database db.DB, auditor audit.Recorder, route models.Route, log lager.Logger) interface{} {
+	if !auditor.Enabled() {
+		return nil
+	}
+
+	existing, err := database.ReadRoute(route)
+	if err != nil {
+		log.Error("failed-to-read-route-for-audit", err)
+		return nil
+	}
+	if existing == (models.Route{}) {
+		return nil
+	}
+	return existing
+}
+
+// currentTcpRouteMapping returns the stored mapping to be recorded as the
+// before value of a mutation, or nil if it does not exist or auditing is
+// disabled.
+func currentTcpRouteMapping(database db.DB, auditor audit.Recorder, tcpMapping models.TcpRouteMapping, log lager.Logger) interface{} {
+	if !auditor.Enabled() {
+		return nil
+	}
+
+	existing, err := database.ReadTcpRouteMapping(tcpMapping)
+	if err != nil {
+		log.Error("failed-to-read-tcp-route-mapping-for-audit", err)
+		return nil
+	}
+	if existing == (models.TcpRouteMapping{}) {
+		return nil
+	}
+	return existing
+}
+
+func keyNotFound(err error) bool {
+	dberr, ok := err.(db.DBError)
+	return ok && dberr.Type == db.KeyNotFound
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	LivenessPath  = "/health"
+	ReadinessPath = "/ready"
+)
+
+// LivenessHandler responds with a 200 as long as the process serves requests.
+func LivenessHandler(logger lager.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"}, logger)
+	})
+}
+
+// ReadinessHandler responds with a 200 when every check passes and a 503
+// otherwise. The body has the result of every check only with details, so
+// that the breakdown can be limited to the admin listener.
+func (c *Checker) ReadinessHandler(details bool, logger lager.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := c.Report()
+		status := http.StatusOK
+		if !report.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		if !details {
+			report.Checks = nil
+		}
+		writeJSON(w, status, report, logger)
+	})
+}
+
+// AdminHandler serves the liveness and the detailed readiness of the routing
+// API without authentication.
+func (c *Checker) AdminHandler(logger lager.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(LivenessPath, LivenessHandler(logger))
+	mux.Handle(ReadinessPath, c.ReadinessHandler(true, logger))
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}, logger lager.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		logger.Error("failed-to-write-health", err)
+	}
+}
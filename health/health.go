@@ -0,0 +1,166 @@
+// Package health reports the liveness and readiness of the routing API.
+package health
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+// Check returns an error while a component of the routing API is not ready.
+type Check func() error
+
+// Checker runs the named checks that make up the readiness of the routing
+// API.
+type Checker struct {
+	mutex  sync.RWMutex
+	names  []string
+	checks map[string]Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: map[string]Check{}}
+}
+
+// Add adds a check. Checks run in the order they are added.
+func (c *Checker) Add(name string, check Check) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Report is the readiness of the routing API and of each of its checks.
+type Report struct {
+	Ready  bool                   `json:"ready"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type CheckResult struct {
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+// Report runs every check. The routing API is ready when all of them pass.
+func (c *Checker) Report() Report {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	report := Report{Ready: true, Checks: map[string]CheckResult{}}
+	for _, name := range c.names {
+		result := CheckResult{Ready: true}
+		if err := c.checks[name](); err != nil {
+			result = CheckResult{Error: err.Error()}
+			report.Ready = false
+		}
+		report.Checks[name] = result
+	}
+	return report
+}
+
+// Condition is a check that passes while it is set.
+type Condition struct {
+	mutex  sync.RWMutex
+	set    bool
+	reason error
+}
+
+// NewCondition returns an unset condition. Its check fails with reason until
+// it is set.
+func NewCondition(reason string) *Condition {
+	return &Condition{reason: errors.New(reason)}
+}
+
+func (c *Condition) Set() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.set = true
+}
+
+func (c *Condition) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.set = false
+}
+
+func (c *Condition) Check() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if !c.set {
+		return c.reason
+	}
+	return nil
+}
+
+// Track sets condition from the moment runner is ready until it exits, e.g.
+// while a lock runner holds its lock.
+func Track(runner ifrit.Runner, condition *Condition) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		runnerReady := make(chan struct{})
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- runner.Run(signals, runnerReady)
+		}()
+
+		select {
+		case <-runnerReady:
+			condition.Set()
+			close(ready)
+		case err := <-errChan:
+			return err
+		}
+
+		err := <-errChan
+		condition.Clear()
+		return err
+	})
+}
+
+// Poller runs a check every interval and sets its condition while the check
+// passes, for checks too slow to run on every readiness request, e.g. ones
+// that call another service.
+type Poller struct {
+	check     Check
+	condition *Condition
+	interval  time.Duration
+	clock     clock.Clock
+	logger    lager.Logger
+}
+
+func NewPoller(check Check, condition *Condition, interval time.Duration, clock clock.Clock, logger lager.Logger) *Poller {
+	return &Poller{
+		check:     check,
+		condition: condition,
+		interval:  interval,
+		clock:     clock,
+		logger:    logger,
+	}
+}
+
+func (p *Poller) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C():
+			err := p.check()
+			if err != nil {
+				p.logger.Error("check-failed", err)
+				p.condition.Clear()
+				continue
+			}
+			p.condition.Set()
+		case <-signals:
+			return nil
+		}
+	}
+}
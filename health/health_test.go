@@ -0,0 +1,173 @@
+package health_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/health"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		checker   *health.Checker
+		condition *health.Condition
+		dbErr     error
+		logger    *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("health-test")
+		dbErr = nil
+		condition = health.NewCondition("migration has not finished")
+		checker = health.NewChecker()
+		checker.Add("database", func() error { return dbErr })
+		checker.Add("migration", condition.Check)
+	})
+
+	Describe("Report", func() {
+		It("is not ready while a check fails", func() {
+			Expect(checker.Report()).To(Equal(health.Report{
+				Ready: false,
+				Checks: map[string]health.CheckResult{
+					"database":  {Ready: true},
+					"migration": {Error: "migration has not finished"},
+				},
+			}))
+		})
+
+		It("is ready when every check passes", func() {
+			condition.Set()
+			report := checker.Report()
+			Expect(report.Ready).To(BeTrue())
+			Expect(report.Checks["migration"]).To(Equal(health.CheckResult{Ready: true}))
+		})
+
+		It("reports the error of a check", func() {
+			condition.Set()
+			dbErr = errors.New("connection refused")
+			report := checker.Report()
+			Expect(report.Ready).To(BeFalse())
+			Expect(report.Checks["database"]).To(Equal(health.CheckResult{Error: "connection refused"}))
+		})
+	})
+
+	Describe("AdminHandler", func() {
+		var responseRecorder *httptest.ResponseRecorder
+
+		serve := func(path string) {
+			request, err := http.NewRequest("GET", path, nil)
+			Expect(err).NotTo(HaveOccurred())
+			responseRecorder = httptest.NewRecorder()
+			checker.AdminHandler(logger).ServeHTTP(responseRecorder, request)
+		}
+
+		It("serves the liveness", func() {
+			serve("/health")
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`{"status":"ok"}`))
+		})
+
+		It("serves the readiness with the result of every check", func() {
+			serve("/ready")
+			Expect(responseRecorder.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`{
+				"ready": false,
+				"checks": {
+					"database": {"ready": true},
+					"migration": {"ready": false, "error": "migration has not finished"}
+				}
+			}`))
+
+			condition.Set()
+			serve("/ready")
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("ReadinessHandler", func() {
+		It("omits the checks without details", func() {
+			request, err := http.NewRequest("GET", "/ready", nil)
+			Expect(err).NotTo(HaveOccurred())
+			responseRecorder := httptest.NewRecorder()
+			checker.ReadinessHandler(false, logger).ServeHTTP(responseRecorder, request)
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(responseRecorder.Body.String()).To(MatchJSON(`{"ready":false}`))
+		})
+	})
+})
+
+var _ = Describe("Track", func() {
+	var (
+		condition *health.Condition
+		runReady  chan struct{}
+		exit      chan error
+		process   ifrit.Process
+	)
+
+	BeforeEach(func() {
+		condition = health.NewCondition("lock is not held")
+		runReady = make(chan struct{})
+		exit = make(chan error)
+		runner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			<-runReady
+			close(ready)
+			return <-exit
+		})
+		process = ifrit.Background(health.Track(runner, condition))
+	})
+
+	It("sets the condition while the runner is ready", func() {
+		Consistently(condition.Check).Should(HaveOccurred())
+
+		close(runReady)
+		Eventually(process.Ready()).Should(BeClosed())
+		Expect(condition.Check()).To(Succeed())
+
+		exit <- errors.New("lost lock")
+		Eventually(process.Wait()).Should(Receive(MatchError("lost lock")))
+		Expect(condition.Check()).To(MatchError("lock is not held"))
+	})
+})
+
+var _ = Describe("Poller", func() {
+	var (
+		condition *health.Condition
+		checkErr  error
+		clock     *fakeclock.FakeClock
+		process   ifrit.Process
+	)
+
+	BeforeEach(func() {
+		condition = health.NewCondition("verification key is not loaded")
+		condition.Set()
+		checkErr = nil
+		clock = fakeclock.NewFakeClock(time.Now())
+		check := func() error { return checkErr }
+		process = ifrit.Invoke(health.NewPoller(check, condition, time.Minute, clock, lagertest.NewTestLogger("poller")))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	It("clears the condition while the check fails", func() {
+		checkErr = errors.New("connection refused")
+		clock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(condition.Check).Should(MatchError("verification key is not loaded"))
+
+		checkErr = nil
+		clock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(condition.Check).Should(Succeed())
+	})
+})
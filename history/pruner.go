@@ -0,0 +1,52 @@
+package history
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+)
+
+// Pruner periodically deletes route versions older than the retention
+// period. The recorder only caps the number of versions per key, which never
+// removes the history of routes that were deleted or expired.
+type Pruner struct {
+	database  db.DB
+	retention time.Duration
+	interval  time.Duration
+	clock     clock.Clock
+	logger    lager.Logger
+}
+
+func NewPruner(database db.DB, retention, interval time.Duration, clock clock.Clock, logger lager.Logger) *Pruner {
+	return &Pruner{
+		database:  database,
+		retention: retention,
+		interval:  interval,
+		clock:     clock,
+		logger:    logger,
+	}
+}
+
+func (p *Pruner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C():
+			olderThan := p.clock.Now().Add(-p.retention)
+			err := p.database.PruneRouteVersions(olderThan)
+			if err != nil {
+				p.logger.Error("failed-to-prune-route-versions", err)
+				continue
+			}
+			p.logger.Debug("pruned-route-versions", lager.Data{"older_than": olderThan})
+		case <-signals:
+			return nil
+		}
+	}
+}
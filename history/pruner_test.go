@@ -0,0 +1,65 @@
+package history_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/history"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Pruner", func() {
+	var (
+		database  *fake_db.FakeDB
+		fakeClock *fakeclock.FakeClock
+		logger    *lagertest.TestLogger
+		process   ifrit.Process
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		now = time.Unix(100000, 0)
+		fakeClock = fakeclock.NewFakeClock(now)
+		logger = lagertest.NewTestLogger("history-test")
+	})
+
+	JustBeforeEach(func() {
+		pruner := history.NewPruner(database, time.Hour, time.Minute, fakeClock, logger)
+		process = ifrit.Invoke(pruner)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("prunes versions older than the retention on every interval", func() {
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(database.PruneRouteVersionsCallCount).Should(Equal(1))
+		Expect(database.PruneRouteVersionsArgsForCall(0)).To(Equal(now.Add(time.Minute).Add(-time.Hour)))
+
+		fakeClock.Increment(time.Minute)
+		Eventually(database.PruneRouteVersionsCallCount).Should(Equal(2))
+	})
+
+	Context("when pruning fails", func() {
+		BeforeEach(func() {
+			database.PruneRouteVersionsReturns(errors.New("stuff broke"))
+		})
+
+		It("logs the error and keeps running", func() {
+			fakeClock.WaitForWatcherAndIncrement(time.Minute)
+			Eventually(logger).Should(gbytes.Say("failed-to-prune-route-versions"))
+			Consistently(process.Wait()).ShouldNot(Receive())
+		})
+	})
+})
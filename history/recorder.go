@@ -0,0 +1,129 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// Recorder records a version of every http route and tcp route mapping that
+// is created, deleted or expires, or whose TTL, log guid or route service url
+// changes. Updates that only refresh the TTL of a route are not recorded, so
+// that registration heartbeats do not push older versions out of the history.
+type Recorder struct {
+	database    db.DB
+	maxVersions int
+	clock       clock.Clock
+	logger      lager.Logger
+	latest      map[string]models.RouteVersion
+}
+
+func NewRecorder(database db.DB, maxVersions int, clock clock.Clock, logger lager.Logger) *Recorder {
+	return &Recorder{
+		database:    database,
+		maxVersions: maxVersions,
+		clock:       clock,
+		logger:      logger,
+		latest:      map[string]models.RouteVersion{},
+	}
+}
+
+func (r *Recorder) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	httpEventChan, httpErrChan, httpCancel := r.database.WatchChanges(db.HTTP_WATCH)
+	tcpEventChan, tcpErrChan, tcpCancel := r.database.WatchChanges(db.TCP_WATCH)
+	defer httpCancel()
+	defer tcpCancel()
+	close(ready)
+
+	for {
+		select {
+		case event, ok := <-httpEventChan:
+			if !ok {
+				return nil
+			}
+			if event.Type == db.ResyncEvent {
+				r.resync(event)
+				continue
+			}
+			var route models.Route
+			err := json.Unmarshal([]byte(event.Value), &route)
+			if err != nil {
+				r.logger.Error("failed-to-unmarshal-route", err, lager.Data{"value": event.Value})
+				continue
+			}
+			r.record(event.Type, models.NewRouteVersion(versionAction(event.Type), route))
+		case event, ok := <-tcpEventChan:
+			if !ok {
+				return nil
+			}
+			if event.Type == db.ResyncEvent {
+				r.resync(event)
+				continue
+			}
+			var tcpMapping models.TcpRouteMapping
+			err := json.Unmarshal([]byte(event.Value), &tcpMapping)
+			if err != nil {
+				r.logger.Error("failed-to-unmarshal-tcp-route-mapping", err, lager.Data{"value": event.Value})
+				continue
+			}
+			r.record(event.Type, models.NewTcpRouteMappingVersion(versionAction(event.Type), tcpMapping))
+		case err := <-httpErrChan:
+			return err
+		case err := <-tcpErrChan:
+			return err
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// record persists the version unless it is an update that does not change the
+// last version recorded by this recorder. Failing to record a version is
+// logged and does not stop the recorder.
+func (r *Recorder) record(eventType db.EventType, version models.RouteVersion) {
+	id := version.Kind + "|" + version.Key
+	if eventType == db.UpdateEvent {
+		if latest, ok := r.latest[id]; ok && latest.SameState(version) {
+			return
+		}
+	}
+
+	version.Time = r.clock.Now()
+	err := r.database.SaveRouteVersion(version, r.maxVersions)
+	if err != nil {
+		r.logger.Error("failed-to-save-route-version", err, lager.Data{"version": version})
+		return
+	}
+
+	if eventType == db.DeleteEvent || eventType == db.ExpireEvent {
+		delete(r.latest, id)
+	} else {
+		r.latest[id] = version
+	}
+}
+
+// resync forgets the last versions recorded after the recorder lost events.
+// The versions of the lost events are not recorded.
+func (r *Recorder) resync(event db.Event) {
+	r.logger.Info("events-dropped", lager.Data{"event": event.Value})
+	r.latest = map[string]models.RouteVersion{}
+}
+
+func versionAction(eventType db.EventType) string {
+	switch eventType {
+	case db.CreateEvent:
+		return models.RouteVersionCreated
+	case db.DeleteEvent:
+		return models.RouteVersionDeleted
+	case db.ExpireEvent:
+		return models.RouteVersionExpired
+	case db.DrainEvent:
+		return models.RouteVersionDrained
+	default:
+		return models.RouteVersionUpdated
+	}
+}
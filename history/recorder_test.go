@@ -0,0 +1,150 @@
+package history_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/db"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/history"
+	"code.cloudfoundry.org/routing-api/models"
+	"github.com/coreos/etcd/Godeps/_workspace/src/golang.org/x/net/context"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		database   *fake_db.FakeDB
+		fakeClock  *fakeclock.FakeClock
+		now        time.Time
+		httpEvents chan db.Event
+		tcpEvents  chan db.Event
+		recorder   *history.Recorder
+		process    ifrit.Process
+	)
+
+	newEvent := func(eventType db.EventType, obj interface{}) db.Event {
+		event, err := db.NewEventFromInterface(eventType, obj)
+		Expect(err).NotTo(HaveOccurred())
+		return event
+	}
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		now = time.Unix(1500000000, 0)
+		fakeClock = fakeclock.NewFakeClock(now)
+		httpEvents = make(chan db.Event)
+		tcpEvents = make(chan db.Event)
+		database.WatchChangesStub = func(watchType string) (<-chan db.Event, <-chan error, context.CancelFunc) {
+			if watchType == db.HTTP_WATCH {
+				return httpEvents, nil, func() {}
+			}
+			return tcpEvents, nil, func() {}
+		}
+
+		logger := lagertest.NewTestLogger("history-test")
+		recorder = history.NewRecorder(database, 5, fakeClock, logger)
+		process = ifrit.Invoke(recorder)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("records created http routes", func() {
+		route := models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "https://rs.example.com", 60)
+		route.ModificationTag = models.ModificationTag{Guid: "abc", Index: 0}
+		httpEvents <- newEvent(db.CreateEvent, route)
+
+		Eventually(database.SaveRouteVersionCallCount).Should(Equal(1))
+		version, maxVersions := database.SaveRouteVersionArgsForCall(0)
+		Expect(maxVersions).To(Equal(5))
+		Expect(version.Kind).To(Equal(models.HistoryKindHttpRoute))
+		Expect(version.Key).To(Equal("a.example.com,1.2.3.4:8080"))
+		Expect(version.Action).To(Equal(models.RouteVersionCreated))
+		Expect(version.Time).To(Equal(now))
+		Expect(version.ModificationTag).To(Equal(route.ModificationTag))
+		Expect(version.GetTTL()).To(Equal(60))
+		Expect(version.LogGuid).To(Equal("log-guid"))
+		Expect(version.RouteServiceUrl).To(Equal("https://rs.example.com"))
+	})
+
+	It("records expired tcp route mappings", func() {
+		tcpEvents <- newEvent(db.ExpireEvent, models.NewTcpRouteMapping("rg-guid", 52000, "1.2.3.4", 60000, 60))
+
+		Eventually(database.SaveRouteVersionCallCount).Should(Equal(1))
+		version, _ := database.SaveRouteVersionArgsForCall(0)
+		Expect(version.Kind).To(Equal(models.HistoryKindTcpRoute))
+		Expect(version.Key).To(Equal("rg-guid/52000/1.2.3.4:60000"))
+		Expect(version.Action).To(Equal(models.RouteVersionExpired))
+	})
+
+	Context("when a route is updated", func() {
+		var route models.Route
+
+		BeforeEach(func() {
+			route = models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60)
+			route.ModificationTag = models.ModificationTag{Guid: "abc", Index: 0}
+			httpEvents <- newEvent(db.CreateEvent, route)
+			Eventually(database.SaveRouteVersionCallCount).Should(Equal(1))
+		})
+
+		It("does not record updates that only refresh the route", func() {
+			route.ModificationTag.Increment()
+			httpEvents <- newEvent(db.UpdateEvent, route)
+
+			Consistently(database.SaveRouteVersionCallCount).Should(Equal(1))
+		})
+
+		It("records updates that change the TTL", func() {
+			ttl := 120
+			route.TTL = &ttl
+			route.ModificationTag.Increment()
+			httpEvents <- newEvent(db.UpdateEvent, route)
+
+			Eventually(database.SaveRouteVersionCallCount).Should(Equal(2))
+			version, _ := database.SaveRouteVersionArgsForCall(1)
+			Expect(version.Action).To(Equal(models.RouteVersionUpdated))
+			Expect(version.GetTTL()).To(Equal(120))
+			Expect(version.ModificationTag.Index).To(BeNumerically("==", 1))
+		})
+
+		It("records the deletion and the next update of the route", func() {
+			httpEvents <- newEvent(db.DeleteEvent, route)
+			Eventually(database.SaveRouteVersionCallCount).Should(Equal(2))
+
+			httpEvents <- newEvent(db.UpdateEvent, route)
+			Eventually(database.SaveRouteVersionCallCount).Should(Equal(3))
+		})
+
+		It("records the next update of the route after losing events", func() {
+			httpEvents <- db.NewResyncEvent(2)
+			route.ModificationTag.Increment()
+			httpEvents <- newEvent(db.UpdateEvent, route)
+
+			Eventually(database.SaveRouteVersionCallCount).Should(Equal(2))
+			version, _ := database.SaveRouteVersionArgsForCall(1)
+			Expect(version.Action).To(Equal(models.RouteVersionUpdated))
+		})
+	})
+
+	Context("when saving a version fails", func() {
+		BeforeEach(func() {
+			database.SaveRouteVersionReturns(errors.New("boom"))
+		})
+
+		It("keeps recording", func() {
+			httpEvents <- newEvent(db.CreateEvent, models.NewRoute("a.example.com", 8080, "1.2.3.4", "", "", 60))
+			httpEvents <- newEvent(db.CreateEvent, models.NewRoute("b.example.com", 8080, "1.2.3.4", "", "", 60))
+
+			Eventually(database.SaveRouteVersionCallCount).Should(Equal(2))
+		})
+	})
+})
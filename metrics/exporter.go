@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.cloudfoundry.org/routing-api/db"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prometheusPrefix = "routing_api_"
+
+var (
+	totalHttpEventsSent int64
+	totalTcpEventsSent  int64
+)
+
+// PrometheusMetrics are the metrics the routing API exposes in the Prometheus
+// format: the gauges it sends to statsd, the requests of every endpoint, the
+// events sent to and dropped for subscribers and the timings of database
+// operations.
+type PrometheusMetrics struct {
+	Registry *prometheus.Registry
+
+	requests         *prometheus.CounterVec
+	requestDurations *prometheus.HistogramVec
+	dbDurations      *prometheus.HistogramVec
+	dbErrors         *prometheus.CounterVec
+
+	mutex  sync.Mutex
+	gauges map[string]*prometheus.GaugeVec
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prometheusPrefix + "http_requests_total",
+			Help: "Number of requests by endpoint and status code.",
+		}, []string{"route", "code"}),
+		requestDurations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: prometheusPrefix + "http_request_duration_seconds",
+			Help: "Duration of requests by endpoint.",
+		}, []string{"route"}),
+		dbDurations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: prometheusPrefix + "db_operation_duration_seconds",
+			Help: "Duration of database operations.",
+		}, []string{"operation"}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prometheusPrefix + "db_operation_errors_total",
+			Help: "Number of failed database operations.",
+		}, []string{"operation"}),
+		gauges: map[string]*prometheus.GaugeVec{},
+	}
+
+	m.Registry.MustRegister(m.requests, m.requestDurations, m.dbDurations, m.dbErrors)
+	for stat, value := range collectedGauges {
+		m.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: prometheusPrefix + stat,
+			Help: gaugeHelp[stat],
+		}, value))
+	}
+	for stream, watchType := range map[string]string{"http": db.HTTP_WATCH, "tcp": db.TCP_WATCH} {
+		watchType := watchType
+		m.Registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        prometheusPrefix + "events_sent_total",
+			Help:        "Number of events sent to subscribers.",
+			ConstLabels: prometheus.Labels{"stream": stream},
+		}, func() float64 { return float64(GetEventsSent(watchType)) }))
+	}
+	return m
+}
+
+// collectedGauges are read when the metrics are scraped, instead of when they
+// are sent to statsd.
+var collectedGauges = map[string]func() float64{
+	TotalTokenErrors:       func() float64 { return float64(GetTokenErrors()) },
+	KeyRefreshEvents:       func() float64 { return float64(GetKeyVerificationRefreshCount()) },
+	TotalHttpEventsDropped: func() float64 { return float64(db.GetEventsDropped(db.HTTP_WATCH)) },
+	TotalTcpEventsDropped:  func() float64 { return float64(db.GetEventsDropped(db.TCP_WATCH)) },
+}
+
+var gaugeHelp = map[string]string{
+	TotalHttpSubscriptions: "Number of subscribers to the http route events.",
+	TotalHttpRoutes:        "Number of http routes.",
+	TotalTcpSubscriptions:  "Number of subscribers to the tcp route mapping events.",
+	TotalTcpRoutes:         "Number of tcp route mappings.",
+	TotalTokenErrors:       "Number of requests with an invalid token.",
+	KeyRefreshEvents:       "Number of times the UAA verification key was refreshed.",
+	TotalHttpEventsDropped: "Number of http route events lost by subscribers that fell behind.",
+	TotalTcpEventsDropped:  "Number of tcp route mapping events lost by subscribers that fell behind.",
+	QuotaMaxUsagePrefix:    "Highest usage of any owner or log guid of the enabled quotas.",
+	QuotaKeysAtLimitPrefix: "Number of owners or log guids that reached the limit of the enabled quotas.",
+}
+
+// Statsd returns a client that records the gauges sent to stats as well.
+func (m *PrometheusMetrics) Statsd(stats PartialStatsdClient) PartialStatsdClient {
+	return &prometheusStatsdClient{metrics: m, stats: stats}
+}
+
+type prometheusStatsdClient struct {
+	metrics *PrometheusMetrics
+	stats   PartialStatsdClient
+}
+
+func (c *prometheusStatsdClient) Gauge(stat string, value int64, rate float32) error {
+	gauge, err := c.metrics.gauge(stat)
+	if err != nil {
+		return err
+	}
+	if gauge != nil {
+		gauge.Set(float64(value))
+	}
+	return c.stats.Gauge(stat, value, rate)
+}
+
+func (c *prometheusStatsdClient) GaugeDelta(stat string, value int64, rate float32) error {
+	gauge, err := c.metrics.gauge(stat)
+	if err != nil {
+		return err
+	}
+	if gauge != nil {
+		gauge.Add(float64(value))
+	}
+	return c.stats.GaugeDelta(stat, value, rate)
+}
+
+// gauge returns the gauge of a statsd stat, registering it the first time.
+// The stats of the quotas, <prefix>.<quota>, are a single gauge per prefix
+// with a quota label. The collected gauges have no gauge to set.
+func (m *PrometheusMetrics) gauge(stat string) (prometheus.Gauge, error) {
+	name, labelNames, labelValues := stat, []string{}, []string{}
+	for _, prefix := range []string{QuotaMaxUsagePrefix, QuotaKeysAtLimitPrefix} {
+		if strings.HasPrefix(stat, prefix+".") {
+			name = prefix
+			labelNames = []string{"quota"}
+			labelValues = []string{strings.TrimPrefix(stat, prefix+".")}
+		}
+	}
+	if _, ok := collectedGauges[name]; ok {
+		return nil, nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	vec, ok := m.gauges[name]
+	if !ok {
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: prometheusPrefix + name,
+			Help: gaugeHelp[name],
+		}, labelNames)
+		err := m.Registry.Register(vec)
+		if err != nil {
+			return nil, err
+		}
+		m.gauges[name] = vec
+	}
+	return vec.WithLabelValues(labelValues...), nil
+}
+
+// InstrumentHandler counts the requests to the endpoint named route and
+// records their durations. The duration of an event stream is the time it
+// stays subscribed. Requests whose handler panics are counted as
+// AbortedStatus.
+func (m *PrometheusMetrics) InstrumentHandler(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := NewResponseRecorder(w)
+		defer func() {
+			recovered := recover()
+			code := recorder.Status()
+			if recovered != nil {
+				code = AbortedStatus
+			}
+			m.requests.WithLabelValues(route, strconv.Itoa(code)).Inc()
+			m.requestDurations.WithLabelValues(route).Observe(time.Since(start).Seconds())
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+		handler.ServeHTTP(recorder, req)
+	})
+}
+
+// ObserveDBOperation records the duration of a database operation, and
+// counts it as failed when err is not nil.
+func (m *PrometheusMetrics) ObserveDBOperation(operation string, duration time.Duration, err error) {
+	m.dbDurations.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		m.dbErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// IncrementEventsSent counts an event sent to a subscriber of watchType.
+func IncrementEventsSent(watchType string) {
+	switch watchType {
+	case db.HTTP_WATCH:
+		atomic.AddInt64(&totalHttpEventsSent, 1)
+	case db.TCP_WATCH:
+		atomic.AddInt64(&totalTcpEventsSent, 1)
+	}
+}
+
+func GetEventsSent(watchType string) int64 {
+	switch watchType {
+	case db.HTTP_WATCH:
+		return atomic.LoadInt64(&totalHttpEventsSent)
+	case db.TCP_WATCH:
+		return atomic.LoadInt64(&totalTcpEventsSent)
+	}
+	return 0
+}
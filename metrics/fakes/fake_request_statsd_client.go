@@ -0,0 +1,190 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/routing-api/metrics"
+)
+
+type FakeRequestStatsdClient struct {
+	GaugeDeltaStub        func(stat string, value int64, rate float32) error
+	gaugeDeltaMutex       sync.RWMutex
+	gaugeDeltaArgsForCall []struct {
+		stat  string
+		value int64
+		rate  float32
+	}
+	gaugeDeltaReturns struct {
+		result1 error
+	}
+	GaugeStub        func(stat string, value int64, rate float32) error
+	gaugeMutex       sync.RWMutex
+	gaugeArgsForCall []struct {
+		stat  string
+		value int64
+		rate  float32
+	}
+	gaugeReturns struct {
+		result1 error
+	}
+	IncStub        func(stat string, value int64, rate float32) error
+	incMutex       sync.RWMutex
+	incArgsForCall []struct {
+		stat  string
+		value int64
+		rate  float32
+	}
+	incReturns struct {
+		result1 error
+	}
+	TimingDurationStub        func(stat string, delta time.Duration, rate float32) error
+	timingDurationMutex       sync.RWMutex
+	timingDurationArgsForCall []struct {
+		stat  string
+		delta time.Duration
+		rate  float32
+	}
+	timingDurationReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeRequestStatsdClient) GaugeDelta(stat string, value int64, rate float32) error {
+	fake.gaugeDeltaMutex.Lock()
+	fake.gaugeDeltaArgsForCall = append(fake.gaugeDeltaArgsForCall, struct {
+		stat  string
+		value int64
+		rate  float32
+	}{stat, value, rate})
+	fake.gaugeDeltaMutex.Unlock()
+	if fake.GaugeDeltaStub != nil {
+		return fake.GaugeDeltaStub(stat, value, rate)
+	} else {
+		return fake.gaugeDeltaReturns.result1
+	}
+}
+
+func (fake *FakeRequestStatsdClient) GaugeDeltaCallCount() int {
+	fake.gaugeDeltaMutex.RLock()
+	defer fake.gaugeDeltaMutex.RUnlock()
+	return len(fake.gaugeDeltaArgsForCall)
+}
+
+func (fake *FakeRequestStatsdClient) GaugeDeltaArgsForCall(i int) (string, int64, float32) {
+	fake.gaugeDeltaMutex.RLock()
+	defer fake.gaugeDeltaMutex.RUnlock()
+	return fake.gaugeDeltaArgsForCall[i].stat, fake.gaugeDeltaArgsForCall[i].value, fake.gaugeDeltaArgsForCall[i].rate
+}
+
+func (fake *FakeRequestStatsdClient) GaugeDeltaReturns(result1 error) {
+	fake.GaugeDeltaStub = nil
+	fake.gaugeDeltaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRequestStatsdClient) Gauge(stat string, value int64, rate float32) error {
+	fake.gaugeMutex.Lock()
+	fake.gaugeArgsForCall = append(fake.gaugeArgsForCall, struct {
+		stat  string
+		value int64
+		rate  float32
+	}{stat, value, rate})
+	fake.gaugeMutex.Unlock()
+	if fake.GaugeStub != nil {
+		return fake.GaugeStub(stat, value, rate)
+	} else {
+		return fake.gaugeReturns.result1
+	}
+}
+
+func (fake *FakeRequestStatsdClient) GaugeCallCount() int {
+	fake.gaugeMutex.RLock()
+	defer fake.gaugeMutex.RUnlock()
+	return len(fake.gaugeArgsForCall)
+}
+
+func (fake *FakeRequestStatsdClient) GaugeArgsForCall(i int) (string, int64, float32) {
+	fake.gaugeMutex.RLock()
+	defer fake.gaugeMutex.RUnlock()
+	return fake.gaugeArgsForCall[i].stat, fake.gaugeArgsForCall[i].value, fake.gaugeArgsForCall[i].rate
+}
+
+func (fake *FakeRequestStatsdClient) GaugeReturns(result1 error) {
+	fake.GaugeStub = nil
+	fake.gaugeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRequestStatsdClient) Inc(stat string, value int64, rate float32) error {
+	fake.incMutex.Lock()
+	fake.incArgsForCall = append(fake.incArgsForCall, struct {
+		stat  string
+		value int64
+		rate  float32
+	}{stat, value, rate})
+	fake.incMutex.Unlock()
+	if fake.IncStub != nil {
+		return fake.IncStub(stat, value, rate)
+	} else {
+		return fake.incReturns.result1
+	}
+}
+
+func (fake *FakeRequestStatsdClient) IncCallCount() int {
+	fake.incMutex.RLock()
+	defer fake.incMutex.RUnlock()
+	return len(fake.incArgsForCall)
+}
+
+func (fake *FakeRequestStatsdClient) IncArgsForCall(i int) (string, int64, float32) {
+	fake.incMutex.RLock()
+	defer fake.incMutex.RUnlock()
+	return fake.incArgsForCall[i].stat, fake.incArgsForCall[i].value, fake.incArgsForCall[i].rate
+}
+
+func (fake *FakeRequestStatsdClient) IncReturns(result1 error) {
+	fake.IncStub = nil
+	fake.incReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRequestStatsdClient) TimingDuration(stat string, delta time.Duration, rate float32) error {
+	fake.timingDurationMutex.Lock()
+	fake.timingDurationArgsForCall = append(fake.timingDurationArgsForCall, struct {
+		stat  string
+		delta time.Duration
+		rate  float32
+	}{stat, delta, rate})
+	fake.timingDurationMutex.Unlock()
+	if fake.TimingDurationStub != nil {
+		return fake.TimingDurationStub(stat, delta, rate)
+	} else {
+		return fake.timingDurationReturns.result1
+	}
+}
+
+func (fake *FakeRequestStatsdClient) TimingDurationCallCount() int {
+	fake.timingDurationMutex.RLock()
+	defer fake.timingDurationMutex.RUnlock()
+	return len(fake.timingDurationArgsForCall)
+}
+
+func (fake *FakeRequestStatsdClient) TimingDurationArgsForCall(i int) (string, time.Duration, float32) {
+	fake.timingDurationMutex.RLock()
+	defer fake.timingDurationMutex.RUnlock()
+	return fake.timingDurationArgsForCall[i].stat, fake.timingDurationArgsForCall[i].delta, fake.timingDurationArgsForCall[i].rate
+}
+
+func (fake *FakeRequestStatsdClient) TimingDurationReturns(result1 error) {
+	fake.TimingDurationStub = nil
+	fake.timingDurationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ metrics.RequestStatsdClient = new(FakeRequestStatsdClient)
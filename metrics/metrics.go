@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/quota"
 )
 
 const (
@@ -17,6 +19,11 @@ const (
 	TotalTcpRoutes         = "total_tcp_routes"
 	TotalTokenErrors       = "total_token_errors"
 	KeyRefreshEvents       = "key_refresh_events"
+	TotalHttpEventsDropped = "total_http_events_dropped"
+	TotalTcpEventsDropped  = "total_tcp_events_dropped"
+	QuotaMaxUsagePrefix    = "quota_max_usage"
+	QuotaKeysAtLimitPrefix = "quota_keys_at_limit"
+	RequestsPrefix         = "requests"
 )
 
 type PartialStatsdClient interface {
@@ -24,8 +31,17 @@ type PartialStatsdClient interface {
 	Gauge(stat string, value int64, rate float32) error
 }
 
+// RequestStatsdClient is the part of the statsd client that reports the
+// requests of every endpoint.
+type RequestStatsdClient interface {
+	PartialStatsdClient
+	Inc(stat string, value int64, rate float32) error
+	TimingDuration(stat string, delta time.Duration, rate float32) error
+}
+
 type MetricsReporter struct {
 	db       db.DB
+	quotas   quota.Enforcer
 	stats    PartialStatsdClient
 	ticker   *time.Ticker
 	doneChan chan bool
@@ -37,8 +53,8 @@ var (
 	totalKeyRefreshEventCount int64
 )
 
-func NewMetricsReporter(database db.DB, stats PartialStatsdClient, ticker *time.Ticker, logger lager.Logger) *MetricsReporter {
-	return &MetricsReporter{db: database, stats: stats, ticker: ticker, logger: logger}
+func NewMetricsReporter(database db.DB, quotas quota.Enforcer, stats PartialStatsdClient, ticker *time.Ticker, logger lager.Logger) *MetricsReporter {
+	return &MetricsReporter{db: database, quotas: quotas, stats: stats, ticker: ticker, logger: logger}
 }
 
 func (r *MetricsReporter) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
@@ -59,12 +75,26 @@ func (r *MetricsReporter) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 	for {
 		select {
 		case event := <-httpEventChan:
+			if event.Type == db.ResyncEvent {
+				err = r.stats.Gauge(TotalHttpRoutes, r.getTotalRoutes(), 1.0)
+				if err != nil {
+					r.logger.Info("error-streaming-totalhttpsubscriptions-metrics", lager.Data{"error": err})
+				}
+				continue
+			}
 			statsDelta := getStatsEventType(event)
 			err = r.stats.GaugeDelta(TotalHttpRoutes, statsDelta, 1.0)
 			if err != nil {
 				r.logger.Info("error-streaming-totalhttpsubscriptions-metrics", lager.Data{"error": err})
 			}
 		case event := <-tcpEventChan:
+			if event.Type == db.ResyncEvent {
+				err = r.stats.Gauge(TotalTcpRoutes, r.getTotalTcpRoutes(), 1.0)
+				if err != nil {
+					r.logger.Info("error-streaming-totaltcpsubscriptions-metrics", lager.Data{"error": err})
+				}
+				continue
+			}
 			statsDelta := getStatsEventType(event)
 			err = r.stats.GaugeDelta(TotalTcpRoutes, statsDelta, 1.0)
 			if err != nil {
@@ -77,9 +107,12 @@ func (r *MetricsReporter) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 			err = r.stats.GaugeDelta(TotalTcpSubscriptions, 0, 1.0)
 			err = r.stats.Gauge(TotalTokenErrors, GetTokenErrors(), 1.0)
 			err = r.stats.Gauge(KeyRefreshEvents, GetKeyVerificationRefreshCount(), 1.0)
+			err = r.stats.Gauge(TotalHttpEventsDropped, db.GetEventsDropped(db.HTTP_WATCH), 1.0)
+			err = r.stats.Gauge(TotalTcpEventsDropped, db.GetEventsDropped(db.TCP_WATCH), 1.0)
 			if err != nil {
 				r.logger.Info("error-emitting-metrics", lager.Data{"error": err})
 			}
+			r.emitQuotaUsage()
 		case <-signals:
 			return nil
 		case err := <-httpErrChan:
@@ -100,6 +133,29 @@ func (r MetricsReporter) getTotalTcpRoutes() int64 {
 	return int64(len(routes))
 }
 
+// emitQuotaUsage gauges the highest usage of every enabled quota as
+// quota_max_usage.<quota>, and the number of owners or log guids that reached
+// its limit as quota_keys_at_limit.<quota>. The usage of each owner or log
+// guid is not emitted, since there is no bound on their number.
+func (r MetricsReporter) emitQuotaUsage() {
+	usages, err := r.quotas.Usage()
+	if err != nil {
+		r.logger.Info("error-reading-quota-usage", lager.Data{"error": err})
+		return
+	}
+
+	for _, usage := range usages {
+		err = r.stats.Gauge(fmt.Sprintf("%s.%s", QuotaMaxUsagePrefix, usage.Quota), int64(usage.MaxUsage()), 1.0)
+		if err != nil {
+			r.logger.Info("error-emitting-quota-usage-metrics", lager.Data{"error": err})
+		}
+		err = r.stats.Gauge(fmt.Sprintf("%s.%s", QuotaKeysAtLimitPrefix, usage.Quota), int64(usage.KeysAtLimit()), 1.0)
+		if err != nil {
+			r.logger.Info("error-emitting-quota-usage-metrics", lager.Data{"error": err})
+		}
+	}
+}
+
 func getStatsEventType(event db.Event) int64 {
 	if event.Type == db.CreateEvent {
 		return 1
@@ -10,6 +10,7 @@ import (
 	. "code.cloudfoundry.org/routing-api/metrics"
 	fake_statsd "code.cloudfoundry.org/routing-api/metrics/fakes"
 	"code.cloudfoundry.org/routing-api/models"
+	fake_quota "code.cloudfoundry.org/routing-api/quota/fakes"
 	"github.com/coreos/etcd/Godeps/_workspace/src/golang.org/x/net/context"
 
 	. "github.com/onsi/ginkgo"
@@ -21,6 +22,7 @@ var _ = Describe("Metrics", func() {
 
 		var (
 			database       *fake_db.FakeDB
+			quotas         *fake_quota.FakeEnforcer
 			reporter       *MetricsReporter
 			stats          *fake_statsd.FakePartialStatsdClient
 			resultsChan    chan db.Event
@@ -32,11 +34,12 @@ var _ = Describe("Metrics", func() {
 
 		BeforeEach(func() {
 			database = &fake_db.FakeDB{}
+			quotas = &fake_quota.FakeEnforcer{}
 			stats = &fake_statsd.FakePartialStatsdClient{}
 
 			tickChan = make(chan time.Time, 1)
 			logger := lagertest.NewTestLogger("metrics")
-			reporter = NewMetricsReporter(database, stats, &time.Ticker{C: tickChan}, logger)
+			reporter = NewMetricsReporter(database, quotas, stats, &time.Ticker{C: tickChan}, logger)
 
 			sigChan = make(chan os.Signal, 1)
 			readyChan = make(chan struct{}, 1)
@@ -107,7 +110,7 @@ var _ = Describe("Metrics", func() {
 		It("periodically gets total routes", func() {
 			tickChan <- time.Now()
 
-			Eventually(stats.GaugeCallCount).Should(Equal(6))
+			Eventually(stats.GaugeCallCount).Should(Equal(8))
 
 			verifyGaugeCall(TotalHttpRoutes, 5, 1.0, 2)
 			verifyGaugeCall(TotalTcpRoutes, 3, 1.0, 3)
@@ -212,7 +215,7 @@ var _ = Describe("Metrics", func() {
 
 			It("emits the incremented token error metric", func() {
 				tickChan <- time.Now()
-				Eventually(stats.GaugeCallCount).Should(Equal(6))
+				Eventually(stats.GaugeCallCount).Should(Equal(8))
 				verifyGaugeCall("total_token_errors", currentTokenErrors+1, 1.0, 4)
 			})
 		})
@@ -229,10 +232,44 @@ var _ = Describe("Metrics", func() {
 
 			It("emits token error metrics", func() {
 				tickChan <- time.Now()
-				Eventually(stats.GaugeCallCount).Should(Equal(6))
+				Eventually(stats.GaugeCallCount).Should(Equal(8))
 				verifyGaugeCall("key_refresh_events", currentKeyRefreshEventCount+1, 1.0, 5)
 			})
 		})
 
+		It("periodically emits the events dropped for slow subscribers", func() {
+			tickChan <- time.Now()
+			Eventually(stats.GaugeCallCount).Should(Equal(8))
+			verifyGaugeCall(TotalHttpEventsDropped, db.GetEventsDropped(db.HTTP_WATCH), 1.0, 6)
+			verifyGaugeCall(TotalTcpEventsDropped, db.GetEventsDropped(db.TCP_WATCH), 1.0, 7)
+		})
+
+		Context("When the reporter lost events", func() {
+			BeforeEach(func() {
+				resultsChan <- db.NewResyncEvent(3)
+			})
+
+			It("emits the total routes again", func() {
+				Eventually(stats.GaugeCallCount).Should(Equal(3))
+				verifyGaugeCall(TotalHttpRoutes, 5, 1.0, 2)
+				Expect(stats.GaugeDeltaCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When quotas are enabled", func() {
+			BeforeEach(func() {
+				quotas.UsageReturns([]models.QuotaUsage{
+					{Quota: models.QuotaHttpRoutesPerOwner, Limit: 4, Usage: map[string]int{"cf": 4, "other": 2, "full": 5}},
+				}, nil)
+			})
+
+			It("periodically emits the highest usage and the keys at the limit of every quota", func() {
+				tickChan <- time.Now()
+				Eventually(stats.GaugeCallCount).Should(Equal(10))
+				verifyGaugeCall("quota_max_usage.http_routes_per_owner", 5, 1.0, 8)
+				verifyGaugeCall("quota_keys_at_limit.http_routes_per_owner", 2, 1.0, 9)
+			})
+		})
+
 	})
 })
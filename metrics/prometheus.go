@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const MetricsPath = "/metrics"
+
+// Handler serves the metrics of the registry in the Prometheus exposition
+// formats.
+func (m *PrometheusMetrics) Handler(logger lager.Logger) http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{
+		ErrorLog:      errorLog{logger},
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// errorLog logs the errors of collecting and writing metrics.
+type errorLog struct {
+	logger lager.Logger
+}
+
+func (l errorLog) Println(v ...interface{}) {
+	l.logger.Error("failed-to-write-metrics", errors.New(fmt.Sprint(v...)))
+}
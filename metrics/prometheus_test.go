@@ -0,0 +1,114 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/db"
+	. "code.cloudfoundry.org/routing-api/metrics"
+	fake_statsd "code.cloudfoundry.org/routing-api/metrics/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prometheus", func() {
+	scrape := func(prometheusMetrics *PrometheusMetrics) string {
+		recorder := httptest.NewRecorder()
+		prometheusMetrics.Handler(lagertest.NewTestLogger("metrics")).ServeHTTP(recorder, httptest.NewRequest("GET", MetricsPath, nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		return recorder.Body.String()
+	}
+
+	Describe("PrometheusMetrics", func() {
+		var prometheusMetrics *PrometheusMetrics
+
+		BeforeEach(func() {
+			prometheusMetrics = NewPrometheusMetrics()
+		})
+
+		It("records the gauges sent to statsd", func() {
+			stats := &fake_statsd.FakePartialStatsdClient{}
+			client := prometheusMetrics.Statsd(stats)
+
+			Expect(client.Gauge(TotalHttpRoutes, 5, 1.0)).To(Succeed())
+			Expect(client.GaugeDelta(TotalHttpRoutes, -1, 1.0)).To(Succeed())
+			Expect(client.Gauge("quota_max_usage.http_routes_per_owner", 3, 1.0)).To(Succeed())
+
+			Expect(stats.GaugeCallCount()).To(Equal(2))
+			Expect(stats.GaugeDeltaCallCount()).To(Equal(1))
+
+			output := scrape(prometheusMetrics)
+			Expect(output).To(ContainSubstring("routing_api_total_http_routes 4\n"))
+			Expect(output).To(ContainSubstring(`routing_api_quota_max_usage{quota="http_routes_per_owner"} 3`))
+		})
+
+		It("serves the metrics in the text format", func() {
+			recorder := httptest.NewRecorder()
+			prometheusMetrics.Handler(lagertest.NewTestLogger("metrics")).ServeHTTP(recorder, httptest.NewRequest("GET", MetricsPath, nil))
+
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+			Expect(recorder.Body.String()).To(ContainSubstring("# HELP routing_api_total_token_errors Number of requests with an invalid token.\n"))
+		})
+
+		It("exposes the token errors, key refreshes and events sent and dropped", func() {
+			IncrementEventsSent(db.TCP_WATCH)
+
+			output := scrape(prometheusMetrics)
+			Expect(output).To(ContainSubstring("# TYPE routing_api_total_token_errors gauge\n"))
+			Expect(output).To(ContainSubstring("# TYPE routing_api_key_refresh_events gauge\n"))
+			Expect(output).To(ContainSubstring("# TYPE routing_api_total_http_events_dropped gauge\n"))
+			Expect(output).To(ContainSubstring("# TYPE routing_api_total_tcp_events_dropped gauge\n"))
+			Expect(output).To(MatchRegexp(`routing_api_events_sent_total{stream="tcp"} [1-9]`))
+		})
+
+		It("counts the requests of a route by status code and records their durations", func() {
+			handler := prometheusMetrics.InstrumentHandler("UpsertRoute", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/routing/v1/routes", nil))
+
+			output := scrape(prometheusMetrics)
+			Expect(output).To(ContainSubstring(`routing_api_http_requests_total{code="201",route="UpsertRoute"} 1`))
+			Expect(output).To(ContainSubstring(`routing_api_http_request_duration_seconds_count{route="UpsertRoute"} 1`))
+		})
+
+		It("counts the requests the handler aborts as failures", func() {
+			handler := prometheusMetrics.InstrumentHandler("ListRoute", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				panic(http.ErrAbortHandler)
+			}))
+			Expect(func() {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/routing/v1/routes", nil))
+			}).To(Panic())
+
+			output := scrape(prometheusMetrics)
+			Expect(output).To(ContainSubstring(`routing_api_http_requests_total{code="500",route="ListRoute"} 1`))
+			Expect(output).To(ContainSubstring(`routing_api_http_request_duration_seconds_count{route="ListRoute"} 1`))
+		})
+
+		It("keeps the flushing of the response writer", func() {
+			handler := prometheusMetrics.InstrumentHandler("EventStreamRoute", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, ok := w.(http.Flusher)
+				Expect(ok).To(BeTrue())
+				_, ok = w.(http.CloseNotifier)
+				Expect(ok).To(BeTrue())
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/routing/v1/events", nil))
+
+			Expect(scrape(prometheusMetrics)).To(ContainSubstring(`routing_api_http_requests_total{code="200",route="EventStreamRoute"} 1`))
+		})
+
+		It("records the durations and failures of database operations", func() {
+			prometheusMetrics.ObserveDBOperation("SaveRoute", 20*time.Millisecond, nil)
+			prometheusMetrics.ObserveDBOperation("SaveRoute", 30*time.Millisecond, errors.New("boom"))
+
+			output := scrape(prometheusMetrics)
+			Expect(output).To(ContainSubstring(`routing_api_db_operation_duration_seconds_count{operation="SaveRoute"} 2`))
+			Expect(output).To(ContainSubstring(`routing_api_db_operation_errors_total{operation="SaveRoute"} 1`))
+		})
+	})
+})
@@ -120,6 +120,15 @@ func (v *V1EtcdMigration) watchForHTTPEvents(etcd db.DB, sqlDB db.DB) {
 					if err != nil {
 						v.logger.Error("failed-to-delete-http-route", err)
 					}
+				case db.DrainEvent:
+					err := json.Unmarshal([]byte(event.Value), &httpRoute)
+					if err != nil {
+						v.logger.Error("failed-to-unmarshal-http-event", err)
+					}
+					err = sqlDB.DrainRoute(httpRoute, httpRoute.GetTTL())
+					if err != nil {
+						v.logger.Error("failed-to-drain-http-route", err)
+					}
 				default:
 					v.logger.Info("unknown-event-type", lager.Data{"event-type": event.Type})
 				}
@@ -198,6 +207,16 @@ func (v *V1EtcdMigration) watchForTCPEvents(etcd db.DB, sqlDB db.DB) {
 					if err != nil {
 						v.logger.Error("failed-to-delete-tcp-route", err)
 					}
+				case db.DrainEvent:
+					err := json.Unmarshal([]byte(event.Value), &tcpRoute)
+					if err != nil || tcpRoute.TTL == nil {
+						v.logger.Error("failed-to-unmarshal-tcp-event", err)
+						continue
+					}
+					err = sqlDB.DrainTcpRouteMapping(tcpRoute, *tcpRoute.TTL)
+					if err != nil {
+						v.logger.Error("failed-to-drain-tcp-route", err)
+					}
 				default:
 					v.logger.Info("unknown-event-type", lager.Data{"event-type": event.Type})
 				}
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type V2AuditMigration struct{}
+
+var _ Migration = new(V2AuditMigration)
+
+func NewV2AuditMigration() *V2AuditMigration {
+	return &V2AuditMigration{}
+}
+
+func (v *V2AuditMigration) Version() int {
+	return 2
+}
+
+func (v *V2AuditMigration) Run(sqlDB *db.SqlDB) error {
+	return sqlDB.Client.AutoMigrate(&models.AuditRecord{})
+}
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type V3HistoryMigration struct{}
+
+var _ Migration = new(V3HistoryMigration)
+
+func NewV3HistoryMigration() *V3HistoryMigration {
+	return &V3HistoryMigration{}
+}
+
+func (v *V3HistoryMigration) Version() int {
+	return 3
+}
+
+func (v *V3HistoryMigration) Run(sqlDB *db.SqlDB) error {
+	return sqlDB.Client.AutoMigrate(&models.RouteVersion{})
+}
@@ -0,0 +1,58 @@
+package migration_test
+
+import (
+	"code.cloudfoundry.org/routing-api/cmd/routing-api/testrunner"
+	"code.cloudfoundry.org/routing-api/config"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/migration"
+	"code.cloudfoundry.org/routing-api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("V3HistoryMigration", func() {
+	var (
+		mysqlAllocator testrunner.DbAllocator
+		dbClient       db.Client
+		sqlDB          *db.SqlDB
+		err            error
+	)
+	BeforeEach(func() {
+		mysqlAllocator = testrunner.NewMySQLAllocator()
+		mysqlSchema, err := mysqlAllocator.Create()
+		Expect(err).NotTo(HaveOccurred())
+
+		sqlCfg := &config.SqlDB{
+			Username: "root",
+			Password: "password",
+			Schema:   mysqlSchema,
+			Host:     "localhost",
+			Port:     3306,
+			Type:     "mysql",
+		}
+
+		sqlDB, err = db.NewSqlDB(sqlCfg)
+		Expect(err).ToNot(HaveOccurred())
+		dbClient = sqlDB.Client
+	})
+
+	AfterEach(func() {
+		err := mysqlAllocator.Delete()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when valid sql config is passed", func() {
+		var v3Migration *migration.V3HistoryMigration
+		BeforeEach(func() {
+			v3Migration = migration.NewV3HistoryMigration()
+		})
+
+		It("should successfully create the route versions table and does not close db connection", func() {
+			err = v3Migration.Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dbClient.HasTable(&models.RouteVersion{})).To(BeTrue())
+		})
+	})
+})
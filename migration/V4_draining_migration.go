@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type V4DrainingMigration struct{}
+
+var _ Migration = new(V4DrainingMigration)
+
+func NewV4DrainingMigration() *V4DrainingMigration {
+	return &V4DrainingMigration{}
+}
+
+func (v *V4DrainingMigration) Version() int {
+	return 4
+}
+
+func (v *V4DrainingMigration) Run(sqlDB *db.SqlDB) error {
+	return sqlDB.Client.AutoMigrate(&models.TcpRouteMapping{}, &models.Route{}, &models.RouteVersion{})
+}
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type V5OwnerMigration struct{}
+
+var _ Migration = new(V5OwnerMigration)
+
+func NewV5OwnerMigration() *V5OwnerMigration {
+	return &V5OwnerMigration{}
+}
+
+func (v *V5OwnerMigration) Version() int {
+	return 5
+}
+
+func (v *V5OwnerMigration) Run(sqlDB *db.SqlDB) error {
+	return sqlDB.Client.AutoMigrate(&models.TcpRouteMapping{}, &models.Route{})
+}
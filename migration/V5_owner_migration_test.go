@@ -0,0 +1,69 @@
+package migration_test
+
+import (
+	"code.cloudfoundry.org/routing-api/cmd/routing-api/testrunner"
+	"code.cloudfoundry.org/routing-api/config"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/migration"
+	"code.cloudfoundry.org/routing-api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("V5OwnerMigration", func() {
+	var (
+		mysqlAllocator testrunner.DbAllocator
+		dbClient       db.Client
+		sqlDB          *db.SqlDB
+		err            error
+	)
+	BeforeEach(func() {
+		mysqlAllocator = testrunner.NewMySQLAllocator()
+		mysqlSchema, err := mysqlAllocator.Create()
+		Expect(err).NotTo(HaveOccurred())
+
+		sqlCfg := &config.SqlDB{
+			Username: "root",
+			Password: "password",
+			Schema:   mysqlSchema,
+			Host:     "localhost",
+			Port:     3306,
+			Type:     "mysql",
+		}
+
+		sqlDB, err = db.NewSqlDB(sqlCfg)
+		Expect(err).ToNot(HaveOccurred())
+		dbClient = sqlDB.Client
+	})
+
+	AfterEach(func() {
+		err := mysqlAllocator.Delete()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when valid sql config is passed", func() {
+		var v5Migration *migration.V5OwnerMigration
+		BeforeEach(func() {
+			err = migration.NewV0InitMigration().Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+			v5Migration = migration.NewV5OwnerMigration()
+		})
+
+		It("should successfully add the owner column to the route tables", func() {
+			err = v5Migration.Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			route, err := models.NewRouteWithModel(models.NewRoute("a.example.com", 8080, "1.2.3.4", "", "", 60))
+			Expect(err).ToNot(HaveOccurred())
+			route.Owner = "some-client"
+			_, err = dbClient.Create(&route)
+			Expect(err).ToNot(HaveOccurred())
+
+			var routes []models.Route
+			err = dbClient.Where("owner = ?", "some-client").Find(&routes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type V6RouterGroupTTLMigration struct{}
+
+var _ Migration = new(V6RouterGroupTTLMigration)
+
+func NewV6RouterGroupTTLMigration() *V6RouterGroupTTLMigration {
+	return &V6RouterGroupTTLMigration{}
+}
+
+func (v *V6RouterGroupTTLMigration) Version() int {
+	return 6
+}
+
+func (v *V6RouterGroupTTLMigration) Run(sqlDB *db.SqlDB) error {
+	return sqlDB.Client.AutoMigrate(&models.RouterGroupDB{})
+}
@@ -0,0 +1,75 @@
+package migration_test
+
+import (
+	"code.cloudfoundry.org/routing-api/cmd/routing-api/testrunner"
+	"code.cloudfoundry.org/routing-api/config"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/migration"
+	"code.cloudfoundry.org/routing-api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("V6RouterGroupTTLMigration", func() {
+	var (
+		mysqlAllocator testrunner.DbAllocator
+		dbClient       db.Client
+		sqlDB          *db.SqlDB
+		err            error
+	)
+	BeforeEach(func() {
+		mysqlAllocator = testrunner.NewMySQLAllocator()
+		mysqlSchema, err := mysqlAllocator.Create()
+		Expect(err).NotTo(HaveOccurred())
+
+		sqlCfg := &config.SqlDB{
+			Username: "root",
+			Password: "password",
+			Schema:   mysqlSchema,
+			Host:     "localhost",
+			Port:     3306,
+			Type:     "mysql",
+		}
+
+		sqlDB, err = db.NewSqlDB(sqlCfg)
+		Expect(err).ToNot(HaveOccurred())
+		dbClient = sqlDB.Client
+	})
+
+	AfterEach(func() {
+		err := mysqlAllocator.Delete()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when valid sql config is passed", func() {
+		var v6Migration *migration.V6RouterGroupTTLMigration
+		BeforeEach(func() {
+			err = migration.NewV0InitMigration().Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+			v6Migration = migration.NewV6RouterGroupTTLMigration()
+		})
+
+		It("should successfully add the ttl columns to the router groups table", func() {
+			err = v6Migration.Run(sqlDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			routerGroup := models.NewRouterGroupDB(models.RouterGroup{
+				Guid:            "some-guid",
+				Name:            "some-group",
+				Type:            "tcp",
+				ReservablePorts: "1024-2048",
+				MaxTTL:          3600,
+				DefaultTTL:      600,
+			})
+			_, err = dbClient.Create(&routerGroup)
+			Expect(err).ToNot(HaveOccurred())
+
+			var routerGroups []models.RouterGroupDB
+			err = dbClient.Where("max_ttl = ?", 3600).Find(&routerGroups)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(routerGroups).To(HaveLen(1))
+			Expect(routerGroups[0].DefaultTTL).To(Equal(600))
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+type V7PermanentMigration struct{}
+
+var _ Migration = new(V7PermanentMigration)
+
+func NewV7PermanentMigration() *V7PermanentMigration {
+	return &V7PermanentMigration{}
+}
+
+func (v *V7PermanentMigration) Version() int {
+	return 7
+}
+
+func (v *V7PermanentMigration) Run(sqlDB *db.SqlDB) error {
+	return sqlDB.Client.AutoMigrate(&models.TcpRouteMapping{}, &models.Route{})
+}
@@ -78,6 +78,24 @@ func InitializeMigrations(etcdCfg *config.Etcd, etcdDone chan struct{}, logger l
 	migration = NewV1EtcdMigration(etcdCfg, etcdDone, logger)
 	migrations = append(migrations, migration)
 
+	migration = NewV2AuditMigration()
+	migrations = append(migrations, migration)
+
+	migration = NewV3HistoryMigration()
+	migrations = append(migrations, migration)
+
+	migration = NewV4DrainingMigration()
+	migrations = append(migrations, migration)
+
+	migration = NewV5OwnerMigration()
+	migrations = append(migrations, migration)
+
+	migration = NewV6RouterGroupTTLMigration()
+	migrations = append(migrations, migration)
+
+	migration = NewV7PermanentMigration()
+	migrations = append(migrations, migration)
+
 	return migrations
 }
 
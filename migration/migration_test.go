@@ -71,10 +71,16 @@ var _ = Describe("Migration", func() {
 				done := make(chan struct{})
 				defer close(done)
 				migrations := migration.InitializeMigrations(etcdConfig, done, logger)
-				Expect(migrations).To(HaveLen(2))
+				Expect(migrations).To(HaveLen(8))
 
 				Expect(migrations[0]).To(BeAssignableToTypeOf(&migration.V0InitMigration{}))
 				Expect(migrations[1]).To(BeAssignableToTypeOf(&migration.V1EtcdMigration{}))
+				Expect(migrations[2]).To(BeAssignableToTypeOf(&migration.V2AuditMigration{}))
+				Expect(migrations[3]).To(BeAssignableToTypeOf(&migration.V3HistoryMigration{}))
+				Expect(migrations[4]).To(BeAssignableToTypeOf(&migration.V4DrainingMigration{}))
+				Expect(migrations[5]).To(BeAssignableToTypeOf(&migration.V5OwnerMigration{}))
+				Expect(migrations[6]).To(BeAssignableToTypeOf(&migration.V6RouterGroupTTLMigration{}))
+				Expect(migrations[7]).To(BeAssignableToTypeOf(&migration.V7PermanentMigration{}))
 			})
 		})
 
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	AuditActionUpsert            = "upsert"
+	AuditActionDelete            = "delete"
+	AuditActionExpire            = "expire"
+	AuditActionDrain             = "drain"
+	AuditActionUpdateRouterGroup = "update_router_group"
+
+	AuditKindHttpRoute   = "http_route"
+	AuditKindTcpRoute    = "tcp_route"
+	AuditKindRouterGroup = "router_group"
+)
+
+// AuditRecord describes a single mutation of a route, tcp route mapping or
+// router group. Before and After hold the JSON representation of the object.
+type AuditRecord struct {
+	Model
+	Time      time.Time  `gorm:"index" json:"time"`
+	Action    string     `json:"action"`
+	Kind      string     `json:"kind"`
+	Key       string     `gorm:"column:audit_key;index" json:"key"`
+	Actor     string     `gorm:"index" json:"actor"`
+	SourceIP  string     `json:"source_ip,omitempty"`
+	RequestID string     `json:"request_id,omitempty"`
+	Before    AuditValue `gorm:"type:text" json:"before"`
+	After     AuditValue `gorm:"type:text" json:"after"`
+}
+
+func (AuditRecord) TableName() string {
+	return "audit_records"
+}
+
+// AuditFilter restricts the audit records returned by a query. Zero values
+// are ignored.
+type AuditFilter struct {
+	Since time.Time
+	Until time.Time
+	Actor string
+	Key   string
+	Limit int
+}
+
+func (f AuditFilter) Matches(record AuditRecord) bool {
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Time.After(f.Until) {
+		return false
+	}
+	if f.Actor != "" && record.Actor != f.Actor {
+		return false
+	}
+	if f.Key != "" && record.Key != f.Key {
+		return false
+	}
+	return true
+}
+
+// AuditValue is a JSON document stored as text and rendered inline.
+type AuditValue string
+
+func NewAuditValue(obj interface{}) AuditValue {
+	if obj == nil {
+		return ""
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return ""
+	}
+	return AuditValue(data)
+}
+
+func (v AuditValue) MarshalJSON() ([]byte, error) {
+	if v == "" {
+		return []byte("null"), nil
+	}
+	return []byte(v), nil
+}
+
+func (v *AuditValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+	*v = AuditValue(data)
+	return nil
+}
+
+// AuditKey is the key under which changes to a route are recorded, so that
+// the history of a route can be queried by its url.
+func (r Route) AuditKey() string {
+	return r.Route
+}
+
+// AuditKey is the key under which changes to a tcp route mapping are
+// recorded, i.e. <router_group_guid>:<external_port>.
+func (m TcpRouteMapping) AuditKey() string {
+	return fmt.Sprintf("%s:%d", m.RouterGroupGuid, m.ExternalPort)
+}
+
+// AuditKey is the key under which changes to a router group are recorded.
+func (g RouterGroup) AuditKey() string {
+	return g.Name
+}
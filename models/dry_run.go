@@ -0,0 +1,35 @@
+package models
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDrain  = "drain"
+	ChangeDelete = "delete"
+	ChangeNone   = "none"
+)
+
+// RouteChange describes what a write would do to an HTTP route. It is
+// returned by dry run requests instead of making the change.
+type RouteChange struct {
+	Action string `json:"action"`
+	Before *Route `json:"before,omitempty"`
+	After  *Route `json:"after,omitempty"`
+}
+
+// TcpRouteMappingChange describes what a write would do to a TCP route
+// mapping, see RouteChange.
+type TcpRouteMappingChange struct {
+	Action string           `json:"action"`
+	Before *TcpRouteMapping `json:"before,omitempty"`
+	After  *TcpRouteMapping `json:"after,omitempty"`
+}
+
+// RouterGroupChange describes what an update would do to a router group.
+// OutOfRangeTcpRoutes lists the mappings of the group whose port is not in
+// the new reservable ports.
+type RouterGroupChange struct {
+	Action              string            `json:"action"`
+	Before              *RouterGroup      `json:"before,omitempty"`
+	After               *RouterGroup      `json:"after,omitempty"`
+	OutOfRangeTcpRoutes []TcpRouteMapping `json:"out_of_range_tcp_routes,omitempty"`
+}
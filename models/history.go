@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	HistoryKindHttpRoute = "http_route"
+	HistoryKindTcpRoute  = "tcp_route"
+
+	RouteVersionCreated = "created"
+	RouteVersionUpdated = "updated"
+	RouteVersionDeleted = "deleted"
+	RouteVersionExpired = "expired"
+	RouteVersionDrained = "drained"
+)
+
+// RouteVersion is the state of a route or tcp route mapping after a change.
+// Versions are kept per history key and identified by the modification tag
+// the route had at that point.
+type RouteVersion struct {
+	Model
+	Kind            string    `gorm:"index:idx_route_version_key" json:"kind"`
+	Key             string    `gorm:"column:history_key;index:idx_route_version_key" json:"key"`
+	Time            time.Time `json:"time"`
+	Action          string    `json:"action"`
+	ModificationTag `json:"modification_tag"`
+	TTL             *int   `json:"ttl"`
+	LogGuid         string `json:"log_guid,omitempty"`
+	RouteServiceUrl string `json:"route_service_url,omitempty"`
+	Draining        bool   `gorm:"not null; default:false" json:"draining,omitempty"`
+}
+
+func (RouteVersion) TableName() string {
+	return "route_versions"
+}
+
+// SameState reports whether two versions describe the same route with the
+// same TTL, log guid, route service url and draining state.
+func (v RouteVersion) SameState(other RouteVersion) bool {
+	return v.ModificationTag.Guid == other.ModificationTag.Guid &&
+		v.GetTTL() == other.GetTTL() &&
+		v.LogGuid == other.LogGuid &&
+		v.RouteServiceUrl == other.RouteServiceUrl &&
+		v.Draining == other.Draining
+}
+
+func (v RouteVersion) GetTTL() int {
+	if v.TTL == nil {
+		return 0
+	}
+	return *v.TTL
+}
+
+func NewRouteVersion(action string, route Route) RouteVersion {
+	return RouteVersion{
+		Kind:            HistoryKindHttpRoute,
+		Key:             route.HistoryKey(),
+		Action:          action,
+		ModificationTag: route.ModificationTag,
+		TTL:             route.TTL,
+		LogGuid:         route.LogGuid,
+		RouteServiceUrl: route.RouteServiceUrl,
+		Draining:        route.Draining,
+	}
+}
+
+func NewTcpRouteMappingVersion(action string, tcpMapping TcpRouteMapping) RouteVersion {
+	return RouteVersion{
+		Kind:            HistoryKindTcpRoute,
+		Key:             tcpMapping.HistoryKey(),
+		Action:          action,
+		ModificationTag: tcpMapping.ModificationTag,
+		TTL:             tcpMapping.TTL,
+		Draining:        tcpMapping.Draining,
+	}
+}
+
+// HistoryKey identifies a route backend across versions, i.e.
+// <route>,<ip>:<port>. The route service url is not part of the key so that
+// changes to it show up in the history of the backend.
+func (r Route) HistoryKey() string {
+	return fmt.Sprintf("%s,%s:%d", r.Route, r.IP, r.Port)
+}
+
+// HistoryKey identifies a tcp route mapping across versions, i.e.
+// <router_group_guid>/<port>/<backend_ip>:<backend_port>.
+func (m TcpRouteMapping) HistoryKey() string {
+	return fmt.Sprintf("%s/%d/%s:%d", m.RouterGroupGuid, m.ExternalPort, m.HostIP, m.HostPort)
+}
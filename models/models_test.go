@@ -2,6 +2,7 @@ package models_test
 
 import (
 	"encoding/json"
+	"time"
 
 	. "code.cloudfoundry.org/routing-api/models"
 
@@ -77,6 +78,36 @@ var _ = Describe("Models", func() {
 			})
 
 		})
+
+		Describe("FormatModificationTag", func() {
+			It("renders the tag as a quoted guid and index", func() {
+				Expect(FormatModificationTag(tag)).To(Equal(`"guid1:5"`))
+			})
+		})
+
+		Describe("ParseModificationTag", func() {
+			It("parses a formatted tag", func() {
+				parsed, err := ParseModificationTag(FormatModificationTag(tag))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(tag))
+			})
+
+			It("accepts a tag without quotes", func() {
+				parsed, err := ParseModificationTag("guid1:5")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(tag))
+			})
+
+			It("returns an error when the index is missing", func() {
+				_, err := ParseModificationTag("guid1")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("returns an error when the index is not a number", func() {
+				_, err := ParseModificationTag("guid1:five")
+				Expect(err).To(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("RouterGroup", func() {
@@ -121,6 +152,64 @@ var _ = Describe("Models", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("Missing reservable_ports in router group: router-group-1"))
 			})
+
+			It("fails for negative ttls", func() {
+				rg = RouterGroup{
+					Name:            "router-group-1",
+					Type:            "tcp",
+					ReservablePorts: "1025-2025",
+					MaxTTL:          -1,
+				}
+				err := rg.Validate()
+				Expect(err).To(MatchError("TTLs cannot be negative in router group: router-group-1"))
+			})
+
+			It("fails when the default ttl is greater than the max ttl", func() {
+				rg = RouterGroup{
+					Name:            "router-group-1",
+					Type:            "tcp",
+					ReservablePorts: "1025-2025",
+					MaxTTL:          60,
+					DefaultTTL:      120,
+				}
+				err := rg.Validate()
+				Expect(err).To(MatchError("default_ttl cannot be greater than max_ttl in router group: router-group-1"))
+			})
+		})
+
+		Describe("TTLPolicy", func() {
+			defaults := TTLPolicy{MaxTTL: 120, DefaultTTL: 60}
+
+			It("uses the defaults when the group sets no ttls", func() {
+				Expect(RouterGroup{}.TTLPolicy(defaults)).To(Equal(defaults))
+			})
+
+			It("uses the ttls of the group", func() {
+				rg = RouterGroup{MaxTTL: 90, DefaultTTL: 30}
+				Expect(rg.TTLPolicy(defaults)).To(Equal(TTLPolicy{MaxTTL: 90, DefaultTTL: 30}))
+			})
+
+			It("does not raise the max ttl of the defaults", func() {
+				rg = RouterGroup{MaxTTL: 3600, DefaultTTL: 600}
+				Expect(rg.TTLPolicy(defaults)).To(Equal(TTLPolicy{MaxTTL: 120, DefaultTTL: 120}))
+			})
+
+			It("limits the default ttl to the max ttl of the group", func() {
+				rg = RouterGroup{MaxTTL: 30}
+				Expect(rg.TTLPolicy(defaults)).To(Equal(TTLPolicy{MaxTTL: 30, DefaultTTL: 30}))
+			})
+		})
+
+		Describe("ValidateTTLs", func() {
+			It("accepts ttls up to the global max ttl", func() {
+				Expect(RouterGroup{MaxTTL: 120, DefaultTTL: 60}.ValidateTTLs(120)).To(Succeed())
+				Expect(RouterGroup{}.ValidateTTLs(120)).To(Succeed())
+			})
+
+			It("rejects ttls greater than the global max ttl", func() {
+				Expect(RouterGroup{Name: "rg", MaxTTL: 121}.ValidateTTLs(120)).To(MatchError("TTLs cannot be greater than the global max_ttl of 120 in router group: rg"))
+				Expect(RouterGroup{Name: "rg", DefaultTTL: 121}.ValidateTTLs(120)).To(HaveOccurred())
+			})
 		})
 	})
 
@@ -286,6 +375,32 @@ var _ = Describe("Models", func() {
 				Expect(testRange.Overlaps(r)).To(BeTrue())
 			})
 		})
+
+		Describe("Contains", func() {
+			testRange, _ := NewRange(6010, 6020)
+
+			It("contains its bounds", func() {
+				Expect(testRange.Contains(6010)).To(BeTrue())
+				Expect(testRange.Contains(6020)).To(BeTrue())
+			})
+
+			It("does not contain ports outside its bounds", func() {
+				Expect(testRange.Contains(6009)).To(BeFalse())
+				Expect(testRange.Contains(6021)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Ranges", func() {
+		Describe("Contains", func() {
+			It("checks every range", func() {
+				ranges, err := ReservablePorts("6010-6020,7000").Parse()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ranges.Contains(6015)).To(BeTrue())
+				Expect(ranges.Contains(7000)).To(BeTrue())
+				Expect(ranges.Contains(6500)).To(BeFalse())
+			})
+		})
 	})
 
 	Describe("Route", func() {
@@ -320,6 +435,57 @@ var _ = Describe("Models", func() {
 					Expect(*route.TTL).To(Equal(66))
 				})
 			})
+
+			Context("when the route is marked as draining", func() {
+				BeforeEach(func() {
+					route.Draining = true
+				})
+
+				It("clears draining", func() {
+					Expect(route.Draining).To(BeFalse())
+				})
+			})
+
+			Context("when ttl is the permanent ttl", func() {
+				BeforeEach(func() {
+					*route.TTL = PermanentTTL
+				})
+
+				It("marks the route as permanent", func() {
+					Expect(route.Permanent).To(BeTrue())
+				})
+			})
+
+			Context("when a route marked as permanent has a ttl", func() {
+				BeforeEach(func() {
+					route.Permanent = true
+				})
+
+				It("clears permanent", func() {
+					Expect(route.Permanent).To(BeFalse())
+				})
+			})
+		})
+
+		Describe("Drain", func() {
+			It("marks the route as draining until the drain ttl elapses", func() {
+				index := route.ModificationTag.Index
+				route.Drain(30)
+
+				Expect(route.Draining).To(BeTrue())
+				Expect(*route.TTL).To(Equal(30))
+				Expect(route.ExpiresAt).To(BeTemporally("~", time.Now().Add(30*time.Second), time.Second))
+				Expect(route.ModificationTag.Index).To(Equal(index + 1))
+			})
+
+			It("makes a permanent route expire", func() {
+				*route.TTL = PermanentTTL
+				route.Permanent = true
+				route.Drain(30)
+
+				Expect(route.Permanent).To(BeFalse())
+				Expect(*route.TTL).To(Equal(30))
+			})
 		})
 	})
 
@@ -353,6 +519,17 @@ var _ = Describe("Models", func() {
 			Context("when ttl is not nil", func() {
 				It("doesn't change ttl", func() {
 					Expect(*route.TTL).To(Equal(66))
+					Expect(route.Permanent).To(BeFalse())
+				})
+			})
+
+			Context("when ttl is the permanent ttl", func() {
+				BeforeEach(func() {
+					*route.TTL = PermanentTTL
+				})
+
+				It("marks the mapping as permanent", func() {
+					Expect(route.Permanent).To(BeTrue())
 				})
 			})
 		})
@@ -373,4 +550,135 @@ var _ = Describe("Models", func() {
 			})
 		})
 	})
+
+	Describe("RouteSelector", func() {
+		var route Route
+
+		BeforeEach(func() {
+			route = NewRoute("/foo/bar", 35, "2.2.2.2", "log-guid", "", 66)
+			route.Owner = "some-client"
+		})
+
+		It("matches routes with every given field", func() {
+			Expect(RouteSelector{LogGuid: "log-guid", Owner: "some-client"}.Matches(route)).To(BeTrue())
+			Expect(RouteSelector{IP: "2.2.2.2"}.Matches(route)).To(BeTrue())
+		})
+
+		It("does not match routes that differ in a given field", func() {
+			Expect(RouteSelector{LogGuid: "log-guid", IP: "3.3.3.3"}.Matches(route)).To(BeFalse())
+			Expect(RouteSelector{Owner: "other-client"}.Matches(route)).To(BeFalse())
+		})
+
+		It("is empty when no field is given", func() {
+			Expect(RouteSelector{}.Empty()).To(BeTrue())
+			Expect(RouteSelector{Owner: "some-client"}.Empty()).To(BeFalse())
+		})
+	})
+
+	Describe("TcpRouteMappingSelector", func() {
+		var tcpMapping TcpRouteMapping
+
+		BeforeEach(func() {
+			tcpMapping = NewTcpRouteMapping("router-group-1", 60000, "2.2.2.2", 64000, 66)
+			tcpMapping.Owner = "some-client"
+		})
+
+		It("matches mappings with every given field", func() {
+			Expect(TcpRouteMappingSelector{RouterGroupGuid: "router-group-1", HostIP: "2.2.2.2"}.Matches(tcpMapping)).To(BeTrue())
+		})
+
+		It("does not match mappings that differ in a given field", func() {
+			Expect(TcpRouteMappingSelector{RouterGroupGuid: "router-group-2"}.Matches(tcpMapping)).To(BeFalse())
+			Expect(TcpRouteMappingSelector{RouterGroupGuid: "router-group-1", Owner: "other-client"}.Matches(tcpMapping)).To(BeFalse())
+		})
+	})
+
+	Describe("EventFilter", func() {
+		var (
+			route      Route
+			tcpMapping TcpRouteMapping
+		)
+
+		BeforeEach(func() {
+			route = NewRoute("a.example.com", 8080, "1.1.1.1", "log-guid", "", 60)
+			tcpMapping = NewTcpRouteMapping("router-group-1", 60000, "2.2.2.2", 64000, 66)
+		})
+
+		It("matches everything when empty", func() {
+			Expect(EventFilter{}.MatchesRoute(route)).To(BeTrue())
+			Expect(EventFilter{}.MatchesTcpRouteMapping(tcpMapping)).To(BeTrue())
+		})
+
+		It("matches routes on their fields", func() {
+			Expect(EventFilter{Route: "a.example.com", LogGuid: "log-guid"}.MatchesRoute(route)).To(BeTrue())
+			Expect(EventFilter{Route: "b.example.com"}.MatchesRoute(route)).To(BeFalse())
+		})
+
+		It("matches tcp route mappings on their fields", func() {
+			Expect(EventFilter{RouterGroupGuid: "router-group-1", ExternalPort: 60000}.MatchesTcpRouteMapping(tcpMapping)).To(BeTrue())
+			Expect(EventFilter{ExternalPort: 60001}.MatchesTcpRouteMapping(tcpMapping)).To(BeFalse())
+		})
+
+		It("ignores the fields of the other kind of route", func() {
+			Expect(EventFilter{RouterGroupGuid: "router-group-2"}.MatchesRoute(route)).To(BeTrue())
+			Expect(EventFilter{LogGuid: "other-guid"}.MatchesTcpRouteMapping(tcpMapping)).To(BeTrue())
+		})
+	})
+
+	Describe("RouteV2", func() {
+		var route Route
+
+		BeforeEach(func() {
+			route = NewRoute("a.b.c", 8080, "1.2.3.4", "log-guid", "", 60)
+			route.Guid = "route-guid"
+			route.CreatedAt = time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+		})
+
+		It("shows the guid and timestamps in JSON", func() {
+			data, err := json.Marshal(NewRouteV2(route))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"guid":"route-guid"`))
+			Expect(string(data)).To(ContainSubstring(`"created_at":"2016-01-02T03:04:05Z"`))
+			Expect(string(data)).To(ContainSubstring(`"route":"a.b.c"`))
+		})
+
+		It("converts back to the route", func() {
+			Expect(NewRouteV2(route).ToRoute()).To(Equal(route))
+		})
+
+		It("identifies routes by their url, port, ip and route service url", func() {
+			other := route.RouteEntity
+			other.TTL = nil
+			other.LogGuid = "other"
+			Expect(route.SameRoute(other)).To(BeTrue())
+
+			other.Port = 8081
+			Expect(route.SameRoute(other)).To(BeFalse())
+		})
+	})
+
+	Describe("QuotaUsage", func() {
+		It("aggregates the usage of the owners or log guids", func() {
+			usage := QuotaUsage{Quota: QuotaHttpRoutesPerOwner, Limit: 3, Usage: map[string]int{"a": 1, "b": 3, "c": 4}}
+			Expect(usage.MaxUsage()).To(Equal(4))
+			Expect(usage.KeysAtLimit()).To(Equal(2))
+		})
+
+		It("aggregates no usage to zero", func() {
+			usage := QuotaUsage{Quota: QuotaHttpRoutesPerOwner, Limit: 3}
+			Expect(usage.MaxUsage()).To(Equal(0))
+			Expect(usage.KeysAtLimit()).To(Equal(0))
+		})
+	})
+
+	Describe("TcpRouteMappingV2", func() {
+		It("identifies mappings by their router group, port and backend", func() {
+			tcpMapping := NewTcpRouteMapping("router-group", 52000, "1.2.3.4", 60000, 60)
+			other := NewTcpRouteMapping("router-group", 52000, "1.2.3.4", 60000, 120)
+			Expect(tcpMapping.SameMapping(other.TcpMappingEntity)).To(BeTrue())
+
+			other.HostIP = "1.2.3.5"
+			Expect(tcpMapping.SameMapping(other.TcpMappingEntity)).To(BeFalse())
+		})
+	})
 })
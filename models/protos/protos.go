@@ -0,0 +1,292 @@
+// Package protos holds the protobuf encoding of the routing API list and event
+// payloads, which clients can request instead of JSON.
+package protos
+
+//go:generate protoc --gogo_out=. routing_api.proto
+
+import (
+	"fmt"
+	"io"
+
+	"code.cloudfoundry.org/routing-api/models"
+	"github.com/gogo/protobuf/proto"
+)
+
+// ContentType is the media type of protobuf encoded responses.
+const ContentType = "application/x-protobuf"
+
+// Marshal encodes a list of routes, tcp route mappings or router groups.
+func Marshal(list interface{}) ([]byte, error) {
+	switch list := list.(type) {
+	case []models.Route:
+		msg := &Routes{}
+		for _, route := range list {
+			msg.Routes = append(msg.Routes, NewRoute(route))
+		}
+		return proto.Marshal(msg)
+	case []models.TcpRouteMapping:
+		msg := &TcpRouteMappings{}
+		for _, tcpMapping := range list {
+			msg.TcpRouteMappings = append(msg.TcpRouteMappings, NewTcpRouteMapping(tcpMapping))
+		}
+		return proto.Marshal(msg)
+	case models.RouterGroups:
+		return Marshal([]models.RouterGroup(list))
+	case []models.RouterGroup:
+		msg := &RouterGroups{}
+		for _, routerGroup := range list {
+			msg.RouterGroups = append(msg.RouterGroups, NewRouterGroup(routerGroup))
+		}
+		return proto.Marshal(msg)
+	default:
+		return nil, fmt.Errorf("cannot encode %T as protobuf", list)
+	}
+}
+
+// Unmarshal decodes a list encoded by Marshal into a pointer to a slice of
+// routes, tcp route mappings or router groups.
+func Unmarshal(data []byte, list interface{}) error {
+	switch list := list.(type) {
+	case *[]models.Route:
+		msg := &Routes{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return err
+		}
+		routes := []models.Route{}
+		for _, route := range msg.Routes {
+			routes = append(routes, route.ToModel())
+		}
+		*list = routes
+	case *[]models.TcpRouteMapping:
+		msg := &TcpRouteMappings{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return err
+		}
+		tcpMappings := []models.TcpRouteMapping{}
+		for _, tcpMapping := range msg.TcpRouteMappings {
+			tcpMappings = append(tcpMappings, tcpMapping.ToModel())
+		}
+		*list = tcpMappings
+	case *[]models.RouterGroup:
+		msg := &RouterGroups{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return err
+		}
+		routerGroups := []models.RouterGroup{}
+		for _, routerGroup := range msg.RouterGroups {
+			routerGroups = append(routerGroups, routerGroup.ToModel())
+		}
+		*list = routerGroups
+	default:
+		return fmt.Errorf("cannot decode protobuf into %T", list)
+	}
+	return nil
+}
+
+// ListEncoder writes a list in the encoding of Marshal one item at a time. The
+// items of a repeated field are encoded one after the other, so they do not
+// need to be held in memory together.
+type ListEncoder struct {
+	w   io.Writer
+	buf *proto.Buffer
+}
+
+func NewListEncoder(w io.Writer) *ListEncoder {
+	return &ListEncoder{w: w, buf: proto.NewBuffer(nil)}
+}
+
+// Encode writes a route, tcp route mapping or router group. All items of a
+// list must have the same type.
+func (e *ListEncoder) Encode(item interface{}) error {
+	var msg proto.Message
+	switch item := item.(type) {
+	case models.Route:
+		msg = NewRoute(item)
+	case models.TcpRouteMapping:
+		msg = NewTcpRouteMapping(item)
+	case models.RouterGroup:
+		msg = NewRouterGroup(item)
+	default:
+		return fmt.Errorf("cannot encode %T as protobuf", item)
+	}
+
+	e.buf.Reset()
+	// the items are field 1 of the Routes, TcpRouteMappings and RouterGroups
+	// messages
+	err := e.buf.EncodeVarint(1<<3 | proto.WireBytes)
+	if err != nil {
+		return err
+	}
+	err = e.buf.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(e.buf.Bytes())
+	return err
+}
+
+// Close does nothing, as the list message has no other fields. It makes
+// ListEncoder interchangeable with encoders that terminate the list.
+func (e *ListEncoder) Close() error {
+	return nil
+}
+
+// EventMetadata is what an event carries besides its route or tcp route
+// mapping: the revision of the table after the event, the id of the request
+// that caused it, and the number of events lost by the subscriber of a
+// resync-required event.
+type EventMetadata struct {
+	Revision  uint64
+	RequestID string
+	Dropped   uint64
+}
+
+// MarshalRouteEvent encodes an http route event together with its metadata.
+func MarshalRouteEvent(route models.Route, metadata EventMetadata) ([]byte, error) {
+	return proto.Marshal(&RouteEvent{
+		Revision:  metadata.Revision,
+		Route:     NewRoute(route),
+		RequestId: metadata.RequestID,
+		Dropped:   metadata.Dropped,
+	})
+}
+
+func UnmarshalRouteEvent(data []byte) (models.Route, EventMetadata, error) {
+	msg := &RouteEvent{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return models.Route{}, EventMetadata{}, err
+	}
+	metadata := EventMetadata{Revision: msg.Revision, RequestID: msg.RequestId, Dropped: msg.Dropped}
+	if msg.Route == nil {
+		return models.Route{}, metadata, nil
+	}
+	return msg.Route.ToModel(), metadata, nil
+}
+
+// MarshalTcpRouteMappingEvent encodes a tcp route event together with its
+// metadata.
+func MarshalTcpRouteMappingEvent(tcpMapping models.TcpRouteMapping, metadata EventMetadata) ([]byte, error) {
+	return proto.Marshal(&TcpRouteMappingEvent{
+		Revision:        metadata.Revision,
+		TcpRouteMapping: NewTcpRouteMapping(tcpMapping),
+		RequestId:       metadata.RequestID,
+		Dropped:         metadata.Dropped,
+	})
+}
+
+func UnmarshalTcpRouteMappingEvent(data []byte) (models.TcpRouteMapping, EventMetadata, error) {
+	msg := &TcpRouteMappingEvent{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return models.TcpRouteMapping{}, EventMetadata{}, err
+	}
+	metadata := EventMetadata{Revision: msg.Revision, RequestID: msg.RequestId, Dropped: msg.Dropped}
+	if msg.TcpRouteMapping == nil {
+		return models.TcpRouteMapping{}, metadata, nil
+	}
+	return msg.TcpRouteMapping.ToModel(), metadata, nil
+}
+
+func NewModificationTag(tag models.ModificationTag) *ModificationTag {
+	return &ModificationTag{Guid: tag.Guid, Index: tag.Index}
+}
+
+func (t *ModificationTag) ToModel() models.ModificationTag {
+	if t == nil {
+		return models.ModificationTag{}
+	}
+	return models.ModificationTag{Guid: t.Guid, Index: t.Index}
+}
+
+func NewRoute(route models.Route) *Route {
+	return &Route{
+		Route:           route.Route,
+		Port:            uint32(route.Port),
+		Ip:              route.IP,
+		Ttl:             int32(route.GetTTL()),
+		LogGuid:         route.LogGuid,
+		RouteServiceUrl: route.RouteServiceUrl,
+		ModificationTag: NewModificationTag(route.ModificationTag),
+		Draining:        route.Draining,
+		Permanent:       route.Permanent,
+		Owner:           route.Owner,
+	}
+}
+
+func (r *Route) ToModel() models.Route {
+	ttl := int(r.Ttl)
+	route := models.NewRoute(r.Route, uint16(r.Port), r.Ip, r.LogGuid, r.RouteServiceUrl, ttl)
+	route.ModificationTag = r.ModificationTag.ToModel()
+	route.Draining = r.Draining
+	route.Permanent = r.Permanent
+	route.Owner = r.Owner
+	return route
+}
+
+func NewTcpRouteMapping(tcpMapping models.TcpRouteMapping) *TcpRouteMapping {
+	var ttl int32
+	if tcpMapping.TTL != nil {
+		ttl = int32(*tcpMapping.TTL)
+	}
+	return &TcpRouteMapping{
+		RouterGroupGuid: tcpMapping.RouterGroupGuid,
+		Port:            uint32(tcpMapping.ExternalPort),
+		BackendIp:       tcpMapping.HostIP,
+		BackendPort:     uint32(tcpMapping.HostPort),
+		Ttl:             ttl,
+		ModificationTag: NewModificationTag(tcpMapping.ModificationTag),
+		Draining:        tcpMapping.Draining,
+		Permanent:       tcpMapping.Permanent,
+		Owner:           tcpMapping.Owner,
+	}
+}
+
+func (m *TcpRouteMapping) ToModel() models.TcpRouteMapping {
+	tcpMapping := models.NewTcpRouteMappingWithModificationTag(
+		m.RouterGroupGuid,
+		uint16(m.Port),
+		m.BackendIp,
+		uint16(m.BackendPort),
+		int(m.Ttl),
+		m.ModificationTag.ToModel(),
+	)
+	tcpMapping.Draining = m.Draining
+	tcpMapping.Permanent = m.Permanent
+	tcpMapping.Owner = m.Owner
+	return tcpMapping
+}
+
+func NewRouterGroup(routerGroup models.RouterGroup) *RouterGroup {
+	msg := &RouterGroup{
+		Guid:            routerGroup.Guid,
+		Name:            routerGroup.Name,
+		Type:            string(routerGroup.Type),
+		ReservablePorts: string(routerGroup.ReservablePorts),
+		MaxTtl:          int32(routerGroup.MaxTTL),
+		DefaultTtl:      int32(routerGroup.DefaultTTL),
+	}
+	if policy := routerGroup.EffectiveTTL; policy != nil {
+		msg.EffectiveTtl = &TTLPolicy{
+			MaxTtl:     int32(policy.MaxTTL),
+			DefaultTtl: int32(policy.DefaultTTL),
+		}
+	}
+	return msg
+}
+
+func (g *RouterGroup) ToModel() models.RouterGroup {
+	routerGroup := models.RouterGroup{
+		Guid:            g.Guid,
+		Name:            g.Name,
+		Type:            models.RouterGroupType(g.Type),
+		ReservablePorts: models.ReservablePorts(g.ReservablePorts),
+		MaxTTL:          int(g.MaxTtl),
+		DefaultTTL:      int(g.DefaultTtl),
+	}
+	if policy := g.EffectiveTtl; policy != nil {
+		routerGroup.EffectiveTTL = &models.TTLPolicy{
+			MaxTTL:     int(policy.MaxTtl),
+			DefaultTTL: int(policy.DefaultTtl),
+		}
+	}
+	return routerGroup
+}
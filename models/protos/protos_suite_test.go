@@ -0,0 +1,13 @@
+package protos_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestProtos(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Protos Suite")
+}
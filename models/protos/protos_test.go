@@ -0,0 +1,151 @@
+package protos_test
+
+import (
+	"bytes"
+
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Protos", func() {
+	Describe("Marshal and Unmarshal", func() {
+		It("round trips routes", func() {
+			route := models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "https://rs.example.com", 60)
+			route.ModificationTag = models.ModificationTag{Guid: "tag-guid", Index: 3}
+			route.Owner = "owner"
+			permanent := models.NewRoute("b.example.com", 8081, "1.2.3.5", "", "", models.PermanentTTL)
+			permanent.Permanent = true
+			routes := []models.Route{route, permanent}
+
+			data, err := protos.Marshal(routes)
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded []models.Route
+			err = protos.Unmarshal(data, &decoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(routes))
+		})
+
+		It("round trips tcp route mappings", func() {
+			tcpMapping := models.NewTcpRouteMappingWithModificationTag("rguid", 52000, "1.2.3.4", 60000, 60, models.ModificationTag{Guid: "tag-guid", Index: 1})
+			tcpMapping.Draining = true
+			tcpMappings := []models.TcpRouteMapping{tcpMapping}
+
+			data, err := protos.Marshal(tcpMappings)
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded []models.TcpRouteMapping
+			err = protos.Unmarshal(data, &decoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(tcpMappings))
+		})
+
+		It("round trips router groups", func() {
+			routerGroups := models.RouterGroups{
+				{
+					Guid:            "rguid",
+					Name:            "default-tcp",
+					Type:            "tcp",
+					ReservablePorts: "1024-65535",
+					MaxTTL:          120,
+					EffectiveTTL:    &models.TTLPolicy{MaxTTL: 120, DefaultTTL: 60},
+				},
+			}
+
+			data, err := protos.Marshal(routerGroups)
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded []models.RouterGroup
+			err = protos.Unmarshal(data, &decoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal([]models.RouterGroup(routerGroups)))
+		})
+
+		It("decodes an empty list", func() {
+			data, err := protos.Marshal([]models.Route{})
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded []models.Route
+			err = protos.Unmarshal(data, &decoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(BeEmpty())
+		})
+
+		It("rejects other types", func() {
+			_, err := protos.Marshal([]string{"route"})
+			Expect(err).To(HaveOccurred())
+
+			var decoded []string
+			err = protos.Unmarshal(nil, &decoded)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ListEncoder", func() {
+		It("writes the same bytes as Marshal", func() {
+			routes := []models.Route{
+				models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60),
+				models.NewRoute("b.example.com", 8081, "1.2.3.5", "", "", 30),
+			}
+			expected, err := protos.Marshal(routes)
+			Expect(err).NotTo(HaveOccurred())
+
+			buf := &bytes.Buffer{}
+			encoder := protos.NewListEncoder(buf)
+			for _, route := range routes {
+				Expect(encoder.Encode(route)).To(Succeed())
+			}
+			Expect(encoder.Close()).To(Succeed())
+			Expect(buf.Bytes()).To(Equal(expected))
+
+			var decoded []models.Route
+			err = protos.Unmarshal(buf.Bytes(), &decoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(routes))
+		})
+
+		It("rejects other types", func() {
+			encoder := protos.NewListEncoder(&bytes.Buffer{})
+			Expect(encoder.Encode("route")).NotTo(Succeed())
+		})
+	})
+
+	Describe("events", func() {
+		It("round trips route events with their metadata", func() {
+			route := models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60)
+			metadata := protos.EventMetadata{Revision: 12, RequestID: "request-id"}
+
+			data, err := protos.MarshalRouteEvent(route, metadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			decoded, decodedMetadata, err := protos.UnmarshalRouteEvent(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decodedMetadata).To(Equal(metadata))
+			Expect(decoded).To(Equal(route))
+		})
+
+		It("round trips tcp route mapping events with their metadata", func() {
+			tcpMapping := models.NewTcpRouteMapping("rguid", 52000, "1.2.3.4", 60000, 60)
+			metadata := protos.EventMetadata{Revision: 4, RequestID: "request-id"}
+
+			data, err := protos.MarshalTcpRouteMappingEvent(tcpMapping, metadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			decoded, decodedMetadata, err := protos.UnmarshalTcpRouteMappingEvent(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decodedMetadata).To(Equal(metadata))
+			Expect(decoded).To(Equal(tcpMapping))
+		})
+
+		It("decodes the events dropped before a resync", func() {
+			data, err := protos.MarshalRouteEvent(models.Route{}, protos.EventMetadata{Dropped: 7})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, metadata, err := protos.UnmarshalRouteEvent(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Dropped).To(Equal(uint64(7)))
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package models
+
+const (
+	QuotaHttpRoutesPerOwner   = "http_routes_per_owner"
+	QuotaHttpRoutesPerLogGuid = "http_routes_per_log_guid"
+	QuotaTcpRoutesPerOwner    = "tcp_routes_per_owner"
+)
+
+// QuotaUsage is the number of routes counted against a quota, keyed by owner
+// or log guid.
+type QuotaUsage struct {
+	Quota string         `json:"quota"`
+	Limit int            `json:"limit"`
+	Usage map[string]int `json:"usage"`
+}
+
+// MaxUsage is the highest usage of any owner or log guid.
+func (u QuotaUsage) MaxUsage() int {
+	max := 0
+	for _, count := range u.Usage {
+		if count > max {
+			max = count
+		}
+	}
+	return max
+}
+
+// KeysAtLimit is the number of owners or log guids whose usage has reached
+// the limit.
+func (u QuotaUsage) KeysAtLimit() int {
+	keys := 0
+	for _, count := range u.Usage {
+		if count >= u.Limit {
+			keys++
+		}
+	}
+	return keys
+}
+
+func NewQuotaUsage(quota string, limit int) QuotaUsage {
+	return QuotaUsage{
+		Quota: quota,
+		Limit: limit,
+		Usage: map[string]int{},
+	}
+}
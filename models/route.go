@@ -1,11 +1,19 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nu7hatch/gouuid"
 )
 
+// PermanentTTL is the TTL of routes and tcp route mappings that never expire.
+// Registering them requires the routing.routes.permanent scope.
+const PermanentTTL = -1
+
 type Route struct {
 	Model
 	ExpiresAt time.Time `json:"-"`
@@ -19,6 +27,9 @@ type RouteEntity struct {
 	TTL             *int   `json:"ttl"`
 	LogGuid         string `json:"log_guid"`
 	RouteServiceUrl string `gorm:"not null; unique_index:idx_route" json:"route_service_url,omitempty"`
+	Draining        bool   `gorm:"not null; default:false" json:"draining,omitempty"`
+	Permanent       bool   `gorm:"not null; default:false" json:"permanent,omitempty"`
+	Owner           string `json:"owner,omitempty"`
 	ModificationTag `json:"modification_tag"`
 }
 
@@ -28,10 +39,12 @@ func NewRouteWithModel(route Route) (Route, error) {
 		return Route{}, err
 	}
 
+	entity := route.RouteEntity
+	entity.Permanent = *route.TTL == PermanentTTL
 	return Route{
 		ExpiresAt:   time.Now().Add(time.Duration(*route.TTL) * time.Second),
 		Model:       Model{Guid: guid.String()},
-		RouteEntity: route.RouteEntity,
+		RouteEntity: entity,
 	}, nil
 }
 func NewRoute(url string, port uint16, ip, logGuid, routeServiceUrl string, ttl int) Route {
@@ -79,13 +92,51 @@ func (r Route) GetTTL() int {
 	return *r.TTL
 }
 
+// SetDefaults defaults the TTL of a route submitted for registration, marks
+// it permanent when its TTL is PermanentTTL and clears Draining, which is only
+// set by draining the route.
 func (r *Route) SetDefaults(defaultTTL int) {
 	if r.TTL == nil {
 		r.TTL = &defaultTTL
 	}
+	r.Permanent = *r.TTL == PermanentTTL
+	r.Draining = false
+}
+
+// Drain marks the route as draining until it expires after drainTTL seconds.
+// Draining a permanent route makes it expire.
+func (r *Route) Drain(drainTTL int) {
+	r.Draining = true
+	r.Permanent = false
+	r.TTL = &drainTTL
+	r.ExpiresAt = time.Now().Add(time.Duration(drainTTL) * time.Second)
+	r.ModificationTag.Increment()
 }
 
 type ModificationTag struct {
 	Guid  string `gorm:"column:modification_guid" json:"guid"`
 	Index uint32 `gorm:"column:modification_index" json:"index"`
 }
+
+// FormatModificationTag renders a tag as an entity tag for the ETag and
+// If-Match headers, e.g. "abc-123:5".
+func FormatModificationTag(t ModificationTag) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%s:%d", t.Guid, t.Index))
+}
+
+// ParseModificationTag parses a tag rendered by FormatModificationTag. The
+// surrounding quotes are optional.
+func ParseModificationTag(value string) (ModificationTag, error) {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	sep := strings.LastIndex(value, ":")
+	if sep <= 0 {
+		return ModificationTag{}, errors.New("modification tag must be of the form guid:index")
+	}
+
+	index, err := strconv.ParseUint(value[sep+1:], 10, 32)
+	if err != nil {
+		return ModificationTag{}, errors.New("modification tag index must be a non-negative integer")
+	}
+
+	return ModificationTag{Guid: value[:sep], Index: uint32(index)}, nil
+}
@@ -18,14 +18,30 @@ type RouterGroupDB struct {
 	Name            string
 	Type            string
 	ReservablePorts string
+	MaxTTL          int
+	DefaultTTL      int
 }
 
+// RouterGroup is a group of routers sharing the same reservable ports. MaxTTL
+// and DefaultTTL, in seconds, override the TTL policy of TCP routes for the
+// group when set. EffectiveTTL is only set in responses and holds the policy
+// that applies to the group.
 type RouterGroup struct {
 	Model
 	Guid            string          `json:"guid"`
 	Name            string          `json:"name"`
 	Type            RouterGroupType `json:"type"`
 	ReservablePorts ReservablePorts `json:"reservable_ports" yaml:"reservable_ports"`
+	MaxTTL          int             `json:"max_ttl,omitempty" yaml:"max_ttl"`
+	DefaultTTL      int             `json:"default_ttl,omitempty" yaml:"default_ttl"`
+	EffectiveTTL    *TTLPolicy      `json:"effective_ttl,omitempty" yaml:"-"`
+}
+
+// TTLPolicy is the maximum TTL of routes and the TTL routes registered
+// without one get, in seconds.
+type TTLPolicy struct {
+	MaxTTL     int `json:"max_ttl"`
+	DefaultTTL int `json:"default_ttl"`
 }
 
 func NewRouterGroupDB(routerGroup RouterGroup) RouterGroupDB {
@@ -39,6 +55,8 @@ func NewRouterGroupDB(routerGroup RouterGroup) RouterGroupDB {
 		Name:            routerGroup.Name,
 		Type:            string(routerGroup.Type),
 		ReservablePorts: string(routerGroup.ReservablePorts),
+		MaxTTL:          routerGroup.MaxTTL,
+		DefaultTTL:      routerGroup.DefaultTTL,
 	}
 }
 
@@ -53,6 +71,8 @@ func (rg *RouterGroupDB) ToRouterGroup() RouterGroup {
 		Name:            rg.Name,
 		Type:            RouterGroupType(rg.Type),
 		ReservablePorts: ReservablePorts(rg.ReservablePorts),
+		MaxTTL:          rg.MaxTTL,
+		DefaultTTL:      rg.DefaultTTL,
 	}
 }
 
@@ -66,6 +86,14 @@ func (rgs RouterGroupsDB) ToRouterGroups() RouterGroups {
 
 type RouterGroups []RouterGroup
 
+func (g RouterGroups) Names() []string {
+	names := make([]string, 0, len(g))
+	for _, r := range g {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
 func (g RouterGroups) Validate() error {
 	for _, r := range g {
 		if err := r.Validate(); err != nil {
@@ -90,9 +118,42 @@ func (g RouterGroup) Validate() error {
 	if err != nil {
 		return err
 	}
+
+	if g.MaxTTL < 0 || g.DefaultTTL < 0 {
+		return errors.New(fmt.Sprintf("TTLs cannot be negative in router group: %s", g.Name))
+	}
+	if g.MaxTTL > 0 && g.DefaultTTL > g.MaxTTL {
+		return errors.New(fmt.Sprintf("default_ttl cannot be greater than max_ttl in router group: %s", g.Name))
+	}
+	return nil
+}
+
+// ValidateTTLs returns an error when a TTL of the group is greater than
+// maxTTL, the max TTL of all TCP routes. Router groups can only lower it.
+func (g RouterGroup) ValidateTTLs(maxTTL int) error {
+	if g.MaxTTL > maxTTL || g.DefaultTTL > maxTTL {
+		return fmt.Errorf("TTLs cannot be greater than the global max_ttl of %d in router group: %s", maxTTL, g.Name)
+	}
 	return nil
 }
 
+// TTLPolicy returns the TTL policy of TCP routes in the router group. TTLs the
+// group does not set are taken from defaults, the group cannot raise the max
+// TTL of defaults, and the default TTL never exceeds the maximum.
+func (g RouterGroup) TTLPolicy(defaults TTLPolicy) TTLPolicy {
+	policy := defaults
+	if g.MaxTTL > 0 && g.MaxTTL < policy.MaxTTL {
+		policy.MaxTTL = g.MaxTTL
+	}
+	if g.DefaultTTL > 0 {
+		policy.DefaultTTL = g.DefaultTTL
+	}
+	if policy.DefaultTTL > policy.MaxTTL {
+		policy.DefaultTTL = policy.MaxTTL
+	}
+	return policy
+}
+
 type ReservablePorts string
 
 func (p ReservablePorts) Validate() error {
@@ -167,6 +228,20 @@ func (r Range) Overlaps(other Range) bool {
 	return maxUpper-minLower <= (r.end-r.start)+(other.end-other.start)
 }
 
+func (r Range) Contains(port uint64) bool {
+	return port >= r.start && port <= r.end
+}
+
+// Contains reports whether the port is in any of the ranges.
+func (r Ranges) Contains(port uint64) bool {
+	for _, portRange := range r {
+		if portRange.Contains(port) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r Range) String() string {
 	if r.start == r.end {
 		return fmt.Sprintf("%d", r.start)
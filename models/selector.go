@@ -0,0 +1,88 @@
+package models
+
+// RouteSelector selects the HTTP routes deleted by a bulk delete. Empty
+// fields match any route.
+type RouteSelector struct {
+	LogGuid string
+	IP      string
+	Owner   string
+}
+
+func (s RouteSelector) Empty() bool {
+	return s == RouteSelector{}
+}
+
+func (s RouteSelector) Matches(route Route) bool {
+	if s.LogGuid != "" && route.LogGuid != s.LogGuid {
+		return false
+	}
+	if s.IP != "" && route.IP != s.IP {
+		return false
+	}
+	if s.Owner != "" && route.Owner != s.Owner {
+		return false
+	}
+	return true
+}
+
+// TcpRouteMappingSelector selects the TCP route mappings deleted by a bulk
+// delete. Empty fields match any mapping.
+type TcpRouteMappingSelector struct {
+	RouterGroupGuid string
+	HostIP          string
+	Owner           string
+}
+
+func (s TcpRouteMappingSelector) Empty() bool {
+	return s == TcpRouteMappingSelector{}
+}
+
+func (s TcpRouteMappingSelector) Matches(tcpMapping TcpRouteMapping) bool {
+	if s.RouterGroupGuid != "" && tcpMapping.RouterGroupGuid != s.RouterGroupGuid {
+		return false
+	}
+	if s.HostIP != "" && tcpMapping.HostIP != s.HostIP {
+		return false
+	}
+	if s.Owner != "" && tcpMapping.Owner != s.Owner {
+		return false
+	}
+	return true
+}
+
+// EventFilter selects the events sent to a WebSocket event subscription.
+// Empty fields match any route, and fields of the other kind of route are
+// ignored.
+type EventFilter struct {
+	Route           string `json:"route,omitempty"`
+	LogGuid         string `json:"log_guid,omitempty"`
+	RouterGroupGuid string `json:"router_group_guid,omitempty"`
+	ExternalPort    uint16 `json:"port,omitempty"`
+	Owner           string `json:"owner,omitempty"`
+}
+
+func (f EventFilter) MatchesRoute(route Route) bool {
+	if f.Route != "" && route.Route != f.Route {
+		return false
+	}
+	if f.LogGuid != "" && route.LogGuid != f.LogGuid {
+		return false
+	}
+	if f.Owner != "" && route.Owner != f.Owner {
+		return false
+	}
+	return true
+}
+
+func (f EventFilter) MatchesTcpRouteMapping(tcpMapping TcpRouteMapping) bool {
+	if f.RouterGroupGuid != "" && tcpMapping.RouterGroupGuid != f.RouterGroupGuid {
+		return false
+	}
+	if f.ExternalPort != 0 && tcpMapping.ExternalPort != f.ExternalPort {
+		return false
+	}
+	if f.Owner != "" && tcpMapping.Owner != f.Owner {
+		return false
+	}
+	return true
+}
@@ -19,7 +19,10 @@ type TcpMappingEntity struct {
 	HostIP          string `gorm:"not null; unique_index:idx_tcp_route" json:"backend_ip"`
 	ExternalPort    uint16 `gorm:"not null; unique_index:idx_tcp_route; type: int" json:"port"`
 	ModificationTag `json:"modification_tag"`
-	TTL             *int `json:"ttl,omitempty"`
+	TTL             *int   `json:"ttl,omitempty"`
+	Draining        bool   `gorm:"not null; default:false" json:"draining,omitempty"`
+	Permanent       bool   `gorm:"not null; default:false" json:"permanent,omitempty"`
+	Owner           string `json:"owner,omitempty"`
 }
 
 func (TcpRouteMapping) TableName() string {
@@ -33,10 +36,12 @@ func NewTcpRouteMappingWithModel(tcpMapping TcpRouteMapping) (TcpRouteMapping, e
 	}
 
 	m := Model{Guid: guid.String()}
+	entity := tcpMapping.TcpMappingEntity
+	entity.Permanent = *tcpMapping.TTL == PermanentTTL
 	return TcpRouteMapping{
 		ExpiresAt:        time.Now().Add(time.Duration(*tcpMapping.TTL) * time.Second),
 		Model:            m,
-		TcpMappingEntity: tcpMapping.TcpMappingEntity,
+		TcpMappingEntity: entity,
 	}, nil
 }
 
@@ -79,11 +84,24 @@ func (m TcpRouteMapping) Matches(other TcpRouteMapping) bool {
 		*m.TTL == *other.TTL
 }
 
-func (t *TcpRouteMapping) SetDefaults(maxTTL int) {
+func (t *TcpRouteMapping) SetDefaults(defaultTTL int) {
 	// default ttl if not present
 	// TTL is a pointer to a uint16 so that we can
 	// detect if it's present or not (i.e. nil or 0)
 	if t.TTL == nil {
-		t.TTL = &maxTTL
+		t.TTL = &defaultTTL
 	}
+	t.Permanent = *t.TTL == PermanentTTL
+	// draining is only set by draining the mapping
+	t.Draining = false
+}
+
+// Drain marks the mapping as draining until it expires after drainTTL seconds.
+// Draining a permanent mapping makes it expire.
+func (t *TcpRouteMapping) Drain(drainTTL int) {
+	t.Draining = true
+	t.Permanent = false
+	t.TTL = &drainTTL
+	t.ExpiresAt = time.Now().Add(time.Duration(drainTTL) * time.Second)
+	t.ModificationTag.Increment()
 }
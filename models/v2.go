@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// RouteV2 is a route as represented by the v2 API, which addresses routes by
+// their guid and shows when they were created and last updated.
+type RouteV2 struct {
+	Guid      string    `json:"guid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	RouteEntity
+}
+
+func NewRouteV2(route Route) RouteV2 {
+	return RouteV2{
+		Guid:        route.Guid,
+		CreatedAt:   route.CreatedAt,
+		UpdatedAt:   route.UpdatedAt,
+		RouteEntity: route.RouteEntity,
+	}
+}
+
+func (r RouteV2) ToRoute() Route {
+	return Route{
+		Model:       Model{Guid: r.Guid, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt},
+		RouteEntity: r.RouteEntity,
+	}
+}
+
+// SameRoute reports whether other has the url, port, ip and route service url
+// of the route, which identify a route and cannot be updated.
+func (r RouteEntity) SameRoute(other RouteEntity) bool {
+	return r.Route == other.Route &&
+		r.Port == other.Port &&
+		r.IP == other.IP &&
+		r.RouteServiceUrl == other.RouteServiceUrl
+}
+
+// TcpRouteMappingV2 is a tcp route mapping as represented by the v2 API.
+type TcpRouteMappingV2 struct {
+	Guid      string    `json:"guid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	TcpMappingEntity
+}
+
+func NewTcpRouteMappingV2(tcpMapping TcpRouteMapping) TcpRouteMappingV2 {
+	return TcpRouteMappingV2{
+		Guid:             tcpMapping.Guid,
+		CreatedAt:        tcpMapping.CreatedAt,
+		UpdatedAt:        tcpMapping.UpdatedAt,
+		TcpMappingEntity: tcpMapping.TcpMappingEntity,
+	}
+}
+
+func (m TcpRouteMappingV2) ToTcpRouteMapping() TcpRouteMapping {
+	return TcpRouteMapping{
+		Model:            Model{Guid: m.Guid, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt},
+		TcpMappingEntity: m.TcpMappingEntity,
+	}
+}
+
+// SameMapping reports whether other has the router group, external port and
+// backend of the mapping, which identify a mapping and cannot be updated.
+func (m TcpMappingEntity) SameMapping(other TcpMappingEntity) bool {
+	return m.RouterGroupGuid == other.RouterGroupGuid &&
+		m.ExternalPort == other.ExternalPort &&
+		m.HostIP == other.HostIP &&
+		m.HostPort == other.HostPort
+}
@@ -0,0 +1,32 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Handler serves the document. It does not require a token, so that clients
+// can be generated from a running API.
+type Handler struct {
+	document []byte
+	logger   lager.Logger
+}
+
+func NewHandler(logger lager.Logger) (*Handler, error) {
+	document, err := json.Marshal(Generate())
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{document: document, logger: logger}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(h.document)
+	if err != nil {
+		h.logger.Error("failed-to-write-to-response", err)
+	}
+}
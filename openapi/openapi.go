@@ -0,0 +1,164 @@
+// Package openapi describes the HTTP API of the routing API as an OpenAPI 3
+// document generated from the route table, the models and the API errors.
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	routing_api "code.cloudfoundry.org/routing-api"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps the lower case methods of a path to their operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Maximum              *int64             `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+const securitySchemeName = "uaaToken"
+
+// Generate returns the document of the operations of routing_api.RoutesMap.
+func Generate() *Document {
+	schemas := newSchemaGenerator()
+	errorSchema := schemas.schemaFor(errorType)
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Routing API",
+			Description: "Registers HTTP and TCP routes and router groups of Cloud Foundry routers.",
+			Version:     "v1",
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				securitySchemeName: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "A UAA token with the scopes listed by each operation.",
+				},
+			},
+		},
+		Security: []map[string][]string{{securitySchemeName: {}}},
+	}
+
+	names := make([]string, 0, len(routing_api.RoutesMap))
+	for name := range routing_api.RoutesMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		op, ok := operations[name]
+		if !ok {
+			continue
+		}
+		route := routing_api.RoutesMap[name]
+		path, pathParams := convertPath(route.Path)
+
+		operation := op.build(name, schemas, errorSchema)
+		operation.Parameters = append(pathParams, operation.Parameters...)
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	// sent by WebSocket clients, which no operation has as a body
+	schemas.schemaFor(reflect.TypeOf(routing_api.WebSocketControl{}))
+
+	doc.Components.Schemas = schemas.schemas
+	return doc
+}
+
+// convertPath converts the :name parameters of a rata path to {name}.
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
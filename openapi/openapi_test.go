@@ -0,0 +1,92 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/openapi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPI", func() {
+	var document *openapi.Document
+
+	BeforeEach(func() {
+		document = openapi.Generate()
+	})
+
+	It("describes every route of the routes map", func() {
+		for name, route := range routing_api.RoutesMap {
+			path := route.Path
+			for _, segment := range strings.Split(path, "/") {
+				if strings.HasPrefix(segment, ":") {
+					path = strings.Replace(path, segment, "{"+segment[1:]+"}", 1)
+				}
+			}
+
+			Expect(document.Paths).To(HaveKey(path), "route %s is missing from the OpenAPI document", name)
+			Expect(document.Paths[path]).To(HaveKey(strings.ToLower(route.Method)), "route %s is missing from the OpenAPI document", name)
+			Expect(document.Paths[path][strings.ToLower(route.Method)].OperationID).To(Equal(name))
+		}
+	})
+
+	It("describes the path parameters", func() {
+		operation := document.Paths["/routing/v1/router_groups/{guid}"]["put"]
+		Expect(operation.Parameters).To(ContainElement(openapi.Parameter{
+			Name:     "guid",
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "string"},
+		}))
+	})
+
+	It("derives the schemas from the JSON encoding of the models", func() {
+		route := document.Components.Schemas["Route"]
+		Expect(route).NotTo(BeNil())
+		Expect(route.Properties).To(HaveKey("route"))
+		Expect(route.Properties).To(HaveKey("log_guid"))
+		Expect(route.Properties["modification_tag"].Ref).To(Equal("#/components/schemas/ModificationTag"))
+		Expect(route.Properties).NotTo(HaveKey("Guid"))
+		Expect(route.Properties).NotTo(HaveKey("ExpiresAt"))
+		Expect(*route.Properties["port"].Maximum).To(Equal(int64(65535)))
+	})
+
+	It("describes the error types", func() {
+		errorSchema := document.Components.Schemas["Error"]
+		Expect(errorSchema).NotTo(BeNil())
+		Expect(errorSchema.Properties["name"].Enum).To(ContainElement(string(routing_api.RouteInvalidError)))
+	})
+
+	It("only refers to defined schemas", func() {
+		data, err := json.Marshal(document)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, part := range strings.Split(string(data), `"$ref":"#/components/schemas/`)[1:] {
+			name := part[:strings.Index(part, `"`)]
+			Expect(document.Components.Schemas).To(HaveKey(name))
+		}
+	})
+
+	Describe("Handler", func() {
+		It("serves the document", func() {
+			handler, err := openapi.NewHandler(lagertest.NewTestLogger("openapi"))
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/routing/v1/openapi.json", nil))
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			var served map[string]interface{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &served)).To(Succeed())
+			Expect(served["openapi"]).To(Equal(openapi.Version))
+		})
+	})
+})
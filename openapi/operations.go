@@ -0,0 +1,423 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	routing_api "code.cloudfoundry.org/routing-api"
+	"code.cloudfoundry.org/routing-api/handlers"
+	"code.cloudfoundry.org/routing-api/health"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+)
+
+// operation describes a route of routing_api.RoutesMap. Bodies are values
+// whose type is encoded as the JSON body.
+type operation struct {
+	summary     string
+	description string
+	scopes      []string
+	parameters  []Parameter
+	request     interface{}
+	responses   map[int]response
+}
+
+type response struct {
+	description string
+	body        interface{}
+	contentType string
+}
+
+func (op operation) build(name string, schemas *schemaGenerator, errorSchema *Schema) *Operation {
+	operation := &Operation{
+		OperationID: name,
+		Summary:     op.summary,
+		Description: op.description,
+		Parameters:  op.parameters,
+		Responses: map[string]Response{
+			"default": {
+				Description: "The request failed.",
+				Content:     map[string]MediaType{"application/json": {Schema: errorSchema}},
+			},
+		},
+	}
+
+	if len(op.scopes) > 0 {
+		scopes := "Requires one of the scopes " + strings.Join(op.scopes, ", ") + "."
+		if operation.Description == "" {
+			operation.Description = scopes
+		} else {
+			operation.Description += " " + scopes
+		}
+	} else {
+		operation.Security = []map[string][]string{{}}
+	}
+
+	if op.request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: schemas.schemaFor(reflect.TypeOf(op.request))},
+			},
+		}
+	}
+
+	codes := make([]int, 0, len(op.responses))
+	for code := range op.responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		resp := op.responses[code]
+		result := Response{Description: resp.description}
+		if resp.body != nil {
+			contentType := resp.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			result.Content = map[string]MediaType{
+				contentType: {Schema: schemas.schemaFor(reflect.TypeOf(resp.body))},
+			}
+			if contentType == "application/json" && isList(resp.body) {
+				result.Content[protos.ContentType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+			}
+		}
+		operation.Responses[strconv.Itoa(code)] = result
+	}
+
+	return operation
+}
+
+// isList reports whether the list endpoints encode body as protobuf for
+// clients that accept it.
+func isList(body interface{}) bool {
+	switch body.(type) {
+	case []models.Route, []models.TcpRouteMapping, models.RouterGroups:
+		return true
+	}
+	return false
+}
+
+func queryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func stringParam(name, description string) Parameter {
+	return queryParam(name, description, &Schema{Type: "string"})
+}
+
+func requiredParam(param Parameter) Parameter {
+	param.Required = true
+	return param
+}
+
+var (
+	portSchema = &Schema{Type: "integer", Minimum: int64Ptr(0), Maximum: int64Ptr(65535)}
+
+	dryRunParam      = queryParam("dry_run", "Describe the changes of the request without writing them.", &Schema{Type: "boolean"})
+	conditionalParam = queryParam("conditional", "Make the write of each item conditional on its modification_tag.", &Schema{Type: "boolean"})
+	drainParam       = queryParam("drain", "Seconds the deleted routes keep draining before they are removed.", &Schema{Type: "integer", Minimum: int64Ptr(1)})
+	ifMatchParam     = Parameter{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "The modification tag, as guid:index, every item of a conditional write must be at.",
+		Schema:      &Schema{Type: "string"},
+	}
+	ifNoneMatchParam = Parameter{
+		Name:        "If-None-Match",
+		In:          "header",
+		Description: "The ETag of a previous response, answered with 304 Not Modified while the table is unchanged.",
+		Schema:      &Schema{Type: "string"},
+	}
+
+	webSocketParams = []Parameter{
+		queryParam("filter", "The initial filter of the subscription, a JSON encoded EventFilter.", &Schema{Type: "string"}),
+		queryParam("window", "The maximum number of unacknowledged events.", &Schema{Type: "integer", Minimum: int64Ptr(0)}),
+		stringParam("access_token", "The UAA token, for clients that cannot set the Authorization header."),
+	}
+
+	notModified  = response{description: "The table is unchanged since the If-None-Match ETag."}
+	eventStream  = response{description: "A stream of server-sent events.", body: "", contentType: "text/event-stream"}
+	webSocket    = response{description: "The connection switched to the WebSocket protocol. Events are sent as WebSocketEvent messages and WebSocketControl messages are accepted.", body: routing_api.WebSocketEvent{}}
+	routeChanges = response{description: "The changes of a dry run.", body: []models.RouteChange{}}
+	tcpChanges   = response{description: "The changes of a dry run.", body: []models.TcpRouteMappingChange{}}
+)
+
+var operations = map[string]operation{
+	routing_api.UpsertRoute: {
+		summary:     "Register HTTP routes",
+		description: "Permanent routes also require the " + handlers.RoutingRoutesPermanentScope + " scope.",
+		scopes:      []string{handlers.RoutingRoutesWriteScope},
+		parameters:  []Parameter{dryRunParam, conditionalParam, ifMatchParam},
+		request:     []models.Route{},
+		responses: map[int]response{
+			200: routeChanges,
+			201: {description: "The routes were registered."},
+		},
+	},
+	routing_api.DeleteRoute: {
+		summary:    "Delete HTTP routes",
+		scopes:     []string{handlers.RoutingRoutesWriteScope},
+		parameters: []Parameter{dryRunParam, drainParam, conditionalParam, ifMatchParam},
+		request:    []models.Route{},
+		responses: map[int]response{
+			200: routeChanges,
+			204: {description: "The routes were deleted."},
+		},
+	},
+	routing_api.ListRoute: {
+		summary:    "List HTTP routes",
+		scopes:     []string{handlers.RoutingRoutesReadScope},
+		parameters: []Parameter{ifNoneMatchParam},
+		responses: map[int]response{
+			200: {description: "The HTTP routes.", body: []models.Route{}},
+			304: notModified,
+		},
+	},
+	routing_api.EventStreamRoute: {
+		summary:   "Subscribe to events for HTTP routes",
+		scopes:    []string{handlers.RoutingRoutesReadScope},
+		responses: map[int]response{200: eventStream},
+	},
+	routing_api.EventStreamWebSocketRoute: {
+		summary:    "Subscribe to events for HTTP routes over WebSocket",
+		scopes:     []string{handlers.RoutingRoutesReadScope},
+		parameters: webSocketParams,
+		responses:  map[int]response{101: webSocket},
+	},
+	routing_api.DeleteRoutesBySelector: {
+		summary: "Delete HTTP routes by selector",
+		scopes:  []string{handlers.RoutingRoutesWriteScope},
+		parameters: []Parameter{
+			stringParam("log_guid", "Delete the routes with this log guid."),
+			stringParam("ip", "Delete the routes with this backend IP."),
+			stringParam("owner", "Delete the routes registered by this client."),
+			dryRunParam,
+		},
+		responses: map[int]response{
+			200: {description: "The deleted routes.", body: []models.Route{}},
+		},
+	},
+	routing_api.ListRouteHistory: {
+		summary: "List the versions of an HTTP route",
+		scopes:  []string{handlers.RoutingRoutesReadScope},
+		parameters: []Parameter{
+			requiredParam(stringParam("route", "The route.")),
+			requiredParam(stringParam("ip", "The backend IP.")),
+			requiredParam(queryParam("port", "The backend port.", portSchema)),
+		},
+		responses: map[int]response{
+			200: {description: "The versions, most recent first.", body: []models.RouteVersion{}},
+		},
+	},
+	routing_api.ListRouterGroups: {
+		summary:    "List router groups",
+		scopes:     []string{handlers.RouterGroupsReadScope, handlers.RouterGroupReadScope("<name>")},
+		parameters: []Parameter{ifNoneMatchParam},
+		responses: map[int]response{
+			200: {description: "The router groups.", body: models.RouterGroups{}},
+			304: notModified,
+		},
+	},
+	routing_api.UpdateRouterGroup: {
+		summary:    "Update a router group",
+		scopes:     []string{handlers.RouterGroupsWriteScope, handlers.RouterGroupWriteScope("<name>")},
+		parameters: []Parameter{dryRunParam},
+		request:    models.RouterGroup{},
+		responses: map[int]response{
+			200: {description: "The updated router group, or the change of a dry run.", body: models.RouterGroup{}},
+		},
+	},
+	routing_api.UpsertTcpRouteMapping: {
+		summary:     "Register TCP routes",
+		description: "Permanent routes also require the " + handlers.RoutingRoutesPermanentScope + " scope.",
+		scopes:      []string{handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope("<name>")},
+		parameters:  []Parameter{dryRunParam, conditionalParam, ifMatchParam},
+		request:     []models.TcpRouteMapping{},
+		responses: map[int]response{
+			200: tcpChanges,
+			201: {description: "The TCP routes were registered."},
+		},
+	},
+	routing_api.DeleteTcpRouteMapping: {
+		summary:    "Delete TCP routes",
+		scopes:     []string{handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope("<name>")},
+		parameters: []Parameter{dryRunParam, drainParam, conditionalParam, ifMatchParam},
+		request:    []models.TcpRouteMapping{},
+		responses: map[int]response{
+			200: tcpChanges,
+			204: {description: "The TCP routes were deleted."},
+		},
+	},
+	routing_api.ListTcpRouteMapping: {
+		summary:    "List TCP routes",
+		scopes:     []string{handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope("<name>")},
+		parameters: []Parameter{ifNoneMatchParam},
+		responses: map[int]response{
+			200: {description: "The TCP routes.", body: []models.TcpRouteMapping{}},
+			304: notModified,
+		},
+	},
+	routing_api.EventStreamTcpRoute: {
+		summary:   "Subscribe to events for TCP routes",
+		scopes:    []string{handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope("<name>")},
+		responses: map[int]response{200: eventStream},
+	},
+	routing_api.EventStreamTcpWebSocketRoute: {
+		summary:    "Subscribe to events for TCP routes over WebSocket",
+		scopes:     []string{handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope("<name>")},
+		parameters: webSocketParams,
+		responses:  map[int]response{101: webSocket},
+	},
+	routing_api.DeleteTcpRouteMappingsBySelector: {
+		summary: "Delete TCP routes by selector",
+		scopes:  []string{handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope("<name>")},
+		parameters: []Parameter{
+			stringParam("router_group_guid", "Delete the TCP routes of this router group."),
+			stringParam("backend_ip", "Delete the TCP routes with this backend IP."),
+			stringParam("owner", "Delete the TCP routes registered by this client."),
+			dryRunParam,
+		},
+		responses: map[int]response{
+			200: {description: "The deleted TCP routes.", body: []models.TcpRouteMapping{}},
+		},
+	},
+	routing_api.ListTcpRouteHistory: {
+		summary: "List the versions of a TCP route",
+		scopes:  []string{handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope("<name>")},
+		parameters: []Parameter{
+			requiredParam(stringParam("router_group_guid", "The router group.")),
+			requiredParam(queryParam("port", "The external port.", portSchema)),
+			requiredParam(stringParam("backend_ip", "The backend IP.")),
+			requiredParam(queryParam("backend_port", "The backend port.", portSchema)),
+		},
+		responses: map[int]response{
+			200: {description: "The versions, most recent first.", body: []models.RouteVersion{}},
+		},
+	},
+	routing_api.ListAuditRecords: {
+		summary: "List audit records",
+		scopes:  []string{handlers.AuditReadScope},
+		parameters: []Parameter{
+			stringParam("since", "Only records at or after this RFC3339 time or unix timestamp."),
+			stringParam("until", "Only records before this RFC3339 time or unix timestamp."),
+			stringParam("actor", "Only records of this actor."),
+			stringParam("route", "Only records of this route key."),
+			queryParam("limit", "The maximum number of records.", &Schema{Type: "integer", Minimum: int64Ptr(0)}),
+		},
+		responses: map[int]response{
+			200: {description: "The audit records, most recent first.", body: []models.AuditRecord{}},
+		},
+	},
+	routing_api.ListQuotas: {
+		summary: "List quota usage",
+		scopes:  []string{handlers.RoutingRoutesReadScope},
+		responses: map[int]response{
+			200: {description: "The usage of each quota.", body: []models.QuotaUsage{}},
+		},
+	},
+	routing_api.ListRoutesV2: {
+		summary: "List HTTP routes with their guids",
+		scopes:  []string{handlers.RoutingRoutesReadScope},
+		responses: map[int]response{
+			200: {description: "The HTTP routes.", body: []models.RouteV2{}},
+		},
+	},
+	routing_api.CreateRouteV2: {
+		summary:     "Register an HTTP route",
+		description: "Registering an existing route fails with 409 Conflict. Permanent routes also require the " + handlers.RoutingRoutesPermanentScope + " scope.",
+		scopes:      []string{handlers.RoutingRoutesWriteScope},
+		request:     models.RouteV2{},
+		responses: map[int]response{
+			201: {description: "The registered route. The Location header is its URL.", body: models.RouteV2{}},
+		},
+	},
+	routing_api.GetRouteV2: {
+		summary: "Get an HTTP route",
+		scopes:  []string{handlers.RoutingRoutesReadScope},
+		responses: map[int]response{
+			200: {description: "The HTTP route.", body: models.RouteV2{}},
+		},
+	},
+	routing_api.UpdateRouteV2: {
+		summary:     "Update the ttl and log guid of an HTTP route",
+		description: "A modification_tag in the body makes the update conditional, as does If-Match.",
+		scopes:      []string{handlers.RoutingRoutesWriteScope},
+		parameters:  []Parameter{ifMatchParam},
+		request:     models.RouteV2{},
+		responses: map[int]response{
+			200: {description: "The updated route.", body: models.RouteV2{}},
+		},
+	},
+	routing_api.DeleteRouteV2: {
+		summary:    "Delete an HTTP route",
+		scopes:     []string{handlers.RoutingRoutesWriteScope},
+		parameters: []Parameter{ifMatchParam},
+		responses: map[int]response{
+			204: {description: "The route was deleted."},
+		},
+	},
+	routing_api.ListTcpRouteMappingsV2: {
+		summary: "List TCP routes with their guids",
+		scopes:  []string{handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope("<name>")},
+		responses: map[int]response{
+			200: {description: "The TCP routes.", body: []models.TcpRouteMappingV2{}},
+		},
+	},
+	routing_api.CreateTcpRouteMappingV2: {
+		summary:     "Register a TCP route",
+		description: "Registering an existing TCP route fails with 409 Conflict. Permanent routes also require the " + handlers.RoutingRoutesPermanentScope + " scope.",
+		scopes:      []string{handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope("<name>")},
+		request:     models.TcpRouteMappingV2{},
+		responses: map[int]response{
+			201: {description: "The registered TCP route. The Location header is its URL.", body: models.TcpRouteMappingV2{}},
+		},
+	},
+	routing_api.GetTcpRouteMappingV2: {
+		summary: "Get a TCP route",
+		scopes:  []string{handlers.RoutingRoutesReadScope, handlers.RoutingRoutesGroupReadScope("<name>")},
+		responses: map[int]response{
+			200: {description: "The TCP route.", body: models.TcpRouteMappingV2{}},
+		},
+	},
+	routing_api.UpdateTcpRouteMappingV2: {
+		summary:     "Update the ttl of a TCP route",
+		description: "A modification_tag in the body makes the update conditional, as does If-Match.",
+		scopes:      []string{handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope("<name>")},
+		parameters:  []Parameter{ifMatchParam},
+		request:     models.TcpRouteMappingV2{},
+		responses: map[int]response{
+			200: {description: "The updated TCP route.", body: models.TcpRouteMappingV2{}},
+		},
+	},
+	routing_api.DeleteTcpRouteMappingV2: {
+		summary:    "Delete a TCP route",
+		scopes:     []string{handlers.RoutingRoutesWriteScope, handlers.RoutingRoutesGroupWriteScope("<name>")},
+		parameters: []Parameter{ifMatchParam},
+		responses: map[int]response{
+			204: {description: "The TCP route was deleted."},
+		},
+	},
+	routing_api.OpenAPIRoute: {
+		summary: "Describe the API",
+		responses: map[int]response{
+			200: {description: "This OpenAPI document.", body: map[string]interface{}{}},
+		},
+	},
+	routing_api.HealthRoute: {
+		summary: "Check liveness",
+		responses: map[int]response{
+			200: {description: "The process serves requests.", body: map[string]string{}},
+		},
+	},
+	routing_api.ReadyRoute: {
+		summary:     "Check readiness",
+		description: "The result of each check is only served by the admin listener.",
+		responses: map[int]response{
+			200: {description: "The API is ready.", body: health.Report{}},
+			503: {description: "A check failed.", body: health.Report{}},
+		},
+	},
+}
@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	routing_api "code.cloudfoundry.org/routing-api"
+)
+
+var (
+	errorType     = reflect.TypeOf(routing_api.Error{})
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// enums are the values of the string types with a fixed set of values.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(routing_api.Type("")): {
+		string(routing_api.ResponseError),
+		string(routing_api.ResourceNotFoundError),
+		string(routing_api.ProcessRequestError),
+		string(routing_api.RouteInvalidError),
+		string(routing_api.RouteServiceUrlInvalidError),
+		string(routing_api.DBCommunicationError),
+		string(routing_api.UnauthorizedError),
+		string(routing_api.TcpRouteMappingInvalidError),
+		string(routing_api.DBConflictError),
+		string(routing_api.PreconditionFailedError),
+		string(routing_api.QuotaExceededError),
+		string(routing_api.PayloadTooLargeError),
+		string(routing_api.NotImplementedError),
+	},
+	reflect.TypeOf(routing_api.DetailCode("")): {
+		string(routing_api.MissingFieldCode),
+		string(routing_api.InvalidFieldCode),
+		string(routing_api.OutOfRangeCode),
+		string(routing_api.NotFoundCode),
+		string(routing_api.UnknownFieldCode),
+	},
+}
+
+// schemaGenerator derives schemas from Go types the way encoding/json
+// encodes them. Named structs become component schemas.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{schemas: map[string]*Schema{}}
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// custom encodings, such as the raw JSON of audit records
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.objectSchema(t)
+		}
+		if _, ok := g.schemas[t.Name()]; !ok {
+			// reserve the name before recursing, so that cycles terminate
+			g.schemas[t.Name()] = &Schema{}
+			*g.schemas[t.Name()] = *g.objectSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int64", Minimum: int64Ptr(0), Maximum: int64Ptr(int64(maxUint(t)))}
+	case reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: int64Ptr(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *schemaGenerator) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the JSON fields of t, including the fields of embedded
+// structs without a JSON name, to schema.
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schemaFor(field.Type)
+	}
+}
+
+func maxUint(t reflect.Type) uint64 {
+	return math.MaxUint64 >> uint(64-t.Bits())
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,177 @@
+package quota
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/routing-api/config"
+	"code.cloudfoundry.org/routing-api/db"
+	"code.cloudfoundry.org/routing-api/models"
+)
+
+// ExceededError is returned when registering routes would take an owner or
+// log guid over its quota.
+type ExceededError struct {
+	Quota string
+	Key   string
+	Limit int
+}
+
+func (e ExceededError) Error() string {
+	return fmt.Sprintf("Quota %s exceeded for %s: limit is %d", e.Quota, e.Key, e.Limit)
+}
+
+//go:generate counterfeiter -o fakes/fake_enforcer.go . Enforcer
+type Enforcer interface {
+	// CheckRoutes returns an ExceededError if registering the routes would
+	// exceed a quota. Routes that already exist, or that the batch repeats,
+	// are not counted again, so refreshing routes never fails a quota check.
+	CheckRoutes(routes []models.Route) error
+	CheckTcpRouteMappings(tcpMappings []models.TcpRouteMapping) error
+
+	// Usage returns the current usage of every enabled quota.
+	Usage() ([]models.QuotaUsage, error)
+}
+
+type enforcer struct {
+	database db.DB
+	limits   config.QuotaConfig
+}
+
+func NewEnforcer(database db.DB, limits config.QuotaConfig) Enforcer {
+	return &enforcer{
+		database: database,
+		limits:   limits,
+	}
+}
+
+// routeKey identifies a route the way the database does.
+type routeKey struct {
+	route           string
+	port            uint16
+	ip              string
+	routeServiceUrl string
+}
+
+func (e *enforcer) CheckRoutes(routes []models.Route) error {
+	if e.limits.MaxHttpRoutesPerOwner <= 0 && e.limits.MaxHttpRoutesPerLogGuid <= 0 {
+		return nil
+	}
+
+	addedPerOwner := map[string]int{}
+	addedPerLogGuid := map[string]int{}
+	seen := map[routeKey]bool{}
+	for _, route := range routes {
+		// a batch may repeat a route, which is only created once
+		key := routeKey{route.Route, route.Port, route.IP, route.RouteServiceUrl}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		existing, err := e.database.ReadRoute(route)
+		if err != nil {
+			return err
+		}
+		if existing != (models.Route{}) {
+			continue
+		}
+		if route.Owner != "" {
+			addedPerOwner[route.Owner]++
+		}
+		if route.LogGuid != "" {
+			addedPerLogGuid[route.LogGuid]++
+		}
+	}
+
+	if limit := e.limits.MaxHttpRoutesPerOwner; limit > 0 {
+		for owner, added := range addedPerOwner {
+			count, err := e.database.CountRoutes(models.RouteSelector{Owner: owner})
+			if err != nil {
+				return err
+			}
+			if count+added > limit {
+				return ExceededError{Quota: models.QuotaHttpRoutesPerOwner, Key: owner, Limit: limit}
+			}
+		}
+	}
+
+	if limit := e.limits.MaxHttpRoutesPerLogGuid; limit > 0 {
+		for logGuid, added := range addedPerLogGuid {
+			count, err := e.database.CountRoutes(models.RouteSelector{LogGuid: logGuid})
+			if err != nil {
+				return err
+			}
+			if count+added > limit {
+				return ExceededError{Quota: models.QuotaHttpRoutesPerLogGuid, Key: logGuid, Limit: limit}
+			}
+		}
+	}
+	return nil
+}
+
+func (e *enforcer) CheckTcpRouteMappings(tcpMappings []models.TcpRouteMapping) error {
+	limit := e.limits.MaxTcpRoutesPerOwner
+	if limit <= 0 {
+		return nil
+	}
+
+	addedPerOwner := map[string]int{}
+	seen := map[string]bool{}
+	for _, tcpMapping := range tcpMappings {
+		key := tcpMapping.HistoryKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		existing, err := e.database.ReadTcpRouteMapping(tcpMapping)
+		if err != nil {
+			return err
+		}
+		if existing != (models.TcpRouteMapping{}) || tcpMapping.Owner == "" {
+			continue
+		}
+		addedPerOwner[tcpMapping.Owner]++
+	}
+
+	for owner, added := range addedPerOwner {
+		count, err := e.database.CountTcpRouteMappings(models.TcpRouteMappingSelector{Owner: owner})
+		if err != nil {
+			return err
+		}
+		if count+added > limit {
+			return ExceededError{Quota: models.QuotaTcpRoutesPerOwner, Key: owner, Limit: limit}
+		}
+	}
+	return nil
+}
+
+func (e *enforcer) Usage() ([]models.QuotaUsage, error) {
+	usages := []models.QuotaUsage{}
+
+	if limit := e.limits.MaxHttpRoutesPerOwner; limit > 0 {
+		counts, err := e.database.CountRoutesPerOwner()
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, models.QuotaUsage{Quota: models.QuotaHttpRoutesPerOwner, Limit: limit, Usage: counts})
+	}
+
+	if limit := e.limits.MaxHttpRoutesPerLogGuid; limit > 0 {
+		counts, err := e.database.CountRoutesPerLogGuid()
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, models.QuotaUsage{Quota: models.QuotaHttpRoutesPerLogGuid, Limit: limit, Usage: counts})
+	}
+
+	if limit := e.limits.MaxTcpRoutesPerOwner; limit > 0 {
+		counts, err := e.database.CountTcpRouteMappingsPerOwner()
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, models.QuotaUsage{Quota: models.QuotaTcpRoutesPerOwner, Limit: limit, Usage: counts})
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,215 @@
+package quota_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/routing-api/config"
+	fake_db "code.cloudfoundry.org/routing-api/db/fakes"
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/quota"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Enforcer", func() {
+	var (
+		database *fake_db.FakeDB
+		limits   config.QuotaConfig
+		enforcer quota.Enforcer
+	)
+
+	BeforeEach(func() {
+		database = &fake_db.FakeDB{}
+		limits = config.QuotaConfig{}
+	})
+
+	JustBeforeEach(func() {
+		enforcer = quota.NewEnforcer(database, limits)
+	})
+
+	Describe("CheckRoutes", func() {
+		var routes []models.Route
+
+		BeforeEach(func() {
+			routes = []models.Route{
+				models.NewRoute("a.example.com", 8080, "1.2.3.4", "log-guid", "", 60),
+				models.NewRoute("b.example.com", 8080, "1.2.3.4", "log-guid", "", 60),
+			}
+			routes[0].Owner = "client-a"
+			routes[1].Owner = "client-a"
+		})
+
+		Context("when no quota is configured", func() {
+			It("does not read the database", func() {
+				Expect(enforcer.CheckRoutes(routes)).To(Succeed())
+				Expect(database.ReadRouteCallCount()).To(Equal(0))
+				Expect(database.CountRoutesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a per owner quota is configured", func() {
+			BeforeEach(func() {
+				limits.MaxHttpRoutesPerOwner = 3
+			})
+
+			It("counts the routes of the owner", func() {
+				database.CountRoutesReturns(1, nil)
+				Expect(enforcer.CheckRoutes(routes)).To(Succeed())
+				Expect(database.CountRoutesCallCount()).To(Equal(1))
+				Expect(database.CountRoutesArgsForCall(0)).To(Equal(models.RouteSelector{Owner: "client-a"}))
+			})
+
+			It("returns an ExceededError when the new routes exceed the quota", func() {
+				database.CountRoutesReturns(2, nil)
+				err := enforcer.CheckRoutes(routes)
+				Expect(err).To(Equal(quota.ExceededError{
+					Quota: models.QuotaHttpRoutesPerOwner,
+					Key:   "client-a",
+					Limit: 3,
+				}))
+			})
+
+			It("counts a route repeated in the batch once", func() {
+				routes = append(routes, routes[1])
+				database.CountRoutesReturns(1, nil)
+				Expect(enforcer.CheckRoutes(routes)).To(Succeed())
+				Expect(database.ReadRouteCallCount()).To(Equal(2))
+			})
+
+			It("does not count routes that already exist", func() {
+				database.ReadRouteReturns(routes[0], nil)
+				database.CountRoutesReturns(3, nil)
+				Expect(enforcer.CheckRoutes(routes)).To(Succeed())
+				Expect(database.CountRoutesCallCount()).To(Equal(0))
+			})
+
+			Context("when reading a route fails", func() {
+				BeforeEach(func() {
+					database.ReadRouteReturns(models.Route{}, errors.New("stuff broke"))
+				})
+
+				It("returns the error", func() {
+					Expect(enforcer.CheckRoutes(routes)).To(MatchError("stuff broke"))
+				})
+			})
+
+			Context("when counting routes fails", func() {
+				BeforeEach(func() {
+					database.CountRoutesReturns(0, errors.New("stuff broke"))
+				})
+
+				It("returns the error", func() {
+					Expect(enforcer.CheckRoutes(routes)).To(MatchError("stuff broke"))
+				})
+			})
+		})
+
+		Context("when a per log guid quota is configured", func() {
+			BeforeEach(func() {
+				limits.MaxHttpRoutesPerLogGuid = 2
+			})
+
+			It("returns an ExceededError when the new routes exceed the quota", func() {
+				database.CountRoutesReturns(1, nil)
+				err := enforcer.CheckRoutes(routes)
+				Expect(err).To(Equal(quota.ExceededError{
+					Quota: models.QuotaHttpRoutesPerLogGuid,
+					Key:   "log-guid",
+					Limit: 2,
+				}))
+				Expect(database.CountRoutesArgsForCall(0)).To(Equal(models.RouteSelector{LogGuid: "log-guid"}))
+			})
+		})
+	})
+
+	Describe("CheckTcpRouteMappings", func() {
+		var tcpMappings []models.TcpRouteMapping
+
+		BeforeEach(func() {
+			tcpMappings = []models.TcpRouteMapping{
+				models.NewTcpRouteMapping("router-group-guid", 52000, "1.2.3.4", 60000, 60),
+			}
+			tcpMappings[0].Owner = "client-a"
+			limits.MaxTcpRoutesPerOwner = 1
+		})
+
+		It("succeeds when the owner is under the quota", func() {
+			Expect(enforcer.CheckTcpRouteMappings(tcpMappings)).To(Succeed())
+			Expect(database.CountTcpRouteMappingsArgsForCall(0)).To(Equal(models.TcpRouteMappingSelector{Owner: "client-a"}))
+		})
+
+		It("returns an ExceededError when the new mappings exceed the quota", func() {
+			database.CountTcpRouteMappingsReturns(1, nil)
+			err := enforcer.CheckTcpRouteMappings(tcpMappings)
+			Expect(err).To(Equal(quota.ExceededError{
+				Quota: models.QuotaTcpRoutesPerOwner,
+				Key:   "client-a",
+				Limit: 1,
+			}))
+		})
+
+		It("counts a mapping repeated in the batch once", func() {
+			tcpMappings = append(tcpMappings, tcpMappings[0])
+			Expect(enforcer.CheckTcpRouteMappings(tcpMappings)).To(Succeed())
+			Expect(database.ReadTcpRouteMappingCallCount()).To(Equal(1))
+		})
+
+		It("does not count mappings that already exist", func() {
+			database.ReadTcpRouteMappingReturns(tcpMappings[0], nil)
+			Expect(enforcer.CheckTcpRouteMappings(tcpMappings)).To(Succeed())
+			Expect(database.CountTcpRouteMappingsCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Usage", func() {
+		BeforeEach(func() {
+			database.CountRoutesPerOwnerReturns(map[string]int{"client-a": 2}, nil)
+			database.CountRoutesPerLogGuidReturns(map[string]int{"log-guid": 2}, nil)
+			database.CountTcpRouteMappingsPerOwnerReturns(map[string]int{"client-b": 1}, nil)
+		})
+
+		It("returns nothing when no quota is configured", func() {
+			usages, err := enforcer.Usage()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usages).To(BeEmpty())
+		})
+
+		Context("when quotas are configured", func() {
+			BeforeEach(func() {
+				limits = config.QuotaConfig{
+					MaxHttpRoutesPerOwner: 10,
+					MaxTcpRoutesPerOwner:  5,
+				}
+			})
+
+			It("returns the usage of the enabled quotas", func() {
+				usages, err := enforcer.Usage()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(usages).To(ConsistOf(
+					models.QuotaUsage{Quota: models.QuotaHttpRoutesPerOwner, Limit: 10, Usage: map[string]int{"client-a": 2}},
+					models.QuotaUsage{Quota: models.QuotaTcpRoutesPerOwner, Limit: 5, Usage: map[string]int{"client-b": 1}},
+				))
+			})
+
+			It("counts the routes in the database instead of reading them", func() {
+				_, err := enforcer.Usage()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(database.ReadRoutesCallCount()).To(Equal(0))
+				Expect(database.ReadTcpRouteMappingsCallCount()).To(Equal(0))
+				Expect(database.CountRoutesPerLogGuidCallCount()).To(Equal(0))
+			})
+
+			Context("when counting routes fails", func() {
+				BeforeEach(func() {
+					database.CountRoutesPerOwnerReturns(nil, errors.New("stuff broke"))
+				})
+
+				It("returns the error", func() {
+					_, err := enforcer.Usage()
+					Expect(err).To(MatchError("stuff broke"))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,165 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/quota"
+)
+
+type FakeEnforcer struct {
+	CheckRoutesStub        func(routes []models.Route) error
+	checkRoutesMutex       sync.RWMutex
+	checkRoutesArgsForCall []struct {
+		routes []models.Route
+	}
+	checkRoutesReturns struct {
+		result1 error
+	}
+	CheckTcpRouteMappingsStub        func(tcpMappings []models.TcpRouteMapping) error
+	checkTcpRouteMappingsMutex       sync.RWMutex
+	checkTcpRouteMappingsArgsForCall []struct {
+		tcpMappings []models.TcpRouteMapping
+	}
+	checkTcpRouteMappingsReturns struct {
+		result1 error
+	}
+	UsageStub        func() ([]models.QuotaUsage, error)
+	usageMutex       sync.RWMutex
+	usageArgsForCall []struct{}
+	usageReturns     struct {
+		result1 []models.QuotaUsage
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEnforcer) CheckRoutes(routes []models.Route) error {
+	var routesCopy []models.Route
+	if routes != nil {
+		routesCopy = make([]models.Route, len(routes))
+		copy(routesCopy, routes)
+	}
+	fake.checkRoutesMutex.Lock()
+	fake.checkRoutesArgsForCall = append(fake.checkRoutesArgsForCall, struct {
+		routes []models.Route
+	}{routesCopy})
+	fake.recordInvocation("CheckRoutes", []interface{}{routesCopy})
+	fake.checkRoutesMutex.Unlock()
+	if fake.CheckRoutesStub != nil {
+		return fake.CheckRoutesStub(routes)
+	} else {
+		return fake.checkRoutesReturns.result1
+	}
+}
+
+func (fake *FakeEnforcer) CheckRoutesCallCount() int {
+	fake.checkRoutesMutex.RLock()
+	defer fake.checkRoutesMutex.RUnlock()
+	return len(fake.checkRoutesArgsForCall)
+}
+
+func (fake *FakeEnforcer) CheckRoutesArgsForCall(i int) []models.Route {
+	fake.checkRoutesMutex.RLock()
+	defer fake.checkRoutesMutex.RUnlock()
+	return fake.checkRoutesArgsForCall[i].routes
+}
+
+func (fake *FakeEnforcer) CheckRoutesReturns(result1 error) {
+	fake.CheckRoutesStub = nil
+	fake.checkRoutesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeEnforcer) CheckTcpRouteMappings(tcpMappings []models.TcpRouteMapping) error {
+	var tcpMappingsCopy []models.TcpRouteMapping
+	if tcpMappings != nil {
+		tcpMappingsCopy = make([]models.TcpRouteMapping, len(tcpMappings))
+		copy(tcpMappingsCopy, tcpMappings)
+	}
+	fake.checkTcpRouteMappingsMutex.Lock()
+	fake.checkTcpRouteMappingsArgsForCall = append(fake.checkTcpRouteMappingsArgsForCall, struct {
+		tcpMappings []models.TcpRouteMapping
+	}{tcpMappingsCopy})
+	fake.recordInvocation("CheckTcpRouteMappings", []interface{}{tcpMappingsCopy})
+	fake.checkTcpRouteMappingsMutex.Unlock()
+	if fake.CheckTcpRouteMappingsStub != nil {
+		return fake.CheckTcpRouteMappingsStub(tcpMappings)
+	} else {
+		return fake.checkTcpRouteMappingsReturns.result1
+	}
+}
+
+func (fake *FakeEnforcer) CheckTcpRouteMappingsCallCount() int {
+	fake.checkTcpRouteMappingsMutex.RLock()
+	defer fake.checkTcpRouteMappingsMutex.RUnlock()
+	return len(fake.checkTcpRouteMappingsArgsForCall)
+}
+
+func (fake *FakeEnforcer) CheckTcpRouteMappingsArgsForCall(i int) []models.TcpRouteMapping {
+	fake.checkTcpRouteMappingsMutex.RLock()
+	defer fake.checkTcpRouteMappingsMutex.RUnlock()
+	return fake.checkTcpRouteMappingsArgsForCall[i].tcpMappings
+}
+
+func (fake *FakeEnforcer) CheckTcpRouteMappingsReturns(result1 error) {
+	fake.CheckTcpRouteMappingsStub = nil
+	fake.checkTcpRouteMappingsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeEnforcer) Usage() ([]models.QuotaUsage, error) {
+	fake.usageMutex.Lock()
+	fake.usageArgsForCall = append(fake.usageArgsForCall, struct{}{})
+	fake.recordInvocation("Usage", []interface{}{})
+	fake.usageMutex.Unlock()
+	if fake.UsageStub != nil {
+		return fake.UsageStub()
+	} else {
+		return fake.usageReturns.result1, fake.usageReturns.result2
+	}
+}
+
+func (fake *FakeEnforcer) UsageCallCount() int {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return len(fake.usageArgsForCall)
+}
+
+func (fake *FakeEnforcer) UsageReturns(result1 []models.QuotaUsage, result2 error) {
+	fake.UsageStub = nil
+	fake.usageReturns = struct {
+		result1 []models.QuotaUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEnforcer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.checkRoutesMutex.RLock()
+	defer fake.checkRoutesMutex.RUnlock()
+	fake.checkTcpRouteMappingsMutex.RLock()
+	defer fake.checkTcpRouteMappingsMutex.RUnlock()
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeEnforcer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ quota.Enforcer = new(FakeEnforcer)
@@ -0,0 +1,20 @@
+package routing_api
+
+import "github.com/nu7hatch/gouuid"
+
+const (
+	// VcapRequestIDHeader carries the id that correlates the logs, audit
+	// records and events of a request. The server accepts RequestIDHeader as
+	// well and generates an id when a request has neither.
+	VcapRequestIDHeader = "X-Vcap-Request-Id"
+	RequestIDHeader     = "X-Request-Id"
+)
+
+// NewRequestID returns a random request id.
+func NewRequestID() (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
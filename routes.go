@@ -3,29 +3,73 @@ package routing_api
 import "github.com/tedsuo/rata"
 
 const (
-	UpsertRoute           = "UpsertRoute"
-	DeleteRoute           = "Delete"
-	ListRoute             = "List"
-	EventStreamRoute      = "EventStream"
-	ListRouterGroups      = "ListRouterGroups"
-	UpdateRouterGroup     = "UpdateRouterGroup"
-	UpsertTcpRouteMapping = "UpsertTcpRouteMapping"
-	DeleteTcpRouteMapping = "DeleteTcpRouteMapping"
-	ListTcpRouteMapping   = "ListTcpRouteMapping"
-	EventStreamTcpRoute   = "TcpRouteEventStream"
+	UpsertRoute                      = "UpsertRoute"
+	DeleteRoute                      = "Delete"
+	ListRoute                        = "List"
+	EventStreamRoute                 = "EventStream"
+	ListRouterGroups                 = "ListRouterGroups"
+	UpdateRouterGroup                = "UpdateRouterGroup"
+	UpsertTcpRouteMapping            = "UpsertTcpRouteMapping"
+	DeleteTcpRouteMapping            = "DeleteTcpRouteMapping"
+	ListTcpRouteMapping              = "ListTcpRouteMapping"
+	EventStreamTcpRoute              = "TcpRouteEventStream"
+	ListAuditRecords                 = "ListAuditRecords"
+	ListRouteHistory                 = "ListRouteHistory"
+	ListTcpRouteHistory              = "ListTcpRouteHistory"
+	DeleteRoutesBySelector           = "DeleteRoutesBySelector"
+	DeleteTcpRouteMappingsBySelector = "DeleteTcpRouteMappingsBySelector"
+	ListQuotas                       = "ListQuotas"
+	EventStreamWebSocketRoute        = "EventStreamWebSocket"
+	EventStreamTcpWebSocketRoute     = "TcpRouteEventStreamWebSocket"
+	OpenAPIRoute                     = "OpenAPI"
+	HealthRoute                      = "Health"
+	ReadyRoute                       = "Ready"
+
+	ListRoutesV2            = "ListRoutesV2"
+	CreateRouteV2           = "CreateRouteV2"
+	GetRouteV2              = "GetRouteV2"
+	UpdateRouteV2           = "UpdateRouteV2"
+	DeleteRouteV2           = "DeleteRouteV2"
+	ListTcpRouteMappingsV2  = "ListTcpRouteMappingsV2"
+	CreateTcpRouteMappingV2 = "CreateTcpRouteMappingV2"
+	GetTcpRouteMappingV2    = "GetTcpRouteMappingV2"
+	UpdateTcpRouteMappingV2 = "UpdateTcpRouteMappingV2"
+	DeleteTcpRouteMappingV2 = "DeleteTcpRouteMappingV2"
 )
 
 var RoutesMap = map[string]rata.Route{
-	UpsertRoute:           {Path: "/routing/v1/routes", Method: "POST", Name: UpsertRoute},
-	DeleteRoute:           {Path: "/routing/v1/routes", Method: "DELETE", Name: DeleteRoute},
-	ListRoute:             {Path: "/routing/v1/routes", Method: "GET", Name: ListRoute},
-	EventStreamRoute:      {Path: "/routing/v1/events", Method: "GET", Name: EventStreamRoute},
-	ListRouterGroups:      {Path: "/routing/v1/router_groups", Method: "GET", Name: ListRouterGroups},
-	UpdateRouterGroup:     {Path: "/routing/v1/router_groups/:guid", Method: "PUT", Name: UpdateRouterGroup},
-	UpsertTcpRouteMapping: {Path: "/routing/v1/tcp_routes/create", Method: "POST", Name: UpsertTcpRouteMapping},
-	DeleteTcpRouteMapping: {Path: "/routing/v1/tcp_routes/delete", Method: "POST", Name: DeleteTcpRouteMapping},
-	ListTcpRouteMapping:   {Path: "/routing/v1/tcp_routes", Method: "GET", Name: ListTcpRouteMapping},
-	EventStreamTcpRoute:   {Path: "/routing/v1/tcp_routes/events", Method: "GET", Name: EventStreamTcpRoute},
+	UpsertRoute:                      {Path: "/routing/v1/routes", Method: "POST", Name: UpsertRoute},
+	DeleteRoute:                      {Path: "/routing/v1/routes", Method: "DELETE", Name: DeleteRoute},
+	ListRoute:                        {Path: "/routing/v1/routes", Method: "GET", Name: ListRoute},
+	EventStreamRoute:                 {Path: "/routing/v1/events", Method: "GET", Name: EventStreamRoute},
+	ListRouterGroups:                 {Path: "/routing/v1/router_groups", Method: "GET", Name: ListRouterGroups},
+	UpdateRouterGroup:                {Path: "/routing/v1/router_groups/:guid", Method: "PUT", Name: UpdateRouterGroup},
+	UpsertTcpRouteMapping:            {Path: "/routing/v1/tcp_routes/create", Method: "POST", Name: UpsertTcpRouteMapping},
+	DeleteTcpRouteMapping:            {Path: "/routing/v1/tcp_routes/delete", Method: "POST", Name: DeleteTcpRouteMapping},
+	ListTcpRouteMapping:              {Path: "/routing/v1/tcp_routes", Method: "GET", Name: ListTcpRouteMapping},
+	EventStreamTcpRoute:              {Path: "/routing/v1/tcp_routes/events", Method: "GET", Name: EventStreamTcpRoute},
+	ListAuditRecords:                 {Path: "/routing/v1/audit", Method: "GET", Name: ListAuditRecords},
+	ListRouteHistory:                 {Path: "/routing/v1/routes/history", Method: "GET", Name: ListRouteHistory},
+	ListTcpRouteHistory:              {Path: "/routing/v1/tcp_routes/history", Method: "GET", Name: ListTcpRouteHistory},
+	DeleteRoutesBySelector:           {Path: "/routing/v1/routes/selector", Method: "DELETE", Name: DeleteRoutesBySelector},
+	DeleteTcpRouteMappingsBySelector: {Path: "/routing/v1/tcp_routes/selector", Method: "DELETE", Name: DeleteTcpRouteMappingsBySelector},
+	ListQuotas:                       {Path: "/routing/v1/quotas", Method: "GET", Name: ListQuotas},
+	EventStreamWebSocketRoute:        {Path: "/routing/v1/events/ws", Method: "GET", Name: EventStreamWebSocketRoute},
+	EventStreamTcpWebSocketRoute:     {Path: "/routing/v1/tcp_routes/events/ws", Method: "GET", Name: EventStreamTcpWebSocketRoute},
+	OpenAPIRoute:                     {Path: "/routing/v1/openapi.json", Method: "GET", Name: OpenAPIRoute},
+	HealthRoute:                      {Path: "/health", Method: "GET", Name: HealthRoute},
+	ReadyRoute:                       {Path: "/ready", Method: "GET", Name: ReadyRoute},
+
+	ListRoutesV2:            {Path: "/routing/v2/routes", Method: "GET", Name: ListRoutesV2},
+	CreateRouteV2:           {Path: "/routing/v2/routes", Method: "POST", Name: CreateRouteV2},
+	GetRouteV2:              {Path: "/routing/v2/routes/:guid", Method: "GET", Name: GetRouteV2},
+	UpdateRouteV2:           {Path: "/routing/v2/routes/:guid", Method: "PUT", Name: UpdateRouteV2},
+	DeleteRouteV2:           {Path: "/routing/v2/routes/:guid", Method: "DELETE", Name: DeleteRouteV2},
+	ListTcpRouteMappingsV2:  {Path: "/routing/v2/tcp_routes", Method: "GET", Name: ListTcpRouteMappingsV2},
+	CreateTcpRouteMappingV2: {Path: "/routing/v2/tcp_routes", Method: "POST", Name: CreateTcpRouteMappingV2},
+	GetTcpRouteMappingV2:    {Path: "/routing/v2/tcp_routes/:guid", Method: "GET", Name: GetTcpRouteMappingV2},
+	UpdateTcpRouteMappingV2: {Path: "/routing/v2/tcp_routes/:guid", Method: "PUT", Name: UpdateTcpRouteMappingV2},
+	DeleteTcpRouteMappingV2: {Path: "/routing/v2/tcp_routes/:guid", Method: "DELETE", Name: DeleteTcpRouteMappingV2},
 }
 
 func Routes() rata.Routes {
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	ServiceName = "routing-api"
+
+	exportTimeout = 10 * time.Second
+)
+
+// NewWriterExporter returns an exporter that writes every span to w as JSON,
+// e.g. to stdout.
+func NewWriterExporter(w io.Writer) (Exporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// NewOTLPExporter returns an exporter that posts the spans to the OTLP/HTTP
+// endpoint of a collector, e.g. http://127.0.0.1:4318/v1/traces.
+func NewOTLPExporter(endpoint string) (Exporter, error) {
+	return otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(endpoint),
+		otlptracehttp.WithTimeout(exportTimeout),
+	)
+}
+
+func serviceResource() *resource.Resource {
+	return resource.NewSchemaless(attribute.String("service.name", ServiceName))
+}
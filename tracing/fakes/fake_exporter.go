@@ -0,0 +1,128 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/routing-api/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type FakeExporter struct {
+	ExportSpansStub        func(ctx context.Context, spans []sdktrace.ReadOnlySpan) error
+	exportSpansMutex       sync.RWMutex
+	exportSpansArgsForCall []struct {
+		ctx   context.Context
+		spans []sdktrace.ReadOnlySpan
+	}
+	exportSpansReturns struct {
+		result1 error
+	}
+	ShutdownStub        func(ctx context.Context) error
+	shutdownMutex       sync.RWMutex
+	shutdownArgsForCall []struct {
+		ctx context.Context
+	}
+	shutdownReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var spansCopy []sdktrace.ReadOnlySpan
+	if spans != nil {
+		spansCopy = make([]sdktrace.ReadOnlySpan, len(spans))
+		copy(spansCopy, spans)
+	}
+	fake.exportSpansMutex.Lock()
+	fake.exportSpansArgsForCall = append(fake.exportSpansArgsForCall, struct {
+		ctx   context.Context
+		spans []sdktrace.ReadOnlySpan
+	}{ctx, spansCopy})
+	fake.recordInvocation("ExportSpans", []interface{}{ctx, spansCopy})
+	fake.exportSpansMutex.Unlock()
+	if fake.ExportSpansStub != nil {
+		return fake.ExportSpansStub(ctx, spans)
+	} else {
+		return fake.exportSpansReturns.result1
+	}
+}
+
+func (fake *FakeExporter) ExportSpansCallCount() int {
+	fake.exportSpansMutex.RLock()
+	defer fake.exportSpansMutex.RUnlock()
+	return len(fake.exportSpansArgsForCall)
+}
+
+func (fake *FakeExporter) ExportSpansArgsForCall(i int) (context.Context, []sdktrace.ReadOnlySpan) {
+	fake.exportSpansMutex.RLock()
+	defer fake.exportSpansMutex.RUnlock()
+	return fake.exportSpansArgsForCall[i].ctx, fake.exportSpansArgsForCall[i].spans
+}
+
+func (fake *FakeExporter) ExportSpansReturns(result1 error) {
+	fake.ExportSpansStub = nil
+	fake.exportSpansReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeExporter) Shutdown(ctx context.Context) error {
+	fake.shutdownMutex.Lock()
+	fake.shutdownArgsForCall = append(fake.shutdownArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("Shutdown", []interface{}{ctx})
+	fake.shutdownMutex.Unlock()
+	if fake.ShutdownStub != nil {
+		return fake.ShutdownStub(ctx)
+	} else {
+		return fake.shutdownReturns.result1
+	}
+}
+
+func (fake *FakeExporter) ShutdownCallCount() int {
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	return len(fake.shutdownArgsForCall)
+}
+
+func (fake *FakeExporter) ShutdownArgsForCall(i int) context.Context {
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	return fake.shutdownArgsForCall[i].ctx
+}
+
+func (fake *FakeExporter) ShutdownReturns(result1 error) {
+	fake.ShutdownStub = nil
+	fake.shutdownReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeExporter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.exportSpansMutex.RLock()
+	defer fake.exportSpansMutex.RUnlock()
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeExporter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ tracing.Exporter = new(FakeExporter)
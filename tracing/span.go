@@ -0,0 +1,68 @@
+// Package tracing records the spans of the requests to the routing API with
+// OpenTelemetry and propagates their trace context in W3C traceparent
+// headers.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Propagator reads and writes the trace context of requests in W3C
+// traceparent and tracestate headers.
+var Propagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+// IsTraced reports whether the span of ctx is recorded, so that callers can
+// skip the work of tracing requests that are not sampled.
+func IsTraced(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// Start starts a child of the span of ctx. The span does nothing when ctx is
+// not traced.
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return StartAt(ctx, name, time.Now())
+}
+
+func StartAt(ctx context.Context, name string, start time.Time) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(ServiceName)
+	return tracer.Start(ctx, name, trace.WithTimestamp(start))
+}
+
+// SetError marks span as failed with err, if it is not nil.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Observer returns a function that records an operation of the given
+// duration, which just ended, as a child of the span of ctx named after the
+// operation with prefix.
+func Observer(ctx context.Context, prefix string) func(operation string, duration time.Duration, err error) {
+	return func(operation string, duration time.Duration, err error) {
+		end := time.Now()
+		_, span := StartAt(ctx, prefix+operation, end.Add(-duration))
+		SetError(span, err)
+		span.End(trace.WithTimestamp(end))
+	}
+}
+
+// Inject sets the traceparent header of the span of ctx, or of its remote
+// parent, on header.
+func Inject(ctx context.Context, header http.Header) {
+	Propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns ctx with the trace context of the traceparent header of
+// header as its remote parent.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return Propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	maxQueuedSpans  = 2048
+	maxBatchSize    = 512
+	shutdownTimeout = 10 * time.Second
+)
+
+//go:generate counterfeiter -o fakes/fake_exporter.go . Exporter
+type Exporter interface {
+	sdktrace.SpanExporter
+}
+
+// Tracer starts the spans of requests and exports the spans of sampled traces
+// in batches, every interval or once a batch is full. Spans finished while
+// the queue is full are dropped.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	logger   lager.Logger
+}
+
+func NewTracer(exporter Exporter, sampler sdktrace.Sampler, interval time.Duration, logger lager.Logger) *Tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(interval),
+			sdktrace.WithMaxQueueSize(maxQueuedSpans),
+			sdktrace.WithMaxExportBatchSize(maxBatchSize),
+		),
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(serviceResource()),
+	)
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(ServiceName),
+		logger:   logger,
+	}
+}
+
+// StartRequest starts the span of a request. It continues the trace of the
+// traceparent header of the request, if there is one, and starts a new trace
+// otherwise.
+func (t *Tracer) StartRequest(ctx context.Context, name string, header http.Header) (context.Context, trace.Span) {
+	return t.tracer.Start(Extract(ctx, header), name, trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// Run exports the spans that are left when it is signalled.
+func (t *Tracer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+	<-signals
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := t.provider.Shutdown(ctx)
+	if err != nil {
+		t.logger.Error("failed-to-export-spans", err)
+	}
+	return nil
+}
+
+// NewSampler returns the sampler of name, one of the OTEL_TRACES_SAMPLER
+// values. The traceidratio samplers sample ratio of the traces;
+// the parentbased ones follow the decision of the traceparent of a request
+// when it has one.
+func NewSampler(name string, ratio float64) (sdktrace.Sampler, error) {
+	switch name {
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+	}
+	return nil, fmt.Errorf("unknown sampler %q", name)
+}
@@ -0,0 +1,242 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/routing-api/tracing"
+	"code.cloudfoundry.org/routing-api/tracing/fakes"
+	"github.com/tedsuo/ifrit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracing", func() {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var (
+		exporter *fakes.FakeExporter
+		tracer   *tracing.Tracer
+		process  ifrit.Process
+	)
+
+	BeforeEach(func() {
+		exporter = &fakes.FakeExporter{}
+		tracer = tracing.NewTracer(exporter, sdktrace.ParentBased(sdktrace.AlwaysSample()), time.Hour, lagertest.NewTestLogger("tracing"))
+		process = ifrit.Invoke(tracer)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	exported := func() []sdktrace.ReadOnlySpan {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		spans := []sdktrace.ReadOnlySpan{}
+		for i := 0; i < exporter.ExportSpansCallCount(); i++ {
+			_, batch := exporter.ExportSpansArgsForCall(i)
+			spans = append(spans, batch...)
+		}
+		return spans
+	}
+
+	withTraceParent := func(value string) http.Header {
+		header := http.Header{}
+		header.Set("traceparent", value)
+		return header
+	}
+
+	Describe("Tracer", func() {
+		It("starts a new trace for requests without a traceparent", func() {
+			ctx, span := tracer.StartRequest(context.Background(), "ListRoute", http.Header{})
+			Expect(tracing.IsTraced(ctx)).To(BeTrue())
+			Expect(span.SpanContext().IsValid()).To(BeTrue())
+			Expect(span.SpanContext().IsSampled()).To(BeTrue())
+			span.End()
+
+			spans := exported()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Parent().IsValid()).To(BeFalse())
+			Expect(spans[0].SpanKind()).To(Equal(trace.SpanKindServer))
+			Expect(spans[0].Resource().Attributes()).To(ContainElement(attribute.String("service.name", "routing-api")))
+		})
+
+		It("continues the trace of the traceparent of requests", func() {
+			_, span := tracer.StartRequest(context.Background(), "ListRoute", withTraceParent(traceParent))
+			Expect(span.SpanContext().TraceID().String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(span.SpanContext().SpanID().String()).NotTo(Equal("00f067aa0ba902b7"))
+			span.End()
+
+			spans := exported()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Parent().SpanID().String()).To(Equal("00f067aa0ba902b7"))
+		})
+
+		It("exports the finished spans with their children when it exits", func() {
+			ctx, span := tracer.StartRequest(context.Background(), "UpsertRoute", http.Header{})
+			_, child := tracing.Start(ctx, "db.SaveRoute")
+			tracing.SetError(child, errors.New("boom"))
+			child.End()
+			span.End()
+
+			spans := exported()
+			Expect(spans).To(HaveLen(2))
+			Expect(spans[0].Name()).To(Equal("db.SaveRoute"))
+			Expect(spans[0].Status().Code).To(Equal(codes.Error))
+			Expect(spans[0].Status().Description).To(Equal("boom"))
+			Expect(spans[0].SpanContext().TraceID()).To(Equal(span.SpanContext().TraceID()))
+			Expect(spans[0].Parent().SpanID()).To(Equal(span.SpanContext().SpanID()))
+			Expect(spans[1].Name()).To(Equal("UpsertRoute"))
+		})
+
+		It("does not export spans of unsampled traces", func() {
+			ctx, span := tracer.StartRequest(context.Background(), "ListRoute", withTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"))
+			Expect(tracing.IsTraced(ctx)).To(BeFalse())
+			span.End()
+
+			Expect(exported()).To(BeEmpty())
+		})
+
+		It("records operations that just ended as children", func() {
+			ctx, span := tracer.StartRequest(context.Background(), "ListRoute", http.Header{})
+			tracing.Observer(ctx, "db.")("ReadRoutes", 20*time.Millisecond, nil)
+			span.End()
+
+			spans := exported()
+			Expect(spans).To(HaveLen(2))
+			Expect(spans[0].Name()).To(Equal("db.ReadRoutes"))
+			Expect(spans[0].EndTime().Sub(spans[0].StartTime())).To(Equal(20 * time.Millisecond))
+		})
+	})
+
+	Describe("without a span", func() {
+		It("starts spans that record nothing", func() {
+			ctx, span := tracing.Start(context.Background(), "db.ReadRoutes")
+			Expect(tracing.IsTraced(ctx)).To(BeFalse())
+			Expect(span.IsRecording()).To(BeFalse())
+			tracing.SetError(span, errors.New("boom"))
+			span.End()
+		})
+	})
+
+	Describe("Inject", func() {
+		It("sets the traceparent of the remote parent of the context", func() {
+			header := http.Header{}
+			tracing.Inject(tracing.Extract(context.Background(), withTraceParent(traceParent)), header)
+			Expect(header.Get("traceparent")).To(Equal(traceParent))
+		})
+
+		It("sets nothing for contexts that are not traced", func() {
+			header := http.Header{}
+			tracing.Inject(context.Background(), header)
+			Expect(header).To(BeEmpty())
+		})
+	})
+
+	Describe("NewSampler", func() {
+		It("returns the OTEL_TRACES_SAMPLER samplers", func() {
+			for _, name := range []string{"always_on", "always_off", "traceidratio", "parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio"} {
+				sampler, err := tracing.NewSampler(name, 0.5)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sampler).NotTo(BeNil())
+			}
+		})
+
+		It("samples the ratio of the traces", func() {
+			sampler, err := tracing.NewSampler("traceidratio", 0.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sampler.Description()).To(Equal("TraceIDRatioBased{0.5}"))
+		})
+
+		It("rejects unknown samplers", func() {
+			_, err := tracing.NewSampler("sometimes", 1)
+			Expect(err).To(MatchError(`unknown sampler "sometimes"`))
+		})
+	})
+
+	Describe("exporters", func() {
+		var spans []sdktrace.ReadOnlySpan
+
+		BeforeEach(func() {
+			_, span := tracer.StartRequest(context.Background(), "UpsertRoute", withTraceParent(traceParent))
+			span.SetAttributes(attribute.String("http.method", "POST"))
+			tracing.SetError(span, errors.New("boom"))
+			span.End()
+			spans = exported()
+			Expect(spans).To(HaveLen(1))
+		})
+
+		It("writes every span as JSON", func() {
+			buf := &bytes.Buffer{}
+			writer, err := tracing.NewWriterExporter(buf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.ExportSpans(context.Background(), spans)).To(Succeed())
+
+			var record struct {
+				Name        string
+				SpanContext struct{ TraceID string }
+				Parent      struct{ SpanID string }
+				Status      struct{ Description string }
+			}
+			Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+			Expect(record.Name).To(Equal("UpsertRoute"))
+			Expect(record.SpanContext.TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(record.Parent.SpanID).To(Equal("00f067aa0ba902b7"))
+			Expect(record.Status.Description).To(Equal("boom"))
+		})
+
+		It("posts the spans to an OTLP/HTTP collector", func() {
+			request := &coltracepb.ExportTraceServiceRequest{}
+			collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.URL.Path).To(Equal("/v1/traces"))
+				Expect(req.Header.Get("Content-Type")).To(Equal("application/x-protobuf"))
+				body, err := ioutil.ReadAll(req.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Unmarshal(body, request)).To(Succeed())
+			}))
+			defer collector.Close()
+
+			otlp, err := tracing.NewOTLPExporter(collector.URL + "/v1/traces")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otlp.ExportSpans(context.Background(), spans)).To(Succeed())
+			Expect(otlp.Shutdown(context.Background())).To(Succeed())
+
+			Expect(request.ResourceSpans).To(HaveLen(1))
+			Expect(request.ResourceSpans[0].ScopeSpans).To(HaveLen(1))
+			otlpSpans := request.ResourceSpans[0].ScopeSpans[0].Spans
+			Expect(otlpSpans).To(HaveLen(1))
+			Expect(otlpSpans[0].Name).To(Equal("UpsertRoute"))
+			Expect(otlpSpans[0].ParentSpanId).To(Equal([]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}))
+			Expect(otlpSpans[0].Status.Message).To(Equal("boom"))
+		})
+
+		It("fails when the collector rejects the spans", func() {
+			collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			}))
+			defer collector.Close()
+
+			otlp, err := tracing.NewOTLPExporter(collector.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otlp.ExportSpans(context.Background(), spans)).NotTo(Succeed())
+		})
+	})
+})
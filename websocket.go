@@ -0,0 +1,182 @@
+package routing_api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"code.cloudfoundry.org/routing-api/models"
+	"code.cloudfoundry.org/routing-api/models/protos"
+	"github.com/vito/go-sse/sse"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocketEvent is a message sent by the server on a WebSocket event
+// subscription. Name and Data are the event name and data of the matching
+// server-sent event. IDs start at 1.
+type WebSocketEvent struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+// WebSocketControl is a message sent by the client on a WebSocket event
+// subscription. It replaces the filter of the subscription when Filter is set
+// and acknowledges the events up to Ack when Ack is not 0.
+type WebSocketControl struct {
+	Filter *models.EventFilter `json:"filter,omitempty"`
+	Ack    uint64              `json:"ack,omitempty"`
+}
+
+//go:generate counterfeiter -o fake_routing_api/fake_web_socket_event_source.go . WebSocketEventSource
+type WebSocketEventSource interface {
+	EventSource
+	// SetFilter replaces the filter of the subscription.
+	SetFilter(filter models.EventFilter) error
+	// Ack acknowledges the events returned by Next so far.
+	Ack() error
+}
+
+//go:generate counterfeiter -o fake_routing_api/fake_tcp_web_socket_event_source.go . TcpWebSocketEventSource
+type TcpWebSocketEventSource interface {
+	TcpEventSource
+	SetFilter(filter models.EventFilter) error
+	Ack() error
+}
+
+type webSocketEventSource struct {
+	EventSource
+	conn *webSocketConn
+}
+
+func (e *webSocketEventSource) SetFilter(filter models.EventFilter) error {
+	return e.conn.setFilter(filter)
+}
+
+func (e *webSocketEventSource) Ack() error {
+	return e.conn.ack()
+}
+
+type tcpWebSocketEventSource struct {
+	TcpEventSource
+	conn *webSocketConn
+}
+
+func (e *tcpWebSocketEventSource) SetFilter(filter models.EventFilter) error {
+	return e.conn.setFilter(filter)
+}
+
+func (e *tcpWebSocketEventSource) Ack() error {
+	return e.conn.ack()
+}
+
+// webSocketConn is the RawEventSource of a WebSocket event subscription.
+type webSocketConn struct {
+	lastID    uint64
+	ws        *websocket.Conn
+	sendMutex sync.Mutex
+}
+
+func (c *webSocketConn) Next() (sse.Event, error) {
+	var event WebSocketEvent
+	err := websocket.JSON.Receive(c.ws, &event)
+	if err != nil {
+		return sse.Event{}, err
+	}
+	atomic.StoreUint64(&c.lastID, event.ID)
+
+	return sse.Event{
+		ID:   strconv.FormatUint(event.ID, 10),
+		Name: event.Name,
+		Data: []byte(event.Data),
+	}, nil
+}
+
+func (c *webSocketConn) Close() error {
+	return c.ws.Close()
+}
+
+func (c *webSocketConn) setFilter(filter models.EventFilter) error {
+	return c.send(WebSocketControl{Filter: &filter})
+}
+
+func (c *webSocketConn) ack() error {
+	lastID := atomic.LoadUint64(&c.lastID)
+	if lastID == 0 {
+		return nil
+	}
+	return c.send(WebSocketControl{Ack: lastID})
+}
+
+func (c *webSocketConn) send(control WebSocketControl) error {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+	return websocket.JSON.Send(c.ws, control)
+}
+
+func (c *client) SubscribeToEventsWithWebSocket(filter models.EventFilter, window uint64) (WebSocketEventSource, error) {
+	conn, err := c.dialWebSocket(EventStreamWebSocketRoute, filter, window)
+	if err != nil {
+		return nil, err
+	}
+	return &webSocketEventSource{EventSource: NewEventSource(conn), conn: conn}, nil
+}
+
+func (c *client) SubscribeToTcpEventsWithWebSocket(filter models.EventFilter, window uint64) (TcpWebSocketEventSource, error) {
+	conn, err := c.dialWebSocket(EventStreamTcpWebSocketRoute, filter, window)
+	if err != nil {
+		return nil, err
+	}
+	return &tcpWebSocketEventSource{TcpEventSource: NewTcpEventSource(conn), conn: conn}, nil
+}
+
+func (c *client) dialWebSocket(routeName string, filter models.EventFilter, window uint64) (*webSocketConn, error) {
+	request, err := c.reqGen.CreateRequest(routeName, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := *request.URL
+	location := *request.URL
+	switch location.Scheme {
+	case "https":
+		location.Scheme = "wss"
+	default:
+		location.Scheme = "ws"
+	}
+
+	query := url.Values{}
+	if filter != (models.EventFilter{}) {
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filter", string(data))
+	}
+	if window != 0 {
+		query.Set("window", strconv.FormatUint(window, 10))
+	}
+	location.RawQuery = query.Encode()
+
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+	config.TlsConfig = c.tlsConfig
+
+	c.tokenMutex.RLock()
+	config.Header.Add("Authorization", "bearer "+c.authToken)
+	c.tokenMutex.RUnlock()
+	c.setTraceParent(config.Header)
+	if c.protobuf {
+		config.Header.Set("Accept", protos.ContentType)
+	}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &webSocketConn{ws: conn}, nil
+}